@@ -0,0 +1,122 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// adminKeyHeader carries the admin key, which must match KANBAN_ADMIN_KEY
+const adminKeyHeader = "X-Admin-Key"
+
+// adminRecentEvents is how many audit events the admin page lists
+const adminRecentEvents = 10
+
+// startedAt is when the server process started, for its uptime
+var startedAt = time.Now()
+
+// AdminColumn is a column's task count on the admin page
+type AdminColumn struct {
+	Status      string
+	DisplayName string
+	Count       int
+}
+
+// FeatureState is one feature flag on the admin page
+type FeatureState struct {
+	Name    string
+	Enabled bool
+}
+
+// AdminData fills admin.html
+type AdminData struct {
+	Uptime         time.Duration
+	Goroutines     int
+	HeapAlloc      uint64 // bytes
+	Sys            uint64 // bytes obtained from the OS
+	NumGC          uint32
+	Columns        []AdminColumn
+	TotalTasks     int
+	RecentEvents   []BoardEvent // newest first
+	SSESubscribers int          // pages connected to /dev/reload
+	Webhooks       WebhookStats
+	Features       []FeatureState
+}
+
+// SystemInfo collects the server's health and the board's stats for the
+// admin page
+func SystemInfo(s Store) AdminData {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	data := AdminData{
+		Uptime:         time.Since(startedAt).Truncate(time.Second),
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAlloc:      mem.HeapAlloc,
+		Sys:            mem.Sys,
+		NumGC:          mem.NumGC,
+		SSESubscribers: devReload.Subscribers(),
+		Webhooks:       webhooks.Stats(),
+	}
+
+	for _, col := range s.GetBoardData().Columns {
+		data.Columns = append(data.Columns, AdminColumn{Status: col.Status, DisplayName: col.DisplayName, Count: len(col.Tasks)})
+		data.TotalTasks += len(col.Tasks)
+	}
+
+	events := auditLog.Events()
+	for i := len(events) - 1; i >= 0 && len(data.RecentEvents) < adminRecentEvents; i-- {
+		data.RecentEvents = append(data.RecentEvents, events[i])
+	}
+
+	// List the flags from their JSON, so new ones show up without changes here
+	var flags map[string]bool
+	raw, _ := json.Marshal(features.Get())
+	json.Unmarshal(raw, &flags)
+	for name, enabled := range flags {
+		data.Features = append(data.Features, FeatureState{Name: name, Enabled: enabled})
+	}
+	sort.Slice(data.Features, func(i, j int) bool { return data.Features[i].Name < data.Features[j].Name })
+	return data
+}
+
+// requireAdminKey only lets requests through whose X-Admin-Key header
+// matches KANBAN_ADMIN_KEY. Without the env var every request is refused.
+func requireAdminKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := os.Getenv("KANBAN_ADMIN_KEY")
+		given := r.Header.Get(adminKeyHeader)
+		if key == "" || subtle.ConstantTimeCompare([]byte(given), []byte(key)) != 1 {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// adminHandler serves GET /admin, the admin dashboard
+func (srv *Server) adminHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	templates.ExecuteTemplate(w, "admin.html", SystemInfo(srv))
+}
+
+// formatBytes formats a byte count in binary units, such as 1.5 MiB
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdminRequiresKey(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("One", "")
+	s.AddTask("Two", "")
+	s.MoveTask(2, "doing")
+
+	get := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if key != "" {
+			req.Header.Set(adminKeyHeader, key)
+		}
+		rr := httptest.NewRecorder()
+		requireAdminKey(NewServer(s).adminHandler)(rr, req)
+		return rr
+	}
+
+	if rr := get("anything"); rr.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 while KANBAN_ADMIN_KEY is unset, got %d", rr.Code)
+	}
+	t.Setenv("KANBAN_ADMIN_KEY", "s3cret")
+	if rr := get(""); rr.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 without the key, got %d", rr.Code)
+	}
+	if rr := get("wrong"); rr.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 with the wrong key, got %d", rr.Code)
+	}
+
+	rr := get("s3cret")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with the key, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"<h2>Tasks (2)</h2>",
+		`<tr><th>To Do</th><td data-status="todo">1</td></tr>`,
+		`<tr><th>Doing</th><td data-status="doing">1</td></tr>`,
+		`<tr><th>Done</th><td data-status="done">0</td></tr>`,
+		"#2 Two",
+		"<tr><th>comments</th><td>on</td></tr>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %s on the admin page", want)
+		}
+	}
+}
+
+func TestSystemInfoRecentEvents(t *testing.T) {
+	s := withTestGlobals(t)
+	for i := 0; i < adminRecentEvents+5; i++ {
+		s.AddTask("Task", "")
+	}
+	info := SystemInfo(store)
+	if len(info.RecentEvents) != adminRecentEvents || info.RecentEvents[0].TaskID != adminRecentEvents+5 {
+		t.Errorf("Expected the last %d events newest first, got %d", adminRecentEvents, len(info.RecentEvents))
+	}
+	if info.HeapAlloc == 0 || info.Goroutines == 0 {
+		t.Errorf("Expected memory and goroutine stats, got %+v", info)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[uint64]string{512: "512 B", 1536: "1.5 KiB", 5 << 20: "5.0 MiB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// AgeInStatus returns how long the task has been in its current column.
+// Tasks saved before StatusChangedAt existed count from their creation.
+func (t *Task) AgeInStatus() time.Duration {
+	since := t.StatusChangedAt
+	if since.IsZero() {
+		since = t.CreatedAt
+	}
+	return time.Since(since)
+}
+
+// FormatAge renders a duration in its largest whole unit: minutes under an
+// hour, then hours, days and weeks, e.g. "45m", "5h", "2d", "3w"
+func FormatAge(d time.Duration) string {
+	const (
+		day  = 24 * time.Hour
+		week = 7 * day
+	)
+	switch {
+	case d < time.Hour:
+		if d < 0 {
+			d = 0
+		}
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < day:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	case d < week:
+		return fmt.Sprintf("%dd", int(d/day))
+	default:
+		return fmt.Sprintf("%dw", int(d/week))
+	}
+}
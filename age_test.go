@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatAge(t *testing.T) {
+	cases := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{-time.Minute, "0m"},
+		{45 * time.Minute, "45m"},
+		{time.Hour, "1h"},
+		{5*time.Hour + 59*time.Minute, "5h"},
+		{23 * time.Hour, "23h"},
+		{24 * time.Hour, "1d"},
+		{2*24*time.Hour + 12*time.Hour, "2d"},
+		{6 * 24 * time.Hour, "6d"},
+		{7 * 24 * time.Hour, "1w"},
+		{3*7*24*time.Hour + 5*24*time.Hour, "3w"},
+		{52 * 7 * 24 * time.Hour, "52w"},
+	}
+	for _, c := range cases {
+		if got := FormatAge(c.d); got != c.want {
+			t.Errorf("FormatAge(%v) = %q, want %q", c.d, got, c.want)
+		}
+	}
+}
+
+func TestStatusChangedAtOnlyChangesOnMove(t *testing.T) {
+	s := newTestStore()
+	clock := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return clock }
+	task := s.AddTask("Aging", "")
+	if !task.StatusChangedAt.Equal(clock) {
+		t.Errorf("Expected StatusChangedAt to be set on creation, got %v", task.StatusChangedAt)
+	}
+
+	clock = clock.Add(time.Hour)
+	s.AssignTask(task.ID, "alice")
+	if got, _ := s.GetTask(task.ID); !got.StatusChangedAt.Equal(clock.Add(-time.Hour)) {
+		t.Errorf("Edits must not reset StatusChangedAt, got %v", got.StatusChangedAt)
+	}
+
+	clock = clock.Add(time.Hour)
+	s.MoveTask(task.ID, "doing")
+	if got, _ := s.GetTask(task.ID); !got.StatusChangedAt.Equal(clock) {
+		t.Errorf("Expected a move to set StatusChangedAt, got %v", got.StatusChangedAt)
+	}
+
+	legacy := &Task{CreatedAt: time.Now().Add(-3 * time.Hour)}
+	if age := FormatAge(legacy.AgeInStatus()); age != "3h" {
+		t.Errorf("Expected tasks without StatusChangedAt to age from creation, got %s", age)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// tasksAPIHandler serves the /api/v1/tasks collection
+func (srv *Server) tasksAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	opts, err := parseFilterOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tasks := srv.FilterTasks(opts)
+
+	if sortParam := r.URL.Query().Get("sort"); sortParam != "" {
+		if _, invalid := ParseSortKeys(sortParam); invalid != "" {
+			http.Error(w, fmt.Sprintf("Invalid sort key %q, valid options: %s",
+				invalid, strings.Join(SortKeyNames(), ", ")), http.StatusBadRequest)
+			return
+		}
+		tasks = SortTasks(tasks, sortParam)
+	}
+
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+// parseFilterOptions reads status, label, assignee, min_priority, and overdue
+// query parameters. List parameters are comma-separated.
+func parseFilterOptions(q url.Values) (FilterOptions, error) {
+	opts := FilterOptions{
+		Statuses:  parseLabels(q.Get("status")),
+		Labels:    parseLabels(q.Get("label")),
+		Assignees: parseLabels(q.Get("assignee")),
+	}
+
+	for _, status := range opts.Statuses {
+		if !isValidStatus(status) {
+			return opts, fmt.Errorf("invalid status %q", status)
+		}
+	}
+
+	if raw := q.Get("min_priority"); raw != "" {
+		priority, err := strconv.Atoi(raw)
+		if err != nil || priority < PriorityNone || priority > PriorityHigh {
+			return opts, fmt.Errorf("invalid min_priority %q", raw)
+		}
+		opts.MinPriority = priority
+	}
+
+	if raw := q.Get("overdue"); raw != "" {
+		overdue, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid overdue %q", raw)
+		}
+		opts.OverdueOnly = overdue
+	}
+	return opts, nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// archive hides a task from the board, keeping it in the store, and returns
+// the event announcing it (must be called with lock held)
+func (s *TaskStore) archive(task *Task) Event {
+	now := s.clock()
+	task.ArchivedAt = &now
+	task.UpdatedAt = now
+	return Event{
+		Type:    EventTaskUpdated,
+		Task:    task.clone(),
+		Changes: []FieldChange{{Field: "archived_at", New: now.Format(time.RFC3339)}},
+	}
+}
+
+// archivable returns the unarchived tasks in status created more than
+// olderThan ago (must be called with lock held)
+func (s *TaskStore) archivable(status string, olderThan time.Duration) []*Task {
+	cutoff := s.clock().Add(-olderThan)
+	var tasks []*Task
+	for _, task := range s.tasks {
+		if task.Status == status && task.ArchivedAt == nil && task.CreatedAt.Before(cutoff) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// CountArchivable returns how many tasks BulkArchive would archive
+func (s *TaskStore) CountArchivable(status string, olderThan time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.archivable(status, olderThan))
+}
+
+// BulkArchive archives every task in status created more than olderThan ago
+// and returns how many it archived, or ErrBoardLocked
+func (s *TaskStore) BulkArchive(status string, olderThan time.Duration) (int, error) {
+	span := s.startSpan(context.Background(), "BulkArchive", attribute.String("task.status", status))
+	defer span.End()
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return 0, err
+	}
+	tasks := s.archivable(status, olderThan)
+	if len(tasks) == 0 {
+		s.mu.Unlock()
+		return 0, nil
+	}
+	events := make([]Event, 0, len(tasks))
+	for _, task := range tasks {
+		events = append(events, s.archive(task))
+	}
+	s.persist(status)
+	s.mu.Unlock()
+
+	for _, e := range events {
+		s.publish(e)
+	}
+	return len(tasks), nil
+}
+
+// parseAge reads an age such as "30d" or any Go duration such as "12h"
+func parseAge(raw string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid age %q", raw)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q", raw)
+	}
+	return d, nil
+}
+
+// bulkArchiveRequest is the body of POST /api/v1/tasks/bulk-archive
+type bulkArchiveRequest struct {
+	OlderThan string `json:"older_than"`
+	Status    string `json:"status"`
+}
+
+// bulkArchiveHandler archives a column's old tasks, or with ?dry_run=true
+// only counts them
+func (srv *Server) bulkArchiveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req bulkArchiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if req.Status == "" {
+		req.Status = "done"
+	}
+	if !isValidStatus(req.Status) {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+	olderThan, err := parseAge(req.OlderThan)
+	if err != nil {
+		http.Error(w, "older_than must be a number of days like 30d or a duration like 12h", http.StatusBadRequest)
+		return
+	}
+
+	var count int
+	if r.URL.Query().Get("dry_run") == "true" {
+		count = srv.CountArchivable(req.Status, olderThan)
+	} else if count, err = srv.BulkArchive(req.Status, olderThan); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"archived_count": count})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBulkArchive(t *testing.T) {
+	s := newTestStore()
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	current := now
+	s.now = func() time.Time { return current }
+	for _, age := range []int{45, 31, 29, 2} {
+		current = now.AddDate(0, 0, -age)
+		task := s.AddTask(fmt.Sprintf("Done %d days ago", age), "")
+		s.MoveTask(task.ID, "done")
+	}
+	current = now.AddDate(0, 0, -60)
+	s.AddTask("Old but open", "")
+	current = now
+
+	if n := s.CountArchivable("done", 30*24*time.Hour); n != 2 {
+		t.Errorf("Expected 2 archivable tasks, got %d", n)
+	}
+	if n, _ := s.BulkArchive("done", 30*24*time.Hour); n != 2 {
+		t.Fatalf("Expected 2 archived tasks, got %d", n)
+	}
+	for id, archived := range map[int]bool{1: true, 2: true, 3: false, 4: false, 5: false} {
+		task, _ := s.GetTask(id)
+		if (task.ArchivedAt != nil) != archived {
+			t.Errorf("Task %d: expected archived=%v", id, archived)
+		}
+	}
+	if got := taskIDs(s.GetTasksByStatus("done")); !equalIDs(got, []int{3, 4}) {
+		t.Errorf("Expected only the recent tasks on the board, got %v", got)
+	}
+	if n, _ := s.BulkArchive("done", 30*24*time.Hour); n != 0 {
+		t.Errorf("Expected a second run to archive nothing, got %d", n)
+	}
+}
+
+func TestBulkArchiveHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	now := time.Now()
+	s.now = func() time.Time { return now.AddDate(0, 0, -10) }
+	s.CreateTask(TaskSpec{Title: "Shipped", Status: "done"})
+	s.now = nil
+
+	archive := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		NewServer(s).bulkArchiveHandler(w, req)
+		return w
+	}
+
+	w := archive("/api/v1/tasks/bulk-archive?dry_run=true", `{"older_than": "7d", "status": "done"}`)
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `{"archived_count":1}` {
+		t.Fatalf("Unexpected dry run response %d: %s", w.Code, w.Body.String())
+	}
+	if task, _ := s.GetTask(1); task.ArchivedAt != nil {
+		t.Errorf("Expected a dry run not to archive")
+	}
+
+	if w := archive("/api/v1/tasks/bulk-archive", `{"older_than": "7d"}`); !strings.Contains(w.Body.String(), `"archived_count":1`) {
+		t.Errorf("Expected status to default to done, got %s", w.Body.String())
+	}
+	if task, _ := s.GetTask(1); task.ArchivedAt == nil {
+		t.Errorf("Expected the task to be archived")
+	}
+
+	for _, body := range []string{`{"older_than": "soon"}`, `{"older_than": "0d"}`, `{"older_than": "7d", "status": "gone"}`} {
+		if w := archive("/api/v1/tasks/bulk-archive", body); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, w.Code)
+		}
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	for raw, want := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "12h": 12 * time.Hour} {
+		if got, err := parseAge(raw); err != nil || got != want {
+			t.Errorf("parseAge(%q) = %v, %v", raw, got, err)
+		}
+	}
+	for _, raw := range []string{"", "-1d", "d", "-5h"} {
+		if _, err := parseAge(raw); err == nil {
+			t.Errorf("Expected parseAge(%q) to fail", raw)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import "fmt"
+
+// AriaData returns the accessibility attributes of a task card, so screen
+// readers announce each card's title, status and priority when it gets
+// keyboard focus
+func AriaData(task *Task) map[string]string {
+	priority := task.PriorityLabel()
+	if priority == "" {
+		priority = "none"
+	}
+	attrs := map[string]string{
+		"role":       "article",
+		"tabindex":   "0",
+		"aria-label": fmt.Sprintf("Task: %s, status: %s, priority: %s", task.Title, task.Status, priority),
+	}
+	if task.Description != "" {
+		attrs["aria-describedby"] = fmt.Sprintf("task-desc-%d", task.ID)
+	}
+	return attrs
+}
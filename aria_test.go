@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// findElements returns every element under n with the given class
+func findElements(n *html.Node, class string) []*html.Node {
+	var found []*html.Node
+	if n.Type == html.ElementNode {
+		for _, c := range strings.Fields(attr(n, "class")) {
+			if c == class {
+				found = append(found, n)
+				break
+			}
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		found = append(found, findElements(child, class)...)
+	}
+	return found
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func TestAriaData(t *testing.T) {
+	attrs := AriaData(&Task{ID: 4, Title: "Plan", Status: "doing", Priority: PriorityHigh, Description: "Details"})
+	if attrs["role"] != "article" || attrs["tabindex"] != "0" {
+		t.Errorf("Unexpected role or tabindex: %v", attrs)
+	}
+	if attrs["aria-label"] != "Task: Plan, status: doing, priority: High" {
+		t.Errorf("Unexpected aria-label %q", attrs["aria-label"])
+	}
+	if attrs["aria-describedby"] != "task-desc-4" {
+		t.Errorf("Unexpected aria-describedby %q", attrs["aria-describedby"])
+	}
+
+	attrs = AriaData(&Task{ID: 5, Title: "Bare", Status: "todo"})
+	if _, ok := attrs["aria-describedby"]; ok {
+		t.Errorf("Expected no aria-describedby without a description")
+	}
+	if !strings.HasSuffix(attrs["aria-label"], "priority: none") {
+		t.Errorf("Expected priority none, got %q", attrs["aria-label"])
+	}
+}
+
+func TestBoardAriaAttributes(t *testing.T) {
+	s := withTestGlobals(t)
+	s.CreateTask(TaskSpec{Title: "Write <docs>", Description: "User guide", Priority: PriorityMedium})
+	s.AddTask("No description", "")
+
+	rr := httptest.NewRecorder()
+	NewServer(s).indexHandler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	doc, err := html.Parse(rr.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := findElements(doc, "column")
+	if len(columns) != 3 {
+		t.Fatalf("Expected 3 columns, got %d", len(columns))
+	}
+	for _, col := range columns {
+		if attr(col, "role") != "region" {
+			t.Errorf("Expected column role region, got %q", attr(col, "role"))
+		}
+	}
+	if label := attr(columns[0], "aria-label"); label != "To Do column, 2 tasks" {
+		t.Errorf("Unexpected column label %q", label)
+	}
+
+	cards := findElements(doc, "task-card")
+	if len(cards) != 2 {
+		t.Fatalf("Expected 2 cards, got %d", len(cards))
+	}
+	first := cards[0]
+	if attr(first, "role") != "article" || attr(first, "tabindex") != "0" {
+		t.Errorf("Expected a focusable article, got role=%q tabindex=%q", attr(first, "role"), attr(first, "tabindex"))
+	}
+	if label := attr(first, "aria-label"); label != "Task: Write <docs>, status: todo, priority: Medium" {
+		t.Errorf("Unexpected card label %q", label)
+	}
+	describedBy := attr(first, "aria-describedby")
+	var described *html.Node
+	for _, desc := range findElements(first, "task-description") {
+		if attr(desc, "id") == describedBy {
+			described = desc
+		}
+	}
+	if described == nil || described.FirstChild == nil || described.FirstChild.Data != "User guide" {
+		t.Errorf("Expected aria-describedby %q to point at the description", describedBy)
+	}
+	if attr(cards[1], "aria-describedby") != "" {
+		t.Errorf("Expected no aria-describedby on a card without a description")
+	}
+
+	if board := findElements(doc, "board"); len(board) != 1 || attr(board[0], "hx-on:htmx:after-swap") != "focusFirstCard()" {
+		t.Errorf("Expected the board to restore focus after swaps")
+	}
+}
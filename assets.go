@@ -0,0 +1,32 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+// The page templates and static files are compiled into the binary, so it
+// runs without the templates/ and static/ directories next to it
+
+//go:embed templates/*
+var embeddedTemplates embed.FS
+
+//go:embed static/*
+var embeddedStatic embed.FS
+
+// assetFS returns the files under dir: from disk in development, so edits
+// show up without a rebuild, and from the binary otherwise
+func assetFS(embedded embed.FS, dir string, dev bool) fs.FS {
+	if dev {
+		return os.DirFS(dir)
+	}
+	sub, err := fs.Sub(embedded, dir)
+	if err != nil {
+		panic(err) // dir is one of the embedded directories
+	}
+	return sub
+}
+
+// staticFiles is served under /static/
+var staticFiles = assetFS(embeddedStatic, "static", false)
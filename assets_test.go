@@ -0,0 +1,60 @@
+package main
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedAssetsWithoutDirectories(t *testing.T) {
+	withTestGlobals(t)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	if _, err := os.Stat("templates"); !os.IsNotExist(err) {
+		t.Fatalf("Expected no templates directory, got %v", err)
+	}
+
+	origTemplates := templates
+	t.Cleanup(func() { templates = origTemplates })
+	templates = mustLoadTemplates(assetFS(embeddedTemplates, "templates", false), "*.html")
+
+	store.AddTask("Embedded", "")
+	w := httptest.NewRecorder()
+	NewServer(store).indexHandler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Embedded") || !strings.Contains(w.Body.String(), "/static/style.css") {
+		t.Errorf("Expected the board to render from embedded templates, got %d", w.Code)
+	}
+
+	server := httptest.NewServer(http.StripPrefix("/static/", http.FileServer(http.FS(assetFS(embeddedStatic, "static", false)))))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/static/style.css")
+	if err != nil {
+		t.Fatalf("GET style.css failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), ".task-card") {
+		t.Errorf("Expected the embedded stylesheet, got %d", resp.StatusCode)
+	}
+}
+
+func TestAssetFSUsesDiskInDevelopment(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "page.html"), []byte("from disk"), 0644)
+
+	data, err := fs.ReadFile(assetFS(embeddedTemplates, dir, true), "page.html")
+	if err != nil || string(data) != "from disk" {
+		t.Errorf("Expected the file on disk in development, got %q (%v)", data, err)
+	}
+}
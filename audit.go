@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AuditEntry is one line of the audit log. Before and After hold the fields
+// the action changed, with the values they had before and after it.
+type AuditEntry struct {
+	ID        int               `json:"id"`
+	Timestamp time.Time         `json:"timestamp"`
+	Action    string            `json:"action"`
+	Actor     string            `json:"actor"`
+	TaskID    int               `json:"task_id"`
+	Before    map[string]string `json:"before"`
+	After     map[string]string `json:"after"`
+}
+
+// newAuditEntry describes an event as an audit log entry
+func newAuditEntry(id int, e Event) AuditEntry {
+	entry := AuditEntry{ID: id, Timestamp: e.Time.UTC(), Action: e.Type, Actor: e.Actor}
+	if e.Task != nil {
+		entry.TaskID = e.Task.ID
+	}
+	switch e.Type {
+	case EventTaskCreated:
+		entry.After = auditTaskFields(e.Task)
+	case EventTaskDeleted:
+		entry.Before = auditTaskFields(e.Task)
+	case EventTaskMoved:
+		entry.Before = map[string]string{"status": e.FromStatus}
+		entry.After = map[string]string{"status": e.ToStatus}
+	default:
+		if len(e.Changes) > 0 {
+			entry.Before = make(map[string]string)
+			entry.After = make(map[string]string)
+		}
+		for _, change := range e.Changes {
+			entry.Before[change.Field] = change.Old
+			entry.After[change.Field] = change.New
+		}
+	}
+	return entry
+}
+
+// auditTaskFields returns the fields recorded for a created or deleted task
+func auditTaskFields(task *Task) map[string]string {
+	if task == nil {
+		return nil
+	}
+	return map[string]string{
+		"title":       task.Title,
+		"description": task.Description,
+		"status":      task.Status,
+	}
+}
+
+// ParseAuditLog reads a JSONL audit log, skipping blank lines
+func ParseAuditLog(r io.Reader) ([]AuditEntry, error) {
+	entries := []AuditEntry{}
+	err := scanAuditLog(r, func(entry AuditEntry) bool {
+		entries = append(entries, entry)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// scanAuditLog calls fn with each entry of a JSONL audit log in order,
+// skipping blank lines, until fn returns false
+func scanAuditLog(r io.Reader, fn func(AuditEntry) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if !fn(entry) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// AuditFile appends board events to a JSONL file. The file is only ever
+// opened for appending, so entries already written are never rewritten.
+type AuditFile struct {
+	mu     sync.Mutex
+	path   string
+	nextID int
+}
+
+// getAuditFilePath returns the audit log path from env var or default
+func getAuditFilePath() string {
+	if path := os.Getenv("KANBAN_AUDIT_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(".", "audit.jsonl")
+}
+
+// auditFile is the on-disk audit log, nil until main opens it
+var auditFile *AuditFile
+
+// OpenAuditFile returns an AuditFile appending to path, continuing the IDs
+// of any entries already in it
+func OpenAuditFile(path string) (*AuditFile, error) {
+	a := &AuditFile{path: path}
+	entries, err := a.read()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.ID > a.nextID {
+			a.nextID = entry.ID
+		}
+	}
+	return a, nil
+}
+
+// Record appends an event to the log. Like EventLog.Record, TaskAssigned,
+// TaskWatched and CommentMention events are skipped since the event they
+// follow already records the change, and TaskIdle events since nothing
+// changed.
+func (a *AuditFile) Record(e Event) {
+	if e.Type == EventTaskAssigned || e.Type == EventTaskWatched || e.Type == EventCommentMention || e.Type == EventTaskIdle {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry := newAuditEntry(a.nextID+1, e)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Error encoding audit entry: %v", err)
+		return
+	}
+	file, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("Error opening audit log: %v", err)
+		return
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		log.Printf("Error writing audit log: %v", err)
+		return
+	}
+	a.nextID = entry.ID
+}
+
+// Entries returns the entries logged in [from, to), oldest first. A zero
+// from or to leaves that end of the range open.
+func (a *AuditFile) Entries(from, to time.Time) ([]AuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entries, err := a.read()
+	if err != nil {
+		return nil, err
+	}
+	filtered := []AuditEntry{}
+	for _, entry := range entries {
+		if !from.IsZero() && entry.Timestamp.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !entry.Timestamp.Before(to) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered, nil
+}
+
+// GetAuditEvents returns up to limit entries with IDs after afterID, oldest
+// first, and whether more follow. Entries are appended with increasing IDs,
+// so the last ID returned is the cursor for the next page. The file is read
+// only as far as the page needs; if it can't be read the error is logged
+// and no entries are returned.
+func (a *AuditFile) GetAuditEvents(afterID, limit int) ([]AuditEntry, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entries := []AuditEntry{}
+	file, err := os.Open(a.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Error reading audit log: %v", err)
+		}
+		return entries, false
+	}
+	defer file.Close()
+
+	hasMore := false
+	err = scanAuditLog(file, func(entry AuditEntry) bool {
+		if entry.ID <= afterID {
+			return true
+		}
+		if len(entries) == limit {
+			hasMore = true
+			return false
+		}
+		entries = append(entries, entry)
+		return true
+	})
+	if err != nil {
+		log.Printf("Error reading audit log: %v", err)
+		return []AuditEntry{}, false
+	}
+	return entries, hasMore
+}
+
+// read parses the whole file, returning nothing if it doesn't exist yet
+func (a *AuditFile) read() ([]AuditEntry, error) {
+	file, err := os.Open(a.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []AuditEntry{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+	return ParseAuditLog(file)
+}
+
+// parseAuditRange reads the from and to query parameters. Each is a
+// YYYY-MM-DD date or an RFC 3339 time, and both ends are inclusive, so a to
+// date covers that whole day. The returned end is exclusive.
+func parseAuditRange(r *http.Request) (time.Time, time.Time, error) {
+	var from, to time.Time
+	if raw := r.URL.Query().Get("from"); raw != "" {
+		t, err := parseDueDate(raw)
+		if err != nil {
+			return from, to, errors.New("from must be a date (YYYY-MM-DD) or RFC 3339 time")
+		}
+		from = t
+	}
+	if raw := r.URL.Query().Get("to"); raw != "" {
+		t, err := parseDueDate(raw)
+		if err != nil {
+			return from, to, errors.New("to must be a date (YYYY-MM-DD) or RFC 3339 time")
+		}
+		if len(raw) == len("2006-01-02") {
+			to = t.AddDate(0, 0, 1)
+		} else {
+			to = t.Add(time.Nanosecond)
+		}
+	}
+	return from, to, nil
+}
+
+const (
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+)
+
+// parseAuditCursor reads the after_id and limit query parameters, reporting
+// whether either was given
+func parseAuditCursor(r *http.Request) (int, int, bool, error) {
+	q := r.URL.Query()
+	rawAfter, rawLimit := q.Get("after_id"), q.Get("limit")
+	if rawAfter == "" && rawLimit == "" {
+		return 0, 0, false, nil
+	}
+	afterID, limit := 0, defaultAuditPageSize
+	if rawAfter != "" {
+		id, err := strconv.Atoi(rawAfter)
+		if err != nil || id < 0 {
+			return 0, 0, true, errors.New("after_id must be an entry ID")
+		}
+		afterID = id
+	}
+	if rawLimit != "" {
+		n, err := strconv.Atoi(rawLimit)
+		if err != nil || n < 1 || n > maxAuditPageSize {
+			return 0, 0, true, fmt.Errorf("limit must be between 1 and %d", maxAuditPageSize)
+		}
+		limit = n
+	}
+	return afterID, limit, true, nil
+}
+
+// auditExportHandler serves the audit log as a downloadable JSONL file,
+// optionally limited to ?from= and ?to=. With ?after_id= or ?limit= it
+// serves one page instead, setting X-Next-Cursor when more entries follow.
+func auditExportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if auditFile == nil {
+		http.Error(w, "Audit log not enabled", http.StatusNotFound)
+		return
+	}
+	from, to, err := parseAuditRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	afterID, limit, paged, err := parseAuditCursor(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var entries []AuditEntry
+	if paged {
+		if !from.IsZero() || !to.IsZero() {
+			http.Error(w, "after_id and limit can't be combined with from and to", http.StatusBadRequest)
+			return
+		}
+		var hasMore bool
+		entries, hasMore = auditFile.GetAuditEvents(afterID, limit)
+		if hasMore {
+			w.Header().Set("X-Next-Cursor", strconv.Itoa(entries[len(entries)-1].ID))
+		}
+	} else {
+		entries, err = auditFile.Entries(from, to)
+		if err != nil {
+			log.Printf("Error reading audit log: %v", err)
+			http.Error(w, "Could not read audit log", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit.jsonl"`)
+	encoder := json.NewEncoder(w)
+	for _, entry := range entries {
+		encoder.Encode(entry)
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withTestAuditFile(t *testing.T) *AuditFile {
+	a, err := OpenAuditFile(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := auditFile
+	auditFile = a
+	t.Cleanup(func() { auditFile = orig })
+	return a
+}
+
+func TestAuditFileRecord(t *testing.T) {
+	a := withTestAuditFile(t)
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	task := &Task{ID: 7, Title: "Write report", Status: "doing"}
+
+	a.Record(Event{Type: EventTaskCreated, Task: task, Actor: "ana", Time: at})
+	a.Record(Event{Type: EventTaskMoved, Task: task, FromStatus: "todo", ToStatus: "doing", Time: at})
+	a.Record(Event{Type: EventTaskUpdated, Task: task, Changes: []FieldChange{{Field: "title", Old: "Report", New: "Write report"}}, Time: at})
+	a.Record(Event{Type: EventTaskAssigned, Task: task, Time: at})
+
+	entries, err := a.Entries(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.ID != i+1 || entry.TaskID != 7 || !entry.Timestamp.Equal(at) {
+			t.Errorf("Entry %d: unexpected %+v", i, entry)
+		}
+	}
+	if entries[0].Action != EventTaskCreated || entries[0].Actor != "ana" || entries[0].Before != nil || entries[0].After["title"] != "Write report" {
+		t.Errorf("Unexpected created entry %+v", entries[0])
+	}
+	if entries[1].Before["status"] != "todo" || entries[1].After["status"] != "doing" {
+		t.Errorf("Unexpected moved entry %+v", entries[1])
+	}
+	if entries[2].Before["title"] != "Report" || entries[2].After["title"] != "Write report" {
+		t.Errorf("Unexpected updated entry %+v", entries[2])
+	}
+}
+
+func TestOpenAuditFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	first, err := OpenAuditFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Record(Event{Type: EventTaskCreated, Task: &Task{ID: 1}, Time: time.Now()})
+	first.Record(Event{Type: EventTaskCreated, Task: &Task{ID: 2}, Time: time.Now()})
+
+	second, err := OpenAuditFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.Record(Event{Type: EventTaskDeleted, Task: &Task{ID: 1}, Time: time.Now()})
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	entries, err := ParseAuditLog(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries after reopening, got %d", len(entries))
+	}
+	if entries[2].ID != 3 || entries[2].Action != EventTaskDeleted {
+		t.Errorf("Expected the reopened log to continue at ID 3, got %+v", entries[2])
+	}
+}
+
+func TestParseAuditLog(t *testing.T) {
+	input := `{"id":1,"timestamp":"2024-03-01T09:00:00Z","action":"TaskCreated","actor":"","task_id":4,"before":null,"after":{"title":"A"}}
+
+{"id":2,"timestamp":"2024-03-02T09:00:00Z","action":"TaskDeleted","actor":"ana","task_id":4,"before":{"title":"A"},"after":null}
+`
+	entries, err := ParseAuditLog(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[1].Actor != "ana" || entries[1].Before["title"] != "A" || entries[1].After != nil {
+		t.Errorf("Unexpected entry %+v", entries[1])
+	}
+
+	if _, err := ParseAuditLog(strings.NewReader("{\"id\":1}\nnot json\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error naming line 2, got %v", err)
+	}
+}
+
+func TestAuditExportHandlerFiltersByTime(t *testing.T) {
+	a := withTestAuditFile(t)
+	for day := 1; day <= 5; day++ {
+		a.Record(Event{
+			Type: EventTaskCreated,
+			Task: &Task{ID: day, Title: "Task"},
+			Time: time.Date(2024, 3, day, 12, 0, 0, 0, time.UTC),
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/audit/export?from=2024-03-02&to=2024-03-04", nil)
+	rr := httptest.NewRecorder()
+	auditExportHandler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Expected JSONL content type, got %q", ct)
+	}
+	entries, err := ParseAuditLog(rr.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, entry := range entries {
+		ids = append(ids, entry.TaskID)
+	}
+	if !equalIDs(ids, []int{2, 3, 4}) {
+		t.Errorf("Expected tasks 2-4, got %v", ids)
+	}
+}
+
+func TestAuditExportHandlerRFC3339Range(t *testing.T) {
+	a := withTestAuditFile(t)
+	for hour := 8; hour <= 11; hour++ {
+		a.Record(Event{
+			Type: EventTaskCreated,
+			Task: &Task{ID: hour},
+			Time: time.Date(2024, 3, 1, hour, 0, 0, 0, time.UTC),
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/audit/export?from=2024-03-01T09:00:00Z&to=2024-03-01T10:00:00Z", nil)
+	rr := httptest.NewRecorder()
+	auditExportHandler(rr, req)
+
+	entries, err := ParseAuditLog(rr.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].TaskID != 9 || entries[1].TaskID != 10 {
+		t.Errorf("Expected the 9:00 and 10:00 entries, got %+v", entries)
+	}
+}
+
+func TestAuditExportHandlerInvalidRange(t *testing.T) {
+	withTestAuditFile(t)
+	req := httptest.NewRequest(http.MethodGet, "/audit/export?from=yesterday", nil)
+	rr := httptest.NewRecorder()
+	auditExportHandler(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rr.Code)
+	}
+}
+
+func TestGetAuditEvents(t *testing.T) {
+	a := withTestAuditFile(t)
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	for i := 1; i <= 250; i++ {
+		a.Record(Event{Type: EventTaskCreated, Task: &Task{ID: i}, Time: at})
+	}
+
+	seen := map[int]bool{}
+	afterID, pages := 0, 0
+	for {
+		entries, hasMore := a.GetAuditEvents(afterID, 100)
+		pages++
+		for _, entry := range entries {
+			if seen[entry.ID] {
+				t.Fatalf("Entry %d returned twice", entry.ID)
+			}
+			if entry.ID <= afterID {
+				t.Fatalf("Entry %d returned after cursor %d", entry.ID, afterID)
+			}
+			seen[entry.ID] = true
+			afterID = entry.ID
+		}
+		if !hasMore {
+			break
+		}
+		if len(entries) != 100 {
+			t.Fatalf("Expected a full page before the last, got %d entries", len(entries))
+		}
+	}
+	if len(seen) != 250 || pages != 3 {
+		t.Errorf("Expected 250 entries over 3 pages, got %d over %d", len(seen), pages)
+	}
+
+	if entries, hasMore := a.GetAuditEvents(250, 100); len(entries) != 0 || hasMore {
+		t.Errorf("Expected nothing after the last entry, got %d, %v", len(entries), hasMore)
+	}
+	if entries, hasMore := a.GetAuditEvents(200, 50); len(entries) != 50 || hasMore {
+		t.Errorf("Expected an exactly full last page without more, got %d, %v", len(entries), hasMore)
+	}
+}
+
+func TestAuditExportHandlerCursor(t *testing.T) {
+	a := withTestAuditFile(t)
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	for i := 1; i <= 250; i++ {
+		a.Record(Event{Type: EventTaskCreated, Task: &Task{ID: i}, Time: at})
+	}
+
+	var ids []int
+	url := "/audit/export?limit=100"
+	for page := 0; page < 5; page++ {
+		rr := httptest.NewRecorder()
+		auditExportHandler(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d %q", rr.Code, rr.Body.String())
+		}
+		cursor := rr.Header().Get("X-Next-Cursor")
+		entries, err := ParseAuditLog(rr.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		if cursor == "" {
+			break
+		}
+		url = "/audit/export?limit=100&after_id=" + cursor
+	}
+	if len(ids) != 250 {
+		t.Fatalf("Expected 250 entries, got %d", len(ids))
+	}
+	for i, id := range ids {
+		if id != i+1 {
+			t.Fatalf("Expected entry %d at position %d, got %d", i+1, i, id)
+		}
+	}
+
+	for _, query := range []string{"limit=0", "limit=5000", "after_id=-1", "after_id=x", "after_id=5&from=2024-03-01"} {
+		rr := httptest.NewRecorder()
+		auditExportHandler(rr, httptest.NewRequest(http.MethodGet, "/audit/export?"+query, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rr.Code)
+		}
+	}
+}
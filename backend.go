@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+)
+
+// TaskRepository is the core task CRUD implemented by TaskStore and by each
+// backend
+type TaskRepository interface {
+	AddTask(title, description string) *Task
+	CreateTask(spec TaskSpec) (*Task, error)
+	GetTask(id int) (*Task, bool)
+	GetTasksByStatus(status string) []*Task
+	GetAllTasks() []*Task
+	MoveTask(id int, newStatus string) (*Task, bool, error)
+	DeleteTask(id int) (bool, error)
+}
+
+// Backend is an external store that TaskStore loads the board from at
+// startup and mirrors every change to through the event bus. Links,
+// comments, reactions and relationships aren't tasks, so rather than
+// through events they are saved whole, as a PersistentData without tasks,
+// by saveExtras whenever one changes.
+type Backend interface {
+	TaskRepository
+	io.Closer
+	Load() (map[int]*Task, int, error)
+	mirror(e Event)
+	saveExtras(data PersistentData) error
+	loadExtras() (PersistentData, error)
+}
+
+// idAllocator is implemented by backends that hand out task IDs shared
+// across server instances
+type idAllocator interface {
+	NextID() (int, error)
+}
+
+// changeFeed is implemented by backends shared between server instances.
+// Changes sends the ID of each task another instance changes, until stop is
+// closed.
+type changeFeed interface {
+	Changes(stop <-chan struct{}) <-chan int
+}
+
+// followChanges rereads each task another instance changed from the backend,
+// so this instance's board shows it too
+func (s *TaskStore) followChanges(changes <-chan int) {
+	for id := range changes {
+		s.refreshTask(id)
+	}
+}
+
+// refreshTask replaces the store's copy of a task with the backend's, or
+// drops it if the backend no longer has it. Nothing is published: the
+// instance that made the change has already mirrored and announced it.
+func (s *TaskStore) refreshTask(id int) {
+	s.mu.Lock()
+	var statuses []string
+	if old, ok := s.tasks[id]; ok {
+		statuses = append(statuses, old.Status)
+	}
+	if task, ok := s.backend.GetTask(id); ok {
+		s.tasks[id] = task
+		s.deps.set(id, task.DependsOn)
+		statuses = append(statuses, task.Status)
+		if id >= s.nextID {
+			s.nextID = id + 1
+		}
+	} else {
+		delete(s.tasks, id)
+		s.deps.remove(id)
+	}
+	s.cache.rebuild(s.tasks, statuses...)
+	s.mu.Unlock()
+	s.invalidatePages()
+}
+
+var (
+	_ TaskRepository = (*TaskStore)(nil)
+	_ io.Closer      = (*TaskStore)(nil)
+)
+
+// attachBackend keeps s's board in b: it is loaded from b, every change s
+// publishes is mirrored to it, and changes other instances make are reread
+func (s *TaskStore) attachBackend(b Backend) {
+	s.backend = b
+	s.events.Subscribe(EventAll, b.mirror)
+	if feed, ok := b.(changeFeed); ok {
+		go s.followChanges(feed.Changes(make(chan struct{})))
+	}
+}
+
+// OpenBackend connects to the external backend named in the storage config
+func OpenBackend(c StorageConfig) (Backend, error) {
+	switch c.Backend {
+	case "redis":
+		return NewRedisStore(c.URL)
+	case "sqlite":
+		return NewSQLiteStore(c.Path)
+	case "postgres":
+		return NewPostgresBackend(c.URL, c.MaxConns)
+	default:
+		return nil, fmt.Errorf("unknown backend %q, valid options: redis, sqlite, postgres", c.Backend)
+	}
+}
+
+// extras returns everything but the tasks that a board saves (must be
+// called with lock held)
+func (s *TaskStore) extras() PersistentData {
+	return PersistentData{
+		Links:              s.allLinks(),
+		NextLinkID:         s.nextLinkID,
+		Comments:           s.allComments(),
+		NextCommentID:      s.nextCommentID,
+		Reactions:          s.allReactions(),
+		Relationships:      s.relationships,
+		NextRelationshipID: s.nextRelationshipID,
+	}
+}
+
+// saveExtras saves links, comments, reactions and relationships to the
+// backend (must be called with lock held)
+func (s *TaskStore) saveExtras() {
+	if err := s.backend.saveExtras(s.extras()); err != nil {
+		log.Printf("Error saving links and comments to the backend: %v", err)
+	}
+}
+
+// mirrorBoard writes the whole board to the backend, for changes too broad
+// to publish one event per task: the tasks with the removed IDs are deleted
+// and every task and the extras are saved again (must be called with lock
+// held)
+func (s *TaskStore) mirrorBoard(removed []int) {
+	if s.backend == nil {
+		return
+	}
+	for _, id := range removed {
+		s.backend.mirror(Event{Type: EventTaskDeleted, Task: &Task{ID: id}})
+	}
+	for _, task := range s.tasks {
+		s.backend.mirror(Event{Type: EventTaskCreated, Task: task.clone()})
+	}
+	s.saveExtras()
+}
+
+// Close releases the store's backend, if it has one
+func (s *TaskStore) Close() error {
+	if s.backend == nil {
+		return nil
+	}
+	return s.backend.Close()
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrBatchOverWIPLimit is returned when a batch move would take a column
+// past its WIP limit. Nothing is moved.
+type ErrBatchOverWIPLimit struct {
+	Status  string
+	Limit   int
+	Current int // tasks already in the column
+	Moving  int // tasks the batch would add
+}
+
+func (e *ErrBatchOverWIPLimit) Error() string {
+	return fmt.Sprintf("moving %d tasks into %q would exceed its WIP limit of %d (it has %d)",
+		e.Moving, e.Status, e.Limit, e.Current)
+}
+
+// Is makes errors.Is(err, ErrWIPLimitExceeded) match a batch over the limit
+func (e *ErrBatchOverWIPLimit) Is(target error) bool {
+	return target == ErrWIPLimitExceeded
+}
+
+// BatchMove moves every unarchived task in from that matches opts to to,
+// under a single lock, and returns the moved tasks ordered by ID. Before
+// moving anything it checks that to's WIP limit has room for the whole
+// batch, returning ErrBatchOverWIPLimit if not. Each task must pass the
+// checks MoveTask makes; if any fails, no task is moved.
+func (s *TaskStore) BatchMove(from, to string, opts FilterOptions) ([]*Task, error) {
+	span := s.startSpan(context.Background(), "BatchMove", attribute.String("task.status", to))
+	defer span.End()
+
+	if !s.hasColumn(from) {
+		return nil, fmt.Errorf("%w %q", ErrInvalidStatus, from)
+	}
+	if !s.hasColumn(to) {
+		return nil, fmt.Errorf("%w %q", ErrInvalidStatus, to)
+	}
+	if from == to {
+		return nil, fieldError("to_status", "to_status must differ from from_status")
+	}
+
+	s.mu.Lock()
+
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	now := s.clock()
+	var batch []*Task
+	current := 0
+	for _, task := range s.tasks {
+		if task.ArchivedAt != nil {
+			continue
+		}
+		if task.Status == to {
+			current++
+		}
+		if task.Status == from && opts.matches(task, now) {
+			batch = append(batch, task)
+		}
+	}
+	sort.Slice(batch, func(i, j int) bool { return batch[i].ID < batch[j].ID })
+	if len(batch) == 0 {
+		s.mu.Unlock()
+		return []*Task{}, nil
+	}
+
+	if limit := s.wipLimit(to); limit > 0 && current+len(batch) > limit {
+		s.mu.Unlock()
+		return nil, &ErrBatchOverWIPLimit{Status: to, Limit: limit, Current: current, Moving: len(batch)}
+	}
+	if err := s.workflow.checkTransition(from, to); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if err := s.settings.TransitionPolicy().check(from, to); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	for _, task := range batch {
+		if err := checkReview(task, to, ""); err != nil {
+			s.mu.Unlock()
+			return nil, err
+		}
+	}
+
+	// Hooks see the column as each earlier task lands in it, so a rejection
+	// partway through puts the tasks already moved back
+	originals := make([]Task, len(batch))
+	moved := make([]*Task, 0, len(batch))
+	events := make([]Event, 0, len(batch))
+	for i, task := range batch {
+		originals[i] = *task
+		hooked, err := s.runTransitionHooks(task, from, to)
+		if err != nil {
+			for j := 0; j <= i; j++ {
+				*batch[j] = originals[j]
+			}
+			s.mu.Unlock()
+			return nil, err
+		}
+		*task = *hooked
+		s.applyMove(task, to)
+		moved = append(moved, task.clone())
+	}
+	for _, task := range moved {
+		events = append(events, Event{Type: EventTaskMoved, Task: task, FromStatus: from, ToStatus: to})
+	}
+	s.persist(from, to)
+	s.mu.Unlock()
+
+	for _, e := range events {
+		s.publish(e)
+	}
+	return moved, nil
+}
+
+// batchMoveRequest is the body of POST /api/v1/tasks/batch-move
+type batchMoveRequest struct {
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Filter     struct {
+		Label    string `json:"label"`
+		Assignee string `json:"assignee"`
+	} `json:"filter"`
+}
+
+// batchMoveHandler moves every task in one column matching an optional
+// label and assignee filter to another, returning the moved task IDs. A
+// batch that doesn't fit the destination's WIP limit is a 409 with the
+// counts.
+func (srv *Server) batchMoveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req batchMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	var opts FilterOptions
+	if label := strings.TrimSpace(req.Filter.Label); label != "" {
+		opts.Labels = []string{label}
+	}
+	if assignee := strings.TrimSpace(req.Filter.Assignee); assignee != "" {
+		opts.Assignees = []string{assignee}
+	}
+
+	moved, err := srv.BatchMove(req.FromStatus, req.ToStatus, opts)
+	var overLimit *ErrBatchOverWIPLimit
+	if errors.As(err, &overLimit) {
+		writeJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":   "wip limit exceeded",
+			"status":  overLimit.Status,
+			"limit":   overLimit.Limit,
+			"current": overLimit.Current,
+			"count":   overLimit.Moving,
+		})
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ids := []int{}
+	for _, task := range moved {
+		ids = append(ids, task.ID)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"moved_count": len(ids),
+		"moved":       ids,
+	})
+}
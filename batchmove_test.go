@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatchMove(t *testing.T) {
+	s := newTestStore()
+	s.CreateTask(TaskSpec{Title: "A", Status: "doing", Labels: []string{"sprint-3"}})
+	s.CreateTask(TaskSpec{Title: "B", Status: "doing", Labels: []string{"sprint-2"}})
+	s.CreateTask(TaskSpec{Title: "C", Status: "doing", Labels: []string{"sprint-3", "bug"}})
+	s.CreateTask(TaskSpec{Title: "D", Status: "todo", Labels: []string{"sprint-3"}})
+
+	moved, err := s.BatchMove("doing", "todo", FilterOptions{Labels: []string{"sprint-3"}})
+	if err != nil {
+		t.Fatalf("BatchMove failed: %v", err)
+	}
+	if !equalIDs(taskIDs(moved), []int{1, 3}) {
+		t.Errorf("Expected only the sprint-3 tasks moved, got %v", taskIDs(moved))
+	}
+	if ids := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(ids, []int{4, 1, 3}) {
+		t.Errorf("Expected the moved tasks at the bottom of todo, got %v", ids)
+	}
+	if ids := taskIDs(s.GetTasksByStatus("doing")); !equalIDs(ids, []int{2}) {
+		t.Errorf("Expected only B left in doing, got %v", ids)
+	}
+	if task, _ := s.GetTask(1); len(task.StatusHistory) != 1 || task.StatusHistory[0].ToStatus != "todo" {
+		t.Errorf("Expected the move recorded in the task's history, got %+v", task.StatusHistory)
+	}
+
+	if moved, err := s.BatchMove("done", "todo", FilterOptions{}); err != nil || len(moved) != 0 {
+		t.Errorf("Expected an empty column to move nothing, got %v, %v", taskIDs(moved), err)
+	}
+	if _, err := s.BatchMove("doing", "doing", FilterOptions{}); err == nil {
+		t.Errorf("Expected an error moving a column onto itself")
+	}
+	if _, err := s.BatchMove("doing", "nowhere", FilterOptions{}); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestBatchMoveChecksWIPLimitFirst(t *testing.T) {
+	s := newTestStore()
+	s.wipLimits = map[string]int{"doing": 3}
+	s.CreateTask(TaskSpec{Title: "Busy", Status: "doing"})
+	for _, title := range []string{"A", "B", "C"} {
+		s.CreateTask(TaskSpec{Title: title, Labels: []string{"sprint-3"}})
+	}
+
+	_, err := s.BatchMove("todo", "doing", FilterOptions{})
+	var overLimit *ErrBatchOverWIPLimit
+	if !errors.As(err, &overLimit) || !errors.Is(err, ErrWIPLimitExceeded) {
+		t.Fatalf("Expected ErrBatchOverWIPLimit, got %v", err)
+	}
+	if overLimit.Moving != 3 || overLimit.Current != 1 || overLimit.Limit != 3 {
+		t.Errorf("Unexpected counts %+v", overLimit)
+	}
+	if ids := taskIDs(s.GetTasksByStatus("doing")); !equalIDs(ids, []int{1}) {
+		t.Errorf("Expected nothing moved when the batch doesn't fit, got %v", ids)
+	}
+
+	// Two fill the column exactly
+	s.UpdateTask(4, func(task *Task) { task.Labels = nil })
+	moved, err := s.BatchMove("todo", "doing", FilterOptions{Labels: []string{"sprint-3"}})
+	if err != nil || !equalIDs(taskIDs(moved), []int{2, 3}) {
+		t.Errorf("Expected tasks 2 and 3 moved, got %v, %v", taskIDs(moved), err)
+	}
+}
+
+func TestBatchMoveRollsBackOnHookRejection(t *testing.T) {
+	s := newTestStore()
+	s.CreateTask(TaskSpec{Title: "A"})
+	s.CreateTask(TaskSpec{Title: "B"})
+	s.RegisterTransitionHook(func(task *Task, from, to string) error {
+		if task.Title == "B" {
+			return errors.New("B stays")
+		}
+		return nil
+	})
+
+	if _, err := s.BatchMove("todo", "doing", FilterOptions{}); err == nil {
+		t.Fatalf("Expected the hook's rejection")
+	}
+	if ids := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(ids, []int{1, 2}) {
+		t.Errorf("Expected both tasks back in todo, got %v", ids)
+	}
+	if task, _ := s.GetTask(1); task.Status != "todo" || len(task.StatusHistory) != 0 {
+		t.Errorf("Expected task 1 untouched, got %+v", task)
+	}
+}
+
+func TestBatchMoveHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.wipLimits = map[string]int{"todo": 3}
+	s.CreateTask(TaskSpec{Title: "A", Status: "doing", Labels: []string{"sprint-3"}})
+	s.CreateTask(TaskSpec{Title: "B", Status: "doing"})
+	s.CreateTask(TaskSpec{Title: "C", Status: "doing", Labels: []string{"sprint-3"}})
+	s.CreateTask(TaskSpec{Title: "D", Status: "todo"})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/batch-move", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		NewServer(s).batchMoveHandler(w, req)
+		return w
+	}
+
+	w := post(`{"from_status": "doing", "to_status": "todo"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected 409, got %d %q", w.Code, w.Body.String())
+	}
+	var conflict struct {
+		Count   int `json:"count"`
+		Current int `json:"current"`
+		Limit   int `json:"limit"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &conflict)
+	if conflict.Count != 3 || conflict.Current != 1 || conflict.Limit != 3 {
+		t.Errorf("Unexpected conflict body %q", w.Body.String())
+	}
+
+	w = post(`{"from_status": "doing", "to_status": "todo", "filter": {"label": "sprint-3"}}`)
+	var resp struct {
+		MovedCount int   `json:"moved_count"`
+		Moved      []int `json:"moved"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with JSON, got %d %q", w.Code, w.Body.String())
+	}
+	if resp.MovedCount != 2 || !equalIDs(resp.Moved, []int{1, 3}) {
+		t.Errorf("Expected tasks 1 and 3 moved, got %+v", resp)
+	}
+	if ids := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(ids, []int{4, 1, 3}) {
+		t.Errorf("Expected the moved tasks in todo, got %v", ids)
+	}
+
+	for _, body := range []string{`{"from_status": "doing", "to_status": "nowhere"}`, `not json`} {
+		if w := post(body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+}
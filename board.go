@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// columnDef describes one column of the board
+type columnDef struct {
+	Status      string
+	DisplayName string
+}
+
+// boardColumns lists the board's columns in display order
+var boardColumns = []columnDef{
+	{Status: "todo", DisplayName: "To Do"},
+	{Status: "doing", DisplayName: "Doing"},
+	{Status: "done", DisplayName: "Done"},
+}
+
+// isValidStatus reports whether status names one of the board's columns
+func isValidStatus(status string) bool {
+	for _, col := range columns() {
+		if col.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ColumnData holds a column's tasks along with its summary stats
+type ColumnData struct {
+	Status           string
+	DisplayName      string
+	Tasks            []*Task
+	Count            int
+	TotalEffort      int
+	WIPLimit         int // 0 means no limit
+	WIPLimitExceeded bool
+	Color            string       // CSS color of the header, "" for the default
+	LinkCounts       map[int]int  // attachments per task ID
+	Collapsed        map[int]bool // task IDs drawn title-only for this viewer
+	Query            string       // search query the tasks were filtered by, if any
+	Labels           []Label      // registered labels, for coloring task labels
+}
+
+// ColumnBadge fills columnBadge.html, a column header's task count. OOB
+// badges carry hx-swap-oob so htmx updates the header alongside another swap.
+type ColumnBadge struct {
+	Column ColumnData
+	OOB    bool
+}
+
+// Badge returns the column's header badge, rendered in place
+func (c ColumnData) Badge() ColumnBadge {
+	return ColumnBadge{Column: c}
+}
+
+// writeColumnBadgesOOB renders every column's badge for an out-of-band swap,
+// so a response replacing only part of the board still refreshes the counts
+func writeColumnBadgesOOB(w io.Writer, data BoardData) {
+	for _, col := range data.Columns {
+		templates.ExecuteTemplate(w, "columnBadge.html", ColumnBadge{Column: col, OOB: true})
+	}
+}
+
+// BoardData holds everything needed to render the board
+type BoardData struct {
+	Columns   []ColumnData
+	View      string                        // "" for columns, "swimlane" for lanes
+	SwimLanes map[string]map[string][]*Task // only set in swimlane view
+}
+
+// GetBoardData builds every column of the board under a single lock
+func (s *TaskStore) GetBoardData() BoardData {
+	return s.GetBoardDataContext(context.Background())
+}
+
+// GetBoardDataContext is GetBoardData, tracing it as part of ctx
+func (s *TaskStore) GetBoardDataContext(ctx context.Context) BoardData {
+	span := s.startSpan(ctx, "GetBoardData")
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var data BoardData
+	for _, col := range s.columnList() {
+		data.Columns = append(data.Columns, s.columnData(col))
+	}
+	return data
+}
+
+// GetColumnData builds a single column of the board
+func (s *TaskStore) GetColumnData(status string) ColumnData {
+	return s.GetColumnDataContext(context.Background(), status)
+}
+
+// GetColumnDataContext is GetColumnData, tracing it as part of ctx
+func (s *TaskStore) GetColumnDataContext(ctx context.Context, status string) ColumnData {
+	span := s.startSpan(ctx, "GetColumnData", attribute.String("task.status", status))
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, col := range s.columnList() {
+		if col.Status == status {
+			return s.columnData(col)
+		}
+	}
+	return s.columnData(columnDef{Status: status, DisplayName: status})
+}
+
+// columnData computes a column's tasks and stats (must be called with lock held)
+func (s *TaskStore) columnData(col columnDef) ColumnData {
+	data := ColumnData{
+		Status:      col.Status,
+		DisplayName: s.columnName(col),
+		WIPLimit:    s.wipLimit(col.Status),
+		Color:       s.GetColumnColor(col.Status),
+		LinkCounts:  s.linkCounts(),
+		Labels:      s.labels().List(),
+	}
+	for _, task := range s.tasks {
+		if task.Status == col.Status && task.ArchivedAt == nil {
+			data.Tasks = append(data.Tasks, task)
+			data.TotalEffort += task.Effort
+		}
+	}
+	sortByPosition(data.Tasks)
+	data.Count = len(data.Tasks)
+	data.WIPLimitExceeded = data.WIPLimit > 0 && data.Count >= data.WIPLimit
+	return data
+}
+
+// ErrWIPLimitReached is returned when a move would push a column past its
+// WIP limit
+type ErrWIPLimitReached struct {
+	Status string
+	Limit  int
+}
+
+func (e *ErrWIPLimitReached) Error() string {
+	return fmt.Sprintf("column %q is at its WIP limit of %d", e.Status, e.Limit)
+}
+
+// Is makes errors.Is(err, ErrWIPLimitExceeded) match any column's limit
+func (e *ErrWIPLimitReached) Is(target error) bool {
+	return target == ErrWIPLimitExceeded
+}
+
+// checkWIPLimit returns ErrWIPLimitReached if moving a task from one status
+// into another would exceed the target's limit. Pass an empty from for a new
+// task. Must be called with lock held.
+func (s *TaskStore) checkWIPLimit(from, to string) error {
+	limit := s.wipLimit(to)
+	if from == to || limit == 0 {
+		return nil
+	}
+	count := 0
+	for _, task := range s.tasks {
+		if task.Status == to && task.ArchivedAt == nil {
+			count++
+		}
+	}
+	if count >= limit {
+		return &ErrWIPLimitReached{Status: to, Limit: limit}
+	}
+	return nil
+}
+
+// LoadWIPLimits reads per-column WIP limits from the KANBAN_WIP_LIMITS env
+// var, e.g. {"doing":3}. Columns without an entry have no limit.
+func LoadWIPLimits() (map[string]int, error) {
+	limits := make(map[string]int)
+	raw := os.Getenv("KANBAN_WIP_LIMITS")
+	if raw == "" {
+		return limits, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &limits); err != nil {
+		return nil, fmt.Errorf("invalid KANBAN_WIP_LIMITS: %w", err)
+	}
+	for status, limit := range limits {
+		if limit < 0 {
+			return nil, fmt.Errorf("invalid WIP limit %d for %q", limit, status)
+		}
+	}
+	return limits, nil
+}
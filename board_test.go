@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestGetBoardData(t *testing.T) {
+	store := newTestStore()
+	store.wipLimits = map[string]int{"doing": 2}
+	store.CreateTask(TaskSpec{Title: "A", Effort: 3})
+	store.CreateTask(TaskSpec{Title: "B", Effort: 5})
+	store.CreateTask(TaskSpec{Title: "C", Effort: 1, Status: "doing"})
+	store.CreateTask(TaskSpec{Title: "D", Status: "done"})
+
+	data := store.GetBoardData()
+	if len(data.Columns) != 3 {
+		t.Fatalf("Expected 3 columns, got %d", len(data.Columns))
+	}
+	todo, doing, done := data.Columns[0], data.Columns[1], data.Columns[2]
+	if todo.Status != "todo" || todo.DisplayName != "To Do" {
+		t.Errorf("Unexpected first column %s/%s", todo.Status, todo.DisplayName)
+	}
+	if todo.Count != 2 || todo.TotalEffort != 8 {
+		t.Errorf("Expected todo count 2 effort 8, got %d/%d", todo.Count, todo.TotalEffort)
+	}
+	if doing.Count != 1 || doing.TotalEffort != 1 || doing.WIPLimit != 2 || doing.WIPLimitExceeded {
+		t.Errorf("Unexpected doing column: %+v", doing)
+	}
+	if done.Count != 1 || done.TotalEffort != 0 {
+		t.Errorf("Expected done count 1 effort 0, got %d/%d", done.Count, done.TotalEffort)
+	}
+}
+
+func TestWIPLimitExceeded(t *testing.T) {
+	store := newTestStore()
+	store.wipLimits = map[string]int{"doing": 2}
+	store.CreateTask(TaskSpec{Title: "A", Status: "doing"})
+	store.CreateTask(TaskSpec{Title: "B", Status: "doing"})
+
+	doing := store.GetColumnData("doing")
+	if !doing.WIPLimitExceeded {
+		t.Errorf("Expected WIP limit to be flagged when count meets the limit")
+	}
+	if store.GetColumnData("todo").WIPLimitExceeded {
+		t.Errorf("Columns without a limit should never be flagged")
+	}
+}
+
+func TestLoadWIPLimits(t *testing.T) {
+	t.Setenv("KANBAN_WIP_LIMITS", `{"doing":3}`)
+	limits, err := LoadWIPLimits()
+	if err != nil || limits["doing"] != 3 {
+		t.Errorf("Expected doing limit 3, got %v (%v)", limits, err)
+	}
+	t.Setenv("KANBAN_WIP_LIMITS", `{"doing":-1}`)
+	if _, err := LoadWIPLimits(); err == nil {
+		t.Errorf("Expected error for negative limit")
+	}
+}
+
+func TestIndexRendersColumnBadges(t *testing.T) {
+	origStore := store
+	defer func() { store = origStore }()
+	store = newTestStore()
+	store.CreateTask(TaskSpec{Title: "Badge", Effort: 4})
+
+	rec := httptest.NewRecorder()
+	NewServer(store).indexHandler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(rec.Body.String(), "1 tasks · 4 pts") {
+		t.Errorf("Expected todo badge with count and effort")
+	}
+}
+
+func TestMoveTaskSendsOOBBadges(t *testing.T) {
+	s := withTestGlobals(t)
+	s.CreateTask(TaskSpec{Title: "A", Effort: 2})
+	s.CreateTask(TaskSpec{Title: "B", Effort: 3})
+
+	req := httptest.NewRequest(http.MethodPost, "/move-task", strings.NewReader("id=2&status=done"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	NewServer(s).moveTaskHandler(rr, req)
+	doc, err := html.Parse(rr.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"badge-todo": "1 tasks · 2 pts", "badge-doing": "0 tasks · 0 pts", "badge-done": "1 tasks · 3 pts"}
+	oob := 0
+	for _, badge := range findElements(doc, "column-badge") {
+		if attr(badge, "hx-swap-oob") != "true" {
+			continue
+		}
+		oob++
+		id := attr(badge, "id")
+		if got := strings.TrimSpace(badge.FirstChild.Data); got != want[id] {
+			t.Errorf("Expected %s to read %q, got %q", id, want[id], got)
+		}
+	}
+	if oob != 3 {
+		t.Errorf("Expected 3 out-of-band badges, got %d", oob)
+	}
+}
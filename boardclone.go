@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var errBoardNotFound = errors.New("board not found")
+
+// clonedBoards holds the task stores of boards cloned at runtime, by board
+// ID. Like invitations and memberships they live only as long as the server.
+var clonedBoards = struct {
+	mu     sync.Mutex
+	stores map[string]Store
+}{stores: make(map[string]Store)}
+
+// CloneBoard creates a board named name with src's column configuration:
+// its columns, WIP limits and workflow, along with its labels, sprints and
+// other settings. With includeTasks, every task not archived is copied into
+// the new board's "todo" column the way CopyTask copies one.
+func (srv *Server) CloneBoard(src *Board, name string, includeTasks bool) (*Board, error) {
+	srcStore, ok := srv.boardStore(src.ID)
+	if !ok {
+		return nil, errBoardNotFound
+	}
+	token, err := newInviteToken()
+	if err != nil {
+		return nil, err
+	}
+	id := token[:12]
+	dst := srcStore.CloneStore(includeTasks)
+
+	board := &Board{ID: id, Name: name, Members: []string{}, Columns: []columnSetting{}}
+	for _, col := range dst.Columns() {
+		board.Columns = append(board.Columns, columnSetting{Status: col.Status, DisplayName: col.DisplayName})
+	}
+	invitations.mu.Lock()
+	invitations.boards[id] = board
+	invitations.mu.Unlock()
+
+	clonedBoards.mu.Lock()
+	clonedBoards.stores[id] = dst
+	clonedBoards.mu.Unlock()
+
+	clone := *board
+	return &clone, nil
+}
+
+// CloneStore returns a new in-memory store with this one's columns, WIP
+// limits, workflow and a copy of its settings, and with includeTasks a copy
+// of each unarchived task in "todo". The clone shares nothing with this
+// store, so later changes to either leave the other as it was. This store is
+// locked for the whole copy, so the clone never sees it half-changed.
+func (s *TaskStore) CloneStore(includeTasks bool) Store {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dst := &TaskStore{
+		tasks:      make(map[int]*Task),
+		nextID:     1,
+		workflow:   s.workflow,
+		wipLimits:  make(map[string]int, len(s.wipLimits)),
+		now:        s.now,
+		settings:   s.settings.Copy(),
+		ownColumns: s.columnList(),
+		encryptKey: s.encryptKey,
+	}
+	for status, limit := range s.wipLimits {
+		dst.wipLimits[status] = limit
+	}
+	if includeTasks {
+		for _, original := range sortedTasks(s.tasks) {
+			if original.ArchivedAt != nil {
+				continue
+			}
+			dst.newTask(copySpec(original))
+		}
+	}
+	dst.persist()
+	return dst
+}
+
+// sortedTasks returns tasks ordered by ID (must be called with the owning
+// store's lock held)
+func sortedTasks(tasks map[int]*Task) []*Task {
+	sorted := make([]*Task, 0, len(tasks))
+	for _, task := range tasks {
+		sorted = append(sorted, task)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+// boardCloneRequest is the body of POST /api/v1/boards/{id}/clone
+type boardCloneRequest struct {
+	NewName      string `json:"new_name"`
+	IncludeTasks bool   `json:"include_tasks"`
+}
+
+// boardsAPIHandler serves POST /api/v1/boards/{id}/clone, returning the new
+// board as JSON
+func (srv *Server) boardsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/boards/"), "/"), "/")
+	if len(parts) != 2 || parts[1] != "clone" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req boardCloneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	req.NewName = strings.TrimSpace(req.NewName)
+	if req.NewName == "" {
+		http.Error(w, "new_name is required", http.StatusBadRequest)
+		return
+	}
+	src, ok := invitations.Board(parts[0])
+	if !ok {
+		http.Error(w, "Board not found", http.StatusNotFound)
+		return
+	}
+
+	board, err := srv.CloneBoard(&src, req.NewName, req.IncludeTasks)
+	if errors.Is(err, errBoardNotFound) {
+		http.Error(w, "Board not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, board)
+}
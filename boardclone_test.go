@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withTestBoards gives a test a fresh default board saved under a temp
+// directory, and forgets the boards it clones
+func withTestBoards(t *testing.T) *TaskStore {
+	s := withTestGlobals(t)
+	s.filePath = filepath.Join(t.TempDir(), "tasks.json")
+	withTestInvitations(t)
+	t.Cleanup(func() {
+		clonedBoards.mu.Lock()
+		clonedBoards.stores = make(map[string]Store)
+		clonedBoards.mu.Unlock()
+	})
+	return s
+}
+
+func TestCloneBoard(t *testing.T) {
+	s := withTestBoards(t)
+	s.wipLimits = map[string]int{"doing": 3}
+	s.CreateTask(TaskSpec{Title: "Plan", Priority: PriorityHigh, Labels: []string{"sprint"}})
+	doing, _ := s.CreateTask(TaskSpec{Title: "Build", Status: "doing", Assignee: "ana"})
+	archived, _ := s.CreateTask(TaskSpec{Title: "Old", Status: "done"})
+	s.UpdateTask(archived.ID, func(task *Task) { now := time.Now(); task.ArchivedAt = &now })
+
+	srv := NewServer(s)
+	src, _ := invitations.Board(defaultBoardID)
+	board, err := srv.CloneBoard(&src, "Sprint 2", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.ID == "" || board.ID == defaultBoardID || board.Name != "Sprint 2" {
+		t.Errorf("Expected a distinct, named board, got %+v", board)
+	}
+	if formatColumns(columns()) != mustJSON(t, board.Columns) {
+		t.Errorf("Expected the source's columns, got %+v", board.Columns)
+	}
+	if got, ok := invitations.Board(board.ID); !ok || got.Name != "Sprint 2" {
+		t.Errorf("Expected the clone to accept invitations, got %+v", got)
+	}
+
+	dst, ok := srv.boardStore(board.ID)
+	if !ok {
+		t.Fatalf("Expected a store for board %s", board.ID)
+	}
+	for _, col := range dst.Columns() {
+		if col.Status == "doing" && col.WIPLimit != 3 {
+			t.Errorf("Expected the WIP limits copied, got %d", col.WIPLimit)
+		}
+	}
+	tasks := dst.GetAllTasks()
+	if len(tasks) != 2 || tasks[0].Title != "Plan" || tasks[1].Title != "Build" {
+		t.Fatalf("Expected the two unarchived tasks copied, got %+v", tasks)
+	}
+	for _, task := range tasks {
+		if task.Status != "todo" || task.Assignee != "" {
+			t.Errorf("Expected copies unassigned in todo, got %+v", task)
+		}
+	}
+	if tasks[0].Priority != PriorityHigh || tasks[0].Labels[0] != "sprint" {
+		t.Errorf("Expected priority and labels copied, got %+v", tasks[0])
+	}
+	if original, _ := s.GetTask(doing.ID); original.Status != "doing" || len(s.GetAllTasks()) != 3 {
+		t.Errorf("Expected the source board untouched, got %+v", original)
+	}
+
+	// Clones are boards in their own right: cloning one again works
+	empty, err := srv.CloneBoard(board, "Sprint 3", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.ID == board.ID {
+		t.Errorf("Expected each clone to get its own ID")
+	}
+	if dst, _ := srv.boardStore(empty.ID); len(dst.GetAllTasks()) != 0 {
+		t.Errorf("Expected no tasks without include_tasks, got %d", len(dst.GetAllTasks()))
+	}
+
+	if _, err := srv.CloneBoard(&Board{ID: "missing"}, "Nope", false); err != errBoardNotFound {
+		t.Errorf("Expected errBoardNotFound, got %v", err)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestBoardCloneHandler(t *testing.T) {
+	s := withTestBoards(t)
+	s.now = func() time.Time { return time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC) }
+	s.AddTask("Plan", "")
+	s.AddTask("Build", "")
+	srv := NewServer(s)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.boardsAPIHandler(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+
+	for _, tc := range []struct {
+		body  string
+		tasks int
+	}{
+		{`{"new_name": "Sprint 2", "include_tasks": true}`, 2},
+		{`{"new_name": "Sprint 2 (empty)", "include_tasks": false}`, 0},
+	} {
+		w := post("/api/v1/boards/default/clone", tc.body)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("%s: expected 201, got %d %q", tc.body, w.Code, w.Body.String())
+		}
+		var board Board
+		if err := json.Unmarshal(w.Body.Bytes(), &board); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		dst, ok := srv.boardStore(board.ID)
+		if !ok || board.ID == defaultBoardID {
+			t.Fatalf("%s: expected a new board, got %+v", tc.body, board)
+		}
+		if got := len(dst.GetAllTasks()); got != tc.tasks {
+			t.Errorf("%s: expected %d tasks, got %d", tc.body, tc.tasks, got)
+		}
+		if len(board.Columns) != len(columns()) {
+			t.Errorf("%s: expected %d columns, got %+v", tc.body, len(columns()), board.Columns)
+		}
+	}
+
+	for _, tc := range []struct {
+		path, body string
+		code       int
+	}{
+		{"/api/v1/boards/default/clone", `{"new_name": "  "}`, http.StatusBadRequest},
+		{"/api/v1/boards/default/clone", `not json`, http.StatusBadRequest},
+		{"/api/v1/boards/missing/clone", `{"new_name": "Sprint 2"}`, http.StatusNotFound},
+		{"/api/v1/boards/default/copy", `{"new_name": "Sprint 2"}`, http.StatusNotFound},
+	} {
+		if w := post(tc.path, tc.body); w.Code != tc.code {
+			t.Errorf("%s %s: expected %d, got %d", tc.path, tc.body, tc.code, w.Code)
+		}
+	}
+}
+
+func TestCloneBoardSharesNothing(t *testing.T) {
+	s := withTestBoards(t)
+	withTestColumns(t)
+	s.settings = withTestSettings(t)
+	s.CreateLabel("bug", "#ef4444")
+	s.CreateSprint("Sprint 1")
+	srv := NewServer(s)
+
+	src, _ := invitations.Board(defaultBoardID)
+	board, err := srv.CloneBoard(&src, "Copy", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst, _ := srv.boardStore(board.ID)
+	if len(dst.ListLabels()) != 1 || len(dst.ListSprints()) != 1 {
+		t.Fatalf("Expected the labels and sprints cloned, got %v %v", dst.ListLabels(), dst.ListSprints())
+	}
+
+	s.CreateLabel("idea", "#fef08a")
+	s.CreateSprint("Sprint 2")
+	if err := s.AddColumn("qa", "QA", 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(dst.ListLabels()) != 1 || len(dst.ListSprints()) != 1 || len(dst.Columns()) != 3 {
+		t.Errorf("Expected the clone unchanged by the source, got %v %v %v", dst.ListLabels(), dst.ListSprints(), dst.Columns())
+	}
+	if err := dst.AddColumn("blocked", "Blocked", 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.CreateLabel("clone-only", "#000000"); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.ListLabels()) != 2 || len(s.Columns()) != 4 {
+		t.Errorf("Expected the source unchanged by the clone, got %v %v", s.ListLabels(), s.Columns())
+	}
+
+	if files, _ := filepath.Glob(filepath.Join(filepath.Dir(s.filePath), "board-*.json")); len(files) != 0 {
+		t.Errorf("Expected cloned boards kept in memory, found %v", files)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// boardStore returns the store holding a board's tasks: the server's own
+// board, defaultBoardID, or one cloned from it
+func (srv *Server) boardStore(boardID string) (Store, bool) {
+	if boardID == defaultBoardID {
+		return srv.Store, true
+	}
+	clonedBoards.mu.Lock()
+	defer clonedBoards.mu.Unlock()
+	s, ok := clonedBoards.stores[boardID]
+	return s, ok
+}
+
+// CopySpec returns what CopyTask copies of a task: its title, description,
+// labels and priority, for a new task in "todo". Links and comments belong
+// to the original and aren't copied. It returns false if there is no such
+// task.
+func (s *TaskStore) CopySpec(id int) (TaskSpec, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	original, ok := s.tasks[id]
+	if !ok {
+		return TaskSpec{}, false
+	}
+	return copySpec(original), true
+}
+
+// copySpec is CopySpec for a task already in hand (must be called with the
+// owning store's lock held)
+func copySpec(original *Task) TaskSpec {
+	source := original.clone()
+	return TaskSpec{
+		Title:       source.Title,
+		Description: source.Description,
+		Status:      "todo",
+		Priority:    source.Priority,
+		Labels:      source.Labels,
+	}
+}
+
+// CreateCopy creates a task from a CopySpec, returning ErrWIPLimitReached
+// if its column is full
+func (s *TaskStore) CreateCopy(spec TaskSpec) (*Task, error) {
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if err := s.checkWIPLimit("", spec.Status); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	task := s.newTask(spec)
+	s.persist(task.Status)
+	created := task.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskCreated, Task: created, ToStatus: created.Status})
+	return created, nil
+}
+
+// CopyTask copies a task from src into a new task in dst's "todo" column.
+// The copy is taken from the task as it was when read, so src needn't stay
+// locked while dst is written. src and dst may be the same store, which
+// clones the task. It returns ErrTaskNotFound if src has no such task.
+func CopyTask(src, dst Store, taskID int) (*Task, error) {
+	spec, ok := src.CopySpec(taskID)
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	return dst.CreateCopy(spec)
+}
+
+// boardsRouter dispatches /boards/{id}/... requests
+func (srv *Server) boardsRouter(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/boards/"), "/"), "/")
+	if len(parts) == 5 && parts[1] == "tasks" && parts[3] == "copy-to" {
+		srv.boardCopyHandler(w, r, parts[0], parts[2], parts[4])
+		return
+	}
+	boardInviteHandler(w, r)
+}
+
+// boardCopyHandler serves POST /boards/{src}/tasks/{id}/copy-to/{dst},
+// returning the copy as JSON
+func (srv *Server) boardCopyHandler(w http.ResponseWriter, r *http.Request, srcID, rawTaskID, dstID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	taskID, err := strconv.Atoi(rawTaskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+	src, ok := srv.boardStore(srcID)
+	if !ok {
+		http.Error(w, "Board not found", http.StatusNotFound)
+		return
+	}
+	dst, ok := srv.boardStore(dstID)
+	if !ok {
+		http.Error(w, "Board not found", http.StatusNotFound)
+		return
+	}
+
+	task, err := CopyTask(src, dst, taskID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, task)
+}
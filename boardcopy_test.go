@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func newCopyTestStore(t *testing.T) *TaskStore {
+	return &TaskStore{tasks: make(map[int]*Task), nextID: 1, filePath: filepath.Join(t.TempDir(), "tasks.json")}
+}
+
+func TestCopyTaskToAnotherBoard(t *testing.T) {
+	src, dst := newCopyTestStore(t), newCopyTestStore(t)
+	dst.AddTask("Existing", "")
+	original, _ := src.CreateTask(TaskSpec{Title: "Ship it", Description: "v2", Status: "doing", Priority: 3, Assignee: "ana", Labels: []string{"release"}})
+
+	copied, err := CopyTask(src, dst, original.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.ID != 2 || copied.Status != "todo" || copied.Title != "Ship it" || copied.Description != "v2" || copied.Priority != 3 {
+		t.Errorf("Unexpected copy %+v", copied)
+	}
+	if copied.Assignee != "" {
+		t.Errorf("Expected assignee not to be copied, got %q", copied.Assignee)
+	}
+	got, ok := dst.GetTask(copied.ID)
+	if !ok || got.Title != "Ship it" {
+		t.Fatalf("Expected the copy in the destination board, got %+v", got)
+	}
+
+	dst.UpdateTask(copied.ID, func(task *Task) {
+		task.Title = "Ship it later"
+		task.Labels[0] = "changed"
+	})
+	after, _ := src.GetTask(original.ID)
+	if after.Title != "Ship it" || after.Description != "v2" || after.Labels[0] != "release" || after.Status != "doing" {
+		t.Errorf("Modifying the copy changed the original: %+v", after)
+	}
+}
+
+func TestCopyTaskSameBoard(t *testing.T) {
+	s := newCopyTestStore(t)
+	original := s.AddTask("Clone me", "")
+
+	copied, err := CopyTask(s, s, original.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.ID == original.ID || copied.Title != "Clone me" {
+		t.Errorf("Expected a clone with a new ID, got %+v", copied)
+	}
+	if n := len(s.GetAllTasks()); n != 2 {
+		t.Errorf("Expected 2 tasks, got %d", n)
+	}
+}
+
+func TestCopyTaskErrors(t *testing.T) {
+	src, dst := newCopyTestStore(t), newCopyTestStore(t)
+	if _, err := CopyTask(src, dst, 99); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", err)
+	}
+
+	task := src.AddTask("Blocked", "")
+	dst.wipLimits = map[string]int{"todo": 1}
+	dst.AddTask("Full", "")
+	var wipErr *ErrWIPLimitReached
+	if _, err := CopyTask(src, dst, task.ID); !errors.As(err, &wipErr) {
+		t.Errorf("Expected ErrWIPLimitReached, got %v", err)
+	}
+}
+
+func TestBoardCopyHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	original := s.AddTask("Clone via API", "")
+	srv := NewServer(s)
+
+	w := httptest.NewRecorder()
+	srv.boardsRouter(w, httptest.NewRequest(http.MethodPost, "/boards/default/tasks/1/copy-to/default", nil))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var copied Task
+	if err := json.Unmarshal(w.Body.Bytes(), &copied); err != nil {
+		t.Fatal(err)
+	}
+	if copied.ID == original.ID || copied.Title != "Clone via API" {
+		t.Errorf("Unexpected copy %+v", copied)
+	}
+
+	for path, want := range map[string]int{
+		"/boards/other/tasks/1/copy-to/default":     http.StatusNotFound,
+		"/boards/default/tasks/1/copy-to/other":     http.StatusNotFound,
+		"/boards/default/tasks/99/copy-to/default":  http.StatusNotFound,
+		"/boards/default/tasks/abc/copy-to/default": http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		srv.boardsRouter(w, httptest.NewRequest(http.MethodPost, path, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, w.Code)
+		}
+	}
+}
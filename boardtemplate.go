@@ -0,0 +1,134 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed board-templates/*.json
+var boardTemplateFS embed.FS
+
+// ColumnSettings configures one column of a board template
+type ColumnSettings struct {
+	DisplayName string `json:"display_name"`
+	WIPLimit    int    `json:"wip_limit"`
+}
+
+// ColumnConfig maps statuses to their column settings
+type ColumnConfig map[string]ColumnSettings
+
+// Settings converts the config to SettingsStore keys
+func (c ColumnConfig) Settings() map[string]string {
+	values := make(map[string]string)
+	for status, col := range c {
+		if col.DisplayName != "" {
+			values["column_name."+status] = col.DisplayName
+		}
+		if col.WIPLimit > 0 {
+			values["wip_limit."+status] = strconv.Itoa(col.WIPLimit)
+		}
+	}
+	return values
+}
+
+// boardTemplate is the JSON layout of a file in board-templates/
+type boardTemplate struct {
+	Description string       `json:"description"`
+	Columns     ColumnConfig `json:"columns"`
+	Tasks       []TaskInput  `json:"tasks"`
+}
+
+// BoardTemplateNames lists the built-in templates in sorted order
+func BoardTemplateNames() []string {
+	files, _ := fs.Glob(boardTemplateFS, "board-templates/*.json")
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, strings.TrimSuffix(path.Base(file), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadBoardTemplate parses and validates a built-in board template
+func LoadBoardTemplate(name string) ([]TaskSpec, ColumnConfig, error) {
+	if !containsString(BoardTemplateNames(), name) {
+		return nil, nil, fmt.Errorf("unknown template %q, valid options: %s",
+			name, strings.Join(BoardTemplateNames(), ", "))
+	}
+	data, err := boardTemplateFS.ReadFile("board-templates/" + name + ".json")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tmpl boardTemplate
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return nil, nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	if err := validateSettings(tmpl.Columns.Settings()); err != nil {
+		return nil, nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	specs := make([]TaskSpec, 0, len(tmpl.Tasks))
+	for i, in := range tmpl.Tasks {
+		spec, err := in.ToSpec()
+		if err != nil {
+			return nil, nil, fmt.Errorf("template %s task %d: %w", name, i, err)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, tmpl.Columns, nil
+}
+
+// boardTemplateHandler seeds the board from a built-in template and
+// redirects to it. The template name comes from a JSON body
+// ({"template":"software-kanban"}) or a form field.
+func (srv *Server) boardTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.FormValue("template")
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var body struct {
+			Template string `json:"template"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		name = body.Template
+	}
+
+	specs, columns, err := LoadBoardTemplate(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := srv.BoardLock(); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := settings.Merge(columns.Settings()); err != nil {
+		log.Printf("Error saving settings: %v", err)
+		http.Error(w, "Could not save column settings", http.StatusInternalServerError)
+		return
+	}
+	if _, err := srv.CreateTasks(specs); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/")
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoadBoardTemplates(t *testing.T) {
+	want := map[string]map[string]int{
+		"content-calendar": {"todo": 3, "doing": 1, "done": 1},
+		"personal-gtd":     {"todo": 4, "doing": 1, "done": 1},
+		"software-kanban":  {"todo": 4, "doing": 2, "done": 2},
+	}
+	names := BoardTemplateNames()
+	if len(names) != len(want) {
+		t.Fatalf("Expected %d templates, got %v", len(want), names)
+	}
+
+	for _, name := range names {
+		specs, columns, err := LoadBoardTemplate(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		counts := make(map[string]int)
+		for _, spec := range specs {
+			counts[spec.Status]++
+		}
+		for status, n := range want[name] {
+			if counts[status] != n {
+				t.Errorf("%s: expected %d %s tasks, got %d", name, n, status, counts[status])
+			}
+		}
+		if len(columns) != len(boardColumns) || columns["doing"].WIPLimit == 0 {
+			t.Errorf("%s: expected all columns configured with a doing WIP limit, got %+v", name, columns)
+		}
+	}
+}
+
+func TestLoadBoardTemplateUnknown(t *testing.T) {
+	if _, _, err := LoadBoardTemplate("../settings"); err == nil {
+		t.Errorf("Expected unknown template to be rejected")
+	}
+}
+
+func TestBoardTemplateHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	ss := withTestSettings(t)
+	s.settings = ss
+
+	req := httptest.NewRequest(http.MethodPost, "/board/template", strings.NewReader(`{"template":"software-kanban"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	NewServer(s).boardTemplateHandler(w, req)
+
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/" {
+		t.Errorf("Expected redirect to /, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+	if n := len(s.GetAllTasks()); n != 8 {
+		t.Errorf("Expected 8 sample tasks, got %d", n)
+	}
+	doing := s.GetColumnData("doing")
+	if doing.DisplayName != "In Development" || doing.WIPLimit != 3 {
+		t.Errorf("Expected template column settings, got %q limit %d", doing.DisplayName, doing.WIPLimit)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/board/template", strings.NewReader(`{"template":"nope"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	NewServer(s).boardTemplateHandler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown template, got %d", w.Code)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultMaxBulkSize is the largest batch accepted by /api/v1/tasks/bulk
+const defaultMaxBulkSize = 100
+
+// maxTitleLength is the longest title accepted by /add-task and
+// /api/v1/tasks/bulk
+const maxTitleLength = 200
+
+// TaskInput is the JSON representation of a task to be created
+type TaskInput struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Assignee    string   `json:"assignee"`
+	Effort      int      `json:"effort"`
+	Priority    int      `json:"priority"`
+	DueDate     string   `json:"due_date"` // YYYY-MM-DD or RFC 3339
+	Labels      []string `json:"labels"`
+}
+
+// BulkError reports why one entry of a bulk request was rejected
+type BulkError struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// ToSpec validates the input and converts it to a TaskSpec
+func (in TaskInput) ToSpec() (TaskSpec, error) {
+	spec := TaskSpec{
+		Title:       strings.TrimSpace(in.Title),
+		Description: in.Description,
+		Status:      in.Status,
+		Assignee:    in.Assignee,
+		Effort:      in.Effort,
+		Priority:    in.Priority,
+		Labels:      in.Labels,
+	}
+	if spec.Title == "" {
+		return spec, fmt.Errorf("title is required")
+	}
+	if len(spec.Title) > maxTitleLength {
+		return spec, fmt.Errorf("title is longer than %d characters", maxTitleLength)
+	}
+	if spec.Status != "" && !isValidStatus(spec.Status) {
+		return spec, fmt.Errorf("invalid status %q", spec.Status)
+	}
+	if spec.Effort < 0 {
+		return spec, fmt.Errorf("effort must not be negative")
+	}
+	if spec.Priority < PriorityNone || spec.Priority > PriorityHigh {
+		return spec, fmt.Errorf("invalid priority %d", spec.Priority)
+	}
+	if in.DueDate != "" {
+		due, err := parseDueDate(in.DueDate)
+		if err != nil {
+			return spec, err
+		}
+		spec.DueDate = &due
+	}
+	return spec, nil
+}
+
+// parseDueDate accepts a plain date or a full RFC 3339 timestamp
+func parseDueDate(raw string) (time.Time, error) {
+	if due, err := time.Parse("2006-01-02", raw); err == nil {
+		return due, nil
+	}
+	due, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid due date %q", raw)
+	}
+	return due, nil
+}
+
+// validateBulkInputs converts every valid entry to a spec and reports the
+// rest. It does not touch the store, so it runs without holding the lock.
+func validateBulkInputs(inputs []TaskInput) ([]TaskSpec, []BulkError) {
+	var specs []TaskSpec
+	errs := []BulkError{}
+	for i, in := range inputs {
+		spec, err := in.ToSpec()
+		if err != nil {
+			errs = append(errs, BulkError{Index: i, Message: err.Error()})
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	return specs, errs
+}
+
+// CreateTasks adds several tasks under a single lock acquisition and save,
+// or returns ErrBoardLocked
+func (s *TaskStore) CreateTasks(specs []TaskSpec) ([]*Task, error) {
+	span := s.startSpan(context.Background(), "CreateTasks")
+	defer span.End()
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	var created []*Task
+	touched := make(map[string]bool)
+	var statuses []string
+	for _, spec := range specs {
+		task := s.newTask(spec)
+		created = append(created, task)
+		if !touched[task.Status] {
+			touched[task.Status] = true
+			statuses = append(statuses, task.Status)
+		}
+	}
+	if len(created) > 0 {
+		s.persist(statuses...)
+	}
+	var events []Event
+	for _, task := range created {
+		events = append(events, Event{Type: EventTaskCreated, Task: task.clone(), ToStatus: task.Status})
+	}
+	s.mu.Unlock()
+
+	for _, e := range events {
+		s.publish(e)
+	}
+	return created, nil
+}
+
+// BulkResult reports which tasks a bulk edit changed and which IDs didn't
+// match a task
+type BulkResult struct {
+	Updated  []int `json:"updated"`
+	NotFound []int `json:"not_found"`
+}
+
+// BulkLabel adds and removes labels on several tasks under a single lock
+// acquisition and save. A label in both lists ends up removed, and tasks that
+// already had the requested labels are left untouched. It returns
+// ErrBoardLocked while the board is locked.
+func (s *TaskStore) BulkLabel(taskIDs []int, addLabels, removeLabels []string) (BulkResult, error) {
+	span := s.startSpan(context.Background(), "BulkLabel")
+	defer span.End()
+
+	remove := make(map[string]bool)
+	for _, label := range removeLabels {
+		remove[strings.TrimSpace(label)] = true
+	}
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return BulkResult{}, err
+	}
+	result := BulkResult{Updated: []int{}, NotFound: []int{}}
+	touched := make(map[string]bool)
+	var statuses []string
+	var events []Event
+	for _, id := range taskIDs {
+		task, ok := s.tasks[id]
+		if !ok {
+			result.NotFound = append(result.NotFound, id)
+			continue
+		}
+		before := task.clone()
+		labels := []string{}
+		for _, label := range append(append([]string(nil), task.Labels...), addLabels...) {
+			label = strings.TrimSpace(label)
+			if label == "" || remove[label] || containsString(labels, label) {
+				continue
+			}
+			labels = append(labels, label)
+		}
+		task.Labels = labels
+		changes := taskFieldChanges(before, task)
+		if len(changes) == 0 {
+			task.Labels = before.Labels
+			continue
+		}
+		task.UpdatedAt = s.clock()
+		result.Updated = append(result.Updated, id)
+		if !touched[task.Status] {
+			touched[task.Status] = true
+			statuses = append(statuses, task.Status)
+		}
+		events = append(events, Event{Type: EventTaskUpdated, Task: task.clone(), Changes: changes})
+	}
+	if len(statuses) > 0 {
+		s.persist(statuses...)
+	}
+	s.mu.Unlock()
+
+	for _, e := range events {
+		s.publish(e)
+	}
+	return result, nil
+}
+
+// bulkLabelRequest is the body of /api/v1/tasks/bulk-label
+type bulkLabelRequest struct {
+	TaskIDs      []int    `json:"task_ids"`
+	AddLabels    []string `json:"add_labels"`
+	RemoveLabels []string `json:"remove_labels"`
+}
+
+// loadMaxBulkSize reads KANBAN_MAX_BULK_SIZE, falling back to the default
+func loadMaxBulkSize() int {
+	if raw := os.Getenv("KANBAN_MAX_BULK_SIZE"); raw != "" {
+		if size, err := strconv.Atoi(raw); err == nil && size > 0 {
+			return size
+		}
+	}
+	return defaultMaxBulkSize
+}
+
+var maxBulkSize = loadMaxBulkSize()
+
+// bulkCreateHandler creates every valid task in a JSON array
+func (srv *Server) bulkCreateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var inputs []TaskInput
+	if err := json.NewDecoder(r.Body).Decode(&inputs); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if len(inputs) > maxBulkSize {
+		http.Error(w, fmt.Sprintf("Batch of %d exceeds the maximum of %d tasks", len(inputs), maxBulkSize),
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	specs, errs := validateBulkInputs(inputs)
+	created, err := srv.CreateTasks(specs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ids := []int{}
+	for _, task := range created {
+		ids = append(ids, task.ID)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"created": ids,
+		"errors":  errs,
+	})
+}
+
+// bulkLabelHandler applies label changes to every task in a JSON request
+func (srv *Server) bulkLabelHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req bulkLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if len(req.TaskIDs) > maxBulkSize {
+		http.Error(w, fmt.Sprintf("Batch of %d exceeds the maximum of %d tasks", len(req.TaskIDs), maxBulkSize),
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	result, err := srv.BulkLabel(req.TaskIDs, req.AddLabels, req.RemoveLabels)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func postBulk(body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/bulk", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	NewServer(store).bulkCreateHandler(w, req)
+	return w
+}
+
+func TestBulkCreateMixedEntries(t *testing.T) {
+	s := withTestGlobals(t)
+
+	w := postBulk(`[
+		{"title": "First", "priority": 2},
+		{"title": "   "},
+		{"title": "Second", "status": "doing", "due_date": "2024-03-01"},
+		{"title": "Third", "status": "archived"},
+		{"title": "Fourth", "labels": ["bug"]}
+	]`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Created []int       `json:"created"`
+		Errors  []BulkError `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if !equalIDs(resp.Created, []int{1, 2, 3}) {
+		t.Errorf("Expected created [1 2 3], got %v", resp.Created)
+	}
+	if len(resp.Errors) != 2 || resp.Errors[0].Index != 1 || resp.Errors[1].Index != 3 {
+		t.Errorf("Expected errors at indexes 1 and 3, got %+v", resp.Errors)
+	}
+
+	if task, _ := s.GetTask(2); task.Status != "doing" || task.DueDate == nil {
+		t.Errorf("Expected second task in doing with a due date, got %+v", task)
+	}
+	if got := len(auditLog.Events()); got != 3 {
+		t.Errorf("Expected 3 created events, got %d", got)
+	}
+}
+
+func TestBulkCreateRejectsOversizedBatch(t *testing.T) {
+	s := withTestGlobals(t)
+	orig := maxBulkSize
+	maxBulkSize = 2
+	defer func() { maxBulkSize = orig }()
+
+	w := postBulk(`[{"title":"a"},{"title":"b"},{"title":"c"}]`)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413, got %d", w.Code)
+	}
+	if len(s.GetAllTasks()) != 0 {
+		t.Errorf("Expected no tasks to be created")
+	}
+}
+
+func TestBulkCreateRequiresJSON(t *testing.T) {
+	withTestGlobals(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/bulk", strings.NewReader(`[]`))
+	w := httptest.NewRecorder()
+	NewServer(store).bulkCreateHandler(w, req)
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected 415, got %d", w.Code)
+	}
+
+	if w := postBulk(`{"title":"not an array"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for non-array body, got %d", w.Code)
+	}
+}
+
+func TestValidateBulkInputsDoesNotTakeLock(t *testing.T) {
+	s := withTestGlobals(t)
+
+	// Hold the store lock; validation must still complete
+	s.mu.Lock()
+	done := make(chan []BulkError)
+	go func() {
+		_, errs := validateBulkInputs([]TaskInput{{Title: "ok"}, {Title: ""}})
+		done <- errs
+	}()
+
+	select {
+	case errs := <-done:
+		if len(errs) != 1 || errs[0].Index != 1 {
+			t.Errorf("Expected one error at index 1, got %+v", errs)
+		}
+	case <-time.After(time.Second):
+		t.Error("Validation blocked on the store mutex")
+	}
+	s.mu.Unlock()
+}
+
+func TestCreateTasksPersistsBatch(t *testing.T) {
+	s := newTestStore()
+	s.partitions = NewColumnStore(t.TempDir())
+
+	created, _ := s.CreateTasks([]TaskSpec{
+		{Title: "A"},
+		{Title: "B", Status: "done"},
+		{Title: "C"},
+	})
+	if len(created) != 3 || created[2].ID != 3 {
+		t.Fatalf("Expected 3 tasks with sequential IDs, got %+v", created)
+	}
+
+	tasks, nextID, err := s.partitions.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(tasks) != 3 || nextID != 4 {
+		t.Errorf("Expected 3 persisted tasks and next ID 4, got %d and %d", len(tasks), nextID)
+	}
+}
+
+func TestBulkLabel(t *testing.T) {
+	s := newTestStore()
+	a, _ := s.CreateTask(TaskSpec{Title: "A", Labels: []string{"backlog", "bug"}})
+	b, _ := s.CreateTask(TaskSpec{Title: "B", Labels: []string{"urgent"}})
+	c, _ := s.CreateTask(TaskSpec{Title: "C"})
+
+	result, _ := s.BulkLabel([]int{a.ID, b.ID, 99, c.ID}, []string{"urgent", "urgent"}, []string{"backlog"})
+	if !equalIDs(result.Updated, []int{a.ID, c.ID}) {
+		t.Errorf("Expected tasks A and C updated, got %v", result.Updated)
+	}
+	if !equalIDs(result.NotFound, []int{99}) {
+		t.Errorf("Expected 99 reported not found, got %v", result.NotFound)
+	}
+
+	got, _ := s.GetTask(a.ID)
+	if strings.Join(got.Labels, ",") != "bug,urgent" {
+		t.Errorf("Expected backlog removed and urgent added once, got %v", got.Labels)
+	}
+	got, _ = s.GetTask(b.ID)
+	if strings.Join(got.Labels, ",") != "urgent" {
+		t.Errorf("Expected no duplicate urgent label, got %v", got.Labels)
+	}
+	got, _ = s.GetTask(c.ID)
+	if strings.Join(got.Labels, ",") != "urgent" {
+		t.Errorf("Expected urgent added to an unlabelled task, got %v", got.Labels)
+	}
+}
+
+func TestBulkLabelHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	task, _ := s.CreateTask(TaskSpec{Title: "A", Labels: []string{"backlog"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/bulk-label",
+		strings.NewReader(`{"task_ids": [1, 2], "add_labels": ["urgent"], "remove_labels": ["backlog"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	NewServer(s).bulkLabelHandler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var result BulkResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if !equalIDs(result.Updated, []int{1}) || !equalIDs(result.NotFound, []int{2}) {
+		t.Errorf("Expected updated [1] and not_found [2], got %+v", result)
+	}
+	if got, _ := s.GetTask(task.ID); strings.Join(got.Labels, ",") != "urgent" {
+		t.Errorf("Expected labels [urgent], got %v", got.Labels)
+	}
+}
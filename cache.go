@@ -0,0 +1,61 @@
+package main
+
+import "sync"
+
+// ReadCache holds each column's tasks pre-sorted by position so reads
+// don't need to scan the whole task map. Columns missing from the cache are
+// filled on first read.
+type ReadCache struct {
+	mu       sync.RWMutex
+	byStatus map[string][]*Task
+}
+
+// get returns a copy of a cached column and whether it was present
+func (c *ReadCache) get(status string) ([]*Task, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tasks, ok := c.byStatus[status]
+	if !ok {
+		return nil, false
+	}
+	return append([]*Task(nil), tasks...), true
+}
+
+// rebuild recomputes the given columns from the task map (must be called
+// with the store lock held)
+func (c *ReadCache) rebuild(tasks map[int]*Task, statuses ...string) {
+	columns := make(map[string][]*Task, len(statuses))
+	for _, status := range statuses {
+		columns[status] = scanTasksByStatus(tasks, status)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byStatus == nil {
+		c.byStatus = make(map[string][]*Task)
+	}
+	for status, column := range columns {
+		c.byStatus[status] = column
+	}
+}
+
+// reset drops every cached column
+func (c *ReadCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byStatus = nil
+}
+
+// scanTasksByStatus collects a column's tasks by walking the whole map,
+// leaving out archived tasks
+func scanTasksByStatus(tasks map[int]*Task, status string) []*Task {
+	column := []*Task{}
+	for _, task := range tasks {
+		if task.Status == status && task.ArchivedAt == nil {
+			column = append(column, task)
+		}
+	}
+	sortByPosition(column)
+	return column
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReadCacheTracksMutations(t *testing.T) {
+	s := newTestStore()
+	a := s.AddTask("A", "")
+	b := s.AddTask("B", "")
+
+	if got := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(got, []int{a.ID, b.ID}) {
+		t.Fatalf("Expected todo [1 2], got %v", got)
+	}
+
+	s.MoveTask(a.ID, "doing")
+	if got := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(got, []int{b.ID}) {
+		t.Errorf("After move, expected todo [2], got %v", got)
+	}
+	if got := taskIDs(s.GetTasksByStatus("doing")); !equalIDs(got, []int{a.ID}) {
+		t.Errorf("After move, expected doing [1], got %v", got)
+	}
+
+	c := s.AddTask("C", "")
+	if got := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(got, []int{b.ID, c.ID}) {
+		t.Errorf("After add, expected todo [2 3], got %v", got)
+	}
+
+	s.UpdateTask(b.ID, func(t *Task) { t.Title = "B2" })
+	if todo := s.GetTasksByStatus("todo"); todo[0].Title != "B2" {
+		t.Errorf("After update, expected title B2, got %q", todo[0].Title)
+	}
+
+	s.DeleteTask(b.ID)
+	if got := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(got, []int{c.ID}) {
+		t.Errorf("After delete, expected todo [3], got %v", got)
+	}
+}
+
+func TestReadCacheReturnsCopies(t *testing.T) {
+	s := newTestStore()
+	s.AddTask("A", "")
+	s.AddTask("B", "")
+
+	todo := s.GetTasksByStatus("todo")
+	todo[0] = nil
+	if s.GetTasksByStatus("todo")[0] == nil {
+		t.Errorf("Modifying a returned slice must not affect the cache")
+	}
+}
+
+func TestReadCacheResetOnLoad(t *testing.T) {
+	s := newTestStore()
+	s.AddTask("A", "")
+	s.GetTasksByStatus("todo")
+
+	// newTestStore reuses the same data file, so this overwrites what s saved
+	other := newTestStore()
+	other.AddTask("X", "")
+	other.AddTask("Y", "")
+
+	if err := s.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	if got := len(s.GetTasksByStatus("todo")); got != 2 {
+		t.Errorf("Expected 2 todo tasks after reload, got %d", got)
+	}
+}
+
+func newBenchmarkStore(b *testing.B, n int) *TaskStore {
+	b.Helper()
+	s := newTestStore()
+	s.partitions = NewColumnStore(b.TempDir())
+	for i := 0; i < n; i++ {
+		s.tasks[s.nextID] = &Task{ID: s.nextID, Title: fmt.Sprintf("Task %d", i), Status: boardColumns[i%3].Status}
+		s.nextID++
+	}
+	s.cache.rebuild(s.tasks, s.allStatuses()...)
+	return s
+}
+
+func BenchmarkGetTasksByStatusCached(b *testing.B) {
+	s := newBenchmarkStore(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.GetTasksByStatus("doing")
+	}
+}
+
+func BenchmarkGetTasksByStatusScan(b *testing.B) {
+	s := newBenchmarkStore(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.mu.Lock()
+		scanTasksByStatus(s.tasks, "doing")
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const maxChecklistItemLength = 200
+
+// ChecklistItem is one line of a task's checklist. Index is the item's
+// position in the list and is renumbered when items are removed.
+type ChecklistItem struct {
+	Index   int    `json:"index"`
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+	TaskID  int    `json:"task_id,omitempty"` // the archived task this subtask was converted from
+}
+
+// ChecklistDone returns how many checklist items are checked
+func (t *Task) ChecklistDone() int {
+	done := 0
+	for _, item := range t.Checklist {
+		if item.Checked {
+			done++
+		}
+	}
+	return done
+}
+
+// formatChecklist renders a checklist as "[x] a; [ ] b" for change records
+func formatChecklist(items []ChecklistItem) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		mark := " "
+		if item.Checked {
+			mark = "x"
+		}
+		parts[i] = fmt.Sprintf("[%s] %s", mark, item.Text)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// AddChecklistItem appends an unchecked item to a task's checklist
+func (s *TaskStore) AddChecklistItem(id int, text string) (*Task, bool, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, true, fmt.Errorf("checklist item text is required")
+	}
+	if len(text) > maxChecklistItemLength {
+		return nil, true, fmt.Errorf("checklist item must be at most %d characters", maxChecklistItemLength)
+	}
+	return checklistResult(s.updateTask(id, func(task *Task) error {
+		task.Checklist = append(task.Checklist, ChecklistItem{Index: len(task.Checklist), Text: text})
+		return nil
+	}))
+}
+
+// checklistResult turns updateTask's result into the checklist methods'
+// found flag and error
+func checklistResult(task *Task, err error) (*Task, bool, error) {
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, false, nil
+	}
+	return task, true, err
+}
+
+// ToggleChecklistItem flips the checked state of the item at index
+func (s *TaskStore) ToggleChecklistItem(id, index int) (*Task, bool, error) {
+	return checklistResult(s.updateTask(id, func(task *Task) error {
+		if index < 0 || index >= len(task.Checklist) {
+			return fmt.Errorf("no checklist item %d", index)
+		}
+		task.Checklist[index].Checked = !task.Checklist[index].Checked
+		return nil
+	}))
+}
+
+// DeleteChecklistItem removes the item at index and renumbers the rest
+func (s *TaskStore) DeleteChecklistItem(id, index int) (*Task, bool, error) {
+	return checklistResult(s.updateTask(id, func(task *Task) error {
+		if index < 0 || index >= len(task.Checklist) {
+			return fmt.Errorf("no checklist item %d", index)
+		}
+		items := append(task.Checklist[:index:index], task.Checklist[index+1:]...)
+		for i := range items {
+			items[i].Index = i
+		}
+		if len(items) == 0 {
+			items = nil
+		}
+		task.Checklist = items
+		return nil
+	}))
+}
+
+// taskChecklistHandler serves POST /task/{id}/checklist (form field text),
+// POST /task/{id}/checklist/{index}/toggle and DELETE
+// /task/{id}/checklist/{index}, re-rendering the task's column
+func (srv *Server) taskChecklistHandler(w http.ResponseWriter, r *http.Request, id int, rest []string) {
+	var (
+		task *Task
+		ok   bool
+		err  error
+	)
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		task, ok, err = srv.AddChecklistItem(id, r.FormValue("text"))
+	case len(rest) == 2 && rest[1] == "toggle" && r.Method == http.MethodPost:
+		index, convErr := strconv.Atoi(rest[0])
+		if convErr != nil {
+			http.Error(w, "Invalid checklist index", http.StatusBadRequest)
+			return
+		}
+		task, ok, err = srv.ToggleChecklistItem(id, index)
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		index, convErr := strconv.Atoi(rest[0])
+		if convErr != nil {
+			http.Error(w, "Invalid checklist index", http.StatusBadRequest)
+			return
+		}
+		task, ok, err = srv.DeleteChecklistItem(id, index)
+	case len(rest) <= 2:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	var locked *ErrBoardLocked
+	switch {
+	case !ok:
+		http.Error(w, "Task not found", http.StatusNotFound)
+	case errors.As(err, &locked):
+		writeError(w, err)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		templates.ExecuteTemplate(w, "column-content.html", srv.GetColumnData(task.Status))
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func checklistRequest(method, path string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	NewServer(store).taskRouter(w, req)
+	return w
+}
+
+func TestChecklistHandlers(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("Release", "")
+
+	for _, text := range []string{"Tag release", "Write notes", "Announce"} {
+		if w := checklistRequest(http.MethodPost, "/task/1/checklist", url.Values{"text": {text}}); w.Code != http.StatusOK {
+			t.Fatalf("Adding %q: expected 200, got %d: %s", text, w.Code, w.Body.String())
+		}
+	}
+	for _, index := range []string{"0", "2"} {
+		if w := checklistRequest(http.MethodPost, "/task/1/checklist/"+index+"/toggle", nil); w.Code != http.StatusOK {
+			t.Fatalf("Toggling %s: expected 200, got %d", index, w.Code)
+		}
+	}
+	w := checklistRequest(http.MethodDelete, "/task/1/checklist/1", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Deleting: expected 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "2/2 done") {
+		t.Errorf("Expected card to show 2/2 done, got %s", w.Body.String())
+	}
+
+	got, _ := s.GetTask(task.ID)
+	if len(got.Checklist) != 2 || got.Checklist[1].Text != "Announce" || got.Checklist[1].Index != 1 {
+		t.Errorf("Expected remaining items to be renumbered, got %+v", got.Checklist)
+	}
+
+	// Checklists are saved with the task
+	reloaded := &TaskStore{tasks: make(map[int]*Task), filePath: s.filePath}
+	if err := reloaded.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	if loaded, _ := reloaded.GetTask(task.ID); len(loaded.Checklist) != 2 || !loaded.Checklist[0].Checked {
+		t.Errorf("Checklist did not persist: %+v", loaded.Checklist)
+	}
+}
+
+func TestChecklistHandlerErrors(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Release", "")
+
+	if w := checklistRequest(http.MethodPost, "/task/1/checklist", url.Values{"text": {"  "}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for empty text, got %d", w.Code)
+	}
+	if w := checklistRequest(http.MethodPost, "/task/1/checklist/0/toggle", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a missing item, got %d", w.Code)
+	}
+	if w := checklistRequest(http.MethodDelete, "/task/1/checklist/x", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a non-numeric index, got %d", w.Code)
+	}
+	if w := checklistRequest(http.MethodPost, "/task/9/checklist", url.Values{"text": {"a"}}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing task, got %d", w.Code)
+	}
+	if w := checklistRequest(http.MethodGet, "/task/1/checklist", nil); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", w.Code)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// columnColorPrefix is the setting prefix for a column's header color
+const columnColorPrefix = "column_color"
+
+// columnPalette maps the color names a column accepts to their CSS values
+var columnPalette = map[string]string{
+	"blue":   "#3b82f6",
+	"yellow": "#f59e0b",
+	"green":  "#10b981",
+	"red":    "#ef4444",
+	"orange": "#f97316",
+	"purple": "#8b5cf6",
+	"pink":   "#ec4899",
+	"teal":   "#14b8a6",
+	"grey":   "#6b7280",
+}
+
+// defaultColumnColors are used for the built-in columns until a color is set
+var defaultColumnColors = map[string]string{
+	"todo":  "blue",
+	"doing": "yellow",
+	"done":  "green",
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// validateColumnColor accepts a palette name or a #RRGGBB hex color
+func validateColumnColor(value string) error {
+	if _, ok := columnPalette[value]; ok || hexColorPattern.MatchString(value) {
+		return nil
+	}
+	return fmt.Errorf("must be #RRGGBB or one of blue, yellow, green, red, orange, purple, pink, teal, grey")
+}
+
+// GetColumnColor returns the CSS color of a column's header: the configured
+// color, or the default palette color for todo, doing and done. It returns
+// "" for other columns with no color set.
+func (s *TaskStore) GetColumnColor(status string) string {
+	color, ok := s.settings.Get(columnColorPrefix + "." + status)
+	if !ok {
+		color = defaultColumnColors[status]
+	}
+	if hex, ok := columnPalette[color]; ok {
+		return hex
+	}
+	return strings.ToLower(color)
+}
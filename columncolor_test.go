@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetColumnColorDefaults(t *testing.T) {
+	withTestColumns(t)
+	s := newTestStore()
+	s.settings = withTestSettings(t)
+
+	for status, want := range map[string]string{"todo": "#3b82f6", "doing": "#f59e0b", "done": "#10b981", "review": ""} {
+		if got := s.GetColumnColor(status); got != want {
+			t.Errorf("%s: expected %q, got %q", status, want, got)
+		}
+	}
+
+	purple := "purple"
+	if err := s.UpdateColumn("done", nil, nil, &purple); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetColumnColor("done"); got != "#8b5cf6" {
+		t.Errorf("Expected the purple palette color, got %q", got)
+	}
+}
+
+func TestValidateColumnColor(t *testing.T) {
+	for _, ok := range []string{"blue", "grey", "#A1b2C3"} {
+		if err := validateColumnColor(ok); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "navy", "#abc", "#12345g", "red; background: url(x)", "#123456;"} {
+		if err := validateColumnColor(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestColumnColorAPIAndRendering(t *testing.T) {
+	withTestColumns(t)
+	s := withTestGlobals(t)
+	s.settings = withTestSettings(t)
+
+	w := columnsRequest(http.MethodPost, "/api/v1/columns/doing", `{"color": "#ff8800"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := columnsRequest(http.MethodPost, "/api/v1/columns/done", `{"color": "expression(alert(1))"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unsafe color, got %d", w.Code)
+	}
+	if w := columnsRequest(http.MethodPost, "/api/v1/columns", `{"status": "review", "display_name": "Review", "color": "teal"}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var cols []ColumnInfo
+	if err := json.Unmarshal(columnsRequest(http.MethodGet, "/api/v1/columns", "").Body.Bytes(), &cols); err != nil {
+		t.Fatal(err)
+	}
+	colors := make(map[string]string)
+	for _, col := range cols {
+		colors[col.Status] = col.Color
+	}
+	if colors["doing"] != "#ff8800" || colors["review"] != "#14b8a6" || colors["todo"] != "#3b82f6" {
+		t.Errorf("Unexpected column colors %v", colors)
+	}
+
+	rr := httptest.NewRecorder()
+	NewServer(s).indexHandler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `<div class="column doing" style="--column-color: #ff8800"`) {
+		t.Errorf("Expected the doing column to use its custom color, got:\n%s", body)
+	}
+	if !strings.Contains(body, `<div class="column review" style="--column-color: #14b8a6"`) {
+		t.Errorf("Expected the review column to use teal")
+	}
+}
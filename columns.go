@@ -0,0 +1,368 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// columnsSettingKey is the setting holding the board's column list as JSON,
+// once it has been changed through /api/v1/columns
+const columnsSettingKey = "columns"
+
+// columnsMu guards boardColumns, which /api/v1/columns changes at runtime
+var columnsMu sync.RWMutex
+
+// columnStatusPattern limits statuses to names safe in file names and HTML IDs
+var columnStatusPattern = regexp.MustCompile(`^[a-z0-9_-]{1,30}$`)
+
+var errColumnNotFound = errors.New("column not found")
+
+// ErrColumnNotEmpty is returned when removing a column that still has tasks
+type ErrColumnNotEmpty struct {
+	Status string
+	Count  int
+}
+
+func (e *ErrColumnNotEmpty) Error() string {
+	return fmt.Sprintf("column %q still has %d tasks", e.Status, e.Count)
+}
+
+// columns returns a copy of the board's columns in display order
+func columns() []columnDef {
+	columnsMu.RLock()
+	defer columnsMu.RUnlock()
+	return append([]columnDef(nil), boardColumns...)
+}
+
+// setColumns replaces the board's columns
+func setColumns(cols []columnDef) {
+	columnsMu.Lock()
+	defer columnsMu.Unlock()
+	boardColumns = cols
+}
+
+// columnList returns a copy of the store's columns in display order: a
+// cloned board's own, otherwise boardColumns
+func (s *TaskStore) columnList() []columnDef {
+	columnsMu.RLock()
+	defer columnsMu.RUnlock()
+	if s.ownColumns != nil {
+		return append([]columnDef(nil), s.ownColumns...)
+	}
+	return append([]columnDef(nil), boardColumns...)
+}
+
+// setColumnList replaces the store's columns
+func (s *TaskStore) setColumnList(cols []columnDef) {
+	columnsMu.Lock()
+	defer columnsMu.Unlock()
+	if s.ownColumns != nil {
+		s.ownColumns = cols
+		return
+	}
+	boardColumns = cols
+}
+
+// hasColumn reports whether status names one of the store's columns
+func (s *TaskStore) hasColumn(status string) bool {
+	for _, col := range s.columnList() {
+		if col.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// columnSetting is how a column is stored in the columns setting
+type columnSetting struct {
+	Status      string `json:"status"`
+	DisplayName string `json:"display_name"`
+}
+
+// parseColumns reads the columns setting
+func parseColumns(value string) ([]columnDef, error) {
+	var stored []columnSetting
+	if err := json.Unmarshal([]byte(value), &stored); err != nil {
+		return nil, fmt.Errorf("must be a JSON array of columns")
+	}
+	cols := make([]columnDef, 0, len(stored))
+	seen := make(map[string]bool)
+	for _, c := range stored {
+		if err := validateColumn(c.Status, c.DisplayName); err != nil {
+			return nil, err
+		}
+		if seen[c.Status] {
+			return nil, fmt.Errorf("column %q is listed twice", c.Status)
+		}
+		seen[c.Status] = true
+		cols = append(cols, columnDef{Status: c.Status, DisplayName: c.DisplayName})
+	}
+	if !seen["todo"] {
+		return nil, fmt.Errorf("the todo column is required")
+	}
+	return cols, nil
+}
+
+// formatColumns encodes columns for the columns setting
+func formatColumns(cols []columnDef) string {
+	data, _ := json.Marshal(columnSettings(cols))
+	return string(data)
+}
+
+// columnSettings converts columns to their stored form
+func columnSettings(cols []columnDef) []columnSetting {
+	stored := make([]columnSetting, 0, len(cols))
+	for _, col := range cols {
+		stored = append(stored, columnSetting{Status: col.Status, DisplayName: col.DisplayName})
+	}
+	return stored
+}
+
+// validateColumn checks a column's status and display name
+func validateColumn(status, displayName string) error {
+	if !columnStatusPattern.MatchString(status) {
+		return fmt.Errorf("status must be 1-30 lowercase letters, digits, dashes or underscores")
+	}
+	if strings.TrimSpace(displayName) == "" || len(displayName) > maxColumnNameLength {
+		return fmt.Errorf("display name must be 1-%d characters", maxColumnNameLength)
+	}
+	return nil
+}
+
+// ColumnInfo describes a column as returned by /api/v1/columns
+type ColumnInfo struct {
+	Status             string   `json:"status"`
+	DisplayName        string   `json:"display_name"`
+	WIPLimit           int      `json:"wip_limit"`
+	Color              string   `json:"color"`
+	AllowedTransitions []string `json:"allowed_transitions"`
+}
+
+// Columns returns the configuration of every column in display order. A
+// column's allowed transitions are the columns the workflow lets its tasks
+// move to.
+func (s *TaskStore) Columns() []ColumnInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cols := s.columnList()
+	configs := make([]ColumnInfo, 0, len(cols))
+	for _, col := range cols {
+		config := ColumnInfo{
+			Status:             col.Status,
+			DisplayName:        s.columnName(col),
+			WIPLimit:           s.wipLimit(col.Status),
+			Color:              s.GetColumnColor(col.Status),
+			AllowedTransitions: []string{},
+		}
+		for _, to := range cols {
+			if to.Status != col.Status && s.workflow.Allows(col.Status, to.Status) {
+				config.AllowedTransitions = append(config.AllowedTransitions, to.Status)
+			}
+		}
+		configs = append(configs, config)
+	}
+	return configs
+}
+
+// AddColumn appends a column to the board. An empty color leaves the
+// header in the default style.
+func (s *TaskStore) AddColumn(status, displayName string, wipLimit int, color string) error {
+	displayName = strings.TrimSpace(displayName)
+	if err := validateColumn(status, displayName); err != nil {
+		return err
+	}
+	if wipLimit < 0 {
+		return fmt.Errorf("wip_limit must not be negative")
+	}
+	if color != "" {
+		if err := validateColumnColor(color); err != nil {
+			return fmt.Errorf("color %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkLocked(); err != nil {
+		return err
+	}
+	if s.hasColumn(status) {
+		return fmt.Errorf("column %q already exists", status)
+	}
+	previous := s.columnList()
+	cols := append(s.columnList(), columnDef{Status: status, DisplayName: displayName})
+	s.setColumnList(cols)
+	updates := map[string]string{columnsSettingKey: formatColumns(cols)}
+	if wipLimit > 0 {
+		updates["wip_limit."+status] = strconv.Itoa(wipLimit)
+	}
+	if color != "" {
+		updates[columnColorPrefix+"."+status] = color
+	}
+	if err := s.saveColumnSettings(updates); err != nil {
+		s.setColumnList(previous)
+		return err
+	}
+	return nil
+}
+
+// UpdateColumn changes a column's display name, WIP limit and color; nil
+// leaves a value unchanged
+func (s *TaskStore) UpdateColumn(status string, displayName *string, wipLimit *int, color *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkLocked(); err != nil {
+		return err
+	}
+	if !s.hasColumn(status) {
+		return errColumnNotFound
+	}
+
+	updates := make(map[string]string)
+	if displayName != nil {
+		name := strings.TrimSpace(*displayName)
+		if err := validateColumn(status, name); err != nil {
+			return err
+		}
+		updates["column_name."+status] = name
+	}
+	if wipLimit != nil {
+		if *wipLimit < 0 {
+			return fmt.Errorf("wip_limit must not be negative")
+		}
+		updates["wip_limit."+status] = strconv.Itoa(*wipLimit)
+	}
+	if color != nil {
+		if err := validateColumnColor(*color); err != nil {
+			return fmt.Errorf("color %w", err)
+		}
+		updates[columnColorPrefix+"."+status] = *color
+	}
+	return s.saveColumnSettings(updates)
+}
+
+// RemoveColumn deletes an empty column along with its settings. It returns
+// ErrColumnNotEmpty if any task is still in it. The todo column, where new
+// tasks go, can't be removed.
+func (s *TaskStore) RemoveColumn(status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkLocked(); err != nil {
+		return err
+	}
+	if !s.hasColumn(status) {
+		return errColumnNotFound
+	}
+	if status == "todo" {
+		return fmt.Errorf("the todo column can't be removed")
+	}
+	count := 0
+	for _, task := range s.tasks {
+		if task.Status == status {
+			count++
+		}
+	}
+	if count > 0 {
+		return &ErrColumnNotEmpty{Status: status, Count: count}
+	}
+
+	var cols []columnDef
+	for _, col := range s.columnList() {
+		if col.Status != status {
+			cols = append(cols, col)
+		}
+	}
+	s.setColumnList(cols)
+	s.cache.reset()
+	if s.settings == nil {
+		return nil
+	}
+	for _, rule := range settingRules {
+		if _, err := s.settings.Delete(rule.prefix + "." + status); err != nil {
+			return err
+		}
+	}
+	return s.settings.Merge(map[string]string{columnsSettingKey: formatColumns(cols)})
+}
+
+// saveColumnSettings persists column changes to settings.json, if the store
+// has settings (must be called with lock held)
+func (s *TaskStore) saveColumnSettings(updates map[string]string) error {
+	if s.settings == nil || len(updates) == 0 {
+		return nil
+	}
+	return s.settings.Merge(updates)
+}
+
+// columnInput is the body of POST and PUT /api/v1/columns
+type columnInput struct {
+	Status      string  `json:"status"`
+	DisplayName *string `json:"display_name"`
+	WIPLimit    *int    `json:"wip_limit"`
+	Color       *string `json:"color"`
+}
+
+// columnsAPIHandler serves /api/v1/columns: GET lists the columns, POST adds
+// one, PUT or POST /api/v1/columns/{status} updates one and DELETE removes it
+func (srv *Server) columnsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	status := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/columns"), "/")
+
+	var in columnInput
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var err error
+	switch {
+	case status == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, srv.Columns())
+		return
+	case status == "" && r.Method == http.MethodPost:
+		name, limit, color := in.Status, 0, ""
+		if in.DisplayName != nil {
+			name = *in.DisplayName
+		}
+		if in.WIPLimit != nil {
+			limit = *in.WIPLimit
+		}
+		if in.Color != nil {
+			color = *in.Color
+		}
+		err = srv.AddColumn(in.Status, name, limit, color)
+	case status != "" && (r.Method == http.MethodPut || r.Method == http.MethodPost):
+		err = srv.UpdateColumn(status, in.DisplayName, in.WIPLimit, in.Color)
+	case status != "" && r.Method == http.MethodDelete:
+		err = srv.RemoveColumn(status)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var (
+		notEmpty *ErrColumnNotEmpty
+		locked   *ErrBoardLocked
+	)
+	switch {
+	case errors.Is(err, errColumnNotFound):
+		http.Error(w, "Column not found", http.StatusNotFound)
+	case errors.As(err, &notEmpty), errors.As(err, &locked):
+		writeError(w, err)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && status == "":
+		writeJSON(w, http.StatusCreated, srv.Columns())
+	default:
+		writeJSON(w, http.StatusOK, srv.Columns())
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// withTestColumns restores the default columns after the test
+func withTestColumns(t *testing.T) {
+	orig := columns()
+	t.Cleanup(func() { setColumns(orig) })
+}
+
+func columnsRequest(method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	NewServer(store).columnsAPIHandler(w, req)
+	return w
+}
+
+func TestColumnsAPILifecycle(t *testing.T) {
+	withTestColumns(t)
+	s := withTestGlobals(t)
+	s.settings = withTestSettings(t)
+
+	w := columnsRequest(http.MethodPost, "/api/v1/columns", `{"status": "review", "display_name": "Review", "wip_limit": 2}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := columnsRequest(http.MethodPost, "/api/v1/columns", `{"status": "review", "display_name": "Again"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a duplicate column, got %d", w.Code)
+	}
+	if w := columnsRequest(http.MethodPost, "/api/v1/columns", `{"status": "Bad Status!", "display_name": "Bad"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid status, got %d", w.Code)
+	}
+
+	// The new column is a valid move target
+	task := s.AddTask("Check", "")
+	if _, ok, err := s.MoveTask(task.ID, "review"); !ok || err != nil {
+		t.Fatalf("Expected move to the new column, got ok=%v err=%v", ok, err)
+	}
+
+	w = columnsRequest(http.MethodPut, "/api/v1/columns/review", `{"display_name": "Code Review", "wip_limit": 5}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	w = columnsRequest(http.MethodGet, "/api/v1/columns", "")
+	var cols []ColumnInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &cols); err != nil || len(cols) != 4 {
+		t.Fatalf("Expected 4 columns, got %s", w.Body.String())
+	}
+	if review := cols[3]; review.Status != "review" || review.DisplayName != "Code Review" || review.WIPLimit != 5 {
+		t.Errorf("Expected the updated review column last, got %+v", review)
+	}
+	if len(cols[0].AllowedTransitions) != 3 {
+		t.Errorf("Expected todo to allow moves to every other column, got %v", cols[0].AllowedTransitions)
+	}
+	if w := columnsRequest(http.MethodPut, "/api/v1/columns/missing", `{"wip_limit": 1}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing column, got %d", w.Code)
+	}
+
+	if w := columnsRequest(http.MethodDelete, "/api/v1/columns/review", ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 while the column has tasks, got %d", w.Code)
+	}
+	s.DeleteTask(task.ID)
+	if w := columnsRequest(http.MethodDelete, "/api/v1/columns/review", ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 for an empty column, got %d", w.Code)
+	}
+	if isValidStatus("review") {
+		t.Errorf("Expected the removed column to no longer be a valid status")
+	}
+	if _, ok := s.settings.Get("wip_limit.review"); ok {
+		t.Errorf("Expected the removed column's settings to be deleted")
+	}
+	if w := columnsRequest(http.MethodDelete, "/api/v1/columns/todo", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected the todo column to be protected, got %d", w.Code)
+	}
+}
+
+func TestColumnsPersistToSettings(t *testing.T) {
+	withTestColumns(t)
+	s := withTestGlobals(t)
+	s.settings = withTestSettings(t)
+	if err := s.AddColumn("review", "Review", 3, ""); err != nil {
+		t.Fatalf("AddColumn failed: %v", err)
+	}
+
+	// A restart starts from the default columns and reads them back
+	setColumns([]columnDef{{Status: "todo", DisplayName: "To Do"}})
+	reloaded := NewSettingsStore(s.settings.filePath)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !isValidStatus("review") || !isValidStatus("done") {
+		t.Errorf("Expected the saved columns to be restored, got %+v", columns())
+	}
+	if limit, ok := reloaded.Get("wip_limit.review"); !ok || limit != "3" {
+		t.Errorf("Expected the added column's WIP limit to survive a restart, got %q", limit)
+	}
+}
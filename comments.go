@@ -0,0 +1,449 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxCommentLength = 2000
+	maxAuthorLength  = 50
+
+	// maxCommentDepth is how deeply GetComments nests replies, counting top
+	// level comments as 1
+	maxCommentDepth = 3
+)
+
+// allowedReactions are the emoji a comment can be reacted to with, in the
+// order they are shown
+var allowedReactions = []string{"👍", "🎉", "🚀", "❤️", "👀"}
+
+var errCommentNotFound = errors.New("comment not found")
+
+// Comment is a note left on a task
+type Comment struct {
+	ID              int       `json:"id"`
+	TaskID          int       `json:"task_id"`
+	ParentCommentID *int      `json:"parent_comment_id,omitempty"` // the comment this replies to, nil at top level
+	Author          string    `json:"author"`
+	Body            string    `json:"body"`
+	Mentions        []string  `json:"mentions,omitempty"` // users @-mentioned in the body, see ParseMentions
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// CommentThread is a comment with the replies to it, oldest first
+type CommentThread struct {
+	*Comment
+	Replies []CommentThread `json:"replies"`
+}
+
+// Reaction is one user's emoji on a comment
+type Reaction struct {
+	CommentID int       `json:"comment_id"`
+	Emoji     string    `json:"emoji"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// validateCommenter checks the name a comment or reaction is made under
+func validateCommenter(name string) error {
+	if name == "" || len(name) > maxAuthorLength {
+		return fmt.Errorf("name must be 1-%d characters", maxAuthorLength)
+	}
+	return nil
+}
+
+// mentionPattern matches an @-mention: a username, or an email address for
+// users who should be emailed, after whitespace or punctuation so an email
+// address in the text isn't mistaken for one
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([\w.+-]+(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+// ParseMentions returns the users @-mentioned in a comment body, lowercased,
+// each once, in the order they first appear. A trailing full stop, as at the
+// end of a sentence, isn't part of the name.
+func ParseMentions(body string) []string {
+	var mentions []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		name := strings.ToLower(strings.TrimRight(match[1], "."))
+		if name == "" || len(name) > maxAuthorLength || containsString(mentions, name) {
+			continue
+		}
+		mentions = append(mentions, name)
+	}
+	return mentions
+}
+
+// AddComment adds a comment to a task, publishing a CommentMention event for
+// each user it @-mentions. It returns false if the task does not exist.
+func (s *TaskStore) AddComment(taskID int, author, body string) (*Comment, bool, error) {
+	return s.addComment(taskID, nil, author, body)
+}
+
+// AddReply adds a comment to a task in reply to parentID, which must be a
+// comment on the same task. Otherwise it is like AddComment.
+func (s *TaskStore) AddReply(taskID, parentID int, author, body string) (*Comment, bool, error) {
+	return s.addComment(taskID, &parentID, author, body)
+}
+
+// addComment adds a comment replying to parentID, or at top level if it is
+// nil
+func (s *TaskStore) addComment(taskID int, parentID *int, author, body string) (*Comment, bool, error) {
+	author, body = strings.TrimSpace(author), strings.TrimSpace(body)
+	if err := validateCommenter(author); err != nil {
+		return nil, true, err
+	}
+	if body == "" || len(body) > maxCommentLength {
+		return nil, true, fmt.Errorf("comment must be 1-%d characters", maxCommentLength)
+	}
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, true, err
+	}
+	task, ok := s.tasks[taskID]
+	if !ok {
+		s.mu.Unlock()
+		return nil, false, nil
+	}
+	if parentID != nil && s.findComment(taskID, *parentID) == nil {
+		s.mu.Unlock()
+		return nil, true, errors.New("parent_id must be a comment on this task")
+	}
+	if s.comments == nil {
+		s.comments = make(map[int][]*Comment)
+	}
+	if s.nextCommentID < 1 {
+		s.nextCommentID = 1
+	}
+	comment := &Comment{ID: s.nextCommentID, TaskID: taskID, ParentCommentID: parentID, Author: author, Body: body, Mentions: ParseMentions(body), CreatedAt: s.clock()}
+	s.nextCommentID++
+	s.comments[taskID] = append(s.comments[taskID], comment)
+	s.persistComments()
+	updated := task.clone()
+	added := *comment
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskUpdated, Task: updated, Actor: author, Changes: []FieldChange{{Field: "comment", New: body}}})
+	for _, user := range added.Mentions {
+		s.publish(Event{Type: EventCommentMention, Task: updated, Actor: author, Comment: &added, Mentioned: user})
+	}
+	return comment, true, nil
+}
+
+// Comments returns a task's comments, oldest first
+func (s *TaskStore) Comments(taskID int) ([]*Comment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[taskID]; !ok {
+		return nil, false
+	}
+	comments := []*Comment{}
+	for _, comment := range s.comments[taskID] {
+		c := *comment
+		comments = append(comments, &c)
+	}
+	return comments, true
+}
+
+// findComment returns a comment on a task by ID, or nil (must be called
+// with lock held)
+func (s *TaskStore) findComment(taskID, commentID int) *Comment {
+	for _, comment := range s.comments[taskID] {
+		if comment.ID == commentID {
+			return comment
+		}
+	}
+	return nil
+}
+
+// GetComments returns a task's comments as threads, oldest first at each
+// level. Replies nest up to maxCommentDepth; replies to comments at that
+// depth, directly or not, are listed alongside them instead. It returns
+// nothing for a missing task.
+func (s *TaskStore) GetComments(taskID int) []CommentThread {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replies := make(map[int][]*Comment)
+	var top []*Comment
+	for _, comment := range s.comments[taskID] {
+		c := *comment
+		// A reply whose parent is gone is shown at top level
+		if c.ParentCommentID != nil && s.findComment(taskID, *c.ParentCommentID) != nil {
+			replies[*c.ParentCommentID] = append(replies[*c.ParentCommentID], &c)
+		} else {
+			top = append(top, &c)
+		}
+	}
+
+	var thread func(comment *Comment, depth int) CommentThread
+	thread = func(comment *Comment, depth int) CommentThread {
+		t := CommentThread{Comment: comment, Replies: []CommentThread{}}
+		if depth < maxCommentDepth-1 {
+			for _, reply := range replies[comment.ID] {
+				t.Replies = append(t.Replies, thread(reply, depth+1))
+			}
+			return t
+		}
+		// Flatten everything below into the last level, oldest first
+		pending := append([]*Comment(nil), replies[comment.ID]...)
+		var flat []*Comment
+		for len(pending) > 0 {
+			reply := pending[0]
+			pending = append(pending[1:], replies[reply.ID]...)
+			flat = append(flat, reply)
+		}
+		sort.Slice(flat, func(i, j int) bool { return flat[i].ID < flat[j].ID })
+		for _, reply := range flat {
+			t.Replies = append(t.Replies, CommentThread{Comment: reply, Replies: []CommentThread{}})
+		}
+		return t
+	}
+
+	threads := []CommentThread{}
+	for _, comment := range top {
+		threads = append(threads, thread(comment, 1))
+	}
+	return threads
+}
+
+// ToggleReaction adds userID's emoji reaction to a comment on the task, or
+// removes it if they already reacted with that emoji. It reports whether the
+// reaction was added.
+func (s *TaskStore) ToggleReaction(taskID, commentID int, emoji, userID string) (bool, error) {
+	if !containsString(allowedReactions, emoji) {
+		return false, fmt.Errorf("reaction must be one of %s", strings.Join(allowedReactions, " "))
+	}
+	userID = strings.TrimSpace(userID)
+	if err := validateCommenter(userID); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkLocked(); err != nil {
+		return false, err
+	}
+	if s.findComment(taskID, commentID) == nil {
+		return false, errCommentNotFound
+	}
+
+	if s.reactions == nil {
+		s.reactions = make(map[int][]Reaction)
+	}
+	added := true
+	reactions := s.reactions[commentID]
+	for i, reaction := range reactions {
+		if reaction.Emoji == emoji && reaction.UserID == userID {
+			s.reactions[commentID] = append(reactions[:i:i], reactions[i+1:]...)
+			added = false
+			break
+		}
+	}
+	if added {
+		s.reactions[commentID] = append(reactions, Reaction{CommentID: commentID, Emoji: emoji, UserID: userID, CreatedAt: s.clock()})
+	}
+	if len(s.reactions[commentID]) == 0 {
+		delete(s.reactions, commentID)
+	}
+	s.persistComments()
+	return added, nil
+}
+
+// GetReactions maps each emoji on a comment to the users who reacted with
+// it, in the order they reacted
+func (s *TaskStore) GetReactions(commentID int) map[string][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make(map[string][]string)
+	for _, reaction := range s.reactions[commentID] {
+		users[reaction.Emoji] = append(users[reaction.Emoji], reaction.UserID)
+	}
+	return users
+}
+
+// allComments flattens the comments map ordered by ID for saving (must be
+// called with lock held)
+func (s *TaskStore) allComments() []*Comment {
+	var all []*Comment
+	for _, comments := range s.comments {
+		all = append(all, comments...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all
+}
+
+// allReactions flattens the reactions map ordered by comment for saving
+// (must be called with lock held)
+func (s *TaskStore) allReactions() []Reaction {
+	var all []Reaction
+	for _, reactions := range s.reactions {
+		all = append(all, reactions...)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].CommentID < all[j].CommentID })
+	return all
+}
+
+// setComments replaces the comments and reactions with loaded ones (must be
+// called with lock held)
+func (s *TaskStore) setComments(comments []*Comment, nextCommentID int, reactions []Reaction) {
+	s.comments = make(map[int][]*Comment)
+	s.nextCommentID = nextCommentID
+	for _, comment := range comments {
+		s.comments[comment.TaskID] = append(s.comments[comment.TaskID], comment)
+		if comment.ID >= s.nextCommentID {
+			s.nextCommentID = comment.ID + 1
+		}
+	}
+	s.reactions = make(map[int][]Reaction)
+	for _, reaction := range reactions {
+		s.reactions[reaction.CommentID] = append(s.reactions[reaction.CommentID], reaction)
+	}
+}
+
+// deleteComments drops a task's comments and their reactions, reporting
+// whether it had any (must be called with lock held)
+func (s *TaskStore) deleteComments(taskID int) bool {
+	comments, ok := s.comments[taskID]
+	for _, comment := range comments {
+		delete(s.reactions, comment.ID)
+	}
+	delete(s.comments, taskID)
+	return ok
+}
+
+// persistComments saves comments and reactions (must be called with lock
+// held), in the data file, comments.json with partitioned storage, or the
+// external backend's extras.
+func (s *TaskStore) persistComments() {
+	switch {
+	case s.backend != nil:
+		s.saveExtras()
+	case s.partitions != nil:
+		if err := s.partitions.WriteComments(s.allComments(), s.nextCommentID, s.allReactions()); err != nil {
+			log.Printf("Error saving comments: %v", err)
+		}
+	default:
+		s.saveToFile()
+	}
+}
+
+// reactionCount is one reaction button under a comment
+type reactionCount struct {
+	Emoji string
+	Count int
+	Users string // who reacted, for the tooltip
+}
+
+// commentView is a comment with its reaction buttons and replies
+type commentView struct {
+	*Comment
+	Reactions []reactionCount
+	Replies   []commentView
+}
+
+// taskCommentsData is what task-comments.html renders
+type taskCommentsData struct {
+	TaskID   int
+	Comments []commentView
+}
+
+// newCommentView builds the view of a comment thread
+func newCommentView(s Store, thread CommentThread) commentView {
+	users := s.GetReactions(thread.ID)
+	view := commentView{Comment: thread.Comment}
+	for _, emoji := range allowedReactions {
+		view.Reactions = append(view.Reactions, reactionCount{
+			Emoji: emoji,
+			Count: len(users[emoji]),
+			Users: strings.Join(users[emoji], ", "),
+		})
+	}
+	for _, reply := range thread.Replies {
+		view.Replies = append(view.Replies, newCommentView(s, reply))
+	}
+	return view
+}
+
+// renderTaskComments writes a task's comment section
+func (srv *Server) renderTaskComments(w http.ResponseWriter, taskID int) {
+	if _, ok := srv.GetTask(taskID); !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	data := taskCommentsData{TaskID: taskID}
+	for _, thread := range srv.GetComments(taskID) {
+		data.Comments = append(data.Comments, newCommentView(srv, thread))
+	}
+	templates.ExecuteTemplate(w, "task-comments.html", data)
+}
+
+// taskCommentsHandler serves /task/{id}/comments: GET renders the comment
+// section, POST adds a comment from the user and body fields, replying to
+// the comment in parent_id if it is set, and POST
+// /task/{id}/comments/{commentID}/react toggles the user's emoji reaction.
+// Each returns the updated comment section.
+func (srv *Server) taskCommentsHandler(w http.ResponseWriter, r *http.Request, id int, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		srv.renderTaskComments(w, id)
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		var (
+			ok  bool
+			err error
+		)
+		if parent := r.FormValue("parent_id"); parent != "" {
+			parentID, convErr := strconv.Atoi(parent)
+			if convErr != nil {
+				http.Error(w, "Invalid parent_id", http.StatusBadRequest)
+				return
+			}
+			_, ok, err = srv.AddReply(id, parentID, r.FormValue("user"), r.FormValue("body"))
+		} else {
+			_, ok, err = srv.AddComment(id, r.FormValue("user"), r.FormValue("body"))
+		}
+		if !ok {
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		srv.renderTaskComments(w, id)
+	case len(rest) == 2 && rest[1] == "react":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		commentID, err := strconv.Atoi(rest[0])
+		if err != nil {
+			http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+			return
+		}
+		_, err = srv.ToggleReaction(id, commentID, r.FormValue("emoji"), r.FormValue("user"))
+		if errors.Is(err, errCommentNotFound) {
+			http.Error(w, "Comment not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		srv.renderTaskComments(w, id)
+	case len(rest) == 0:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestAddAndListComments(t *testing.T) {
+	store := newTestStore()
+	task := store.AddTask("Design", "")
+
+	first, ok, err := store.AddComment(task.ID, "alice", "Looks good")
+	if !ok || err != nil {
+		t.Fatalf("AddComment failed: ok=%v err=%v", ok, err)
+	}
+	store.AddComment(task.ID, "bob", "Ship it")
+	if first.ID != 1 || first.TaskID != task.ID || first.CreatedAt.IsZero() {
+		t.Errorf("Unexpected comment: %+v", first)
+	}
+	comments, ok := store.Comments(task.ID)
+	if !ok || len(comments) != 2 || comments[0].Author != "alice" || comments[1].Body != "Ship it" {
+		t.Errorf("Expected both comments oldest first, got %+v", comments)
+	}
+
+	if _, _, err := store.AddComment(task.ID, "alice", "   "); err == nil {
+		t.Errorf("Expected an empty comment to be rejected")
+	}
+	if _, _, err := store.AddComment(task.ID, "", "Anonymous"); err == nil {
+		t.Errorf("Expected a comment without an author to be rejected")
+	}
+	if _, ok, _ := store.AddComment(99, "alice", "Hi"); ok {
+		t.Errorf("Expected missing task to report not found")
+	}
+}
+
+func TestToggleReaction(t *testing.T) {
+	store := newTestStore()
+	task := store.AddTask("Design", "")
+	comment, _, _ := store.AddComment(task.ID, "alice", "Looks good")
+
+	if added, err := store.ToggleReaction(task.ID, comment.ID, "👍", "bob"); !added || err != nil {
+		t.Fatalf("Expected reaction to be added, got added=%v err=%v", added, err)
+	}
+	store.ToggleReaction(task.ID, comment.ID, "👍", "carol")
+	store.ToggleReaction(task.ID, comment.ID, "🚀", "bob")
+
+	reactions := store.GetReactions(comment.ID)
+	if len(reactions["👍"]) != 2 || reactions["👍"][0] != "bob" || len(reactions["🚀"]) != 1 {
+		t.Errorf("Expected 2 👍 and 1 🚀, got %v", reactions)
+	}
+
+	// Reacting again with the same emoji takes the reaction back
+	if added, err := store.ToggleReaction(task.ID, comment.ID, "👍", "bob"); added || err != nil {
+		t.Errorf("Expected reaction to be removed, got added=%v err=%v", added, err)
+	}
+	if users := store.GetReactions(comment.ID)["👍"]; len(users) != 1 || users[0] != "carol" {
+		t.Errorf("Expected only carol's 👍 to remain, got %v", users)
+	}
+
+	if _, err := store.ToggleReaction(task.ID, comment.ID, "💩", "bob"); err == nil {
+		t.Errorf("Expected an emoji outside the allowlist to be rejected")
+	}
+	if _, err := store.ToggleReaction(99, comment.ID, "👍", "bob"); err != errCommentNotFound {
+		t.Errorf("Expected a comment on another task to be not found, got %v", err)
+	}
+}
+
+func TestCommentsPersistenceRoundTrip(t *testing.T) {
+	store := newTestStore()
+	task := store.AddTask("Design", "")
+	comment, _, _ := store.AddComment(task.ID, "alice", "Looks good")
+	store.ToggleReaction(task.ID, comment.ID, "🎉", "bob")
+
+	loaded := &TaskStore{tasks: make(map[int]*Task), filePath: store.filePath}
+	if err := loaded.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	comments, _ := loaded.Comments(task.ID)
+	if len(comments) != 1 || comments[0].Body != "Looks good" {
+		t.Errorf("Expected the comment to round-trip, got %+v", comments)
+	}
+	if users := loaded.GetReactions(comment.ID)["🎉"]; len(users) != 1 || users[0] != "bob" {
+		t.Errorf("Expected bob's reaction to round-trip, got %v", users)
+	}
+	if next, _, _ := loaded.AddComment(task.ID, "bob", "Thanks"); next.ID != 2 {
+		t.Errorf("Expected next comment ID 2, got %d", next.ID)
+	}
+
+	loaded.DeleteTask(task.ID)
+	if len(loaded.allComments()) != 0 || len(loaded.allReactions()) != 0 {
+		t.Errorf("Deleting a task must remove its comments and reactions")
+	}
+}
+
+func TestTaskCommentsHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("Design", "")
+
+	post := func(path string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		NewServer(s).taskRouter(w, req)
+		return w
+	}
+
+	w := post("/task/1/comments", url.Values{"user": {"alice"}, "body": {"Looks good"}})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Looks good") {
+		t.Fatalf("Expected the comment to render, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = post("/task/1/comments/1/react", url.Values{"user": {"bob"}, "emoji": {"🚀"}})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "🚀 1") {
+		t.Errorf("Expected a reaction count of 1, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("/task/1/comments/1/react", url.Values{"user": {"bob"}, "emoji": {"🙃"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a disallowed emoji, got %d", w.Code)
+	}
+	if w := post("/task/1/comments/7/react", url.Values{"user": {"bob"}, "emoji": {"🚀"}}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing comment, got %d", w.Code)
+	}
+	if w := post("/task/9/comments", url.Values{"user": {"alice"}, "body": {"Hi"}}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing task, got %d", w.Code)
+	}
+	if len(s.GetReactions(1)["🚀"]) != 1 || task.ID != 1 {
+		t.Errorf("Expected one stored reaction")
+	}
+}
+
+func TestParseMentions(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		want []string
+	}{
+		{"@alice can you check this?", []string{"alice"}},
+		{"Thanks @Bob, and @bob again, @ALICE.", []string{"bob", "alice"}},
+		{"cc @dana@example.com and @erin", []string{"dana@example.com", "erin"}},
+		{"Mail ann@example.com about it", nil},
+		{"(@carol) @ @.", []string{"carol"}},
+		{"No mentions here", nil},
+	} {
+		got := ParseMentions(tc.body)
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%q: expected %v, got %v", tc.body, tc.want, got)
+		}
+	}
+}
+
+func TestAddCommentPublishesMentions(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("Design", "")
+	var mentions []Event
+	bus.Subscribe(EventCommentMention, func(e Event) { mentions = append(mentions, e) })
+
+	comment, _, err := s.AddComment(task.ID, "alice", "@Bob @carol please review, @bob")
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if strings.Join(comment.Mentions, ",") != "bob,carol" {
+		t.Errorf("Expected bob and carol to be mentioned, got %v", comment.Mentions)
+	}
+	if len(mentions) != 2 || mentions[0].Mentioned != "bob" || mentions[1].Mentioned != "carol" {
+		t.Fatalf("Expected a mention event for bob and carol, got %+v", mentions)
+	}
+	for _, e := range mentions {
+		if e.Task.ID != task.ID || e.Actor != "alice" || e.Comment.ID != comment.ID {
+			t.Errorf("Unexpected mention event %+v", e)
+		}
+	}
+	if history := auditLog.GetTaskHistory(task.ID); len(history) != 2 {
+		t.Errorf("Expected mentions to stay out of the audit log, got %+v", history)
+	}
+
+	mentions = nil
+	s.AddComment(task.ID, "bob", "Done")
+	if len(mentions) != 0 {
+		t.Errorf("Expected no mention events, got %+v", mentions)
+	}
+
+	loaded := &TaskStore{tasks: make(map[int]*Task), filePath: s.filePath}
+	if err := loaded.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	comments, _ := loaded.Comments(task.ID)
+	if len(comments) != 2 || strings.Join(comments[0].Mentions, ",") != "bob,carol" || comments[1].Mentions != nil {
+		t.Errorf("Expected mentions to round-trip, got %+v", comments)
+	}
+}
+
+func TestCommentThreads(t *testing.T) {
+	store := newTestStore()
+	task := store.AddTask("Design", "")
+	other := store.AddTask("Build", "")
+
+	root, _, _ := store.AddComment(task.ID, "alice", "Which colour?")
+	level2, _, err := store.AddReply(task.ID, root.ID, "bob", "Blue")
+	if err != nil {
+		t.Fatalf("AddReply failed: %v", err)
+	}
+	level3, _, _ := store.AddReply(task.ID, level2.ID, "alice", "Which blue?")
+	level4, _, _ := store.AddReply(task.ID, level3.ID, "bob", "Navy")
+	second, _, _ := store.AddComment(task.ID, "carol", "Ship it")
+	sibling, _, _ := store.AddReply(task.ID, level2.ID, "carol", "Green")
+
+	if level4.ParentCommentID == nil || *level4.ParentCommentID != level3.ID {
+		t.Errorf("Expected the reply to remember its parent, got %+v", level4)
+	}
+	if _, _, err := store.AddReply(task.ID, 99, "bob", "Hi"); err == nil {
+		t.Errorf("Expected a reply to a missing comment to be rejected")
+	}
+	if _, _, err := store.AddReply(other.ID, root.ID, "bob", "Hi"); err == nil {
+		t.Errorf("Expected a reply to another task's comment to be rejected")
+	}
+
+	threads := store.GetComments(task.ID)
+	if len(threads) != 2 || threads[0].ID != root.ID || threads[1].ID != second.ID || len(threads[1].Replies) != 0 {
+		t.Fatalf("Expected two top-level threads, got %+v", threads)
+	}
+	if replies := threads[0].Replies; len(replies) != 1 || replies[0].ID != level2.ID {
+		t.Fatalf("Expected one reply at level 2, got %+v", replies)
+	}
+	// The level 4 reply is flattened to level 3, beside its parent
+	var ids []int
+	for _, reply := range threads[0].Replies[0].Replies {
+		ids = append(ids, reply.ID)
+		if len(reply.Replies) != 0 {
+			t.Errorf("Expected nothing nested below level 3, got %+v", reply.Replies)
+		}
+	}
+	if !equalIDs(ids, []int{level3.ID, level4.ID, sibling.ID}) {
+		t.Errorf("Expected levels 3 and 4 oldest first at level 3, got %v", ids)
+	}
+
+	if threads := store.GetComments(99); len(threads) != 0 {
+		t.Errorf("Expected no threads for a missing task, got %+v", threads)
+	}
+}
+
+func TestTaskCommentsHandlerReply(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("Design", "")
+	root, _, _ := s.AddComment(task.ID, "alice", "Which colour?")
+
+	post := func(form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/task/1/comments", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		NewServer(s).taskCommentsHandler(w, req, task.ID, nil)
+		return w
+	}
+
+	w := post(url.Values{"user": {"bob"}, "body": {"Blue"}, "parent_id": {"1"}})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "comment-replies") {
+		t.Fatalf("Expected the reply to render nested, got %d: %s", w.Code, w.Body.String())
+	}
+	if threads := s.GetComments(task.ID); len(threads) != 1 || len(threads[0].Replies) != 1 || threads[0].Replies[0].Body != "Blue" {
+		t.Errorf("Expected the reply under %d, got %+v", root.ID, threads)
+	}
+
+	for _, parent := range []string{"abc", "42"} {
+		if w := post(url.Values{"user": {"bob"}, "body": {"Hi"}, "parent_id": {parent}}); w.Code != http.StatusBadRequest {
+			t.Errorf("parent_id %q: expected 400, got %d", parent, w.Code)
+		}
+	}
+}
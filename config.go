@@ -0,0 +1,392 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config gathers the server's settings from kanban.yaml. Environment
+// variables take precedence over values in the file.
+type Config struct {
+	Server        ServerConfig        `yaml:"server"`
+	Storage       StorageConfig       `yaml:"storage"`
+	Features      FeaturesConfig      `yaml:"features"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+
+	// envErrors are the environment variables applyEnv couldn't parse,
+	// reported by ValidateConfig
+	envErrors []ConfigError
+}
+
+// ConfigError is one invalid setting: the config field or environment
+// variable, the value it had, and why it was rejected
+type ConfigError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e ConfigError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s (got %q)", e.Field, e.Reason, e.Value)
+}
+
+// ServerConfig configures the HTTP listener. Zero timeouts mean no timeout.
+type ServerConfig struct {
+	Addr         string        `yaml:"addr"`
+	Env          string        `yaml:"env"`       // "development" enables template live reload
+	LogLevel     string        `yaml:"log_level"` // lowest access log level: debug, info, warn or error
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	TLS          TLSConfig     `yaml:"tls"`
+
+	// MaxConns caps simultaneously open connections. Beyond it new
+	// connections wait up to AcceptTimeout (0 waits forever) for a free slot.
+	MaxConns      int           `yaml:"max_conns"`
+	AcceptTimeout time.Duration `yaml:"accept_timeout"`
+
+	// Notifications are sent by JobWorkers background workers, with up to
+	// JobQueueSize waiting. Beyond that they are dropped.
+	JobQueueSize int `yaml:"job_queue_size"`
+	JobWorkers   int `yaml:"job_workers"`
+}
+
+// TLSConfig enables HTTPS when both files are set
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+// StorageConfig selects where tasks are kept. Path is the JSON data file for
+// the file backend and the database file for sqlite; URL is used by redis and
+// postgres.
+type StorageConfig struct {
+	Backend     string `yaml:"backend"`
+	Path        string `yaml:"path"`
+	URL         string `yaml:"url"`
+	MaxConns    int    `yaml:"max_conns"` // postgres connection pool size
+	Partitioned bool   `yaml:"partitioned"`
+}
+
+// FeaturesConfig holds board behaviour settings
+type FeaturesConfig struct {
+	WIPLimits map[string]int      `yaml:"wip_limits"`
+	Workflow  map[string][]string `yaml:"workflow"`
+	RateLimit RateLimitConfig     `yaml:"rate_limit"`
+}
+
+// RateLimitConfig limits requests per client IP. Zero disables the limit.
+type RateLimitConfig struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+}
+
+// NotificationsConfig configures outgoing notifications
+type NotificationsConfig struct {
+	SMTP     SMTPConfig  `yaml:"smtp"`
+	Webhooks []string    `yaml:"webhooks"`
+	Slack    SlackConfig `yaml:"slack"`
+}
+
+// SlackConfig configures "task done" messages to a Slack incoming webhook.
+// They are off when WebhookURL is empty.
+type SlackConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+	BaseURL    string `yaml:"base_url"` // where the board is reachable, for task links
+}
+
+// SMTPConfig configures assignment emails. They are off when Host is empty.
+type SMTPConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	User string `yaml:"user"`
+	Pass string `yaml:"pass"`
+	From string `yaml:"from"`
+}
+
+// storageBackends lists the valid storage.backend values
+var storageBackends = []string{"file", "redis", "sqlite", "postgres"}
+
+// DefaultConfig returns the configuration used when no file is present
+func DefaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Addr:         ":8080",
+			LogLevel:     "info",
+			MaxConns:     defaultMaxConns,
+			JobQueueSize: defaultJobQueueSize,
+			JobWorkers:   defaultJobWorkers,
+		},
+		Storage:       StorageConfig{Backend: "file"},
+		Notifications: NotificationsConfig{SMTP: SMTPConfig{Port: 587}},
+	}
+}
+
+// getConfigFilePath returns KANBAN_CONFIG or ./kanban.yaml
+func getConfigFilePath() string {
+	if path := os.Getenv("KANBAN_CONFIG"); path != "" {
+		return path
+	}
+	return filepath.Join(".", "kanban.yaml")
+}
+
+// LoadConfig reads the config file at path and applies environment
+// overrides. A missing file leaves the defaults in place. Only a malformed
+// file is an error here; ValidateConfig checks the settings themselves.
+func LoadConfig(path string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	file, err := os.Open(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true)
+		err = decoder.Decode(cfg)
+		file.Close()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	cfg.applyEnv()
+	cfg.applyDefaults()
+	return cfg, nil
+}
+
+// applyEnv overrides file values with any environment variables that are
+// set. Values that don't parse are kept in envErrors for ValidateConfig.
+func (c *Config) applyEnv() {
+	invalid := func(key, value, reason string) {
+		c.envErrors = append(c.envErrors, ConfigError{Field: key, Value: value, Reason: reason})
+	}
+	setString := func(dst *string, key string) {
+		if v := os.Getenv(key); v != "" {
+			*dst = v
+		}
+	}
+	setInt := func(dst *int, key string) {
+		v := os.Getenv(key)
+		if v == "" {
+			return
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			invalid(key, v, "must be a whole number")
+			return
+		}
+		*dst = n
+	}
+	setDuration := func(dst *time.Duration, key string) {
+		v := os.Getenv(key)
+		if v == "" {
+			return
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			invalid(key, v, "must be a duration like 30s")
+			return
+		}
+		*dst = d
+	}
+
+	setString(&c.Server.Addr, "KANBAN_ADDR")
+	setString(&c.Server.Env, "KANBAN_ENV")
+	setString(&c.Server.LogLevel, "KANBAN_LOG_LEVEL")
+	setString(&c.Server.TLS.CertFile, "KANBAN_TLS_CERT_FILE")
+	setString(&c.Server.TLS.KeyFile, "KANBAN_TLS_KEY_FILE")
+	setInt(&c.Server.MaxConns, "KANBAN_MAX_CONNS")
+	setDuration(&c.Server.AcceptTimeout, "KANBAN_ACCEPT_TIMEOUT")
+	setInt(&c.Server.JobQueueSize, "KANBAN_JOB_QUEUE_SIZE")
+	setInt(&c.Server.JobWorkers, "KANBAN_JOB_WORKERS")
+
+	setString(&c.Storage.Backend, "KANBAN_BACKEND")
+	switch c.Storage.Backend {
+	case "file":
+		setString(&c.Storage.Path, "KANBAN_DATA_FILE")
+	case "sqlite":
+		setString(&c.Storage.Path, "KANBAN_SQLITE_PATH")
+	}
+	setString(&c.Storage.URL, "KANBAN_REDIS_URL")
+	if c.Storage.Backend == "postgres" {
+		setString(&c.Storage.URL, "KANBAN_POSTGRES_URL")
+	}
+	setInt(&c.Storage.MaxConns, "KANBAN_DB_MAX_CONNS")
+	if v := os.Getenv("KANBAN_PARTITION_STORAGE"); v != "" {
+		c.Storage.Partitioned = v == "true"
+	}
+
+	if raw := os.Getenv("KANBAN_WIP_LIMITS"); raw != "" {
+		if limits, err := LoadWIPLimits(); err != nil {
+			invalid("KANBAN_WIP_LIMITS", raw, err.Error())
+		} else {
+			c.Features.WIPLimits = limits
+		}
+	}
+	if raw := os.Getenv("KANBAN_WORKFLOW"); raw != "" {
+		if wf, err := ParseWorkflow([]byte(raw)); err != nil {
+			invalid("KANBAN_WORKFLOW", raw, err.Error())
+		} else {
+			c.Features.Workflow = wf.Transitions
+		}
+	}
+	setInt(&c.Features.RateLimit.RequestsPerMinute, "KANBAN_RATE_LIMIT")
+
+	smtpCfg := &c.Notifications.SMTP
+	setString(&smtpCfg.Host, "KANBAN_SMTP_HOST")
+	setInt(&smtpCfg.Port, "KANBAN_SMTP_PORT")
+	setString(&smtpCfg.User, "KANBAN_SMTP_USER")
+	setString(&smtpCfg.Pass, "KANBAN_SMTP_PASS")
+	setString(&smtpCfg.From, "KANBAN_SMTP_FROM")
+	if raw := os.Getenv("KANBAN_WEBHOOK_URLS"); raw != "" {
+		c.Notifications.Webhooks = strings.Split(raw, ",")
+	}
+	setString(&c.Notifications.Slack.WebhookURL, "KANBAN_SLACK_WEBHOOK_URL")
+	setString(&c.Notifications.Slack.BaseURL, "KANBAN_BASE_URL")
+}
+
+// applyDefaults fills in values that depend on other settings
+func (c *Config) applyDefaults() {
+	if c.Storage.Path == "" {
+		switch c.Storage.Backend {
+		case "file":
+			c.Storage.Path = filepath.Join(".", "tasks.json")
+		case "sqlite":
+			c.Storage.Path = "kanban.db"
+		}
+	}
+	if c.Storage.Backend == "redis" && c.Storage.URL == "" {
+		c.Storage.URL = "redis://localhost:6379/0"
+	}
+	if c.Notifications.SMTP.From == "" {
+		c.Notifications.SMTP.From = c.Notifications.SMTP.User
+	}
+	if c.Notifications.Slack.BaseURL == "" {
+		scheme := "http"
+		if c.Server.TLS.CertFile != "" {
+			scheme = "https"
+		}
+		c.Notifications.Slack.BaseURL = scheme + "://" + displayAddr(c.Server.Addr)
+	}
+}
+
+// ValidateConfig checks every setting, including that the TLS files can be
+// read, and returns all the problems found. main runs it before serving
+// anything.
+func ValidateConfig(c *Config) []ConfigError {
+	errs := append([]ConfigError(nil), c.envErrors...)
+	add := func(field string, value interface{}, reason string) {
+		errs = append(errs, ConfigError{Field: field, Value: fmt.Sprint(value), Reason: reason})
+	}
+
+	if _, port, err := net.SplitHostPort(c.Server.Addr); err != nil {
+		add("server.addr", c.Server.Addr, err.Error())
+	} else if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		add("server.addr", c.Server.Addr, "invalid port")
+	}
+	if _, err := parseLogLevel(c.Server.LogLevel); err != nil {
+		add("server.log_level", c.Server.LogLevel, "must be debug, info, warn or error")
+	}
+	timeouts := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"read_timeout", c.Server.ReadTimeout},
+		{"write_timeout", c.Server.WriteTimeout},
+		{"idle_timeout", c.Server.IdleTimeout},
+		{"accept_timeout", c.Server.AcceptTimeout},
+	}
+	for _, t := range timeouts {
+		if t.d < 0 {
+			add("server."+t.name, t.d, "must not be negative")
+		}
+	}
+	if c.Server.MaxConns < 1 {
+		add("server.max_conns", c.Server.MaxConns, "must be at least 1")
+	}
+	if c.Server.JobQueueSize < 1 {
+		add("server.job_queue_size", c.Server.JobQueueSize, "must be at least 1")
+	}
+	if c.Server.JobWorkers < 1 {
+		add("server.job_workers", c.Server.JobWorkers, "must be at least 1")
+	}
+	if tls := c.Server.TLS; (tls.CertFile == "") != (tls.KeyFile == "") {
+		add("server.tls", "", "cert_file and key_file must be set together")
+	} else if tls.CertFile != "" {
+		for _, f := range []struct{ field, path string }{
+			{"server.tls.cert_file", tls.CertFile},
+			{"server.tls.key_file", tls.KeyFile},
+		} {
+			if info, err := os.Stat(f.path); err != nil {
+				add(f.field, f.path, "cannot read file: "+err.Error())
+			} else if info.IsDir() {
+				add(f.field, f.path, "is a directory")
+			}
+		}
+	}
+
+	validBackend := false
+	for _, b := range storageBackends {
+		validBackend = validBackend || c.Storage.Backend == b
+	}
+	if !validBackend {
+		add("storage.backend", c.Storage.Backend, "unknown backend, valid options: "+strings.Join(storageBackends, ", "))
+	}
+	if c.Storage.Backend == "postgres" && c.Storage.URL == "" {
+		add("storage.url", "", "required by the postgres backend (KANBAN_POSTGRES_URL)")
+	}
+	if c.Storage.MaxConns < 0 {
+		add("storage.max_conns", c.Storage.MaxConns, "must not be negative")
+	}
+	if c.Storage.Partitioned && c.Storage.Backend != "file" {
+		add("storage.partitioned", c.Storage.Backend, "only supported by the file backend")
+	}
+
+	for status, limit := range c.Features.WIPLimits {
+		if limit < 0 {
+			add("features.wip_limits."+status, limit, "must not be negative")
+		}
+	}
+	if c.Features.RateLimit.RequestsPerMinute < 0 {
+		add("features.rate_limit.requests_per_minute", c.Features.RateLimit.RequestsPerMinute, "must not be negative")
+	}
+
+	if smtpCfg := c.Notifications.SMTP; smtpCfg.Host != "" && (smtpCfg.Port < 1 || smtpCfg.Port > 65535) {
+		add("notifications.smtp.port", smtpCfg.Port, "invalid port")
+	}
+	for _, raw := range c.Notifications.Webhooks {
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("notifications.webhooks", raw, "invalid URL")
+		}
+	}
+	if raw := c.Notifications.Slack.WebhookURL; raw != "" {
+		if u, err := url.Parse(raw); err != nil || u.Scheme != "https" || u.Host == "" {
+			add("notifications.slack.webhook_url", raw, "invalid URL, must be an https URL")
+		}
+	}
+	return errs
+}
+
+// parseLogLevel parses a server.log_level value
+func parseLogLevel(raw string) (slog.Level, error) {
+	var level slog.Level
+	switch strings.ToLower(raw) {
+	case "debug", "info", "warn", "error":
+		return level, level.UnmarshalText([]byte(raw))
+	}
+	return level, fmt.Errorf("unknown log level %q", raw)
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const fullConfigYAML = `
+server:
+  addr: "127.0.0.1:9090"
+  read_timeout: 5s
+  write_timeout: 10s
+  idle_timeout: 1m
+  tls:
+    cert_file: cert.pem
+    key_file: key.pem
+storage:
+  backend: sqlite
+  path: /var/lib/kanban/board.db
+features:
+  wip_limits:
+    doing: 3
+  workflow:
+    todo: [doing]
+    doing: [todo, done]
+    done: []
+  rate_limit:
+    requests_per_minute: 120
+notifications:
+  smtp:
+    host: smtp.example.com
+    port: 2525
+    user: bot@example.com
+  webhooks:
+    - https://hooks.example.com/kanban
+`
+
+func writeTestConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "kanban.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// clearConfigEnv unsets every variable LoadConfig reads for the test's duration
+func clearConfigEnv(t *testing.T) {
+	for _, key := range []string{
+		"KANBAN_ADDR", "KANBAN_ENV", "KANBAN_LOG_LEVEL", "KANBAN_MAX_CONNS", "KANBAN_ACCEPT_TIMEOUT", "KANBAN_JOB_QUEUE_SIZE", "KANBAN_JOB_WORKERS", "KANBAN_TLS_CERT_FILE", "KANBAN_TLS_KEY_FILE",
+		"KANBAN_BACKEND", "KANBAN_DATA_FILE", "KANBAN_SQLITE_PATH", "KANBAN_REDIS_URL", "KANBAN_POSTGRES_URL", "KANBAN_DB_MAX_CONNS", "KANBAN_PARTITION_STORAGE",
+		"KANBAN_WIP_LIMITS", "KANBAN_WORKFLOW", "KANBAN_RATE_LIMIT",
+		"KANBAN_SMTP_HOST", "KANBAN_SMTP_PORT", "KANBAN_SMTP_USER", "KANBAN_SMTP_PASS", "KANBAN_SMTP_FROM",
+		"KANBAN_WEBHOOK_URLS", "KANBAN_SLACK_WEBHOOK_URL", "KANBAN_BASE_URL",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestLoadConfigFullFile(t *testing.T) {
+	clearConfigEnv(t)
+	cfg, err := LoadConfig(writeTestConfig(t, fullConfigYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	srv := cfg.Server
+	if srv.Addr != "127.0.0.1:9090" || srv.ReadTimeout != 5*time.Second || srv.WriteTimeout != 10*time.Second || srv.IdleTimeout != time.Minute {
+		t.Errorf("Unexpected server config %+v", srv)
+	}
+	if srv.TLS.CertFile != "cert.pem" || srv.TLS.KeyFile != "key.pem" {
+		t.Errorf("Unexpected TLS config %+v", srv.TLS)
+	}
+	if cfg.Storage.Backend != "sqlite" || cfg.Storage.Path != "/var/lib/kanban/board.db" {
+		t.Errorf("Unexpected storage config %+v", cfg.Storage)
+	}
+	if cfg.Features.WIPLimits["doing"] != 3 || len(cfg.Features.Workflow["doing"]) != 2 || cfg.Features.RateLimit.RequestsPerMinute != 120 {
+		t.Errorf("Unexpected features config %+v", cfg.Features)
+	}
+	smtpCfg := cfg.Notifications.SMTP
+	if smtpCfg.Host != "smtp.example.com" || smtpCfg.Port != 2525 || smtpCfg.From != "bot@example.com" {
+		t.Errorf("Expected SMTP from to default to the user, got %+v", smtpCfg)
+	}
+	if len(cfg.Notifications.Webhooks) != 1 {
+		t.Errorf("Expected one webhook, got %v", cfg.Notifications.Webhooks)
+	}
+}
+
+func TestLoadConfigMissingFileUsesDefaults(t *testing.T) {
+	clearConfigEnv(t)
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Server.Addr != ":8080" || cfg.Server.MaxConns != defaultMaxConns || cfg.Storage.Backend != "file" || cfg.Storage.Path != "tasks.json" {
+		t.Errorf("Unexpected defaults %+v", cfg)
+	}
+	if cfg.Features.RateLimit.RequestsPerMinute != 0 || cfg.Notifications.SMTP.Host != "" {
+		t.Errorf("Expected rate limiting and email off by default, got %+v", cfg)
+	}
+}
+
+func TestLoadConfigEnvOverridesFile(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("KANBAN_ADDR", ":7000")
+	t.Setenv("KANBAN_SQLITE_PATH", "env.db")
+	t.Setenv("KANBAN_WIP_LIMITS", `{"doing":5}`)
+	t.Setenv("KANBAN_SMTP_PORT", "465")
+	cfg, err := LoadConfig(writeTestConfig(t, fullConfigYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Server.Addr != ":7000" || cfg.Storage.Path != "env.db" {
+		t.Errorf("Expected env to override addr and path, got %q and %q", cfg.Server.Addr, cfg.Storage.Path)
+	}
+	if cfg.Features.WIPLimits["doing"] != 5 || cfg.Notifications.SMTP.Port != 465 {
+		t.Errorf("Expected env to override WIP limits and SMTP port, got %+v", cfg)
+	}
+	if cfg.Server.ReadTimeout != 5*time.Second {
+		t.Errorf("File values without an env override must be kept")
+	}
+}
+
+func TestLoadConfigPostgresFromEnv(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("KANBAN_BACKEND", "postgres")
+	t.Setenv("KANBAN_POSTGRES_URL", "postgres://kanban@db/kanban")
+	t.Setenv("KANBAN_DB_MAX_CONNS", "25")
+	cfg, err := LoadConfig(writeTestConfig(t, ""))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Storage.URL != "postgres://kanban@db/kanban" || cfg.Storage.MaxConns != 25 {
+		t.Errorf("Expected the postgres URL and pool size from env, got %+v", cfg.Storage)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	clearConfigEnv(t)
+	for name, tc := range map[string]struct {
+		yaml string
+		want string
+	}{
+		"negative port":        {"server:\n  addr: \":-1\"\n", "server.addr"},
+		"port out of range":    {"server:\n  addr: \":70000\"\n", "server.addr"},
+		"negative timeout":     {"server:\n  read_timeout: -5s\n", "server.read_timeout"},
+		"zero max conns":       {"server:\n  max_conns: 0\n", "server.max_conns"},
+		"zero job workers":     {"server:\n  job_workers: 0\n", "server.job_workers"},
+		"bad log level":        {"server:\n  log_level: verbose\n", "server.log_level"},
+		"half tls":             {"server:\n  tls:\n    cert_file: c.pem\n", "server.tls"},
+		"missing cert file":    {"server:\n  tls:\n    cert_file: missing.pem\n    key_file: missing.key\n", "server.tls.cert_file"},
+		"unknown backend":      {"storage:\n  backend: mongo\n", "storage.backend"},
+		"postgres without url": {"storage:\n  backend: postgres\n", "storage.url"},
+		"negative wip limit":   {"features:\n  wip_limits:\n    doing: -1\n", "features.wip_limits.doing"},
+		"negative rate limit":  {"features:\n  rate_limit:\n    requests_per_minute: -1\n", "features.rate_limit.requests_per_minute"},
+		"bad smtp port":        {"notifications:\n  smtp:\n    host: h\n    port: 0\n", "notifications.smtp.port"},
+		"bad webhook":          {"notifications:\n  webhooks: [\"ftp://x\"]\n", "notifications.webhooks"},
+	} {
+		cfg, err := LoadConfig(writeTestConfig(t, tc.yaml))
+		if err != nil {
+			t.Fatalf("%s: LoadConfig failed: %v", name, err)
+		}
+		errs := ValidateConfig(cfg)
+		if len(errs) == 0 || errs[0].Field != tc.want {
+			t.Errorf("%s: expected a %s error, got %v", name, tc.want, errs)
+		}
+	}
+}
+
+func TestValidateConfigEnv(t *testing.T) {
+	for name, tc := range map[string]struct {
+		key, value string
+	}{
+		"workflow json":  {"KANBAN_WORKFLOW", `{"todo": [`},
+		"wip limits":     {"KANBAN_WIP_LIMITS", `{"doing": "three"}`},
+		"smtp port":      {"KANBAN_SMTP_PORT", "smtp"},
+		"accept timeout": {"KANBAN_ACCEPT_TIMEOUT", "soon"},
+		"log level":      {"KANBAN_LOG_LEVEL", "loud"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			clearConfigEnv(t)
+			t.Setenv(tc.key, tc.value)
+			cfg, err := LoadConfig(writeTestConfig(t, ""))
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			errs := ValidateConfig(cfg)
+			if len(errs) != 1 || errs[0].Value != tc.value {
+				t.Fatalf("Expected one error for %s, got %v", tc.key, errs)
+			}
+			if !strings.Contains(errs[0].Error(), errs[0].Field) || errs[0].Reason == "" {
+				t.Errorf("Expected the error to name the field and a reason, got %q", errs[0].Error())
+			}
+		})
+	}
+}
+
+func TestValidateConfigReportsEverything(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("KANBAN_WORKFLOW", "not json")
+	t.Setenv("KANBAN_SMTP_PORT", "abc")
+	cfg, err := LoadConfig(writeTestConfig(t, "server:\n  idle_timeout: -1s\nstorage:\n  backend: mongo\n"))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	var fields []string
+	for _, e := range ValidateConfig(cfg) {
+		fields = append(fields, e.Field)
+	}
+	want := []string{"KANBAN_WORKFLOW", "KANBAN_SMTP_PORT", "server.idle_timeout", "storage.backend"}
+	if strings.Join(fields, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected errors for %v, got %v", want, fields)
+	}
+}
+
+func TestValidateConfigAcceptsReadableTLSFiles(t *testing.T) {
+	clearConfigEnv(t)
+	dir := t.TempDir()
+	for _, name := range []string{"cert.pem", "key.pem"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("pem"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("KANBAN_TLS_CERT_FILE", filepath.Join(dir, "cert.pem"))
+	t.Setenv("KANBAN_TLS_KEY_FILE", filepath.Join(dir, "key.pem"))
+	cfg, err := LoadConfig(writeTestConfig(t, fullConfigYAML))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if errs := ValidateConfig(cfg); len(errs) != 0 {
+		t.Errorf("Expected a valid config, got %v", errs)
+	}
+}
+
+func TestLoadConfigUnknownKey(t *testing.T) {
+	clearConfigEnv(t)
+	_, err := LoadConfig(writeTestConfig(t, "server:\n  adress: \":80\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "adress") {
+		t.Errorf("Expected an error mentioning the unknown key, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// ErrNoCycleTime is returned for a task that has not gone through doing to
+// done
+var ErrNoCycleTime = errors.New("task has no cycle time")
+
+// cycleTime is the time from the task first entering doing to first
+// entering done after that. A task created in doing starts at its creation.
+func (t *Task) cycleTime() (time.Duration, error) {
+	initial := t.Status
+	if len(t.StatusHistory) > 0 {
+		initial = t.StatusHistory[0].FromStatus
+	}
+	var started *time.Time
+	if initial == "doing" {
+		started = &t.CreatedAt
+	}
+	for i := range t.StatusHistory {
+		tr := &t.StatusHistory[i]
+		switch {
+		case started == nil && tr.ToStatus == "doing":
+			started = &tr.At
+		case started != nil && tr.ToStatus == "done":
+			return tr.At.Sub(*started), nil
+		}
+	}
+	return 0, ErrNoCycleTime
+}
+
+// CycleTime returns how long a task took from first entering doing to first
+// entering done. It returns ErrTaskNotFound for a missing task and
+// ErrNoCycleTime if the task hasn't been through both.
+func (s *TaskStore) CycleTime(taskID int) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return 0, ErrTaskNotFound
+	}
+	return task.cycleTime()
+}
+
+// AverageCycleTime averages the cycle time of the tasks now in status,
+// usually "done", skipping those without one. It is zero if none have one.
+func (s *TaskStore) AverageCycleTime(status string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, avg := s.cycleTimes(status)
+	return avg
+}
+
+// cycleTimes returns the cycle time of each task in status that has one, by
+// task ID, and their average (must be called with lock held)
+func (s *TaskStore) cycleTimes(status string) (map[int]time.Duration, time.Duration) {
+	times := make(map[int]time.Duration)
+	var total time.Duration
+	for _, task := range s.tasks {
+		if task.Status != status {
+			continue
+		}
+		if d, err := task.cycleTime(); err == nil {
+			times[task.ID] = d
+			total += d
+		}
+	}
+	if len(times) == 0 {
+		return times, 0
+	}
+	return times, total / time.Duration(len(times))
+}
+
+// TaskCycleTime is one task's entry in a cycle time report
+type TaskCycleTime struct {
+	ID      int     `json:"id"`
+	Title   string  `json:"title"`
+	Seconds float64 `json:"cycle_time_seconds"`
+}
+
+// CycleTimeStats reports the cycle time of every task in a column that has
+// one, by ID, and their count, average, minimum and maximum in seconds
+type CycleTimeStats struct {
+	Status         string          `json:"status"`
+	Tasks          []TaskCycleTime `json:"tasks"`
+	Count          int             `json:"count"`
+	AverageSeconds float64         `json:"average_seconds"`
+	MinSeconds     float64         `json:"min_seconds"`
+	MaxSeconds     float64         `json:"max_seconds"`
+}
+
+// CycleTimes builds the cycle time report for the tasks now in status
+func (s *TaskStore) CycleTimes(status string) CycleTimeStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	times, avg := s.cycleTimes(status)
+	stats := CycleTimeStats{Status: status, Tasks: []TaskCycleTime{}, Count: len(times), AverageSeconds: avg.Seconds()}
+	for id, d := range times {
+		stats.Tasks = append(stats.Tasks, TaskCycleTime{ID: id, Title: s.tasks[id].Title, Seconds: d.Seconds()})
+	}
+	sort.Slice(stats.Tasks, func(i, j int) bool { return stats.Tasks[i].ID < stats.Tasks[j].ID })
+	for i, task := range stats.Tasks {
+		if i == 0 || task.Seconds < stats.MinSeconds {
+			stats.MinSeconds = task.Seconds
+		}
+		if task.Seconds > stats.MaxSeconds {
+			stats.MaxSeconds = task.Seconds
+		}
+	}
+	return stats
+}
+
+// cycleTimeHandler serves GET /api/v1/metrics/cycle-time, the cycle time
+// report for the tasks in ?status= (default done)
+func (srv *Server) cycleTimeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = "done"
+	}
+	if !isValidStatus(status) {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusOK, srv.CycleTimes(status))
+}
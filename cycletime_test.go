@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCycleTime(t *testing.T) {
+	s := newTestStore()
+	start := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	current := start
+	s.now = func() time.Time { return current }
+
+	bounced := s.AddTask("Bounced", "")
+	straight := s.AddTask("Straight", "")
+	skipped := s.AddTask("Skipped doing", "")
+	started, _ := s.CreateTask(TaskSpec{Title: "Started in doing", Status: "doing"})
+	pending := s.AddTask("Still doing", "")
+
+	current = start.Add(1 * time.Hour)
+	s.MoveTask(bounced.ID, "doing")
+	s.MoveTask(pending.ID, "doing")
+	current = start.Add(2 * time.Hour)
+	s.MoveTask(bounced.ID, "todo")
+	s.MoveTask(straight.ID, "doing")
+	s.MoveTask(skipped.ID, "done")
+	current = start.Add(5 * time.Hour)
+	s.MoveTask(bounced.ID, "doing")
+	s.MoveTask(straight.ID, "done")
+	current = start.Add(9 * time.Hour)
+	s.MoveTask(bounced.ID, "done")
+	s.MoveTask(started.ID, "done")
+	current = start.Add(12 * time.Hour)
+	s.MoveTask(bounced.ID, "doing")
+	s.MoveTask(bounced.ID, "done") // only the first arrival in done counts
+
+	for id, want := range map[int]time.Duration{
+		bounced.ID:  8 * time.Hour,
+		straight.ID: 3 * time.Hour,
+		started.ID:  9 * time.Hour,
+	} {
+		if got, err := s.CycleTime(id); err != nil || got != want {
+			t.Errorf("CycleTime(%d) = %v, %v; want %v", id, got, err, want)
+		}
+	}
+	for _, id := range []int{skipped.ID, pending.ID} {
+		if _, err := s.CycleTime(id); !errors.Is(err, ErrNoCycleTime) {
+			t.Errorf("CycleTime(%d): expected ErrNoCycleTime, got %v", id, err)
+		}
+	}
+	if _, err := s.CycleTime(99); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound for a missing task, got %v", err)
+	}
+
+	if got, want := s.AverageCycleTime("done"), (8+3+9)*time.Hour/3; got != want {
+		t.Errorf("AverageCycleTime(done) = %v, want %v", got, want)
+	}
+	if got := s.AverageCycleTime("todo"); got != 0 {
+		t.Errorf("Expected no average for a column without cycle times, got %v", got)
+	}
+}
+
+func TestCycleTimeHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	start := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	current := start
+	s.now = func() time.Time { return current }
+	a, _ := s.CreateTask(TaskSpec{Title: "A", Status: "doing"})
+	b, _ := s.CreateTask(TaskSpec{Title: "B", Status: "doing"})
+	s.AddTask("Never started", "")
+	current = start.Add(time.Hour)
+	s.MoveTask(a.ID, "done")
+	current = start.Add(3 * time.Hour)
+	s.MoveTask(b.ID, "done")
+
+	w := httptest.NewRecorder()
+	NewServer(s).cycleTimeHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/metrics/cycle-time", nil))
+	var stats CycleTimeStats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if stats.Status != "done" || stats.Count != 2 || len(stats.Tasks) != 2 {
+		t.Fatalf("Expected two done tasks, got %+v", stats)
+	}
+	if stats.Tasks[0].ID != a.ID || stats.Tasks[0].Seconds != 3600 || stats.Tasks[1].Seconds != 3*3600 {
+		t.Errorf("Unexpected per-task cycle times %+v", stats.Tasks)
+	}
+	if stats.AverageSeconds != 2*3600 || stats.MinSeconds != 3600 || stats.MaxSeconds != 3*3600 {
+		t.Errorf("Unexpected aggregates %+v", stats)
+	}
+
+	w = httptest.NewRecorder()
+	NewServer(s).cycleTimeHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/metrics/cycle-time?status=someday", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown status, got %d", w.Code)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const defaultDeadlineWarningHours = 48
+
+// loadDeadlineWarning reads KANBAN_DEADLINE_WARNING_HOURS, falling back to
+// the default
+func loadDeadlineWarning() time.Duration {
+	if raw := os.Getenv("KANBAN_DEADLINE_WARNING_HOURS"); raw != "" {
+		if hours, err := strconv.Atoi(raw); err == nil && hours >= 0 {
+			return time.Duration(hours) * time.Hour
+		}
+	}
+	return defaultDeadlineWarningHours * time.Hour
+}
+
+// deadlineWarning is how long before its due date a card is highlighted
+var deadlineWarning = loadDeadlineWarning()
+
+// DueStatus returns "overdue" for a task past its due date, "warning" for
+// one due within deadlineWarning, and "" otherwise. Done tasks are never
+// flagged.
+func (t *Task) DueStatus() string {
+	return t.dueStatus(time.Now(), deadlineWarning)
+}
+
+func (t *Task) dueStatus(now time.Time, window time.Duration) string {
+	switch {
+	case t.DueDate == nil || t.Status == "done":
+		return ""
+	case t.IsOverdue(now):
+		return "overdue"
+	case t.DueDate.Sub(now) <= window:
+		return "warning"
+	}
+	return ""
+}
+
+// GetTasksDueWithin returns the unfinished, unarchived tasks due in the next
+// d, soonest first. Overdue tasks are not included.
+func (s *TaskStore) GetTasksDueWithin(d time.Duration) []*Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock()
+	var tasks []*Task
+	for _, task := range s.tasks {
+		if task.ArchivedAt == nil && task.dueStatus(now, d) == "warning" {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].DueDate.Before(*tasks[j].DueDate) })
+	return tasks
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDueStatus(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		due := now.Add(d)
+		return &due
+	}
+	cases := []struct {
+		name string
+		task Task
+		want string
+	}{
+		{"no due date", Task{Status: "todo"}, ""},
+		{"overdue", Task{Status: "doing", DueDate: at(-time.Hour)}, "overdue"},
+		{"due now", Task{Status: "todo", DueDate: at(0)}, "warning"},
+		{"inside window", Task{Status: "todo", DueDate: at(47 * time.Hour)}, "warning"},
+		{"window edge", Task{Status: "todo", DueDate: at(48 * time.Hour)}, "warning"},
+		{"outside window", Task{Status: "todo", DueDate: at(49 * time.Hour)}, ""},
+		{"done and overdue", Task{Status: "done", DueDate: at(-time.Hour)}, ""},
+		{"done and due soon", Task{Status: "done", DueDate: at(time.Hour)}, ""},
+	}
+	for _, tc := range cases {
+		if got := tc.task.dueStatus(now, 48*time.Hour); got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestLoadDeadlineWarning(t *testing.T) {
+	t.Setenv("KANBAN_DEADLINE_WARNING_HOURS", "")
+	if d := loadDeadlineWarning(); d != 48*time.Hour {
+		t.Errorf("Expected the 48h default, got %v", d)
+	}
+	t.Setenv("KANBAN_DEADLINE_WARNING_HOURS", "12")
+	if d := loadDeadlineWarning(); d != 12*time.Hour {
+		t.Errorf("Expected 12h, got %v", d)
+	}
+	t.Setenv("KANBAN_DEADLINE_WARNING_HOURS", "soon")
+	if d := loadDeadlineWarning(); d != 48*time.Hour {
+		t.Errorf("Expected an invalid value to fall back to the default, got %v", d)
+	}
+}
+
+func TestGetTasksDueWithin(t *testing.T) {
+	s := newTestStore()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	due := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	s.CreateTask(TaskSpec{Title: "Later", DueDate: due(20 * time.Hour)})
+	s.CreateTask(TaskSpec{Title: "Soon", DueDate: due(2 * time.Hour)})
+	s.CreateTask(TaskSpec{Title: "Overdue", DueDate: due(-time.Hour)})
+	s.CreateTask(TaskSpec{Title: "Far", DueDate: due(72 * time.Hour)})
+	s.CreateTask(TaskSpec{Title: "Finished", Status: "done", DueDate: due(time.Hour)})
+	s.CreateTask(TaskSpec{Title: "Undated"})
+
+	tasks := s.GetTasksDueWithin(24 * time.Hour)
+	if len(tasks) != 2 || tasks[0].Title != "Soon" || tasks[1].Title != "Later" {
+		t.Errorf("Expected Soon then Later, got %v", taskIDs(tasks))
+	}
+}
+
+func TestColumnMarksDueCards(t *testing.T) {
+	s := withTestGlobals(t)
+	soon := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	s.CreateTask(TaskSpec{Title: "Soon", DueDate: &soon})
+	s.CreateTask(TaskSpec{Title: "Late", DueDate: &past})
+
+	w := httptest.NewRecorder()
+	NewServer(s).columnHandler(w, httptest.NewRequest(http.MethodGet, "/column/todo", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, `class="task-card due-warning" data-id="1"`) {
+		t.Errorf("Expected the card due soon to have due-warning")
+	}
+	if !strings.Contains(body, `class="task-card due-overdue" data-id="2"`) {
+		t.Errorf("Expected the overdue card to have due-overdue")
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// registerDebugHandlers serves the net/http/pprof profiles under
+// /debug/pprof/ when env is "development", and 404s there otherwise.
+// Importing net/http/pprof registers the same paths on
+// http.DefaultServeMux, so mux must sit in front of the default mux for the
+// 404s to hide them.
+func registerDebugHandlers(mux *http.ServeMux, env string) {
+	if env != "development" {
+		mux.HandleFunc("/debug/pprof/", http.NotFound)
+		return
+	}
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterDebugHandlers(t *testing.T) {
+	for _, tc := range []struct {
+		env  string
+		want int
+	}{
+		{"development", http.StatusOK},
+		{"production", http.StatusNotFound},
+		{"", http.StatusNotFound},
+	} {
+		mux := http.NewServeMux()
+		registerDebugHandlers(mux, tc.env)
+		// The pprof import also registers on the default mux, which must
+		// stay hidden behind the 404s
+		mux.Handle("/", http.DefaultServeMux)
+
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/goroutine?debug=1"} {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+			if rr.Code != tc.want {
+				t.Errorf("%q: expected %d for %s, got %d", tc.env, tc.want, path, rr.Code)
+			}
+		}
+	}
+}
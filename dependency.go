@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DependencyGraph mirrors the tasks' depends_on lists as an adjacency list.
+// The store updates it as tasks are created, changed and deleted rather than
+// rebuilding it from every task, and it caches a dependency order between
+// changes. Its methods must be called with the store's lock held.
+type DependencyGraph struct {
+	deps  map[int][]int // task ID to the IDs it depends on
+	order []int         // cached dependency order, nil when stale
+	pos   map[int]int   // each task's index in order
+	cycle bool          // the last sort found a cycle, until the next change
+}
+
+// reset rebuilds the graph from tasks, after they are replaced wholesale
+func (g *DependencyGraph) reset(tasks map[int]*Task) {
+	g.deps = make(map[int][]int, len(tasks))
+	for id, task := range tasks {
+		g.deps[id] = append([]int(nil), task.DependsOn...)
+	}
+	g.invalidate()
+}
+
+// add records a new task. Task IDs aren't reused, so nothing depends on it
+// yet and it can go last in the cached order.
+func (g *DependencyGraph) add(id int) {
+	if g.deps == nil {
+		g.deps = make(map[int][]int)
+	}
+	g.deps[id] = nil
+	if g.order != nil {
+		g.pos[id] = len(g.order)
+		g.order = append(g.order, id)
+	}
+}
+
+// remove drops a deleted task. Its place in the cached order is skipped
+// from then on, and dependencies on it are ignored like any on a missing
+// task. A cycle through it is gone, so a cycle found before must be
+// looked for again.
+func (g *DependencyGraph) remove(id int) {
+	delete(g.deps, id)
+	g.cycle = false
+}
+
+// set replaces the tasks id depends on. The cached order survives as long
+// as every dependency still comes before id in it.
+func (g *DependencyGraph) set(id int, deps []int) {
+	if g.deps == nil {
+		g.deps = make(map[int][]int)
+	}
+	g.deps[id] = append([]int(nil), deps...)
+	if g.cycle {
+		g.invalidate()
+	}
+	if g.order == nil {
+		return
+	}
+	at, ok := g.pos[id]
+	if !ok {
+		g.invalidate()
+		return
+	}
+	for _, dep := range deps {
+		if p, ok := g.pos[dep]; ok && p > at {
+			g.invalidate()
+			return
+		}
+	}
+}
+
+// invalidate drops the cached order
+func (g *DependencyGraph) invalidate() {
+	g.order, g.pos, g.cycle = nil, nil, false
+}
+
+// wouldCycle reports whether making id depend on deps would create a cycle,
+// that is whether id is one of deps or any of them already depends on id,
+// directly or not. The walk from each dependency is an iterative DFS sharing
+// one visited set, so tasks already shown not to lead to id are never walked
+// again. When the cached order is valid it bounds the walk too: dependencies
+// always come first, so a task before id in it can't depend on id.
+func (g *DependencyGraph) wouldCycle(id int, deps []int) bool {
+	bound, bounded := -1, false
+	if g.order != nil {
+		bound, bounded = g.pos[id]
+	}
+	visited := make(map[int]bool)
+	for _, dep := range deps {
+		if dep == id {
+			return true
+		}
+		stack := []int{dep}
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if current == id {
+				return true
+			}
+			if visited[current] {
+				continue
+			}
+			visited[current] = true
+			if bounded && g.pos[current] < bound {
+				continue
+			}
+			for _, next := range g.deps[current] {
+				if _, ok := g.deps[next]; ok && !visited[next] {
+					stack = append(stack, next)
+				}
+			}
+		}
+	}
+	return false
+}
+
+// TopologicalSort returns every task ID with each task after the tasks it
+// depends on, or ErrDependencyCycle if some tasks depend on each other.
+// Dependencies on missing tasks are ignored. The result is cached until the
+// graph changes in a way that breaks it.
+func (g *DependencyGraph) TopologicalSort() ([]int, error) {
+	if g.cycle {
+		return nil, ErrDependencyCycle
+	}
+	if g.order == nil && !g.sort() {
+		return nil, ErrDependencyCycle
+	}
+	order := make([]int, 0, len(g.deps))
+	for _, id := range g.order {
+		if _, ok := g.deps[id]; ok {
+			order = append(order, id)
+		}
+	}
+	return order, nil
+}
+
+// sort fills the cached order with an iterative DFS, reporting false and
+// remembering the cycle if it finds one. Tasks are visited in ID order so
+// the same graph always sorts the same way.
+func (g *DependencyGraph) sort() bool {
+	ids := make([]int, 0, len(g.deps))
+	for id := range g.deps {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	type frame struct {
+		id   int
+		next int // index of the next dependency to visit
+	}
+	order := make([]int, 0, len(ids))
+	done := make(map[int]bool, len(ids))
+	onStack := make(map[int]bool)
+	for _, root := range ids {
+		if done[root] {
+			continue
+		}
+		stack := []frame{{id: root}}
+		onStack[root] = true
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			deps := g.deps[top.id]
+			if top.next < len(deps) {
+				dep := deps[top.next]
+				top.next++
+				if _, ok := g.deps[dep]; !ok || done[dep] {
+					continue
+				}
+				if onStack[dep] {
+					g.cycle = true
+					return false
+				}
+				onStack[dep] = true
+				stack = append(stack, frame{id: dep})
+				continue
+			}
+			onStack[top.id] = false
+			done[top.id] = true
+			order = append(order, top.id)
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	g.order = order
+	g.pos = make(map[int]int, len(order))
+	for i, id := range order {
+		g.pos[id] = i
+	}
+	return true
+}
+
+// checkDependencies returns ErrUnknownDependency if any of deps is not a
+// task, and ErrDependencyCycle if making id depend on them would create a
+// cycle (must be called with lock held)
+func (s *TaskStore) checkDependencies(id int, deps []int) error {
+	for _, dep := range deps {
+		if _, ok := s.tasks[dep]; !ok {
+			return &ErrUnknownDependency{ID: dep}
+		}
+	}
+	if s.deps.wouldCycle(id, deps) {
+		return ErrDependencyCycle
+	}
+	return nil
+}
+
+// SetDependencies replaces the tasks a task depends on and returns it. It
+// returns ErrTaskNotFound if the task is missing, ErrUnknownDependency if one
+// of deps is, and ErrDependencyCycle if the task would end up depending on
+// itself, directly or not.
+func (s *TaskStore) SetDependencies(id int, deps []int) (*Task, error) {
+	span := s.startSpan(context.Background(), "SetDependencies", attribute.Int("task.id", id))
+	defer span.End()
+
+	return s.updateTask(id, func(task *Task) error {
+		if err := s.checkDependencies(id, deps); err != nil {
+			return err
+		}
+		if len(deps) == 0 {
+			task.DependsOn = nil
+		} else {
+			task.DependsOn = append([]int(nil), deps...)
+		}
+		return nil
+	})
+}
+
+// TopologicalSort returns every task ID ordered so that each task comes
+// after the tasks it depends on, or ErrDependencyCycle
+func (s *TaskStore) TopologicalSort() ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deps.TopologicalSort()
+}
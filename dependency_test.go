@@ -0,0 +1,257 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// checkDependencyOrder fails unless order lists every task once, each after
+// the tasks it depends on
+func checkDependencyOrder(t *testing.T, s *TaskStore, order []int) {
+	t.Helper()
+	pos := make(map[int]int, len(order))
+	for i, id := range order {
+		if _, dup := pos[id]; dup {
+			t.Fatalf("Task %d listed twice in %v", id, order)
+		}
+		pos[id] = i
+	}
+	if len(order) != len(s.tasks) {
+		t.Fatalf("Expected %d tasks in order, got %v", len(s.tasks), order)
+	}
+	for _, task := range s.tasks {
+		for _, dep := range task.DependsOn {
+			if p, ok := pos[dep]; ok && p > pos[task.ID] {
+				t.Errorf("Task %d comes before its dependency %d in %v", task.ID, dep, order)
+			}
+		}
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	s := newTestStore()
+	for i := 0; i < 5; i++ {
+		s.AddTask("Task", "")
+	}
+	s.SetDependencies(1, []int{3})
+	s.SetDependencies(3, []int{5, 4})
+	s.SetDependencies(2, []int{1})
+
+	order, err := s.TopologicalSort()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !equalIDs(order, []int{5, 4, 3, 1, 2}) {
+		t.Errorf("Expected [5 4 3 1 2], got %v", order)
+	}
+	if s.deps.order == nil {
+		t.Fatalf("Expected the order to be cached")
+	}
+
+	// A change the cached order already satisfies keeps it, one that
+	// breaks it drops it
+	s.SetDependencies(2, []int{1, 4})
+	if s.deps.order == nil {
+		t.Errorf("Expected a dependency on an earlier task to keep the cached order")
+	}
+	s.SetDependencies(5, []int{4})
+	if s.deps.order != nil {
+		t.Errorf("Expected a dependency on a later task to drop the cached order")
+	}
+	order, err = s.TopologicalSort()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkDependencyOrder(t, s, order)
+}
+
+func TestTopologicalSortKeepsUpWithChanges(t *testing.T) {
+	s := newTestStore()
+	for i := 0; i < 4; i++ {
+		s.AddTask("Task", "")
+	}
+	s.SetDependencies(1, []int{2})
+	s.TopologicalSort()
+
+	s.SetDependencies(2, []int{4})
+	s.AddTask("Later", "")
+	s.SetDependencies(5, []int{1})
+	s.DeleteTask(3)
+	order, err := s.TopologicalSort()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkDependencyOrder(t, s, order)
+}
+
+func TestSetDependenciesRejectsCycles(t *testing.T) {
+	s := newTestStore()
+	for i := 0; i < 4; i++ {
+		s.AddTask("Task", "")
+	}
+	s.SetDependencies(1, []int{2})
+	s.SetDependencies(2, []int{3})
+	s.TopologicalSort()
+
+	for _, tc := range []struct {
+		id   int
+		deps []int
+	}{
+		{1, []int{1}},
+		{3, []int{1}},
+		{3, []int{4, 2}},
+		{2, []int{1}},
+	} {
+		if _, err := s.SetDependencies(tc.id, tc.deps); !errors.Is(err, ErrDependencyCycle) {
+			t.Errorf("Expected task %d depending on %v to be a cycle, got %v", tc.id, tc.deps, err)
+		}
+	}
+	if task, _ := s.GetTask(3); task.DependsOn != nil {
+		t.Errorf("A refused change must leave the task alone, got %v", task.DependsOn)
+	}
+
+	if _, err := s.SetDependencies(4, []int{1}); err != nil {
+		t.Errorf("Expected task 4 to depend on task 1, got %v", err)
+	}
+	s.DeleteTask(2)
+	if _, err := s.SetDependencies(3, []int{1}); err != nil {
+		t.Errorf("Expected deleting task 2 to break the chain, got %v", err)
+	}
+	if _, err := s.SetDependencies(9, []int{1}); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestSetDependenciesRejectsUnknownTasks(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("First", "")
+	s.AddTask("Second", "")
+
+	var unknown *ErrUnknownDependency
+	if _, err := s.SetDependencies(2, []int{1, 7}); !errors.As(err, &unknown) || unknown.ID != 7 || !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrUnknownDependency for task 7, got %v", err)
+	}
+	if task, _ := s.GetTask(2); task.DependsOn != nil {
+		t.Errorf("A refused change must leave the task alone, got %v", task.DependsOn)
+	}
+
+	withTestFeatures(t, FeatureFlags{Dependencies: true})
+	w := putMergePatch("/api/v1/tasks/2", `{"depends_on":[7]}`)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "task 7") {
+		t.Errorf("Expected 400 naming task 7, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTopologicalSortReportsLoadedCycles(t *testing.T) {
+	s := newTestStore()
+	s.AddTask("First", "")
+	s.AddTask("Second", "")
+	s.tasks[1].DependsOn = []int{2}
+	s.tasks[2].DependsOn = []int{1}
+	s.deps.reset(s.tasks)
+
+	if _, err := s.TopologicalSort(); !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("Expected ErrDependencyCycle, got %v", err)
+	}
+	if _, err := s.SetDependencies(2, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if order, err := s.TopologicalSort(); err != nil || !equalIDs(order, []int{2, 1}) {
+		t.Errorf("Expected [2 1] once the cycle is broken, got %v, %v", order, err)
+	}
+}
+
+func TestMergePatchRejectsDependencyCycle(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("First", "")
+	s.AddTask("Second", "")
+	withTestFeatures(t, FeatureFlags{Dependencies: true})
+
+	if w := putMergePatch("/api/v1/tasks/2", `{"depends_on":[1]}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	w := putMergePatch("/api/v1/tasks/1", `{"depends_on":[2],"title":"Renamed"}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	if task, _ := s.GetTask(1); task.Title != "First" || task.DependsOn != nil {
+		t.Errorf("Expected the patch to be refused whole, got %+v", task)
+	}
+}
+
+// newDependencyBenchmarkStore returns n tasks where each depends on the
+// three before it
+func newDependencyBenchmarkStore(n int) *TaskStore {
+	s := newTestStore()
+	for i := 1; i <= n; i++ {
+		task := s.newTask(TaskSpec{Title: "Task"})
+		for dep := i - 3; dep < i; dep++ {
+			if dep >= 1 {
+				task.DependsOn = append(task.DependsOn, dep)
+			}
+		}
+	}
+	s.deps.reset(s.tasks)
+	return s
+}
+
+// naiveWouldCycle is the check the graph replaces: a DFS over the tasks
+// themselves from each new dependency, walking everything reachable
+func naiveWouldCycle(tasks map[int]*Task, id int, deps []int) bool {
+	visited := make(map[int]bool)
+	var reaches func(from int) bool
+	reaches = func(from int) bool {
+		if from == id {
+			return true
+		}
+		task, ok := tasks[from]
+		if !ok || visited[from] {
+			return false
+		}
+		visited[from] = true
+		for _, dep := range task.DependsOn {
+			if reaches(dep) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, dep := range deps {
+		if reaches(dep) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNaiveWouldCycleAgrees(t *testing.T) {
+	s := newDependencyBenchmarkStore(20)
+	s.TopologicalSort()
+	for _, tc := range [][2]int{{1, 20}, {20, 1}, {5, 6}, {6, 5}, {10, 10}} {
+		deps := []int{tc[1]}
+		if naive, memoized := naiveWouldCycle(s.tasks, tc[0], deps), s.deps.wouldCycle(tc[0], deps); naive != memoized {
+			t.Errorf("Task %d depending on %d: naive says %v, memoized %v", tc[0], tc[1], naive, memoized)
+		}
+	}
+}
+
+// Both benchmarks check the usual case on a 500-task chain: a new
+// dependency on a task far upstream, which can't form a cycle
+func BenchmarkWouldCycleNaive(b *testing.B) {
+	s := newDependencyBenchmarkStore(500)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		naiveWouldCycle(s.tasks, 500, []int{499, 1})
+	}
+}
+
+func BenchmarkWouldCycleMemoized(b *testing.B) {
+	s := newDependencyBenchmarkStore(500)
+	s.TopologicalSort()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.deps.wouldCycle(500, []int{499, 1})
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// templateSet holds the parsed page templates. In development mode they are
+// re-parsed when a file changes, so handlers read them through an atomic
+// pointer.
+type templateSet struct {
+	fsys    fs.FS
+	pattern string
+	current atomic.Pointer[template.Template]
+}
+
+// mustLoadTemplates parses the templates in fsys matching pattern, panicking
+// on error
+func mustLoadTemplates(fsys fs.FS, pattern string) *templateSet {
+	ts := &templateSet{fsys: fsys, pattern: pattern}
+	if err := ts.reload(); err != nil {
+		panic(err)
+	}
+	return ts
+}
+
+// reload re-parses the templates, keeping the old set if parsing fails
+func (ts *templateSet) reload() error {
+	t, err := template.New("").Funcs(template.FuncMap{
+		"formatAge":      FormatAge,
+		"feature":        func(name string) bool { return features.Get().Enabled(name) },
+		"aria":           AriaData,
+		"labelColor":     labelColor,
+		"labelTextColor": labelTextColor,
+		"formatBytes":    formatBytes,
+	}).ParseFS(ts.fsys, ts.pattern)
+	if err != nil {
+		return err
+	}
+	ts.current.Store(t)
+	return nil
+}
+
+// ExecuteTemplate renders the named template to w
+func (ts *templateSet) ExecuteTemplate(w io.Writer, name string, data interface{}) error {
+	return ts.current.Load().ExecuteTemplate(w, name, data)
+}
+
+// DevReloadServer watches a directory and tells connected browsers to reload
+// over server-sent events whenever a file in it changes
+type DevReloadServer struct {
+	watcher  *fsnotify.Watcher
+	onChange func() error // runs before clients are notified; an error skips the reload
+
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+// devReload is set when KANBAN_ENV=development
+var devReload *DevReloadServer
+
+// NewDevReloadServer starts watching dir. onChange may be nil.
+func NewDevReloadServer(dir string, onChange func() error) (*DevReloadServer, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("could not watch %s: %w", dir, err)
+	}
+	d := &DevReloadServer{watcher: watcher, onChange: onChange, clients: make(map[chan struct{}]struct{})}
+	go d.run()
+	return d, nil
+}
+
+// Close stops watching
+func (d *DevReloadServer) Close() error {
+	return d.watcher.Close()
+}
+
+// run handles watcher events until the watcher is closed
+func (d *DevReloadServer) run() {
+	for {
+		select {
+		case event, ok := <-d.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if d.onChange != nil {
+				if err := d.onChange(); err != nil {
+					log.Printf("Not reloading after %s changed: %v", event.Name, err)
+					continue
+				}
+			}
+			d.broadcast()
+		case err, ok := <-d.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("Template watcher error: %v", err)
+		}
+	}
+}
+
+// broadcast signals every client without blocking; a client with a reload
+// already pending doesn't need a second one
+func (d *DevReloadServer) broadcast() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for ch := range d.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (d *DevReloadServer) subscribe() chan struct{} {
+	ch := make(chan struct{}, 1)
+	d.mu.Lock()
+	d.clients[ch] = struct{}{}
+	d.mu.Unlock()
+	return ch
+}
+
+func (d *DevReloadServer) unsubscribe(ch chan struct{}) {
+	d.mu.Lock()
+	delete(d.clients, ch)
+	d.mu.Unlock()
+}
+
+// Subscribers returns how many pages are listening for reloads
+func (d *DevReloadServer) Subscribers() int {
+	if d == nil {
+		return 0
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.clients)
+}
+
+// ServeHTTP serves GET /dev/reload, an SSE stream sending "event: reload"
+// after each change
+func (d *DevReloadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := d.subscribe()
+	defer d.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-ch:
+			fmt.Fprint(w, "event: reload\ndata: changed\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
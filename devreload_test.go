@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDevReloadServerSendsReloadOnChange(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "page.html")
+	if err := os.WriteFile(page, []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changes := make(chan struct{}, 10)
+	d, err := NewDevReloadServer(dir, func() error {
+		changes <- struct{}{}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewDevReloadServer failed: %v", err)
+	}
+	defer d.Close()
+
+	srv := httptest.NewServer(d)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected an event stream, got %q", ct)
+	}
+
+	events := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "event: ") {
+				events <- line
+			}
+		}
+	}()
+
+	if err := os.WriteFile(page, []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case line := <-events:
+		if line != "event: reload" {
+			t.Errorf("Expected a reload event, got %q", line)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatalf("No reload event within 500ms")
+	}
+	if len(changes) == 0 {
+		t.Errorf("Expected onChange to run before the reload")
+	}
+}
+
+func TestTemplateSetReload(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "page.html")
+	os.WriteFile(page, []byte(`v1 {{formatAge .}}`), 0644)
+	ts := mustLoadTemplates(os.DirFS(dir), "*.html")
+
+	render := func() string {
+		var b strings.Builder
+		ts.ExecuteTemplate(&b, "page.html", 2*time.Hour)
+		return b.String()
+	}
+	if got := render(); got != "v1 2h" {
+		t.Errorf("Unexpected render %q", got)
+	}
+
+	os.WriteFile(page, []byte(`v2`), 0644)
+	if err := ts.reload(); err != nil || render() != "v2" {
+		t.Errorf("Expected the changed template after reload, got %q (%v)", render(), err)
+	}
+
+	os.WriteFile(page, []byte(`{{if}}`), 0644)
+	if err := ts.reload(); err == nil || render() != "v2" {
+		t.Errorf("A broken template must keep the previous set, got %q (%v)", render(), err)
+	}
+}
+
+func TestIndexIncludesReloadListenerOnlyInDevelopment(t *testing.T) {
+	withTestGlobals(t)
+	orig := devReload
+	t.Cleanup(func() { devReload = orig })
+
+	devReload = nil
+	w := httptest.NewRecorder()
+	NewServer(store).indexHandler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.Contains(w.Body.String(), "/dev/reload") {
+		t.Errorf("The reload listener must only be included in development")
+	}
+
+	devReload = &DevReloadServer{}
+	w = httptest.NewRecorder()
+	NewServer(store).indexHandler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(w.Body.String(), `sse-connect="/dev/reload"`) {
+		t.Errorf("Expected the reload listener in development")
+	}
+}
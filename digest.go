@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultDigestTime is when the daily digest goes out, in local time
+const defaultDigestTime = "08:00"
+
+// DigestColumn is one column's task count in a digest
+type DigestColumn struct {
+	Status      string
+	DisplayName string
+	Count       int
+}
+
+// DigestReport summarizes the board for the daily digest email
+type DigestReport struct {
+	GeneratedAt time.Time
+	Columns     []DigestColumn
+	Moved       []*Task // moved in the last 24 hours, most recent first
+	DueSoon     []*Task // not done and due within 48 hours, soonest first
+	Overdue     []*Task // not done and past due, most overdue first
+}
+
+// GenerateDailyDigest builds a digest of the board as it stands now
+func GenerateDailyDigest(store *TaskStore) DigestReport {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.clock()
+	report := DigestReport{GeneratedAt: now}
+	for _, col := range store.columnList() {
+		report.Columns = append(report.Columns, DigestColumn{Status: col.Status, DisplayName: store.columnName(col)})
+	}
+	for _, task := range store.tasks {
+		for i := range report.Columns {
+			if report.Columns[i].Status == task.Status {
+				report.Columns[i].Count++
+			}
+		}
+		if task.MovedAt != nil && now.Sub(*task.MovedAt) <= 24*time.Hour {
+			report.Moved = append(report.Moved, task.clone())
+		}
+		switch {
+		case task.IsOverdue(now):
+			report.Overdue = append(report.Overdue, task.clone())
+		case task.DueDate != nil && task.Status != "done" && task.DueDate.Sub(now) <= 48*time.Hour:
+			report.DueSoon = append(report.DueSoon, task.clone())
+		}
+	}
+	sort.Slice(report.Moved, func(i, j int) bool { return report.Moved[i].MovedAt.After(*report.Moved[j].MovedAt) })
+	byDueDate := func(tasks []*Task) {
+		sort.Slice(tasks, func(i, j int) bool { return tasks[i].DueDate.Before(*tasks[j].DueDate) })
+	}
+	byDueDate(report.DueSoon)
+	byDueDate(report.Overdue)
+	return report
+}
+
+// RenderDigestHTML renders a digest as an HTML email body
+func RenderDigestHTML(report DigestReport) string {
+	var body bytes.Buffer
+	if err := emailTemplates.ExecuteTemplate(&body, "daily-digest.html", report); err != nil {
+		log.Printf("Error rendering daily digest: %v", err)
+	}
+	return body.String()
+}
+
+// DigestSchedule says when the daily digest is sent and to whom
+type DigestSchedule struct {
+	Hour, Minute int
+	Recipients   []string
+}
+
+// LoadDigestSchedule reads KANBAN_DIGEST_TO, a comma-separated list of email
+// addresses, and KANBAN_DIGEST_TIME ("HH:MM", default 08:00). It returns nil
+// when there are no recipients.
+func LoadDigestSchedule() (*DigestSchedule, error) {
+	var recipients []string
+	for _, addr := range strings.Split(os.Getenv("KANBAN_DIGEST_TO"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			recipients = append(recipients, addr)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+
+	raw := os.Getenv("KANBAN_DIGEST_TIME")
+	if raw == "" {
+		raw = defaultDigestTime
+	}
+	at, err := time.Parse("15:04", raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KANBAN_DIGEST_TIME %q, want HH:MM", raw)
+	}
+	return &DigestSchedule{Hour: at.Hour(), Minute: at.Minute(), Recipients: recipients}, nil
+}
+
+// next returns the first send time after now, in now's location
+func (d *DigestSchedule) next(now time.Time) time.Time {
+	at := time.Date(now.Year(), now.Month(), now.Day(), d.Hour, d.Minute, 0, 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at
+}
+
+// sendDailyDigest emails the current digest to every recipient
+func sendDailyDigest(store *TaskStore, n *EmailNotifier, recipients []string) {
+	report := GenerateDailyDigest(store)
+	subject := "Daily board digest for " + report.GeneratedAt.Format("Jan 2, 2006")
+	body := RenderDigestHTML(report)
+	for _, to := range recipients {
+		if err := n.Notify(to, subject, body); err != nil {
+			log.Printf("Error emailing digest to %s: %v", to, err)
+		}
+	}
+}
+
+// run sends the digest at the scheduled time each day until
+// stop is closed
+func (d *DigestSchedule) run(store *TaskStore, n *EmailNotifier, stop <-chan struct{}) {
+	for {
+		timer := time.NewTimer(time.Until(d.next(time.Now())))
+		select {
+		case <-timer.C:
+			sendDailyDigest(store, n, d.Recipients)
+		case <-stop:
+			timer.Stop()
+			return
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateDailyDigest(t *testing.T) {
+	s := newTestStore()
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	current := start
+	s.now = func() time.Time { return current }
+	due := func(d time.Duration) *time.Time {
+		at := start.Add(d)
+		return &at
+	}
+
+	s.CreateTask(TaskSpec{Title: "Moved long ago"})
+	s.MoveTask(1, "doing")
+	current = start.Add(3 * 24 * time.Hour)
+	s.CreateTask(TaskSpec{Title: "Late report", DueDate: due(2 * 24 * time.Hour), Assignee: "dana"})
+	s.CreateTask(TaskSpec{Title: "Due tomorrow", DueDate: due(4 * 24 * time.Hour)})
+	s.CreateTask(TaskSpec{Title: "Due next week", DueDate: due(10 * 24 * time.Hour)})
+	s.CreateTask(TaskSpec{Title: "Shipped <v1>", DueDate: due(24 * time.Hour)})
+	s.MoveTask(5, "done")
+	current = current.Add(time.Hour)
+	s.MoveTask(4, "doing")
+
+	report := GenerateDailyDigest(s)
+	counts := map[string]int{}
+	for _, col := range report.Columns {
+		counts[col.Status] = col.Count
+	}
+	if counts["todo"] != 2 || counts["doing"] != 2 || counts["done"] != 1 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+	if !equalIDs(taskIDs(report.Moved), []int{4, 5}) || report.Moved[0].ID != 4 {
+		t.Errorf("Expected tasks 4 then 5 as moved, got %v", taskIDs(report.Moved))
+	}
+	if !equalIDs(taskIDs(report.DueSoon), []int{3}) {
+		t.Errorf("Expected task 3 due soon, got %v", taskIDs(report.DueSoon))
+	}
+	if !equalIDs(taskIDs(report.Overdue), []int{2}) {
+		t.Errorf("Expected task 2 overdue, got %v", taskIDs(report.Overdue))
+	}
+
+	html := RenderDigestHTML(report)
+	for _, want := range []string{"To Do: 2", "Late report", "Due tomorrow", "Shipped &lt;v1&gt;", "Due next week"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in the digest", want)
+		}
+	}
+	if strings.Contains(html, "Moved long ago") {
+		t.Errorf("Expected tasks moved more than a day ago to be left out")
+	}
+}
+
+func TestLoadDigestSchedule(t *testing.T) {
+	t.Setenv("KANBAN_DIGEST_TO", "")
+	if d, err := LoadDigestSchedule(); d != nil || err != nil {
+		t.Errorf("Expected no digest without recipients, got %+v, %v", d, err)
+	}
+
+	t.Setenv("KANBAN_DIGEST_TO", "a@example.com, b@example.com")
+	d, err := LoadDigestSchedule()
+	if err != nil || d.Hour != 8 || d.Minute != 0 || len(d.Recipients) != 2 {
+		t.Errorf("Expected an 08:00 digest to two recipients, got %+v, %v", d, err)
+	}
+
+	t.Setenv("KANBAN_DIGEST_TIME", "17:30")
+	d, _ = LoadDigestSchedule()
+	now := time.Date(2025, 3, 10, 18, 0, 0, 0, time.Local)
+	if next := d.next(now); !next.Equal(time.Date(2025, 3, 11, 17, 30, 0, 0, time.Local)) {
+		t.Errorf("Expected the next digest tomorrow at 17:30, got %v", next)
+	}
+	if next := d.next(now.Add(-time.Hour)); !next.Equal(time.Date(2025, 3, 10, 17, 30, 0, 0, time.Local)) {
+		t.Errorf("Expected the next digest today at 17:30, got %v", next)
+	}
+
+	t.Setenv("KANBAN_DIGEST_TIME", "8am")
+	if _, err := LoadDigestSchedule(); err == nil {
+		t.Errorf("Expected an invalid time to be rejected")
+	}
+}
+
+func TestSendDailyDigest(t *testing.T) {
+	addr, messages := startStubSMTP(t)
+	s := newTestStore()
+	s.AddTask("Write digest", "")
+
+	sendDailyDigest(s, &EmailNotifier{Addr: addr, Host: "127.0.0.1", From: "kanban@example.com"}, []string{"lead@example.com"})
+	msg := receiveMail(t, messages)
+	if msg.To[0] != "lead@example.com" || !strings.Contains(msg.Data, "Subject: Daily board digest") {
+		t.Errorf("Unexpected digest email %+v", msg)
+	}
+}
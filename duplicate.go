@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DuplicateTask copies a task's content into a new task in targetStatus. The
+// copy must be allowed by the workflow as a move from the original's status
+// and must fit the target column's WIP limit. It returns false if the
+// original task does not exist.
+func (s *TaskStore) DuplicateTask(id int, targetStatus string) (*Task, bool, error) {
+	span := s.startSpan(context.Background(), "DuplicateTask", attribute.Int("task.id", id), attribute.String("task.status", targetStatus))
+	defer span.End()
+
+	if !s.hasColumn(targetStatus) {
+		return nil, true, fmt.Errorf("%w %q", ErrInvalidStatus, targetStatus)
+	}
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, true, err
+	}
+	original, ok := s.tasks[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, false, nil
+	}
+	if err := s.workflow.checkTransition(original.Status, targetStatus); err != nil {
+		s.mu.Unlock()
+		return nil, true, err
+	}
+	if err := s.checkWIPLimit("", targetStatus); err != nil {
+		s.mu.Unlock()
+		return nil, true, err
+	}
+
+	source := original.clone()
+	task := s.newTask(TaskSpec{
+		Title:       source.Title,
+		Description: source.Description,
+		Status:      targetStatus,
+		Assignee:    source.Assignee,
+		Effort:      source.Effort,
+		Priority:    source.Priority,
+		DueDate:     source.DueDate,
+		Labels:      source.Labels,
+	})
+	s.persist(targetStatus)
+	created := task.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskCreated, Task: created, ToStatus: created.Status})
+	return task, true, nil
+}
+
+// taskDuplicateHandler copies a task into target_status and re-renders that
+// column
+func (srv *Server) taskDuplicateHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	target := r.FormValue("target_status")
+	if !isValidStatus(target) {
+		http.Error(w, "Invalid target status", http.StatusBadRequest)
+		return
+	}
+
+	_, ok, err := srv.DuplicateTask(id, target)
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	templates.ExecuteTemplate(w, "column-content.html", srv.GetColumnData(target))
+}
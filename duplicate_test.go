@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDuplicateTaskToEachStatus(t *testing.T) {
+	s := newTestStore()
+	due := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	original, _ := s.CreateTask(TaskSpec{
+		Title: "Original", Description: "Desc", Assignee: "alice",
+		Effort: 3, Priority: PriorityMedium, DueDate: &due, Labels: []string{"bug"},
+	})
+
+	for _, col := range boardColumns {
+		dup, ok, err := s.DuplicateTask(original.ID, col.Status)
+		if !ok || err != nil {
+			t.Fatalf("%s: unexpected result ok=%v err=%v", col.Status, ok, err)
+		}
+		if dup.ID == original.ID || dup.Status != col.Status {
+			t.Errorf("%s: expected a new task in %s, got ID %d in %s", col.Status, col.Status, dup.ID, dup.Status)
+		}
+		if dup.Title != "Original" || dup.Description != "Desc" || dup.Assignee != "alice" ||
+			dup.Effort != 3 || dup.Priority != PriorityMedium || !dup.DueDate.Equal(due) ||
+			len(dup.Labels) != 1 || dup.Labels[0] != "bug" {
+			t.Errorf("%s: fields not copied: %+v", col.Status, dup)
+		}
+	}
+
+	// The copy must not share mutable state with the original
+	dup, _ := s.GetTask(4)
+	dup.Labels[0] = "changed"
+	if original.Labels[0] != "bug" {
+		t.Errorf("Duplicate shares labels with the original")
+	}
+	if len(s.GetAllTasks()) != 4 {
+		t.Errorf("Expected 4 tasks, got %d", len(s.GetAllTasks()))
+	}
+}
+
+func TestDuplicateTaskErrors(t *testing.T) {
+	s := newTestStore()
+	s.workflow = &WorkflowConfig{Transitions: map[string][]string{"todo": {"doing"}}}
+	s.wipLimits = map[string]int{"doing": 1}
+	task := s.AddTask("A", "")
+
+	if _, ok, _ := s.DuplicateTask(99, "todo"); ok {
+		t.Errorf("Expected missing task to report not found")
+	}
+	if _, _, err := s.DuplicateTask(task.ID, "archived"); err == nil {
+		t.Errorf("Expected invalid status to be rejected")
+	}
+	var transitionErr *ErrTransitionNotAllowed
+	if _, _, err := s.DuplicateTask(task.ID, "done"); !errors.As(err, &transitionErr) {
+		t.Errorf("Expected workflow to reject todo -> done, got %v", err)
+	}
+
+	if _, _, err := s.DuplicateTask(task.ID, "doing"); err != nil {
+		t.Fatalf("First duplicate into doing should fit the limit: %v", err)
+	}
+	var wipErr *ErrWIPLimitReached
+	if _, _, err := s.DuplicateTask(task.ID, "doing"); !errors.As(err, &wipErr) {
+		t.Errorf("Expected WIP limit error, got %v", err)
+	}
+}
+
+func TestTaskDuplicateHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.wipLimits = map[string]int{"done": 1}
+	task := s.AddTask("Copy me", "")
+
+	post := func(path, target string) *httptest.ResponseRecorder {
+		form := url.Values{"target_status": {target}}
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		NewServer(s).taskRouter(w, req)
+		return w
+	}
+
+	w := post("/task/1/duplicate", "done")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Copy me") {
+		t.Errorf("Expected done column partial, got %d: %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "No tasks yet") {
+		t.Errorf("Response should render the target column only")
+	}
+	if w := post("/task/1/duplicate", "done"); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 when WIP limit is reached, got %d", w.Code)
+	}
+	if w := post("/task/1/duplicate", "nowhere"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid status, got %d", w.Code)
+	}
+	if w := post("/task/42/duplicate", "todo"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing task, got %d", w.Code)
+	}
+	if len(s.GetTasksByStatus("done")) != 1 || task.Status != "todo" {
+		t.Errorf("Expected a single copy in done and the original untouched")
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"log"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+//go:embed emails/*.html
+var emailFS embed.FS
+
+var emailTemplates = template.Must(template.ParseFS(emailFS, "emails/*.html"))
+
+// EmailNotifier sends notification emails over SMTP. A nil notifier is
+// valid and sends nothing.
+type EmailNotifier struct {
+	Addr string // host:port
+	Host string
+	User string
+	Pass string
+	From string
+
+	BaseURL string // board address used for task links, may be empty
+}
+
+// NewEmailNotifier builds a notifier from the SMTP config. It returns nil
+// when no host is set.
+func NewEmailNotifier(c SMTPConfig) *EmailNotifier {
+	if c.Host == "" {
+		return nil
+	}
+	return &EmailNotifier{
+		Addr: net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Host: c.Host,
+		User: c.User,
+		Pass: c.Pass,
+		From: c.From,
+	}
+}
+
+// Notify sends an HTML email
+func (n *EmailNotifier) Notify(to, subject, body string) error {
+	if n == nil {
+		return nil
+	}
+
+	var auth smtp.Auth
+	if n.User != "" {
+		auth = smtp.PlainAuth("", n.User, n.Pass, n.Host)
+	}
+	// Strip line breaks so the subject can't inject extra headers
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	return smtp.SendMail(n.Addr, auth, n.From, []string{to}, msg.Bytes())
+}
+
+// notifyAssignee emails a task's new assignee. Assignees that are not email
+// addresses are skipped.
+func (n *EmailNotifier) notifyAssignee(ctx context.Context, e Event) {
+	if n == nil || e.Task == nil {
+		return
+	}
+	addr, err := mail.ParseAddress(e.Task.Assignee)
+	if err != nil {
+		return
+	}
+
+	previous := ""
+	for _, change := range e.Changes {
+		if change.Field == "assignee" {
+			previous = change.Old
+		}
+	}
+	var body bytes.Buffer
+	if err := emailTemplates.ExecuteTemplate(&body, "task-assigned.html", map[string]interface{}{
+		"Task":     e.Task,
+		"Previous": previous,
+	}); err != nil {
+		log.Printf("Error rendering assignment email: %v", err)
+		return
+	}
+
+	subject := "You've been assigned: " + e.Task.Title
+	if ctx.Err() != nil {
+		return
+	}
+	if err := n.Notify(addr.Address, subject, body.String()); err != nil {
+		log.Printf("Error emailing %s: %v", addr.Address, err)
+	}
+}
+
+// notifyWatchers emails each of a changed task's watchers, except the one
+// who made the change. Watchers that are not email addresses are skipped.
+func (n *EmailNotifier) notifyWatchers(ctx context.Context, e Event) {
+	if n == nil || e.Task == nil {
+		return
+	}
+	var body bytes.Buffer
+	if err := emailTemplates.ExecuteTemplate(&body, "task-watched.html", e); err != nil {
+		log.Printf("Error rendering watcher email: %v", err)
+		return
+	}
+	subject := "Task updated: " + e.Task.Title
+	if e.ToStatus != "" {
+		subject = "Task moved to " + e.ToStatus + ": " + e.Task.Title
+	}
+	for _, watcher := range e.Watchers {
+		addr, err := mail.ParseAddress(watcher)
+		if err != nil || strings.EqualFold(watcher, e.Actor) {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err := n.Notify(addr.Address, subject, body.String()); err != nil {
+			log.Printf("Error emailing %s: %v", addr.Address, err)
+		}
+	}
+}
+
+// notifyIdle reminds an idle task's assignee about it. Assignees that are
+// not email addresses are skipped.
+func (n *EmailNotifier) notifyIdle(ctx context.Context, e Event) {
+	if n == nil || e.Task == nil {
+		return
+	}
+	addr, err := mail.ParseAddress(e.Task.Assignee)
+	if err != nil {
+		return
+	}
+	var body bytes.Buffer
+	if err := emailTemplates.ExecuteTemplate(&body, "task-idle.html", e.Task); err != nil {
+		log.Printf("Error rendering idle task email: %v", err)
+		return
+	}
+	subject := "Still working on it? " + e.Task.Title
+	if ctx.Err() != nil {
+		return
+	}
+	if err := n.Notify(addr.Address, subject, body.String()); err != nil {
+		log.Printf("Error emailing %s: %v", addr.Address, err)
+	}
+}
+
+// notifyMention emails a user @-mentioned in a comment, unless they wrote
+// it. Mentions that are not email addresses are skipped.
+func (n *EmailNotifier) notifyMention(ctx context.Context, e Event) {
+	if n == nil || e.Task == nil || e.Comment == nil {
+		return
+	}
+	addr, err := mail.ParseAddress(e.Mentioned)
+	if err != nil || strings.EqualFold(e.Mentioned, e.Actor) {
+		return
+	}
+	var body bytes.Buffer
+	if err := emailTemplates.ExecuteTemplate(&body, "comment-mention.html", map[string]interface{}{
+		"Task":    e.Task,
+		"Comment": e.Comment,
+		"Link":    fmt.Sprintf("%s/task/%d", n.BaseURL, e.Task.ID),
+	}); err != nil {
+		log.Printf("Error rendering mention email: %v", err)
+		return
+	}
+	subject := e.Comment.Author + " mentioned you on " + e.Task.Title
+	if ctx.Err() != nil {
+		return
+	}
+	if err := n.Notify(addr.Address, subject, body.String()); err != nil {
+		log.Printf("Error emailing %s: %v", addr.Address, err)
+	}
+}
+
+// subscribeEmailNotifier sends assignment, watcher, idle task and mention
+// emails as background jobs so slow SMTP servers don't hold up requests.
+// Emails not yet sent when a job is cancelled are skipped. A nil notifier
+// subscribes nothing.
+func subscribeEmailNotifier(b *EventBus, n *EmailNotifier) {
+	if n == nil {
+		return
+	}
+	background := func(name string, notify func(context.Context, Event)) func(Event) {
+		return func(e Event) {
+			runInBackground(name, func(ctx context.Context) error {
+				notify(ctx, e)
+				return ctx.Err()
+			})
+		}
+	}
+	b.Subscribe(EventTaskAssigned, background("assignment email", n.notifyAssignee))
+	b.Subscribe(EventTaskWatched, background("watcher email", n.notifyWatchers))
+	b.Subscribe(EventTaskIdle, background("idle task email", n.notifyIdle))
+	b.Subscribe(EventCommentMention, background("mention email", n.notifyMention))
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpMessage is one message captured by the stub server
+type smtpMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// startStubSMTP accepts connections on a local port and speaks just enough
+// SMTP for net/smtp.SendMail, sending each captured message on the channel
+func startStubSMTP(t *testing.T) (string, <-chan smtpMessage) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan smtpMessage, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveStubSMTP(conn, messages)
+		}
+	}()
+	return ln.Addr().String(), messages
+}
+
+func serveStubSMTP(conn net.Conn, messages chan<- smtpMessage) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 stub ESMTP")
+	var msg smtpMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-stub")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "AUTH"):
+			reply("235 ok")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg = smtpMessage{From: strings.Trim(line[len("MAIL FROM:"):], "<>")}
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			msg.To = append(msg.To, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 ok")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			msg.Data = data.String()
+			messages <- msg
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func receiveMail(t *testing.T, messages <-chan smtpMessage) smtpMessage {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for email")
+		return smtpMessage{}
+	}
+}
+
+func TestEmailNotifierNotify(t *testing.T) {
+	addr, messages := startStubSMTP(t)
+	n := &EmailNotifier{Addr: addr, Host: "127.0.0.1", User: "kanban@example.com", Pass: "secret", From: "kanban@example.com"}
+
+	if err := n.Notify("dana@example.com", "Hello\r\nBcc: evil@example.com", "<p>Hi</p>"); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	msg := receiveMail(t, messages)
+	if msg.From != "kanban@example.com" || len(msg.To) != 1 || msg.To[0] != "dana@example.com" {
+		t.Errorf("Unexpected envelope %+v", msg)
+	}
+	if !strings.Contains(msg.Data, "Subject: Hello  Bcc: evil@example.com\r\n") {
+		t.Errorf("Subject line breaks must be stripped, got %q", msg.Data)
+	}
+	if !strings.Contains(msg.Data, "Content-Type: text/html") || !strings.HasSuffix(msg.Data, "<p>Hi</p>\r\n") {
+		t.Errorf("Expected HTML body, got %q", msg.Data)
+	}
+}
+
+func TestAssignmentSendsEmail(t *testing.T) {
+	addr, messages := startStubSMTP(t)
+	s := withTestGlobals(t)
+	subscribeEmailNotifier(bus, &EmailNotifier{Addr: addr, Host: "127.0.0.1", From: "kanban@example.com"})
+
+	task := s.AddTask("Fix <login>", "Users can't sign in")
+	s.AssignTask(task.ID, "Dana <dana@example.com>")
+
+	msg := receiveMail(t, messages)
+	if msg.To[0] != "dana@example.com" {
+		t.Errorf("Expected mail to dana@example.com, got %v", msg.To)
+	}
+	if !strings.Contains(msg.Data, "Subject: You've been assigned: Fix <login>") {
+		t.Errorf("Unexpected subject in %q", msg.Data)
+	}
+	if !strings.Contains(msg.Data, "Fix &lt;login&gt;") || !strings.Contains(msg.Data, "Users can&#39;t sign in") {
+		t.Errorf("Expected escaped task details in body, got %q", msg.Data)
+	}
+
+	// Plain names and unassignment don't send mail
+	s.AssignTask(task.ID, "dana")
+	s.AssignTask(task.ID, "")
+	select {
+	case msg := <-messages:
+		t.Errorf("Unexpected email %+v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestNewEmailNotifier(t *testing.T) {
+	if n := NewEmailNotifier(SMTPConfig{Port: 587}); n != nil {
+		t.Errorf("Expected nil notifier without an SMTP host")
+	}
+	var n *EmailNotifier
+	if err := n.Notify("a@example.com", "s", "b"); err != nil {
+		t.Errorf("Nil notifier should be a no-op, got %v", err)
+	}
+
+	n = NewEmailNotifier(SMTPConfig{Host: "smtp.example.com", Port: 587, From: "bot@example.com"})
+	if n == nil || n.Addr != "smtp.example.com:587" || n.From != "bot@example.com" {
+		t.Errorf("Unexpected notifier %+v", n)
+	}
+}
+
+func TestIdleTaskSendsEmail(t *testing.T) {
+	addr, messages := startStubSMTP(t)
+	s := withTestGlobals(t)
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	current := start
+	s.now = func() time.Time { return current }
+	subscribeEmailNotifier(bus, &EmailNotifier{Addr: addr, Host: "127.0.0.1", From: "kanban@example.com"})
+
+	s.CreateTask(TaskSpec{Title: "Renew certificates", Assignee: "Dana <dana@example.com>"})
+	s.CreateTask(TaskSpec{Title: "Tidy wiki", Assignee: "erin"})
+	current = start.Add(4 * 24 * time.Hour)
+	s.CheckIdleTasks(72 * time.Hour)
+
+	msg := receiveMail(t, messages)
+	if msg.To[0] != "dana@example.com" {
+		t.Errorf("Expected mail to dana@example.com, got %v", msg.To)
+	}
+	if !strings.Contains(msg.Data, "Subject: Still working on it? Renew certificates") {
+		t.Errorf("Unexpected subject in %q", msg.Data)
+	}
+	if !strings.Contains(msg.Data, "hasn't been updated since Mar 3, 2025") {
+		t.Errorf("Expected the last update date in the body, got %q", msg.Data)
+	}
+	select {
+	case msg := <-messages:
+		t.Errorf("Expected no mail for a plain-name assignee, got %+v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCommentMentionSendsEmail(t *testing.T) {
+	addr, messages := startStubSMTP(t)
+	s := withTestGlobals(t)
+	subscribeEmailNotifier(bus, &EmailNotifier{Addr: addr, Host: "127.0.0.1", From: "kanban@example.com", BaseURL: "https://kanban.example.com"})
+
+	task := s.AddTask("Renew certificates", "")
+	s.AddComment(task.ID, "alice", "@dana@example.com @erin please check the expiry dates")
+
+	msg := receiveMail(t, messages)
+	if msg.To[0] != "dana@example.com" {
+		t.Errorf("Expected mail to dana@example.com, got %v", msg.To)
+	}
+	if !strings.Contains(msg.Data, "Subject: alice mentioned you on Renew certificates") {
+		t.Errorf("Unexpected subject in %q", msg.Data)
+	}
+	if !strings.Contains(msg.Data, "please check the expiry dates") || !strings.Contains(msg.Data, `href="https://kanban.example.com/task/1"`) {
+		t.Errorf("Expected the comment and a task link in the body, got %q", msg.Data)
+	}
+	select {
+	case msg := <-messages:
+		t.Errorf("Expected no mail for a plain-name mention, got %+v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// encryptedPrefix marks a stored field as ciphertext, so files written
+// before encryption was switched on still load
+const encryptedPrefix = "enc:"
+
+var (
+	errNoEncryptKey = errors.New("data file has encrypted fields but KANBAN_ENCRYPT_KEY is not set")
+	errDecryptField = errors.New("could not decrypt field: wrong key or corrupted data")
+)
+
+// LoadEncryptKey reads KANBAN_ENCRYPT_KEY, a hex-encoded 32-byte AES-256
+// key. A nil key means task fields are stored in plaintext.
+func LoadEncryptKey() ([]byte, error) {
+	raw := os.Getenv("KANBAN_ENCRYPT_KEY")
+	if raw == "" {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(raw)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("KANBAN_ENCRYPT_KEY must be 64 hex characters (a 32-byte key)")
+	}
+	return key, nil
+}
+
+// encryptField seals plaintext with AES-GCM under key, returning the random
+// nonce followed by the ciphertext, base64-encoded
+func encryptField(plaintext, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptField reverses encryptField
+func decryptField(ciphertext string, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, errDecryptField
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errDecryptField
+	}
+	nonce, sealed := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, errDecryptField
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptTasks returns copies of tasks with their title and description
+// encrypted, leaving the originals in plaintext
+func encryptTasks(tasks map[int]*Task, key []byte) (map[int]*Task, error) {
+	encrypted := make(map[int]*Task, len(tasks))
+	for id, task := range tasks {
+		c := task.clone()
+		for _, field := range []*string{&c.Title, &c.Description} {
+			sealed, err := encryptField([]byte(*field), key)
+			if err != nil {
+				return nil, err
+			}
+			*field = encryptedPrefix + sealed
+		}
+		encrypted[id] = c
+	}
+	return encrypted, nil
+}
+
+// decryptTasks decrypts the title and description of loaded tasks in place.
+// Fields without the enc: prefix are left as they are.
+func decryptTasks(tasks map[int]*Task, key []byte) error {
+	for _, task := range tasks {
+		for _, field := range []*string{&task.Title, &task.Description} {
+			if !strings.HasPrefix(*field, encryptedPrefix) {
+				continue
+			}
+			if key == nil {
+				return errNoEncryptKey
+			}
+			plaintext, err := decryptField(strings.TrimPrefix(*field, encryptedPrefix), key)
+			if err != nil {
+				return fmt.Errorf("task %d: %w", task.ID, err)
+			}
+			*field = string(plaintext)
+		}
+	}
+	return nil
+}
+
+// storedTasks returns the tasks as they should be written to disk,
+// encrypted if the store has a key (must be called with lock held)
+func (s *TaskStore) storedTasks() (map[int]*Task, error) {
+	if s.encryptKey == nil {
+		return s.tasks, nil
+	}
+	return encryptTasks(s.tasks, s.encryptKey)
+}
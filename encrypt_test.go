@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testEncryptKey = bytes.Repeat([]byte{0x42}, 32)
+
+func TestEncryptFieldRoundTrip(t *testing.T) {
+	sealed, err := encryptField([]byte("Salary review for Ana"), testEncryptKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sealed, "Salary") {
+		t.Errorf("Ciphertext contains the plaintext: %s", sealed)
+	}
+	again, _ := encryptField([]byte("Salary review for Ana"), testEncryptKey)
+	if again == sealed {
+		t.Errorf("Expected a fresh nonce for each encryption")
+	}
+
+	plaintext, err := decryptField(sealed, testEncryptKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "Salary review for Ana" {
+		t.Errorf("Expected the original value, got %q", plaintext)
+	}
+}
+
+func TestDecryptFieldWrongKey(t *testing.T) {
+	sealed, _ := encryptField([]byte("secret"), testEncryptKey)
+	wrong := bytes.Repeat([]byte{0x17}, 32)
+	if _, err := decryptField(sealed, wrong); !errors.Is(err, errDecryptField) {
+		t.Errorf("Expected errDecryptField, got %v", err)
+	}
+	if _, err := decryptField("not base64!", testEncryptKey); !errors.Is(err, errDecryptField) {
+		t.Errorf("Expected errDecryptField for malformed input, got %v", err)
+	}
+}
+
+func TestLoadEncryptKey(t *testing.T) {
+	t.Setenv("KANBAN_ENCRYPT_KEY", "")
+	if key, err := LoadEncryptKey(); key != nil || err != nil {
+		t.Errorf("Expected no key when unset, got %v, %v", key, err)
+	}
+
+	t.Setenv("KANBAN_ENCRYPT_KEY", strings.Repeat("ab", 32))
+	key, err := LoadEncryptKey()
+	if err != nil || len(key) != 32 {
+		t.Errorf("Expected a 32-byte key, got %d bytes, %v", len(key), err)
+	}
+
+	for _, bad := range []string{"abcd", strings.Repeat("zz", 32)} {
+		t.Setenv("KANBAN_ENCRYPT_KEY", bad)
+		if _, err := LoadEncryptKey(); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+func TestEncryptedDataFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	s := &TaskStore{tasks: make(map[int]*Task), nextID: 1, filePath: path, encryptKey: testEncryptKey}
+	task := s.AddTask("Acquisition plan", "Offer 2M for Initech")
+
+	if task.Title != "Acquisition plan" {
+		t.Errorf("Expected in-memory title to stay plaintext, got %q", task.Title)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("Acquisition")) || bytes.Contains(raw, []byte("Initech")) {
+		t.Errorf("Stored JSON contains plaintext:\n%s", raw)
+	}
+	if !bytes.Contains(raw, []byte(`"enc:`)) {
+		t.Errorf("Expected enc: tagged fields in stored JSON:\n%s", raw)
+	}
+
+	loaded := &TaskStore{tasks: make(map[int]*Task), nextID: 1, filePath: path, encryptKey: testEncryptKey}
+	if err := loaded.LoadFromFile(); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := loaded.GetTask(task.ID)
+	if !ok || got.Title != "Acquisition plan" || got.Description != "Offer 2M for Initech" {
+		t.Errorf("Expected decrypted task after reload, got %+v", got)
+	}
+
+	noKey := &TaskStore{tasks: make(map[int]*Task), nextID: 1, filePath: path}
+	if err := noKey.LoadFromFile(); !errors.Is(err, errNoEncryptKey) {
+		t.Errorf("Expected errNoEncryptKey without a key, got %v", err)
+	}
+}
+
+func TestEncryptedStoreLoadsPlaintextFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	plain := &TaskStore{tasks: make(map[int]*Task), nextID: 1, filePath: path}
+	plain.AddTask("Old task", "Written before encryption")
+
+	s := &TaskStore{tasks: make(map[int]*Task), nextID: 1, filePath: path, encryptKey: testEncryptKey}
+	if err := s.LoadFromFile(); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := s.GetTask(1); !ok || got.Title != "Old task" {
+		t.Errorf("Expected the plaintext task to load, got %+v", got)
+	}
+}
+
+func TestEncryptedPartitions(t *testing.T) {
+	dir := t.TempDir()
+	s := &TaskStore{tasks: make(map[int]*Task), nextID: 1, partitions: NewColumnStore(dir), encryptKey: testEncryptKey}
+	s.AddTask("Partitioned secret", "")
+
+	raw, err := os.ReadFile(filepath.Join(dir, "todo.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("Partitioned secret")) {
+		t.Errorf("Partition file contains plaintext:\n%s", raw)
+	}
+
+	loaded := &TaskStore{tasks: make(map[int]*Task), nextID: 1, partitions: NewColumnStore(dir), encryptKey: testEncryptKey}
+	if err := loaded.LoadFromFile(); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := loaded.GetTask(1); !ok || got.Title != "Partitioned secret" {
+		t.Errorf("Expected decrypted partitioned task, got %+v", got)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors shared across the store. Functions wrap them with context
+// (fmt.Errorf("%w ...")) or return a typed error that matches them, so
+// callers test with errors.Is rather than comparing messages.
+var (
+	ErrTaskNotFound     = errors.New("task not found")
+	ErrInvalidStatus    = errors.New("invalid status")
+	ErrWIPLimitExceeded = errors.New("wip limit reached") // matched by *ErrWIPLimitReached
+	ErrVersionConflict  = errors.New("task was changed since it was read")
+	ErrCircularSubtask  = errors.New("a task cannot become a subtask of itself or of its own subtasks")
+	ErrDependencyCycle  = errors.New("task dependencies would form a cycle")
+)
+
+// ErrValidation reports invalid input, with a message per offending field
+type ErrValidation struct {
+	Fields map[string]string
+}
+
+// fieldError returns an ErrValidation for a single field
+func fieldError(field, format string, args ...interface{}) *ErrValidation {
+	return &ErrValidation{Fields: map[string]string{field: fmt.Sprintf(format, args...)}}
+}
+
+func (e *ErrValidation) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = e.Fields[field]
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ErrBoardLocked is returned by changes refused while the board is locked,
+// see LockBoard
+type ErrBoardLocked struct {
+	Reason string
+}
+
+func (e *ErrBoardLocked) Error() string { return "board is locked: " + e.Reason }
+
+// ErrUnknownDependency is returned when a task is made to depend on a task
+// that doesn't exist. It matches ErrTaskNotFound but is reported as a 400,
+// since the task being changed was found.
+type ErrUnknownDependency struct {
+	ID int
+}
+
+func (e *ErrUnknownDependency) Error() string {
+	return fmt.Sprintf("task %d in depends_on does not exist", e.ID)
+}
+
+func (e *ErrUnknownDependency) Unwrap() error { return ErrTaskNotFound }
+
+// ErrHookRejected wraps the error a transition hook refused a move with
+type ErrHookRejected struct {
+	Err error
+}
+
+func (e *ErrHookRejected) Error() string { return e.Err.Error() }
+
+func (e *ErrHookRejected) Unwrap() error { return e.Err }
+
+// errorToHTTP maps an error from the store to a response status and message.
+// Errors it doesn't recognize are reported as a 500 without their details.
+func errorToHTTP(err error) (int, string) {
+	var (
+		validation    *ErrValidation
+		notAllowed    *ErrTransitionNotAllowed
+		violation     *PolicyViolation
+		pinLimit      *ErrPinLimitReached
+		labelInUse    *ErrLabelInUse
+		columnInUse   *ErrColumnNotEmpty
+		notInColumn   *ErrNotInColumn
+		hookRejection *ErrHookRejected
+		needsReview   *ErrReviewRequired
+		locked        *ErrBoardLocked
+		unknownDep    *ErrUnknownDependency
+	)
+	switch {
+	case errors.As(err, &unknownDep):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, ErrTaskNotFound):
+		return http.StatusNotFound, "Task not found"
+	case errors.Is(err, errColumnNotFound):
+		return http.StatusNotFound, "Column not found"
+	case errors.Is(err, errSprintNotFound):
+		return http.StatusNotFound, "Sprint not found"
+	case errors.As(err, &locked):
+		return http.StatusLocked, err.Error()
+	case errors.Is(err, ErrSelfApproval):
+		return http.StatusForbidden, err.Error()
+	case errors.Is(err, ErrInvalidStatus), errors.As(err, &validation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, ErrWIPLimitExceeded), errors.Is(err, ErrVersionConflict),
+		errors.As(err, &pinLimit), errors.As(err, &labelInUse), errors.As(err, &columnInUse):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, ErrCircularSubtask), errors.Is(err, ErrDependencyCycle), errors.As(err, &notAllowed), errors.As(err, &violation),
+		errors.As(err, &notInColumn), errors.As(err, &hookRejection), errors.As(err, &needsReview):
+		return http.StatusUnprocessableEntity, err.Error()
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+// writeError responds with the status and message errorToHTTP picks for err,
+// logging errors it doesn't recognize
+func writeError(w http.ResponseWriter, err error) {
+	status, message := errorToHTTP(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("Unexpected error: %v", err)
+	}
+	http.Error(w, message, status)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestErrorToHTTP(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		status int
+	}{
+		{ErrTaskNotFound, http.StatusNotFound},
+		{fmt.Errorf("copying: %w", ErrTaskNotFound), http.StatusNotFound},
+		{fmt.Errorf("%w %q", ErrInvalidStatus, "archived"), http.StatusBadRequest},
+		{fieldError("title", "title is required"), http.StatusBadRequest},
+		{&ErrWIPLimitReached{Status: "doing", Limit: 2}, http.StatusConflict},
+		{&ErrHookRejected{Err: &ErrWIPLimitReached{Status: "doing", Limit: 2}}, http.StatusConflict},
+		{fmt.Errorf("saving: %w", ErrVersionConflict), http.StatusConflict},
+		{&ErrTransitionNotAllowed{From: "todo", To: "done"}, http.StatusUnprocessableEntity},
+		{&ErrHookRejected{Err: errors.New("needs a due date")}, http.StatusUnprocessableEntity},
+		{errors.New("disk full"), http.StatusInternalServerError},
+	} {
+		status, message := errorToHTTP(tc.err)
+		if status != tc.status {
+			t.Errorf("errorToHTTP(%v) = %d, want %d", tc.err, status, tc.status)
+		}
+		if status == http.StatusInternalServerError && strings.Contains(message, "disk") {
+			t.Errorf("Expected unexpected errors to hide their details, got %q", message)
+		}
+	}
+}
+
+func TestErrorsMatchThroughTheStack(t *testing.T) {
+	s := newTestStore()
+	s.wipLimits = map[string]int{"doing": 1}
+	s.AddTask("First", "")
+	s.AddTask("Second", "")
+	s.MoveTask(1, "doing")
+
+	// The WIP limit runs as a hook, so its error arrives wrapped
+	_, _, err := s.MoveTask(2, "doing")
+	var wipErr *ErrWIPLimitReached
+	var rejected *ErrHookRejected
+	if !errors.Is(err, ErrWIPLimitExceeded) || !errors.As(err, &wipErr) || !errors.As(err, &rejected) {
+		t.Errorf("Expected a wrapped ErrWIPLimitReached, got %T %v", err, err)
+	}
+	if wipErr != nil && wipErr.Limit != 1 {
+		t.Errorf("Expected the limit to survive unwrapping, got %d", wipErr.Limit)
+	}
+
+	if _, _, err := s.DuplicateTask(1, "archived"); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected ErrInvalidStatus, got %v", err)
+	}
+	if _, err := CopyTask(s, s, 42); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", err)
+	}
+
+	var validation *ErrValidation
+	err = ApplyMergePatch(&Task{ID: 1, Title: "x"}, map[string]interface{}{"effort": -1.0})
+	if !errors.As(err, &validation) || validation.Fields["effort"] != "effort must not be negative" {
+		t.Errorf("Expected an ErrValidation for effort, got %v", err)
+	}
+}
+
+func TestMergePatchStatusErrors(t *testing.T) {
+	s := withTestGlobals(t)
+	s.wipLimits = map[string]int{"doing": 1}
+	s.AddTask("First", "")
+	s.AddTask("Second", "")
+	s.MoveTask(1, "doing")
+
+	if w := putMergePatch("/api/v1/tasks/2", `{"status": "doing"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a full column, got %d", w.Code)
+	}
+	if w := putMergePatch("/api/v1/tasks/2", `{"priority": 9}`); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid priority 9") {
+		t.Errorf("Expected 400 naming the bad priority, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+// estimateWindowDays is how many days of velocity a completion estimate
+// averages over
+const estimateWindowDays = 28
+
+// EstimateCompletion predicts when a task will be done: its effort divided
+// by the board's daily velocity, counted in days from its creation. It
+// returns nil for a task without effort or a board without velocity.
+func EstimateCompletion(task *Task, velocityPerDay float64) *time.Time {
+	if task.Effort <= 0 || velocityPerDay <= 0 {
+		return nil
+	}
+	days := float64(task.Effort) / velocityPerDay
+	estimate := task.CreatedAt.Add(time.Duration(days * float64(24*time.Hour)))
+	return &estimate
+}
+
+// dailyVelocity averages the effort finished per day over periods of one
+// day each, and counts the days that finished anything
+func dailyVelocity(periods []VelocityPeriod) (perDay float64, dataPoints int) {
+	if len(periods) == 0 {
+		return 0, 0
+	}
+	var effort int
+	for _, period := range periods {
+		effort += period.EffortDone
+		if period.TasksDone > 0 {
+			dataPoints++
+		}
+	}
+	return float64(effort) / float64(len(periods)), dataPoints
+}
+
+// estimateConfidence rates an estimate by how many days of velocity it
+// rests on
+func estimateConfidence(dataPoints int) string {
+	switch {
+	case dataPoints >= 8:
+		return "high"
+	case dataPoints >= 2:
+		return "medium"
+	}
+	return "low"
+}
+
+// taskEstimateHandler serves GET /api/v1/tasks/{id}/estimated-completion,
+// {"estimated_date", "confidence"} from the last four weeks of velocity.
+// The date is null when nothing was finished in that time.
+func (srv *Server) taskEstimateHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	task, ok := srv.GetTask(id)
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	if task.Effort <= 0 {
+		http.Error(w, "Task has no effort estimate", http.StatusUnprocessableEntity)
+		return
+	}
+
+	perDay, dataPoints := dailyVelocity(srv.Velocity(24*time.Hour, estimateWindowDays))
+	var date *string
+	if estimate := EstimateCompletion(task, perDay); estimate != nil {
+		formatted := estimate.Format("2006-01-02")
+		date = &formatted
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"estimated_date": date,
+		"confidence":     estimateConfidence(dataPoints),
+	})
+}
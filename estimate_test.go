@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEstimateCompletion(t *testing.T) {
+	created := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	task := &Task{Effort: 6, CreatedAt: created}
+
+	if got := EstimateCompletion(task, 2); got == nil || !got.Equal(created.AddDate(0, 0, 3)) {
+		t.Errorf("Expected 6 points at 2 a day to take 3 days, got %v", got)
+	}
+	if got := EstimateCompletion(task, 4); got == nil || !got.Equal(created.Add(36*time.Hour)) {
+		t.Errorf("Expected 6 points at 4 a day to take a day and a half, got %v", got)
+	}
+	if EstimateCompletion(task, 0) != nil {
+		t.Errorf("Expected no estimate without velocity")
+	}
+	if EstimateCompletion(&Task{CreatedAt: created}, 2) != nil {
+		t.Errorf("Expected no estimate without effort")
+	}
+}
+
+func TestDailyVelocity(t *testing.T) {
+	periods := []VelocityPeriod{{TasksDone: 1, EffortDone: 3}, {}, {TasksDone: 2, EffortDone: 5}, {}}
+	if perDay, points := dailyVelocity(periods); perDay != 2 || points != 2 {
+		t.Errorf("Expected 2 points a day from 2 data points, got %v and %d", perDay, points)
+	}
+	if perDay, points := dailyVelocity(nil); perDay != 0 || points != 0 {
+		t.Errorf("Expected nothing from no periods, got %v and %d", perDay, points)
+	}
+}
+
+func TestTaskEstimateHandler(t *testing.T) {
+	for _, tc := range []struct {
+		doneDays   int
+		date       string
+		confidence string
+	}{
+		{0, "", "low"},
+		{2, "2025-06-29", "medium"},
+		{8, "2025-06-08", "high"},
+		{10, "2025-06-07", "high"},
+	} {
+		s := withTestGlobals(t)
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		current := now.AddDate(0, 0, -20).Add(time.Hour)
+		s.now = func() time.Time { return current }
+		// One 7-point task finished on each of doneDays days
+		for day := 0; day < tc.doneDays; day++ {
+			task, _ := s.CreateTask(TaskSpec{Title: "Done", Effort: 7})
+			s.MoveTask(task.ID, "done")
+			current = current.Add(24 * time.Hour)
+		}
+		current = now
+		task, _ := s.CreateTask(TaskSpec{Title: "Next", Effort: 14})
+
+		w := httptest.NewRecorder()
+		NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/tasks/"+strconv.Itoa(task.ID)+"/estimated-completion", nil))
+		var resp struct {
+			EstimatedDate *string `json:"estimated_date"`
+			Confidence    string  `json:"confidence"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%d days: invalid JSON %q: %v", tc.doneDays, w.Body.String(), err)
+		}
+		date := ""
+		if resp.EstimatedDate != nil {
+			date = *resp.EstimatedDate
+		}
+		if date != tc.date || resp.Confidence != tc.confidence {
+			t.Errorf("%d days: expected %q with %s confidence, got %q with %s", tc.doneDays, tc.date, tc.confidence, date, resp.Confidence)
+		}
+	}
+}
+
+func TestTaskEstimateHandlerErrors(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Unsized", "")
+
+	w := httptest.NewRecorder()
+	NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/tasks/1/estimated-completion", nil))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a task without effort, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/tasks/9/estimated-completion", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown task, got %d", w.Code)
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types published by TaskStore
+const (
+	EventTaskCreated = "TaskCreated"
+	EventTaskMoved   = "TaskMoved"
+	EventTaskUpdated = "TaskUpdated"
+	EventTaskDeleted = "TaskDeleted"
+
+	// EventTaskAssigned follows a TaskUpdated event that gives a task a new,
+	// non-empty assignee
+	EventTaskAssigned = "TaskAssigned"
+
+	// EventTaskWatched follows a TaskUpdated or TaskMoved event on a task
+	// with watchers, carrying the watchers to notify
+	EventTaskWatched = "TaskWatched"
+
+	// EventTaskIdle is published by the idle check for an assigned task
+	// nobody has updated in a while; the task's assignee is the one to nudge
+	EventTaskIdle = "TaskIdle"
+
+	// EventCommentMention follows the TaskUpdated event for a new comment,
+	// once for each user the comment @-mentions
+	EventCommentMention = "CommentMention"
+
+	// EventAll subscribes a handler to every event type
+	EventAll = "*"
+)
+
+// Event describes a change to a task
+type Event struct {
+	Type       string
+	Task       *Task // copy of the task after the change (before it, for deletes)
+	FromStatus string
+	ToStatus   string
+	Changes    []FieldChange
+	Actor      string   // who made the change, empty for anonymous web users
+	Watchers   []string // who to notify, for TaskWatched events
+	Comment    *Comment // the new comment, for CommentMention events
+	Mentioned  string   // who to notify, for CommentMention events
+	Time       time.Time
+}
+
+// EventBus delivers published events to subscribed handlers
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]func(Event)
+}
+
+// NewEventBus returns an event bus with no subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[string][]func(Event))}
+}
+
+var bus = NewEventBus()
+
+// Subscribe registers handler for events of the given type, or every type with EventAll
+func (b *EventBus) Subscribe(eventType string, handler func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish calls every matching handler synchronously, in subscription order
+func (b *EventBus) Publish(e Event) {
+	for _, handler := range b.subscribers(e.Type) {
+		handler(e)
+	}
+}
+
+// PublishAsync calls every matching handler in its own goroutine
+func (b *EventBus) PublishAsync(e Event) {
+	for _, handler := range b.subscribers(e.Type) {
+		go handler(e)
+	}
+}
+
+func (b *EventBus) subscribers(eventType string) []func(Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var handlers []func(Event)
+	handlers = append(handlers, b.handlers[eventType]...)
+	handlers = append(handlers, b.handlers[EventAll]...)
+	return handlers
+}
+
+// publish sends an event to the store's bus, if it has one. It must be
+// called without the lock held so handlers can read from the store.
+func (s *TaskStore) publish(e Event) {
+	s.invalidatePages()
+	s.notifyTaskSubscribers(e)
+	if s.events == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = s.clock()
+	}
+	s.events.Publish(e)
+	if watched, ok := watchedEvent(e); ok {
+		s.events.Publish(watched)
+	}
+}
+
+// BoardEvent is an audit log entry for a single task event
+type BoardEvent struct {
+	ID         int           `json:"id"`
+	Type       string        `json:"type"`
+	TaskID     int           `json:"task_id"`
+	TaskTitle  string        `json:"task_title"`
+	FromStatus string        `json:"from_status,omitempty"`
+	ToStatus   string        `json:"to_status,omitempty"`
+	Changes    []FieldChange `json:"changes,omitempty"`
+	Actor      string        `json:"actor,omitempty"`
+	Timestamp  time.Time     `json:"timestamp"`
+}
+
+// EventLog records board events in memory for auditing
+type EventLog struct {
+	mu     sync.Mutex
+	events []BoardEvent
+	nextID int
+}
+
+var auditLog = &EventLog{}
+
+// Record appends an event to the log. TaskAssigned, TaskWatched and
+// CommentMention events are skipped since the event they follow already
+// records the change, and TaskIdle events since nothing changed.
+func (l *EventLog) Record(e Event) {
+	if e.Type == EventTaskAssigned || e.Type == EventTaskWatched || e.Type == EventCommentMention || e.Type == EventTaskIdle {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextID++
+	entry := BoardEvent{
+		ID:         l.nextID,
+		Type:       e.Type,
+		FromStatus: e.FromStatus,
+		ToStatus:   e.ToStatus,
+		Changes:    e.Changes,
+		Actor:      e.Actor,
+		Timestamp:  e.Time,
+	}
+	if e.Task != nil {
+		entry.TaskID = e.Task.ID
+		entry.TaskTitle = e.Task.Title
+	}
+	l.events = append(l.events, entry)
+}
+
+// GetTaskHistory returns the events for a single task, newest first
+func (l *EventLog) GetTaskHistory(taskID int) []BoardEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	history := []BoardEvent{}
+	for i := len(l.events) - 1; i >= 0; i-- {
+		if l.events[i].TaskID == taskID {
+			history = append(history, l.events[i])
+		}
+	}
+	return history
+}
+
+// Events returns a copy of every recorded event, oldest first
+func (l *EventLog) Events() []BoardEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := make([]BoardEvent, len(l.events))
+	copy(events, l.events)
+	return events
+}
+
+// warnOnWIPLimit logs when a change fills a column up to its WIP limit
+func warnOnWIPLimit(s *TaskStore) func(Event) {
+	return func(e Event) {
+		if e.Task == nil {
+			return
+		}
+		col := s.GetColumnData(e.Task.Status)
+		if col.WIPLimitExceeded {
+			log.Printf("Column %s is at its WIP limit (%d/%d)", col.Status, col.Count, col.WIPLimit)
+		}
+	}
+}
+
+// subscribeDefaultHandlers wires the built-in side effects to the bus
+func subscribeDefaultHandlers(b *EventBus, s *TaskStore, l *EventLog) {
+	b.Subscribe(EventAll, l.Record)
+	b.Subscribe(EventTaskCreated, warnOnWIPLimit(s))
+	b.Subscribe(EventTaskMoved, warnOnWIPLimit(s))
+}
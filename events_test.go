@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestEventBusDeliversToEverySubscriberOnce(t *testing.T) {
+	b := NewEventBus()
+	counts := make(map[string]int)
+	b.Subscribe(EventTaskMoved, func(Event) { counts["sse"]++ })
+	b.Subscribe(EventTaskMoved, func(Event) { counts["webhook"]++ })
+	b.Subscribe(EventAll, func(Event) { counts["audit"]++ })
+	b.Subscribe(EventTaskCreated, func(Event) { counts["created"]++ })
+
+	b.Publish(Event{Type: EventTaskMoved})
+
+	for _, name := range []string{"sse", "webhook", "audit"} {
+		if counts[name] != 1 {
+			t.Errorf("Expected %s subscriber to run once, ran %d times", name, counts[name])
+		}
+	}
+	if counts["created"] != 0 {
+		t.Errorf("TaskCreated subscriber should not receive TaskMoved events")
+	}
+}
+
+func TestTaskStorePublishesEvents(t *testing.T) {
+	store := newTestStore()
+	store.events = NewEventBus()
+	var got []Event
+	store.events.Subscribe(EventAll, func(e Event) { got = append(got, e) })
+
+	store.AddTask("Evented", "")
+	store.MoveTask(1, "doing")
+	store.AssignTask(1, "dana")
+	store.DeleteTask(1)
+
+	want := []string{EventTaskCreated, EventTaskMoved, EventTaskUpdated, EventTaskAssigned, EventTaskDeleted}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(got))
+	}
+	for i, e := range got {
+		if e.Type != want[i] {
+			t.Errorf("Event %d: expected %s, got %s", i, want[i], e.Type)
+		}
+		if e.Task == nil || e.Task.ID != 1 {
+			t.Errorf("Event %d: expected task 1 payload", i)
+		}
+	}
+	if got[1].FromStatus != "todo" || got[1].ToStatus != "doing" {
+		t.Errorf("Unexpected move payload: %+v", got[1])
+	}
+	if len(got[2].Changes) != 1 || got[2].Changes[0].New != "dana" {
+		t.Errorf("Expected assignee change in update payload, got %+v", got[2].Changes)
+	}
+}
+
+func TestSubscribersCanReadStore(t *testing.T) {
+	store := newTestStore()
+	store.events = NewEventBus()
+	var count int
+	store.events.Subscribe(EventTaskCreated, func(e Event) {
+		count = len(store.GetAllTasks())
+	})
+	store.AddTask("Reentrant", "")
+	if count != 1 {
+		t.Errorf("Subscriber should see the new task, got %d tasks", count)
+	}
+}
+
+func TestEventLogRecordsDefaultSubscription(t *testing.T) {
+	store := newTestStore()
+	store.events = NewEventBus()
+	eventLog := &EventLog{}
+	subscribeDefaultHandlers(store.events, store, eventLog)
+
+	store.AddTask("Audited", "")
+	store.MoveTask(1, "done")
+
+	events := eventLog.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 audit events, got %d", len(events))
+	}
+	if events[1].Type != EventTaskMoved || events[1].TaskID != 1 || events[1].ToStatus != "done" {
+		t.Errorf("Unexpected audit entry: %+v", events[1])
+	}
+	if events[0].ID != 1 || events[1].ID != 2 {
+		t.Errorf("Expected sequential event IDs")
+	}
+}
+
+func TestDeleteTask(t *testing.T) {
+	store := newTestStore()
+	store.AddTask("Doomed", "")
+	if ok, err := store.DeleteTask(1); !ok || err != nil {
+		t.Errorf("DeleteTask failed: %v", err)
+	}
+	if _, ok := store.GetTask(1); ok {
+		t.Errorf("Task should be gone")
+	}
+	if ok, _ := store.DeleteTask(1); ok {
+		t.Errorf("Deleting twice should fail")
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"log"
+	"os"
+	"sort"
+	"time"
+)
+
+// loadTaskRetention reads KANBAN_TASK_RETENTION, a duration such as 168h
+// after which new tasks are deleted. Empty or invalid means tasks never
+// expire.
+func loadTaskRetention() time.Duration {
+	raw := os.Getenv("KANBAN_TASK_RETENTION")
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Printf("Ignoring KANBAN_TASK_RETENTION %q: must be a positive duration like 168h", raw)
+		return 0
+	}
+	return d
+}
+
+// GetExpiringTasks returns the tasks that expire within the given window,
+// including any already past their expiry, soonest first
+func (s *TaskStore) GetExpiringTasks(within time.Duration) []*Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.clock().Add(within)
+	tasks := []*Task{}
+	for _, task := range s.tasks {
+		if task.ExpiresAt != nil && !task.ExpiresAt.After(cutoff) {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].ExpiresAt.Equal(*tasks[j].ExpiresAt) {
+			return tasks[i].ExpiresAt.Before(*tasks[j].ExpiresAt)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks
+}
+
+// DeleteExpiredTasks deletes every task past its expiry, along with its
+// links and comments, and returns how many it deleted. While the board is
+// locked it deletes nothing and returns ErrBoardLocked.
+func (s *TaskStore) DeleteExpiredTasks() (int, error) {
+	if err := s.BoardLock(); err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, task := range s.GetExpiringTasks(0) {
+		ok, err := s.DeleteTask(task.ID)
+		if err != nil {
+			return deleted, err
+		}
+		if ok {
+			log.Printf("Deleted expired task %d (%s)", task.ID, task.Title)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// runExpirySweeper deletes expired tasks on every tick until stop is closed.
+// Ticks that arrive while the board is locked are skipped.
+func (s *TaskStore) runExpirySweeper(ticks <-chan time.Time, stop <-chan struct{}) {
+	for {
+		select {
+		case <-ticks:
+			s.DeleteExpiredTasks()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// countExpiring counts the tasks that expire before cutoff
+func countExpiring(tasks []Task, cutoff time.Time) int {
+	count := 0
+	for _, task := range tasks {
+		if task.ExpiresAt != nil && !task.ExpiresAt.After(cutoff) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewTasksExpireAfterRetention(t *testing.T) {
+	s := newTestStore()
+	created := time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return created }
+
+	if task := s.AddTask("Kept", ""); task.ExpiresAt != nil {
+		t.Errorf("Expected no expiry without a retention period, got %v", task.ExpiresAt)
+	}
+	s.retention = 7 * 24 * time.Hour
+	task := s.AddTask("Standup", "")
+	if task.ExpiresAt == nil || !task.ExpiresAt.Equal(created.AddDate(0, 0, 7)) {
+		t.Errorf("Expected expiry a week after creation, got %v", task.ExpiresAt)
+	}
+}
+
+func TestGetExpiringTasks(t *testing.T) {
+	s := newTestStore()
+	now := time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	for title, expires := range map[string]time.Duration{"Soon": 2 * time.Hour, "Past": -time.Hour, "Later": 48 * time.Hour} {
+		task := s.AddTask(title, "")
+		s.UpdateTask(task.ID, func(task *Task) {
+			at := now.Add(expires)
+			task.ExpiresAt = &at
+		})
+	}
+	s.AddTask("Forever", "")
+
+	var titles []string
+	for _, task := range s.GetExpiringTasks(24 * time.Hour) {
+		titles = append(titles, task.Title)
+	}
+	if len(titles) != 2 || titles[0] != "Past" || titles[1] != "Soon" {
+		t.Errorf("Expected [Past Soon], got %v", titles)
+	}
+}
+
+func TestExpirySweeperDeletesExpiredTasks(t *testing.T) {
+	s := withTestGlobals(t)
+	now := time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	expired := s.AddTask("Yesterday's standup", "")
+	fresh := s.AddTask("Today's standup", "")
+	s.AddTask("Forever", "")
+	s.UpdateTask(expired.ID, func(task *Task) {
+		past := now.Add(-time.Minute)
+		task.ExpiresAt = &past
+	})
+	s.UpdateTask(fresh.ID, func(task *Task) {
+		future := now.Add(time.Hour)
+		task.ExpiresAt = &future
+	})
+
+	ticks := make(chan time.Time)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		s.runExpirySweeper(ticks, stop)
+		close(done)
+	}()
+	ticks <- now
+	close(stop)
+	<-done
+
+	if _, ok := s.GetTask(expired.ID); ok {
+		t.Errorf("Expected the expired task deleted")
+	}
+	if ids := taskIDs(s.GetAllTasks()); !equalIDs(ids, []int{fresh.ID, 3}) {
+		t.Errorf("Expected the other tasks kept, got %v", ids)
+	}
+	if history := auditLog.GetTaskHistory(expired.ID); len(history) == 0 || history[0].Type != EventTaskDeleted {
+		t.Errorf("Expected the deletion recorded, got %+v", history)
+	}
+}
+
+func TestHealthReportsExpiringTasks(t *testing.T) {
+	s := withTestGlobals(t)
+	now := time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.retention = 12 * time.Hour
+	s.AddTask("A", "")
+	s.AddTask("B", "")
+	s.retention = 72 * time.Hour
+	s.AddTask("C", "")
+
+	w := httptest.NewRecorder()
+	NewServer(s).healthHandler(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var body struct {
+		Expiring24h int `json:"expiring_24h"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if body.Expiring24h != 2 {
+		t.Errorf("Expected 2 tasks expiring within 24h, got %d", body.Expiring24h)
+	}
+}
+
+func TestLoadTaskRetention(t *testing.T) {
+	for raw, want := range map[string]time.Duration{"": 0, "168h": 168 * time.Hour, "a week": 0, "-1h": 0} {
+		t.Setenv("KANBAN_TASK_RETENTION", raw)
+		if got := loadTaskRetention(); got != want {
+			t.Errorf("%q: expected %v, got %v", raw, want, got)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FeatureFlags switches experimental features on or off. Requests to a
+// disabled feature's endpoints return 404.
+type FeatureFlags struct {
+	Subtasks     bool `json:"subtasks"`
+	Dependencies bool `json:"dependencies"`
+	Comments     bool `json:"comments"`
+}
+
+// DefaultFeatureFlags returns the flags used when none are configured
+func DefaultFeatureFlags() FeatureFlags {
+	return FeatureFlags{Comments: true}
+}
+
+// Enabled reports whether the named feature is on. Unknown names are off.
+func (f FeatureFlags) Enabled(name string) bool {
+	switch name {
+	case "subtasks":
+		return f.Subtasks
+	case "dependencies":
+		return f.Dependencies
+	case "comments":
+		return f.Comments
+	}
+	return false
+}
+
+// applyFlags overlays a JSON object of flags onto base. Flags missing from
+// data keep their value, and unknown flags are rejected.
+func applyFlags(base FeatureFlags, data []byte) (FeatureFlags, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&base); err != nil {
+		return base, fmt.Errorf("invalid feature flags: %w", err)
+	}
+	return base, nil
+}
+
+// LoadFeatureFlags reads flags from the KANBAN_FEATURES env var, then from
+// features.json, on top of the defaults
+func LoadFeatureFlags() (FeatureFlags, error) {
+	if raw := os.Getenv("KANBAN_FEATURES"); raw != "" {
+		return applyFlags(DefaultFeatureFlags(), []byte(raw))
+	}
+
+	data, err := os.ReadFile(filepath.Join(".", "features.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultFeatureFlags(), nil
+		}
+		return FeatureFlags{}, err
+	}
+	return applyFlags(DefaultFeatureFlags(), data)
+}
+
+// FeatureFlagStore holds the current flags, which can change at runtime
+type FeatureFlagStore struct {
+	mu    sync.RWMutex
+	flags FeatureFlags
+}
+
+// NewFeatureFlagStore returns a store starting with the given flags
+func NewFeatureFlagStore(flags FeatureFlags) *FeatureFlagStore {
+	return &FeatureFlagStore{flags: flags}
+}
+
+var features = NewFeatureFlagStore(DefaultFeatureFlags())
+
+// Get returns a copy of the current flags
+func (fs *FeatureFlagStore) Get() FeatureFlags {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.flags
+}
+
+// Set replaces the current flags
+func (fs *FeatureFlagStore) Set(flags FeatureFlags) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.flags = flags
+}
+
+// Update overlays a JSON object of flags onto the current ones and returns
+// the result. Nothing changes if the object is invalid.
+func (fs *FeatureFlagStore) Update(data []byte) (FeatureFlags, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	flags, err := applyFlags(fs.flags, data)
+	if err != nil {
+		return fs.flags, err
+	}
+	fs.flags = flags
+	return flags, nil
+}
+
+type featureFlagsKey struct{}
+
+// FlagMiddleware adds the flags in effect when a request arrives to its
+// context, so a toggle mid-request doesn't change what the request sees
+func FlagMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), featureFlagsKey{}, features.Get())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// featureEnabled reports whether the named feature is on for a request,
+// using the current flags if FlagMiddleware didn't run
+func featureEnabled(ctx context.Context, name string) bool {
+	flags, ok := ctx.Value(featureFlagsKey{}).(FeatureFlags)
+	if !ok {
+		flags = features.Get()
+	}
+	return flags.Enabled(name)
+}
+
+// featuresHandler returns the feature flags as JSON (GET) or updates them
+// from a JSON object such as {"subtasks": true} (POST)
+func featuresHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, features.Get())
+	case http.MethodPost:
+		var body bytes.Buffer
+		if _, err := body.ReadFrom(http.MaxBytesReader(w, r.Body, 1<<20)); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		flags, err := features.Update(body.Bytes())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusOK, flags)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func withTestFeatures(t *testing.T, flags FeatureFlags) {
+	orig := features
+	t.Cleanup(func() { features = orig })
+	features = NewFeatureFlagStore(flags)
+}
+
+func TestLoadFeatureFlagsFromEnv(t *testing.T) {
+	t.Setenv("KANBAN_FEATURES", `{"subtasks": true}`)
+	flags, err := LoadFeatureFlags()
+	if err != nil {
+		t.Fatalf("LoadFeatureFlags failed: %v", err)
+	}
+	if !flags.Subtasks || flags.Dependencies || !flags.Comments {
+		t.Errorf("Expected subtasks on and the other flags at their defaults, got %+v", flags)
+	}
+
+	t.Setenv("KANBAN_FEATURES", `{"gantt": true}`)
+	if _, err := LoadFeatureFlags(); err == nil {
+		t.Errorf("Expected an unknown flag to be rejected")
+	}
+}
+
+func TestDisabledFeatureReturns404(t *testing.T) {
+	s := withTestGlobals(t)
+	withTestFeatures(t, FeatureFlags{Comments: false})
+	s.AddTask("Design", "")
+	handler := FlagMiddleware(http.HandlerFunc(NewServer(s).taskRouter))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/task/1/comments", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 while comments are disabled, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	NewServer(s).columnHandler(w, httptest.NewRequest(http.MethodGet, "/column/todo", nil))
+	if strings.Contains(w.Body.String(), "/comments") {
+		t.Errorf("Expected the Comments button to be hidden")
+	}
+}
+
+func TestRuntimeFeatureToggle(t *testing.T) {
+	s := withTestGlobals(t)
+	withTestFeatures(t, FeatureFlags{Comments: false})
+	s.AddTask("Design", "")
+	handler := FlagMiddleware(http.HandlerFunc(NewServer(s).taskRouter))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/features", strings.NewReader(`{"comments": true}`))
+	w := httptest.NewRecorder()
+	featuresHandler(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"comments":true`) {
+		t.Fatalf("Expected comments to be enabled, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/task/1/comments", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected comments to be served after enabling them, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	featuresHandler(w, httptest.NewRequest(http.MethodPost, "/admin/features", strings.NewReader(`{"comments": "yes"}`)))
+	if w.Code != http.StatusBadRequest || !features.Get().Comments {
+		t.Errorf("Expected an invalid update to be rejected without changing flags, got %d", w.Code)
+	}
+}
+
+func TestFeaturesRequiresAdminKey(t *testing.T) {
+	withTestFeatures(t, FeatureFlags{Comments: false})
+	t.Setenv("KANBAN_ADMIN_KEY", "s3cret")
+
+	w := httptest.NewRecorder()
+	requireAdminKey(featuresHandler)(w, httptest.NewRequest(http.MethodPost, "/admin/features", strings.NewReader(`{"comments": true}`)))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 without the key, got %d", w.Code)
+	}
+	if features.Get().Comments {
+		t.Errorf("Expected the flags to be unchanged without the key")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/features", nil)
+	req.Header.Set(adminKeyHeader, "s3cret")
+	w = httptest.NewRecorder()
+	requireAdminKey(featuresHandler)(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 with the key, got %d", w.Code)
+	}
+}
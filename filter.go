@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// FilterOptions narrows a task listing. Empty fields match every task; a
+// task must match every non-empty field, and any one value within a field.
+type FilterOptions struct {
+	Statuses    []string
+	Labels      []string
+	Assignees   []string
+	MinPriority int
+	OverdueOnly bool
+}
+
+// FilterTasks returns the tasks matching opts, ordered by ID
+func (s *TaskStore) FilterTasks(opts FilterOptions) []*Task {
+	span := s.startSpan(context.Background(), "FilterTasks")
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock()
+	tasks := []*Task{}
+	for _, task := range s.tasks {
+		if opts.matches(task, now) {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
+func (opts FilterOptions) matches(task *Task, now time.Time) bool {
+	if len(opts.Statuses) > 0 && !containsString(opts.Statuses, task.Status) {
+		return false
+	}
+	if len(opts.Assignees) > 0 && !containsString(opts.Assignees, task.Assignee) {
+		return false
+	}
+	if len(opts.Labels) > 0 {
+		found := false
+		for _, label := range task.Labels {
+			if containsString(opts.Labels, label) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if task.Priority < opts.MinPriority {
+		return false
+	}
+	if opts.OverdueOnly && !task.IsOverdue(now) {
+		return false
+	}
+	return true
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func filterFixture() *TaskStore {
+	store := newTestStore()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	past := now.Add(-48 * time.Hour)
+	future := now.Add(48 * time.Hour)
+
+	store.CreateTask(TaskSpec{Title: "1", Assignee: "alice", Labels: []string{"bug"}, Priority: PriorityHigh, DueDate: &past})
+	store.CreateTask(TaskSpec{Title: "2", Assignee: "bob", Labels: []string{"feature"}, Priority: PriorityLow, Status: "doing"})
+	store.CreateTask(TaskSpec{Title: "3", Assignee: "alice", Labels: []string{"bug", "ui"}, Priority: PriorityMedium, Status: "done", DueDate: &past})
+	store.CreateTask(TaskSpec{Title: "4", Labels: []string{"ui"}, Status: "doing", DueDate: &future})
+	return store
+}
+
+func TestFilterTasks(t *testing.T) {
+	store := filterFixture()
+	cases := []struct {
+		name string
+		opts FilterOptions
+		want []int
+	}{
+		{"none", FilterOptions{}, []int{1, 2, 3, 4}},
+		{"statuses", FilterOptions{Statuses: []string{"todo", "doing"}}, []int{1, 2, 4}},
+		{"labels", FilterOptions{Labels: []string{"ui"}}, []int{3, 4}},
+		{"assignees", FilterOptions{Assignees: []string{"bob", "alice"}}, []int{1, 2, 3}},
+		{"min priority", FilterOptions{MinPriority: PriorityMedium}, []int{1, 3}},
+		{"overdue", FilterOptions{OverdueOnly: true}, []int{1}},
+		{"combined", FilterOptions{Assignees: []string{"alice"}, Labels: []string{"bug"}, Statuses: []string{"done"}}, []int{3}},
+		{"no match", FilterOptions{Assignees: []string{"bob"}, MinPriority: PriorityHigh}, []int{}},
+	}
+	for _, tc := range cases {
+		got := taskIDs(store.FilterTasks(tc.opts))
+		if !equalIDs(got, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestTasksAPIFilter(t *testing.T) {
+	srv := NewServer(filterFixture())
+
+	rec := httptest.NewRecorder()
+	srv.tasksAPIHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tasks?status=todo,doing&label=ui,feature", nil))
+	var tasks []*Task
+	if err := json.NewDecoder(rec.Body).Decode(&tasks); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if !equalIDs(taskIDs(tasks), []int{2, 4}) {
+		t.Errorf("Expected tasks 2 and 4, got %v", taskIDs(tasks))
+	}
+
+	for _, query := range []string{"status=todo,archived", "min_priority=9", "overdue=maybe"} {
+		rec = httptest.NewRecorder()
+		srv.tasksAPIHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tasks?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+// GanttEntry is one bar of a Gantt chart: a task from its creation to its
+// due date
+type GanttEntry struct {
+	ID           int        `json:"id"`
+	Title        string     `json:"title"`
+	Start        time.Time  `json:"start"`
+	End          *time.Time `json:"end"` // due date, null if the task has none
+	Status       string     `json:"status"`
+	Assignee     string     `json:"assignee"`
+	Dependencies []int      `json:"dependencies"`
+}
+
+// BuildGanttData returns a Gantt entry for each task with a due date, in
+// start order
+func BuildGanttData(tasks []*Task) []GanttEntry {
+	return buildGanttEntries(tasks, false)
+}
+
+// buildGanttEntries is BuildGanttData, optionally keeping tasks without a
+// due date
+func buildGanttEntries(tasks []*Task, includeNoDue bool) []GanttEntry {
+	entries := []GanttEntry{}
+	for _, task := range tasks {
+		if task.DueDate == nil && !includeNoDue {
+			continue
+		}
+		entry := GanttEntry{
+			ID:           task.ID,
+			Title:        task.Title,
+			Start:        task.CreatedAt,
+			Status:       task.Status,
+			Assignee:     task.Assignee,
+			Dependencies: append([]int{}, task.DependsOn...),
+		}
+		if task.DueDate != nil {
+			end := *task.DueDate
+			entry.End = &end
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Start.Equal(entries[j].Start) {
+			return entries[i].Start.Before(entries[j].Start)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// ganttExportHandler serves GET /api/v1/export/gantt. Tasks without a due
+// date are left out unless ?include_no_due=true.
+func (srv *Server) ganttExportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	includeNoDue := r.URL.Query().Get("include_no_due") == "true"
+	writeJSON(w, http.StatusOK, buildGanttEntries(srv.GetAllTasks(), includeNoDue))
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildGanttData(t *testing.T) {
+	created := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	tasks := []*Task{
+		{ID: 1, Title: "Design", Status: "done", Assignee: "ana", CreatedAt: created, DueDate: &due},
+		{ID: 2, Title: "Build", Status: "doing", CreatedAt: created.Add(-time.Hour), DueDate: &due, DependsOn: []int{1}},
+		{ID: 3, Title: "Someday", Status: "todo", CreatedAt: created},
+	}
+
+	entries := BuildGanttData(tasks)
+	if len(entries) != 2 {
+		t.Fatalf("Expected tasks without a due date to be left out, got %+v", entries)
+	}
+	if entries[0].ID != 2 || entries[1].ID != 1 {
+		t.Errorf("Expected entries in start order, got %d, %d", entries[0].ID, entries[1].ID)
+	}
+	if deps := entries[0].Dependencies; len(deps) != 1 || deps[0] != 1 {
+		t.Errorf("Expected task 2 to depend on task 1, got %v", deps)
+	}
+	if entries[1].Dependencies == nil || len(entries[1].Dependencies) != 0 {
+		t.Errorf("Expected an empty dependency list, got %v", entries[1].Dependencies)
+	}
+	if entries[1].Assignee != "ana" || !entries[1].Start.Equal(created) || !entries[1].End.Equal(due) {
+		t.Errorf("Unexpected entry %+v", entries[1])
+	}
+
+	tasks[1].DependsOn[0] = 9
+	if entries[0].Dependencies[0] != 1 {
+		t.Errorf("Expected entries not to share dependency slices with tasks")
+	}
+
+	if all := buildGanttEntries(tasks, true); len(all) != 3 || all[2].ID != 3 || all[2].End != nil {
+		t.Errorf("Expected the undated task with a nil end, got %+v", all)
+	}
+}
+
+func TestGanttExportHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	due := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	s.CreateTask(TaskSpec{Title: "Dated", DueDate: &due})
+	s.AddTask("Undated", "")
+
+	w := httptest.NewRecorder()
+	NewServer(s).ganttExportHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/export/gantt", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var raw []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if len(raw) != 1 || raw[0]["title"] != "Dated" {
+		t.Fatalf("Expected only the dated task, got %s", w.Body.String())
+	}
+	for _, key := range []string{"start", "end"} {
+		value, _ := raw[0][key].(string)
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			t.Errorf("Expected %s in ISO 8601, got %q", key, value)
+		}
+	}
+	if deps, ok := raw[0]["dependencies"].([]interface{}); !ok || len(deps) != 0 {
+		t.Errorf("Expected an empty dependencies array, got %v", raw[0]["dependencies"])
+	}
+
+	w = httptest.NewRecorder()
+	NewServer(s).ganttExportHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/export/gantt?include_no_due=true", nil))
+	if !strings.Contains(w.Body.String(), `"title":"Undated"`) || !strings.Contains(w.Body.String(), `"end":null`) {
+		t.Errorf("Expected the undated task with a null end, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// githubAPIURL is the GitHub REST API base, overridable in tests
+var githubAPIURL = "https://api.github.com"
+
+// githubRepoPattern matches an "owner/repo" slug
+var githubRepoPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// defaultGitHubStatusMap assigns an initial status from issue labels
+var defaultGitHubStatusMap = map[string]string{
+	"in-progress": "doing",
+}
+
+// GitHubIssue is the subset of the GitHub issue payload used for import
+type GitHubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	PullRequest *struct{} `json:"pull_request"` // set when the "issue" is a PR
+}
+
+// GitHubImporter fetches open issues from a repository
+type GitHubImporter struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+// FetchIssues returns every open issue in repo, following Link pagination.
+// Pull requests, which the issues endpoint also returns, are skipped.
+func (g *GitHubImporter) FetchIssues(repo string) ([]GitHubIssue, error) {
+	client := g.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	var issues []GitHubIssue
+	next := fmt.Sprintf("%s/repos/%s/issues?state=open&per_page=100", g.BaseURL, repo)
+	for next != "" {
+		req, err := http.NewRequest(http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if g.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+g.Token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("github returned %s", resp.Status)
+		}
+		var page []GitHubIssue
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid github response: %w", err)
+		}
+
+		for _, issue := range page {
+			if issue.PullRequest == nil {
+				issues = append(issues, issue)
+			}
+		}
+		next = nextPageURL(resp.Header.Get("Link"))
+	}
+	return issues, nil
+}
+
+// nextPageURL extracts the rel="next" target from a Link header
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(segments[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+// issueToSpec converts an issue to a task, renaming labels through labelMap
+// and taking the status from the first label found in statusMap
+func issueToSpec(issue GitHubIssue, labelMap, statusMap map[string]string) TaskSpec {
+	spec := TaskSpec{Title: issue.Title, Description: issue.Body}
+	var labels []string
+	for _, label := range issue.Labels {
+		if status, ok := statusMap[label.Name]; ok && spec.Status == "" {
+			spec.Status = status
+		}
+		name := label.Name
+		if mapped, ok := labelMap[name]; ok {
+			name = mapped
+		}
+		if name != "" && !containsString(labels, name) {
+			labels = append(labels, name)
+		}
+	}
+	spec.Labels = labels
+	return spec
+}
+
+// parseStringMap decodes an optional JSON object of strings
+func parseStringMap(raw string) (map[string]string, error) {
+	m := make(map[string]string)
+	if raw == "" {
+		return m, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// importGitHubHandler creates a task for each open issue in a GitHub repo
+func (srv *Server) importGitHubHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	repo := r.FormValue("repo")
+	if !githubRepoPattern.MatchString(repo) {
+		http.Error(w, "Repo must be in owner/repo form", http.StatusBadRequest)
+		return
+	}
+	labelMap, err := parseStringMap(r.FormValue("label_map"))
+	if err != nil {
+		http.Error(w, "Invalid label_map JSON", http.StatusBadRequest)
+		return
+	}
+	statusMap := defaultGitHubStatusMap
+	if raw := r.FormValue("status_map"); raw != "" {
+		if statusMap, err = parseStringMap(raw); err != nil {
+			http.Error(w, "Invalid status_map JSON", http.StatusBadRequest)
+			return
+		}
+	}
+	for label, status := range statusMap {
+		if !isValidStatus(status) {
+			http.Error(w, fmt.Sprintf("Invalid status %q for label %q", status, label), http.StatusBadRequest)
+			return
+		}
+	}
+
+	importer := &GitHubImporter{BaseURL: githubAPIURL, Token: r.FormValue("token")}
+	issues, err := importer.FetchIssues(repo)
+	if err != nil {
+		http.Error(w, "GitHub import failed: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	specs := make([]TaskSpec, 0, len(issues))
+	for _, issue := range issues {
+		specs = append(specs, issueToSpec(issue, labelMap, statusMap))
+	}
+	created, err := srv.CreateTasks(specs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ids := []int{}
+	for _, task := range created {
+		ids = append(ids, task.ID)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"imported": len(ids),
+		"created":  ids,
+	})
+}
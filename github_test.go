@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newGitHubStub serves two pages of issues for octo/repo
+func newGitHubStub(t *testing.T) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/repo/issues" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Expected bearer token, got %q", got)
+		}
+		if r.URL.Query().Get("state") != "open" || r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("Unexpected query %q", r.URL.RawQuery)
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/octo/repo/issues?state=open&per_page=100&page=2>; rel="next", <%s/repos/octo/repo/issues?state=open&per_page=100&page=2>; rel="last"`, srv.URL, srv.URL))
+			fmt.Fprint(w, `[
+				{"number": 1, "title": "Crash on save", "body": "Stack trace", "labels": [{"name": "bug"}, {"name": "in-progress"}]},
+				{"number": 2, "title": "A pull request", "pull_request": {}}
+			]`)
+		case "2":
+			fmt.Fprint(w, `[{"number": 3, "title": "Dark mode", "body": "", "labels": [{"name": "enhancement"}]}]`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestImportGitHub(t *testing.T) {
+	s := withTestGlobals(t)
+	srv := newGitHubStub(t)
+	orig := githubAPIURL
+	githubAPIURL = srv.URL
+	defer func() { githubAPIURL = orig }()
+
+	form := url.Values{
+		"repo":      {"octo/repo"},
+		"token":     {"secret"},
+		"label_map": {`{"enhancement": "feature"}`},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/import/github", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	NewServer(s).importGitHubHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Imported int   `json:"imported"`
+		Created  []int `json:"created"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Imported != 2 {
+		t.Fatalf("Expected 2 imported issues (PR skipped), got %d", resp.Imported)
+	}
+
+	crash, _ := s.GetTask(resp.Created[0])
+	if crash.Title != "Crash on save" || crash.Description != "Stack trace" || crash.Status != "doing" {
+		t.Errorf("Unexpected first task %+v", crash)
+	}
+	dark, _ := s.GetTask(resp.Created[1])
+	if dark.Status != "todo" || len(dark.Labels) != 1 || dark.Labels[0] != "feature" {
+		t.Errorf("Expected mapped label and todo status, got %+v", dark)
+	}
+}
+
+func TestImportGitHubErrors(t *testing.T) {
+	withTestGlobals(t)
+	srv := newGitHubStub(t)
+	orig := githubAPIURL
+	githubAPIURL = srv.URL
+	defer func() { githubAPIURL = orig }()
+
+	cases := []struct {
+		form url.Values
+		code int
+	}{
+		{url.Values{"repo": {"not-a-repo"}}, http.StatusBadRequest},
+		{url.Values{"repo": {"octo/repo"}, "label_map": {"{"}}, http.StatusBadRequest},
+		{url.Values{"repo": {"octo/repo"}, "status_map": {`{"wip": "archived"}`}}, http.StatusBadRequest},
+		{url.Values{"repo": {"octo/missing"}, "token": {"secret"}}, http.StatusBadGateway},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/import/github", strings.NewReader(tc.form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		NewServer(store).importGitHubHandler(w, req)
+		if w.Code != tc.code {
+			t.Errorf("%v: expected %d, got %d", tc.form, tc.code, w.Code)
+		}
+	}
+	if n := len(store.GetAllTasks()); n != 0 {
+		t.Errorf("Expected no tasks after failed imports, got %d", n)
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	link := `<https://api.github.com/x?page=2>; rel="next", <https://api.github.com/x?page=5>; rel="last"`
+	if got := nextPageURL(link); got != "https://api.github.com/x?page=2" {
+		t.Errorf("Unexpected next URL %q", got)
+	}
+	if got := nextPageURL(`<https://api.github.com/x?page=1>; rel="prev"`); got != "" {
+		t.Errorf("Expected no next page, got %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+// healthActivityWindow is how far back /healthz counts task activity
+const healthActivityWindow = 24 * time.Hour
+
+// ActivitySummary counts tasks created and moved within a recent window
+type ActivitySummary struct {
+	Created int `json:"created"`
+	Moved   int `json:"moved"`
+}
+
+// snapshotTasks copies every task so statistics can be computed without
+// holding the store lock
+func (s *TaskStore) snapshotTasks() []Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks := make([]Task, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		tasks = append(tasks, *task)
+	}
+	return tasks
+}
+
+// CompletionRate returns the percentage of tasks that are done, or 0 for an
+// empty board
+func (s *TaskStore) CompletionRate() float64 {
+	return completionRate(s.snapshotTasks())
+}
+
+func completionRate(tasks []Task) float64 {
+	if len(tasks) == 0 {
+		return 0
+	}
+	done := 0
+	for _, task := range tasks {
+		if task.Status == "done" {
+			done++
+		}
+	}
+	return float64(done) / float64(len(tasks)) * 100
+}
+
+// RecentActivity counts the tasks created and moved within the last since
+func (s *TaskStore) RecentActivity(since time.Duration) ActivitySummary {
+	return recentActivity(s.snapshotTasks(), s.clock().Add(-since))
+}
+
+func recentActivity(tasks []Task, cutoff time.Time) ActivitySummary {
+	var summary ActivitySummary
+	for _, task := range tasks {
+		if !task.CreatedAt.Before(cutoff) {
+			summary.Created++
+		}
+		if task.MovedAt != nil && !task.MovedAt.Before(cutoff) {
+			summary.Moved++
+		}
+	}
+	return summary
+}
+
+// BoardHealth is the board summary /healthz reports
+type BoardHealth struct {
+	TotalTasks     int             `json:"total_tasks"`
+	TasksByStatus  map[string]int  `json:"tasks_by_status"`
+	CompletionRate float64         `json:"completion_rate"`
+	Recent         ActivitySummary `json:"last_24h"`
+	Expiring       int             `json:"expiring_24h"`
+}
+
+// Health summarizes the board from a single snapshot, so the figures are
+// consistent with each other. Activity and expiry are counted over
+// healthActivityWindow either side of now.
+func (s *TaskStore) Health() BoardHealth {
+	tasks := s.snapshotTasks()
+	now := s.clock()
+	byStatus := make(map[string]int)
+	for _, col := range s.columnList() {
+		byStatus[col.Status] = 0
+	}
+	for _, task := range tasks {
+		byStatus[task.Status]++
+	}
+	return BoardHealth{
+		TotalTasks:     len(tasks),
+		TasksByStatus:  byStatus,
+		CompletionRate: completionRate(tasks),
+		Recent:         recentActivity(tasks, now.Add(-healthActivityWindow)),
+		Expiring:       countExpiring(tasks, now.Add(healthActivityWindow)),
+	}
+}
+
+// healthHandler serves /healthz: the server status plus task totals,
+// completion rate, the last 24 hours of activity and how many tasks expire
+// in the next 24 hours
+func (srv *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+		BoardHealth
+	}{"ok", srv.Health()})
+}
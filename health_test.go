@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCompletionRateAndRecentActivity(t *testing.T) {
+	s := newTestStore()
+	if s.CompletionRate() != 0 {
+		t.Errorf("Expected 0%% for an empty board")
+	}
+
+	clock := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return clock }
+	old := s.AddTask("Old", "")
+	s.MoveTask(old.ID, "done")
+
+	clock = clock.Add(48 * time.Hour)
+	a := s.AddTask("A", "")
+	s.AddTask("B", "")
+	s.AddTask("C", "")
+	s.MoveTask(a.ID, "doing")
+	s.MoveTask(a.ID, "done")
+	s.MoveTask(a.ID, "done") // same column, not a move
+
+	if rate := s.CompletionRate(); rate != 50 {
+		t.Errorf("Expected 2 of 4 tasks done = 50%%, got %v", rate)
+	}
+	got := s.RecentActivity(24 * time.Hour)
+	if got.Created != 3 || got.Moved != 1 {
+		t.Errorf("Expected 3 created and 1 moved in the last 24h, got %+v", got)
+	}
+	if all := s.RecentActivity(72 * time.Hour); all.Created != 4 || all.Moved != 2 {
+		t.Errorf("Expected 4 created and 2 moved in the last 72h, got %+v", all)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("A", "")
+	s.AddTask("B", "")
+	s.AddTask("C", "")
+	s.MoveTask(task.ID, "done")
+
+	w := httptest.NewRecorder()
+	NewServer(s).healthHandler(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var body struct {
+		Status         string          `json:"status"`
+		TotalTasks     int             `json:"total_tasks"`
+		TasksByStatus  map[string]int  `json:"tasks_by_status"`
+		CompletionRate float64         `json:"completion_rate"`
+		Last24h        ActivitySummary `json:"last_24h"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if body.Status != "ok" || body.TotalTasks != 3 {
+		t.Errorf("Unexpected status or total: %+v", body)
+	}
+	if body.TasksByStatus["todo"] != 2 || body.TasksByStatus["doing"] != 0 || body.TasksByStatus["done"] != 1 {
+		t.Errorf("Unexpected per-status counts %v", body.TasksByStatus)
+	}
+	if body.CompletionRate < 33.3 || body.CompletionRate > 33.4 {
+		t.Errorf("Expected 1 of 3 done, got %v", body.CompletionRate)
+	}
+	if body.Last24h.Created != 3 || body.Last24h.Moved != 1 {
+		t.Errorf("Unexpected activity %+v", body.Last24h)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultHeatmapDays = 90
+	maxHeatmapDays     = 366
+)
+
+// HeatmapData counts events per day over the days ending on now's date,
+// keyed by YYYY-MM-DD in now's time zone. Every day in the range is present,
+// even with no events.
+func HeatmapData(events []BoardEvent, days int, now time.Time) map[string]int {
+	counts := make(map[string]int, days)
+	if days <= 0 {
+		return counts
+	}
+
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	start := today.AddDate(0, 0, -(days - 1))
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		counts[day.Format("2006-01-02")] = 0
+	}
+
+	for _, e := range events {
+		key := e.Timestamp.In(loc).Format("2006-01-02")
+		if _, ok := counts[key]; ok {
+			counts[key]++
+		}
+	}
+	return counts
+}
+
+// parseHeatmapDays reads ?days=, defaulting to 90 days
+func parseHeatmapDays(r *http.Request) (int, bool) {
+	raw := r.URL.Query().Get("days")
+	if raw == "" {
+		return defaultHeatmapDays, true
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < 1 || days > maxHeatmapDays {
+		return 0, false
+	}
+	return days, true
+}
+
+// heatmapHandler returns daily event counts as JSON
+func heatmapHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	days, ok := parseHeatmapDays(r)
+	if !ok {
+		http.Error(w, "Days must be between 1 and "+strconv.Itoa(maxHeatmapDays), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusOK, HeatmapData(auditLog.Events(), days, time.Now()))
+}
+
+// boardStatsPageData is rendered by board-stats.html
+type boardStatsPageData struct {
+	Days    int
+	Heatmap map[string]int
+}
+
+// boardStatsHandler renders the activity heatmap page
+func boardStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	days, ok := parseHeatmapDays(r)
+	if !ok {
+		http.Error(w, "Days must be between 1 and "+strconv.Itoa(maxHeatmapDays), http.StatusBadRequest)
+		return
+	}
+	templates.ExecuteTemplate(w, "board-stats.html", boardStatsPageData{
+		Days:    days,
+		Heatmap: HeatmapData(auditLog.Events(), days, time.Now()),
+	})
+}
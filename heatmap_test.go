@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeatmapData(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	at := func(daysAgo, hour int) BoardEvent {
+		day := time.Date(2024, 3, 10-daysAgo, hour, 0, 0, 0, time.UTC)
+		return BoardEvent{Type: EventTaskMoved, Timestamp: day}
+	}
+	events := []BoardEvent{
+		at(0, 9), at(0, 10), at(0, 23),
+		at(1, 0),
+		at(6, 12), at(6, 13),
+		at(7, 12), // outside a 7 day window
+		{Type: EventTaskCreated, Timestamp: now.Add(24 * time.Hour)}, // in the future
+	}
+
+	counts := HeatmapData(events, 7, now)
+	if len(counts) != 7 {
+		t.Fatalf("Expected 7 days, got %d: %v", len(counts), counts)
+	}
+	want := map[string]int{
+		"2024-03-10": 3,
+		"2024-03-09": 1,
+		"2024-03-08": 0,
+		"2024-03-04": 2,
+	}
+	for day, count := range want {
+		if counts[day] != count {
+			t.Errorf("%s: expected %d events, got %d", day, count, counts[day])
+		}
+	}
+	if _, ok := counts["2024-03-03"]; ok {
+		t.Errorf("Days outside the window must be omitted")
+	}
+}
+
+func TestHeatmapDataUsesNowTimeZone(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, tokyo)
+	// 20:00 UTC on the 9th is already the 10th in Tokyo
+	event := BoardEvent{Timestamp: time.Date(2024, 3, 9, 20, 0, 0, 0, time.UTC)}
+
+	if counts := HeatmapData([]BoardEvent{event}, 2, now); counts["2024-03-10"] != 1 {
+		t.Errorf("Expected event on 2024-03-10 in JST, got %v", counts)
+	}
+}
+
+func TestHeatmapHandlers(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("A", "")
+	s.MoveTask(task.ID, "doing")
+
+	w := httptest.NewRecorder()
+	heatmapHandler(w, httptest.NewRequest(http.MethodGet, "/board/stats/heatmap?days=30", nil))
+	var counts map[string]int
+	json.Unmarshal(w.Body.Bytes(), &counts)
+	if len(counts) != 30 || counts[time.Now().Format("2006-01-02")] != 2 {
+		t.Errorf("Expected 30 days with 2 events today, got %v", counts)
+	}
+
+	w = httptest.NewRecorder()
+	heatmapHandler(w, httptest.NewRequest(http.MethodGet, "/board/stats/heatmap?days=0", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for days=0, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	boardStatsHandler(w, httptest.NewRequest(http.MethodGet, "/board/stats", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, "<canvas") || !strings.Contains(body, `"`+time.Now().Format("2006-01-02")+`":2`) {
+		t.Errorf("Expected canvas and embedded heatmap JSON, got %s", body)
+	}
+}
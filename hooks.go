@@ -0,0 +1,38 @@
+package main
+
+// TransitionHook runs when a task is about to change status. It is called
+// with the store locked, after the workflow and accept policies have allowed
+// the move, and may change the task's other fields, for instance to assign
+// it. Returning an error aborts the move. Hooks must not call back into the
+// store.
+type TransitionHook func(task *Task, fromStatus, toStatus string) error
+
+// RegisterTransitionHook adds a hook to run on every move, after the
+// built-in ones and any registered earlier
+func (s *TaskStore) RegisterTransitionHook(hook TransitionHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+// enforceWIPLimit is the built-in hook refusing moves into a full column
+func (s *TaskStore) enforceWIPLimit(task *Task, fromStatus, toStatus string) error {
+	return s.checkWIPLimit(fromStatus, toStatus)
+}
+
+// runTransitionHooks calls the built-in hooks and then the registered ones
+// in order on a copy of task, stopping at the first error. It returns the
+// copy with the hooks' changes, leaving task itself untouched so an aborted
+// move changes nothing (must be called with lock held).
+func (s *TaskStore) runTransitionHooks(task *Task, fromStatus, toStatus string) (*Task, error) {
+	candidate := task.clone()
+	hooks := append([]TransitionHook{s.enforceWIPLimit, s.unpinIfColumnFull}, s.hooks...)
+	for _, hook := range hooks {
+		if err := hook(candidate, fromStatus, toStatus); err != nil {
+			return nil, &ErrHookRejected{Err: err}
+		}
+	}
+	candidate.ID = task.ID
+	candidate.Status = task.Status
+	return candidate, nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errNoDueDate = errors.New("tasks need a due date before they can be done")
+
+// requireDueDateForDone rejects moves to done for tasks without a due date
+func requireDueDateForDone(task *Task, fromStatus, toStatus string) error {
+	if toStatus == "done" && task.DueDate == nil {
+		return errNoDueDate
+	}
+	return nil
+}
+
+func TestTransitionHookRejectsMove(t *testing.T) {
+	s := newTestStore()
+	calls := 0
+	s.RegisterTransitionHook(func(task *Task, fromStatus, toStatus string) error {
+		calls++
+		return requireDueDateForDone(task, fromStatus, toStatus)
+	})
+	undated := s.AddTask("Undated", "")
+	due := time.Now().Add(24 * time.Hour)
+	dated, _ := s.CreateTask(TaskSpec{Title: "Dated", DueDate: &due})
+
+	if _, _, err := s.MoveTask(undated.ID, "done"); !errors.Is(err, errNoDueDate) {
+		t.Errorf("Expected the hook to reject the move, got %v", err)
+	}
+	if task, _ := s.GetTask(undated.ID); task.Status != "todo" {
+		t.Errorf("Expected a rejected move to leave the task in todo, got %s", task.Status)
+	}
+	if _, _, err := s.MoveTask(dated.ID, "done"); err != nil {
+		t.Errorf("Expected the dated task to move, got %v", err)
+	}
+	if _, _, err := s.MoveTask(undated.ID, "doing"); err != nil {
+		t.Errorf("Expected moves elsewhere to pass, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected the hook to fire on every move, got %d calls", calls)
+	}
+}
+
+func TestTransitionHooksRunInOrder(t *testing.T) {
+	s := newTestStore()
+	var order []string
+	s.RegisterTransitionHook(func(task *Task, fromStatus, toStatus string) error {
+		order = append(order, "assign")
+		if toStatus == "doing" && task.Assignee == "" {
+			task.Assignee = "on-call"
+		}
+		return nil
+	})
+	s.RegisterTransitionHook(func(task *Task, fromStatus, toStatus string) error {
+		order = append(order, "check:"+task.Assignee)
+		return nil
+	})
+	task := s.AddTask("Auto-assign", "")
+
+	moved, _, err := s.MoveTask(task.ID, "doing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(order, ",") != "assign,check:on-call" {
+		t.Errorf("Expected hooks in registration order, got %v", order)
+	}
+	if moved.Assignee != "on-call" || moved.Status != "doing" {
+		t.Errorf("Expected the hook's change to be saved with the move, got %+v", moved)
+	}
+}
+
+func TestFailedHookDiscardsEarlierChanges(t *testing.T) {
+	s := newTestStore()
+	s.RegisterTransitionHook(func(task *Task, fromStatus, toStatus string) error {
+		task.Assignee = "someone"
+		task.Status = "archived"
+		return nil
+	})
+	s.RegisterTransitionHook(requireDueDateForDone)
+	task := s.AddTask("Rollback", "")
+
+	s.MoveTask(task.ID, "done")
+	if got, _ := s.GetTask(task.ID); got.Assignee != "" || got.Status != "todo" {
+		t.Errorf("Expected an aborted move to change nothing, got %+v", got)
+	}
+	moved, _, _ := s.MoveTask(task.ID, "doing")
+	if moved.Status != "doing" {
+		t.Errorf("Expected hooks not to override the new status, got %s", moved.Status)
+	}
+}
+
+func TestWIPLimitRunsAsBuiltInHook(t *testing.T) {
+	s := newTestStore()
+	s.wipLimits = map[string]int{"doing": 1}
+	calls := 0
+	s.RegisterTransitionHook(func(task *Task, fromStatus, toStatus string) error {
+		calls++
+		return nil
+	})
+	s.AddTask("One", "")
+	s.AddTask("Two", "")
+	s.MoveTask(1, "doing")
+
+	var wipErr *ErrWIPLimitReached
+	if _, _, err := s.MoveTask(2, "doing"); !errors.As(err, &wipErr) {
+		t.Errorf("Expected ErrWIPLimitReached, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the WIP limit to stop the move before registered hooks, got %d calls", calls)
+	}
+}
+
+func TestMoveTaskHandlerHookError(t *testing.T) {
+	s := withTestGlobals(t)
+	s.RegisterTransitionHook(requireDueDateForDone)
+	s.AddTask("Undated", "")
+
+	req := httptest.NewRequest(http.MethodPost, "/move-task", strings.NewReader("id=1&status=done"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	NewServer(s).moveTaskHandler(w, req)
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), errNoDueDate.Error()) {
+		t.Errorf("Expected 422 with the hook's error, got %d: %s", w.Code, w.Body.String())
+	}
+}
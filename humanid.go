@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// taskPrefixPattern limits KANBAN_TASK_PREFIX to a short word like PROJ
+var taskPrefixPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]{0,9}$`)
+
+// loadTaskIDPrefix reads KANBAN_TASK_PREFIX, ignoring an invalid one
+func loadTaskIDPrefix() string {
+	prefix := os.Getenv("KANBAN_TASK_PREFIX")
+	if prefix != "" && !taskPrefixPattern.MatchString(prefix) {
+		log.Printf("Ignoring KANBAN_TASK_PREFIX %q: use 1-10 letters and digits, starting with a letter", prefix)
+		return ""
+	}
+	return prefix
+}
+
+// taskIDPrefix turns task IDs into human-readable ones like PROJ-42 in the
+// UI and API, while tasks keep their integer IDs
+var taskIDPrefix = loadTaskIDPrefix()
+
+// HumanID returns the task's ID for display: PROJ-42 with a prefix, and
+// #42 without one
+func (t *Task) HumanID() string {
+	if taskIDPrefix == "" {
+		return "#" + strconv.Itoa(t.ID)
+	}
+	return taskIDPrefix + "-" + strconv.Itoa(t.ID)
+}
+
+// ParseTaskID reads a task ID given either as an integer or as a
+// human-readable ID with the prefix, which is matched ignoring case
+func ParseTaskID(s string, prefix string) (int, error) {
+	if id, err := strconv.Atoi(s); err == nil {
+		return id, nil
+	}
+	head, number, ok := strings.Cut(s, "-")
+	if !ok || prefix == "" {
+		return 0, fmt.Errorf("invalid task ID %q", s)
+	}
+	if !strings.EqualFold(head, prefix) {
+		return 0, fmt.Errorf("task ID %q doesn't start with %s-", s, prefix)
+	}
+	id, err := strconv.Atoi(number)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid task ID %q", s)
+	}
+	return id, nil
+}
+
+// taskJSON has Task's fields without its MarshalJSON
+type taskJSON Task
+
+// MarshalJSON adds the human-readable ID to a task's JSON when a prefix is
+// set. It has a value receiver so tasks held by value get it too.
+func (t Task) MarshalJSON() ([]byte, error) {
+	if taskIDPrefix == "" {
+		return json.Marshal(taskJSON(t))
+	}
+	return json.Marshal(struct {
+		taskJSON
+		HumanID string `json:"human_id"`
+	}{taskJSON(t), t.HumanID()})
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// withTaskIDPrefix sets KANBAN_TASK_PREFIX for the test
+func withTaskIDPrefix(t *testing.T, prefix string) {
+	orig := taskIDPrefix
+	t.Cleanup(func() { taskIDPrefix = orig })
+	taskIDPrefix = prefix
+}
+
+func TestParseTaskID(t *testing.T) {
+	for _, tc := range []struct {
+		in, prefix string
+		want       int
+	}{
+		{"42", "PROJ", 42},
+		{"42", "", 42},
+		{"PROJ-42", "PROJ", 42},
+		{"proj-7", "PROJ", 7},
+	} {
+		if got, err := ParseTaskID(tc.in, tc.prefix); err != nil || got != tc.want {
+			t.Errorf("ParseTaskID(%q, %q) = %d, %v; want %d", tc.in, tc.prefix, got, err, tc.want)
+		}
+	}
+	for _, tc := range []struct{ in, prefix string }{
+		{"OTHER-42", "PROJ"},
+		{"PROJ-42", ""},
+		{"PROJ-", "PROJ"},
+		{"PROJ-x", "PROJ"},
+		{"PROJ-0", "PROJ"},
+		{"PROJ42", "PROJ"},
+	} {
+		if _, err := ParseTaskID(tc.in, tc.prefix); err == nil {
+			t.Errorf("Expected ParseTaskID(%q, %q) to fail", tc.in, tc.prefix)
+		}
+	}
+}
+
+func TestHumanID(t *testing.T) {
+	task := &Task{ID: 42, Title: "Plan"}
+	if got := task.HumanID(); got != "#42" {
+		t.Errorf("Expected #42 without a prefix, got %q", got)
+	}
+	if data, _ := json.Marshal(task); strings.Contains(string(data), "human_id") {
+		t.Errorf("Expected no human_id without a prefix, got %s", data)
+	}
+
+	withTaskIDPrefix(t, "PROJ")
+	if got := task.HumanID(); got != "PROJ-42" {
+		t.Errorf("Expected PROJ-42, got %q", got)
+	}
+	var decoded map[string]interface{}
+	data, _ := json.Marshal(task)
+	json.Unmarshal(data, &decoded)
+	if decoded["human_id"] != "PROJ-42" || decoded["id"] != float64(42) || decoded["title"] != "Plan" {
+		t.Errorf("Expected the task JSON with a human_id, got %s", data)
+	}
+}
+
+func TestTaskRoutesAcceptHumanIDs(t *testing.T) {
+	withTaskIDPrefix(t, "PROJ")
+	s := withTestGlobals(t)
+	s.AddTask("Write docs", "")
+
+	rr := httptest.NewRecorder()
+	NewServer(s).taskRouter(rr, httptest.NewRequest(http.MethodGet, "/task/PROJ-1", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `<span class="task-id">PROJ-1</span>`) {
+		t.Errorf("Expected the detail page for PROJ-1, got %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	NewServer(s).taskRouter(rr, httptest.NewRequest(http.MethodGet, "/task/1", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected integer IDs to keep working, got %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	NewServer(s).taskRouter(rr, httptest.NewRequest(http.MethodGet, "/task/OTHER-1", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for another prefix, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	NewServer(s).indexHandler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(rr.Body.String(), `<span class="task-id">PROJ-1</span>`) {
+		t.Errorf("Expected the card to show PROJ-1")
+	}
+}
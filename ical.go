@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// icalTimeFormat is the RFC 5545 UTC DATE-TIME form
+const icalTimeFormat = "20060102T150405Z"
+
+// icalStatuses maps kanban statuses to RFC 5545 to-do statuses
+var icalStatuses = map[string]string{
+	"todo":  "NEEDS-ACTION",
+	"doing": "IN-PROCESS",
+	"done":  "COMPLETED",
+}
+
+// WriteICal renders every task with a due date as an iCalendar document.
+// Tasks are emitted as VTODO components, the RFC 5545 component that
+// carries DUE and the NEEDS-ACTION/IN-PROCESS/COMPLETED statuses; a VEVENT
+// with those properties would not validate.
+func WriteICal(tasks []*Task, now time.Time) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICalLine(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//go-htmx-demo//Kanban Board//EN")
+	line("CALSCALE:GREGORIAN")
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		due := task.DueDate.UTC()
+		line("BEGIN:VTODO")
+		line(fmt.Sprintf("UID:task-%d@go-htmx-demo", task.ID))
+		line("DTSTAMP:" + now.UTC().Format(icalTimeFormat))
+		// RFC 5545 requires DUE to be later than DTSTART
+		if !task.CreatedAt.IsZero() && task.CreatedAt.Before(due) {
+			line("DTSTART:" + task.CreatedAt.UTC().Format(icalTimeFormat))
+		}
+		line("DUE:" + due.Format(icalTimeFormat))
+		line("SUMMARY:" + escapeICalText(task.Title))
+		if task.Description != "" {
+			line("DESCRIPTION:" + escapeICalText(task.Description))
+		}
+		if status, ok := icalStatuses[task.Status]; ok {
+			line("STATUS:" + status)
+		}
+		if len(task.Labels) > 0 {
+			escaped := make([]string, len(task.Labels))
+			for i, label := range task.Labels {
+				escaped[i] = escapeICalText(label)
+			}
+			line("CATEGORIES:" + strings.Join(escaped, ","))
+		}
+		line("END:VTODO")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// escapeICalText escapes a TEXT value per RFC 5545 section 3.3.11
+func escapeICalText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	return r.Replace(s)
+}
+
+// foldICalLine splits content lines longer than 75 octets, continuing each
+// with a single space, without breaking multi-byte characters
+func foldICalLine(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
+
+// exportICalHandler serves tasks with due dates as a downloadable .ics file
+func (srv *Server) exportICalHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="kanban.ics"`)
+	fmt.Fprint(w, WriteICal(srv.GetAllTasks(), time.Now()))
+}
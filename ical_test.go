@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseICal is a minimal iCalendar reader: it unfolds lines and returns the
+// properties of each VTODO component
+func parseICal(t *testing.T, body string) []map[string]string {
+	if !strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(body, "END:VCALENDAR\r\n") {
+		t.Fatalf("Not a VCALENDAR document: %q", body)
+	}
+	unfolded := strings.ReplaceAll(body, "\r\n ", "")
+
+	var todos []map[string]string
+	var cur map[string]string
+	for _, line := range strings.Split(strings.TrimSuffix(unfolded, "\r\n"), "\r\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			t.Fatalf("Malformed content line %q", line)
+		}
+		switch {
+		case line == "BEGIN:VTODO":
+			cur = make(map[string]string)
+		case line == "END:VTODO":
+			todos = append(todos, cur)
+			cur = nil
+		case cur != nil:
+			cur[name] = value
+		}
+	}
+	return todos
+}
+
+func TestExportICal(t *testing.T) {
+	s := withTestGlobals(t)
+	created := time.Date(2024, 1, 10, 9, 30, 0, 0, time.UTC)
+	s.now = func() time.Time { return created }
+
+	due := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	s.CreateTask(TaskSpec{Title: "Ship release, v2", Description: "Tag; build\nannounce", DueDate: &due})
+	s.CreateTask(TaskSpec{Title: "No deadline"})
+	doing, _ := s.CreateTask(TaskSpec{Title: "In flight", DueDate: &due})
+	s.MoveTask(doing.ID, "doing")
+	done, _ := s.CreateTask(TaskSpec{Title: "Finished", DueDate: &due})
+	s.MoveTask(done.ID, "done")
+
+	req := httptest.NewRequest(http.MethodGet, "/export/ical", nil)
+	w := httptest.NewRecorder()
+	NewServer(s).exportICalHandler(w, req)
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("Expected text/calendar, got %q", ct)
+	}
+	todos := parseICal(t, w.Body.String())
+	if len(todos) != 3 {
+		t.Fatalf("Expected 3 VTODOs, got %d", len(todos))
+	}
+
+	first := todos[0]
+	if first["SUMMARY"] != `Ship release\, v2` {
+		t.Errorf("Unexpected SUMMARY %q", first["SUMMARY"])
+	}
+	if first["DESCRIPTION"] != `Tag\; build\nannounce` {
+		t.Errorf("Unexpected DESCRIPTION %q", first["DESCRIPTION"])
+	}
+	if first["DTSTART"] != "20240110T093000Z" || first["DUE"] != "20240201T000000Z" {
+		t.Errorf("Unexpected dates DTSTART=%q DUE=%q", first["DTSTART"], first["DUE"])
+	}
+	if first["UID"] == "" || first["DTSTAMP"] == "" {
+		t.Errorf("UID and DTSTAMP are required: %+v", first)
+	}
+
+	statuses := []string{todos[0]["STATUS"], todos[1]["STATUS"], todos[2]["STATUS"]}
+	want := []string{"NEEDS-ACTION", "IN-PROCESS", "COMPLETED"}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("Task %d: expected STATUS %s, got %s", i, want[i], statuses[i])
+		}
+	}
+}
+
+func TestExportICalOmitsDTSTARTAfterDue(t *testing.T) {
+	due := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := &Task{ID: 1, Title: "Late", Status: "todo", DueDate: &due, CreatedAt: due.Add(time.Hour)}
+
+	todos := parseICal(t, WriteICal([]*Task{task}, due))
+	if _, ok := todos[0]["DTSTART"]; ok {
+		t.Errorf("DTSTART must be omitted when it is not before DUE")
+	}
+}
+
+func TestFoldICalLine(t *testing.T) {
+	long := "SUMMARY:" + strings.Repeat("é", 60)
+	folded := foldICalLine(long)
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("Folded line is %d octets", len(part))
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != long {
+		t.Errorf("Unfolding did not restore the original line")
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	defaultIdleCheckInterval = 24 * time.Hour
+	defaultIdleThreshold     = 72 * time.Hour
+)
+
+// loadIdleDuration reads a positive duration such as 12h from the named
+// environment variable, falling back to def
+func loadIdleDuration(name string, def time.Duration) time.Duration {
+	if raw := os.Getenv(name); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			return d
+		}
+	}
+	return def
+}
+
+// idleCheckInterval is how often assigned tasks are checked for idleness
+// (KANBAN_IDLE_CHECK_INTERVAL), and idleThreshold how long a task can go
+// without an update before its assignee is nudged (KANBAN_IDLE_THRESHOLD)
+var (
+	idleCheckInterval = loadIdleDuration("KANBAN_IDLE_CHECK_INTERVAL", defaultIdleCheckInterval)
+	idleThreshold     = loadIdleDuration("KANBAN_IDLE_THRESHOLD", defaultIdleThreshold)
+)
+
+// GetIdleTasks returns the assigned tasks not yet done that have not been
+// updated within the threshold, ordered by ID. Archived tasks are skipped.
+func (s *TaskStore) GetIdleTasks(threshold time.Duration) []*Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.clock().Add(-threshold)
+	var tasks []*Task
+	for _, task := range s.tasks {
+		if task.Assignee != "" && task.Status != "done" && task.ArchivedAt == nil &&
+			!task.UpdatedAt.IsZero() && task.UpdatedAt.Before(cutoff) {
+			tasks = append(tasks, task.clone())
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
+// CheckIdleTasks publishes a TaskIdle event for each idle task and returns
+// them. A task stays idle, and is reported again on the next check, until
+// someone updates it.
+func (s *TaskStore) CheckIdleTasks(threshold time.Duration) []*Task {
+	idle := s.GetIdleTasks(threshold)
+	if s.events == nil {
+		return idle
+	}
+	now := s.clock()
+	for _, task := range idle {
+		s.events.Publish(Event{Type: EventTaskIdle, Task: task, Actor: "system", Time: now})
+	}
+	return idle
+}
+
+// runIdleChecker checks for idle tasks on every tick until stop is closed
+func (s *TaskStore) runIdleChecker(ticks <-chan time.Time, threshold time.Duration, stop <-chan struct{}) {
+	for {
+		select {
+		case <-ticks:
+			s.CheckIdleTasks(threshold)
+		case <-stop:
+			return
+		}
+	}
+}
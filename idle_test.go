@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckIdleTasks(t *testing.T) {
+	s := withTestGlobals(t)
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	current := start
+	s.now = func() time.Time { return current }
+
+	var events []Event
+	bus.Subscribe(EventTaskIdle, func(e Event) { events = append(events, e) })
+
+	forgotten, _ := s.CreateTask(TaskSpec{Title: "Forgotten", Assignee: "alice"})
+	s.CreateTask(TaskSpec{Title: "Unassigned"})
+	finished, _ := s.CreateTask(TaskSpec{Title: "Finished", Assignee: "bob"})
+	s.MoveTask(finished.ID, "done")
+	recent, _ := s.CreateTask(TaskSpec{Title: "Recent", Assignee: "carol"})
+
+	current = start.Add(2 * 24 * time.Hour)
+	s.AssignTask(recent.ID, "dave")
+
+	current = start.Add(4 * 24 * time.Hour)
+	idle := s.CheckIdleTasks(72 * time.Hour)
+	if !equalIDs(taskIDs(idle), []int{forgotten.ID}) {
+		t.Fatalf("Expected only the forgotten task to be idle, got %v", taskIDs(idle))
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected one idle event, got %d", len(events))
+	}
+	e := events[0]
+	if e.Task.ID != forgotten.ID || e.Task.Assignee != "alice" {
+		t.Errorf("Expected task %d assigned to alice, got task %d assigned to %q", forgotten.ID, e.Task.ID, e.Task.Assignee)
+	}
+	if !e.Time.Equal(current) {
+		t.Errorf("Expected the event stamped %v, got %v", current, e.Time)
+	}
+	if history := auditLog.GetTaskHistory(forgotten.ID); len(history) != 1 {
+		t.Errorf("Expected idle events kept out of the task history, got %+v", history)
+	}
+
+	// Another day on, the reassigned task is idle too
+	current = start.Add(5*24*time.Hour + time.Hour)
+	events = nil
+	s.CheckIdleTasks(72 * time.Hour)
+	if len(events) != 2 || events[1].Task.ID != recent.ID || events[1].Task.Assignee != "dave" {
+		t.Errorf("Expected both tasks reported, the second for dave, got %+v", events)
+	}
+}
+
+func TestIdleCheckerRunsOnTick(t *testing.T) {
+	s := withTestGlobals(t)
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	current := start
+	s.now = func() time.Time { return current }
+	s.CreateTask(TaskSpec{Title: "Forgotten", Assignee: "alice"})
+	current = start.Add(4 * 24 * time.Hour)
+
+	idle := make(chan Event, 1)
+	bus.Subscribe(EventTaskIdle, func(e Event) { idle <- e })
+
+	ticks := make(chan time.Time)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		s.runIdleChecker(ticks, 72*time.Hour, stop)
+		close(done)
+	}()
+	ticks <- current
+	close(stop)
+	<-done
+
+	select {
+	case e := <-idle:
+		if e.Task.Assignee != "alice" {
+			t.Errorf("Expected alice nudged, got %q", e.Task.Assignee)
+		}
+	default:
+		t.Errorf("Expected an idle event after the tick")
+	}
+}
+
+func TestLoadIdleDuration(t *testing.T) {
+	t.Setenv("KANBAN_IDLE_THRESHOLD", "")
+	if d := loadIdleDuration("KANBAN_IDLE_THRESHOLD", defaultIdleThreshold); d != 72*time.Hour {
+		t.Errorf("Expected the 72h default, got %v", d)
+	}
+	t.Setenv("KANBAN_IDLE_THRESHOLD", "36h")
+	if d := loadIdleDuration("KANBAN_IDLE_THRESHOLD", defaultIdleThreshold); d != 36*time.Hour {
+		t.Errorf("Expected 36h, got %v", d)
+	}
+	for _, raw := range []string{"3 days", "-1h", "0s"} {
+		t.Setenv("KANBAN_IDLE_CHECK_INTERVAL", raw)
+		if d := loadIdleDuration("KANBAN_IDLE_CHECK_INTERVAL", defaultIdleCheckInterval); d != 24*time.Hour {
+			t.Errorf("%q: expected the 24h default, got %v", raw, d)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ValidationIssue is one problem with one entry of an import. Row is the
+// entry's index among the parsed tasks, from 0.
+type ValidationIssue struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidateImport checks a batch before it is imported, reporting every
+// problem rather than stopping at the first: missing or overlong titles,
+// unknown statuses, out of range priorities and efforts, labels that aren't
+// registered, unparseable due dates, and titles repeated within the batch.
+// Labels are only checked once some are registered.
+func (s *TaskStore) ValidateImport(tasks []TaskInput) []ValidationIssue {
+	issues := []ValidationIssue{}
+	add := func(row int, field, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{Row: row, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	checkLabels := len(s.labels().List()) > 0
+
+	firstRow := make(map[string]int)
+	for i, in := range tasks {
+		title := strings.TrimSpace(in.Title)
+		switch {
+		case title == "":
+			add(i, "title", "title is required")
+		case len(title) > maxTitleLength:
+			add(i, "title", "title is longer than %d characters", maxTitleLength)
+		}
+		if title != "" {
+			key := strings.ToLower(title)
+			if first, ok := firstRow[key]; ok {
+				add(i, "title", "title duplicates row %d", first)
+			} else {
+				firstRow[key] = i
+			}
+		}
+		if in.Status != "" && !s.hasColumn(in.Status) {
+			add(i, "status", "invalid status %q", in.Status)
+		}
+		if in.Priority < PriorityNone || in.Priority > PriorityHigh {
+			add(i, "priority", "invalid priority %d", in.Priority)
+		}
+		if in.Effort < 0 {
+			add(i, "effort", "effort must not be negative")
+		}
+		if checkLabels {
+			for _, label := range in.Labels {
+				if _, ok := s.labels().Get(strings.TrimSpace(label)); !ok {
+					add(i, "labels", "unknown label %q", label)
+				}
+			}
+		}
+		if in.DueDate != "" {
+			if _, err := parseDueDate(in.DueDate); err != nil {
+				add(i, "due_date", "%v", err)
+			}
+		}
+	}
+	return issues
+}
+
+// specInputs turns parsed specs back into inputs for ValidateImport
+func specInputs(specs []TaskSpec) []TaskInput {
+	inputs := make([]TaskInput, len(specs))
+	for i, spec := range specs {
+		inputs[i] = TaskInput{
+			Title:       spec.Title,
+			Description: spec.Description,
+			Status:      spec.Status,
+			Assignee:    spec.Assignee,
+			Effort:      spec.Effort,
+			Priority:    spec.Priority,
+			Labels:      spec.Labels,
+		}
+		if spec.DueDate != nil {
+			inputs[i].DueDate = spec.DueDate.Format("2006-01-02")
+		}
+	}
+	return inputs
+}
+
+// importValidateHandler serves POST /import/validate. It takes either body
+// /import/text does, or the JSON array /api/v1/tasks/bulk does, and returns
+// {"tasks": n, "issues": [...]} without creating anything.
+func (srv *Server) importValidateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var inputs []TaskInput
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		r.Body = http.MaxBytesReader(w, r.Body, maxTextImportBytes)
+		if err := json.NewDecoder(r.Body).Decode(&inputs); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		text, ok := readImportText(w, r)
+		if !ok {
+			return
+		}
+		inputs = specInputs(ParseTextImport(text))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tasks":  len(inputs),
+		"issues": srv.ValidateImport(inputs),
+	})
+}
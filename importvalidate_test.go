@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateImport(t *testing.T) {
+	s := newTestStore()
+	s.settings = withTestSettings(t)
+	s.labels().Create("bug", "#ef4444")
+
+	issues := s.ValidateImport([]TaskInput{
+		{Title: "Fine", Labels: []string{"Bug"}, DueDate: "2026-01-02"},
+		{Title: "  "},
+		{Title: strings.Repeat("x", maxTitleLength+1)},
+		{Title: "Moved", Status: "archived"},
+		{Title: "Urgent", Priority: 9},
+		{Title: "Tagged", Labels: []string{"bug", "feature"}},
+		{Title: "Dated", DueDate: "next week"},
+		{Title: "fine "},
+	})
+
+	want := []struct {
+		row   int
+		field string
+	}{
+		{1, "title"}, {2, "title"}, {3, "status"}, {4, "priority"}, {5, "labels"}, {6, "due_date"}, {7, "title"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %+v", len(want), issues)
+	}
+	for i, w := range want {
+		if issues[i].Row != w.row || issues[i].Field != w.field {
+			t.Errorf("Expected issue %d on row %d %s, got %+v", i, w.row, w.field, issues[i])
+		}
+	}
+	if !strings.Contains(issues[6].Message, "row 0") {
+		t.Errorf("Expected the duplicate to name row 0, got %q", issues[6].Message)
+	}
+}
+
+func TestImportValidateHandler(t *testing.T) {
+	s := withTestGlobals(t)
+
+	for _, tc := range []struct {
+		contentType, body string
+	}{
+		{"text/plain", "- Write docs\n- write docs\n- Ship it\n"},
+		{"application/json", `[{"title": "Write docs"}, {"title": ""}, {"title": "Ship it", "status": "archived"}]`},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/import/validate", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		rr := httptest.NewRecorder()
+		NewServer(s).importValidateHandler(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected 200 for %s, got %d: %s", tc.contentType, rr.Code, rr.Body.String())
+		}
+		var report struct {
+			Tasks  int               `json:"tasks"`
+			Issues []ValidationIssue `json:"issues"`
+		}
+		json.Unmarshal(rr.Body.Bytes(), &report)
+		if report.Tasks != 3 || len(report.Issues) == 0 {
+			t.Errorf("Unexpected report for %s: %s", tc.contentType, rr.Body.String())
+		}
+	}
+	if n := len(s.GetAllTasks()); n != 0 {
+		t.Errorf("Expected validation to create no tasks, got %d", n)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+)
+
+// Integrity problem kinds
+const (
+	IntegrityDuplicateID   = "duplicate_id"
+	IntegrityNextID        = "next_id"
+	IntegrityInvalidStatus = "invalid_status"
+	IntegrityOrphanLinks   = "orphan_links"
+	IntegrityDanglingDeps  = "dangling_dependency"
+	IntegrityOrphanSubtask = "orphan_subtask"
+)
+
+// IntegrityError describes one inconsistency in the stored board. Fixable
+// problems can be corrected by Repair; the rest need a manual edit.
+type IntegrityError struct {
+	Kind    string `json:"kind"`
+	TaskID  int    `json:"task_id,omitempty"`
+	Message string `json:"message"`
+	Fixable bool   `json:"fixable"`
+}
+
+// CheckIntegrity looks for problems left by crashes or manual edits of the
+// data file: task IDs that appear more than once on disk (only the last copy
+// is loaded), a next ID that would reuse an existing one, tasks in unknown
+// columns, dependencies on and subtasks of tasks that no longer exist, and
+// links to them
+func (s *TaskStore) CheckIntegrity() []IntegrityError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	problems := []IntegrityError{}
+	problems = append(problems, s.duplicateIDs()...)
+
+	ids := make([]int, 0, len(s.tasks))
+	for id := range s.tasks {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	if len(ids) > 0 && s.nextID <= ids[len(ids)-1] {
+		problems = append(problems, IntegrityError{
+			Kind:    IntegrityNextID,
+			Message: fmt.Sprintf("next ID %d is not above the highest task ID %d", s.nextID, ids[len(ids)-1]),
+			Fixable: true,
+		})
+	}
+	for _, id := range ids {
+		if status := s.tasks[id].Status; !s.hasColumn(status) {
+			problems = append(problems, IntegrityError{
+				Kind:    IntegrityInvalidStatus,
+				TaskID:  id,
+				Message: fmt.Sprintf("task %d has unknown status %q", id, status),
+			})
+		}
+		if missing := s.missingDependencies(s.tasks[id]); len(missing) > 0 {
+			problems = append(problems, IntegrityError{
+				Kind:    IntegrityDanglingDeps,
+				TaskID:  id,
+				Message: fmt.Sprintf("task %d depends on missing tasks %s", id, formatIDs(missing)),
+				Fixable: true,
+			})
+		}
+		if missing := s.missingSubtasks(s.tasks[id]); len(missing) > 0 {
+			problems = append(problems, IntegrityError{
+				Kind:    IntegrityOrphanSubtask,
+				TaskID:  id,
+				Message: fmt.Sprintf("task %d has subtasks of missing tasks %s", id, formatIDs(missing)),
+				Fixable: true,
+			})
+		}
+	}
+
+	var orphans []int
+	for id := range s.links {
+		if _, ok := s.tasks[id]; !ok {
+			orphans = append(orphans, id)
+		}
+	}
+	sort.Ints(orphans)
+	for _, id := range orphans {
+		problems = append(problems, IntegrityError{
+			Kind:    IntegrityOrphanLinks,
+			TaskID:  id,
+			Message: fmt.Sprintf("%d links refer to missing task %d", len(s.links[id]), id),
+			Fixable: true,
+		})
+	}
+	return problems
+}
+
+// missingDependencies returns the IDs a task depends on that aren't tasks
+// (must be called with lock held)
+func (s *TaskStore) missingDependencies(task *Task) []int {
+	var missing []int
+	for _, dep := range task.DependsOn {
+		if _, ok := s.tasks[dep]; !ok {
+			missing = append(missing, dep)
+		}
+	}
+	return missing
+}
+
+// missingSubtasks returns the task IDs of a task's checklist items that were
+// converted from tasks which no longer exist (must be called with lock held)
+func (s *TaskStore) missingSubtasks(task *Task) []int {
+	var missing []int
+	for _, item := range task.Checklist {
+		if item.TaskID == 0 {
+			continue
+		}
+		if _, ok := s.tasks[item.TaskID]; !ok {
+			missing = append(missing, item.TaskID)
+		}
+	}
+	return missing
+}
+
+// duplicateIDs re-reads the data file to find task IDs stored more than
+// once, which loading collapses into one task (must be called with lock
+// held). Partition files are already checked for duplicates on load, and
+// backends keep IDs unique themselves.
+func (s *TaskStore) duplicateIDs() []IntegrityError {
+	if s.backend != nil || s.partitions != nil {
+		return nil
+	}
+	raw, err := os.ReadFile(s.filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Error reading %s for integrity check: %v", s.filePath, err)
+		}
+		return nil
+	}
+	var data PersistentData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		log.Printf("Error decoding %s for integrity check: %v", s.filePath, err)
+		return nil
+	}
+
+	counts := make(map[int]int)
+	var dupes []int
+	for _, task := range data.Tasks {
+		counts[task.ID]++
+		if counts[task.ID] == 2 {
+			dupes = append(dupes, task.ID)
+		}
+	}
+	sort.Ints(dupes)
+
+	var problems []IntegrityError
+	for _, id := range dupes {
+		problems = append(problems, IntegrityError{
+			Kind:    IntegrityDuplicateID,
+			TaskID:  id,
+			Message: fmt.Sprintf("task ID %d appears %d times in %s; only the last copy was loaded", id, counts[id], s.filePath),
+		})
+	}
+	return problems
+}
+
+// Repair fixes the fixable problems found by CheckIntegrity, bumping the next
+// ID past every task, dropping dependencies on and links to missing tasks,
+// and turning subtasks of missing tasks into plain checklist items, then
+// saves the board. It returns how many problems it fixed, or ErrBoardLocked.
+func (s *TaskStore) Repair(problems []IntegrityError) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkLocked(); err != nil {
+		return 0, err
+	}
+
+	fixed := 0
+	for _, p := range problems {
+		switch p.Kind {
+		case IntegrityNextID:
+			maxID := 0
+			for id := range s.tasks {
+				if id > maxID {
+					maxID = id
+				}
+			}
+			if s.nextID <= maxID {
+				s.nextID = maxID + 1
+				fixed++
+			}
+		case IntegrityOrphanLinks:
+			if _, ok := s.tasks[p.TaskID]; !ok && len(s.links[p.TaskID]) > 0 {
+				delete(s.links, p.TaskID)
+				fixed++
+			}
+		case IntegrityDanglingDeps:
+			task, ok := s.tasks[p.TaskID]
+			if !ok || len(s.missingDependencies(task)) == 0 {
+				continue
+			}
+			var deps []int
+			for _, dep := range task.DependsOn {
+				if _, ok := s.tasks[dep]; ok {
+					deps = append(deps, dep)
+				}
+			}
+			task.DependsOn = deps
+			s.deps.set(task.ID, deps)
+			fixed++
+		case IntegrityOrphanSubtask:
+			task, ok := s.tasks[p.TaskID]
+			if !ok || len(s.missingSubtasks(task)) == 0 {
+				continue
+			}
+			for i, item := range task.Checklist {
+				if _, ok := s.tasks[item.TaskID]; item.TaskID != 0 && !ok {
+					task.Checklist[i].TaskID = 0
+				}
+			}
+			fixed++
+		}
+	}
+	if fixed > 0 {
+		s.persist(s.allStatuses()...)
+		if s.partitions != nil {
+			s.persistLinks()
+		}
+		s.mirrorBoard(nil)
+	}
+	return fixed, nil
+}
+
+// checkIntegrityHandler serves POST /admin/check-integrity, returning the
+// problems found as {"errors": [...], "repaired": n}. Fixable problems are
+// only repaired with repair=true.
+func (srv *Server) checkIntegrityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	problems := srv.CheckIntegrity()
+	repaired := 0
+	if r.FormValue("repair") == "true" {
+		var err error
+		if repaired, err = srv.Repair(problems); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"errors":   problems,
+		"repaired": repaired,
+	})
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// problemKinds counts the problems of each kind
+func problemKinds(problems []IntegrityError) map[string]int {
+	kinds := make(map[string]int)
+	for _, p := range problems {
+		kinds[p.Kind]++
+	}
+	return kinds
+}
+
+func TestCheckIntegrityCleanBoard(t *testing.T) {
+	s := newTestStore()
+	task := s.AddTask("A", "")
+	s.AddLink(task.ID, "https://example.com", "")
+	if problems := s.CheckIntegrity(); len(problems) != 0 {
+		t.Errorf("Expected no problems, got %+v", problems)
+	}
+}
+
+func TestCheckIntegrityDetectsDuplicateIDs(t *testing.T) {
+	s := newTestStore()
+	data := PersistentData{NextID: 3, Tasks: []*Task{
+		{ID: 1, Title: "First", Status: "todo"},
+		{ID: 2, Title: "Second", Status: "todo"},
+		{ID: 1, Title: "First again", Status: "doing"},
+	}}
+	raw, _ := json.Marshal(data)
+	if err := os.WriteFile(s.filePath, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+
+	problems := s.CheckIntegrity()
+	if len(problems) != 1 || problems[0].Kind != IntegrityDuplicateID || problems[0].TaskID != 1 || problems[0].Fixable {
+		t.Fatalf("Expected one unfixable duplicate of task 1, got %+v", problems)
+	}
+	if n, _ := s.Repair(problems); n != 0 {
+		t.Errorf("Duplicate IDs must not be auto-repaired")
+	}
+}
+
+func TestCheckIntegrityRepairsNextID(t *testing.T) {
+	s := newTestStore()
+	s.AddTask("A", "")
+	s.AddTask("B", "")
+	s.nextID = 2
+
+	problems := s.CheckIntegrity()
+	if kinds := problemKinds(problems); kinds[IntegrityNextID] != 1 || len(problems) != 1 {
+		t.Fatalf("Expected a next ID problem, got %+v", problems)
+	}
+	if fixed, _ := s.Repair(problems); fixed != 1 {
+		t.Errorf("Expected 1 fix, got %d", fixed)
+	}
+	if task := s.AddTask("C", ""); task.ID != 3 {
+		t.Errorf("Expected the repaired next ID to be 3, got %d", task.ID)
+	}
+
+	loaded := &TaskStore{tasks: make(map[int]*Task), filePath: s.filePath}
+	loaded.LoadFromFile()
+	if len(loaded.CheckIntegrity()) != 0 {
+		t.Errorf("Expected the repair to be saved")
+	}
+}
+
+func TestCheckIntegrityReportsInvalidStatus(t *testing.T) {
+	s := newTestStore()
+	task := s.AddTask("A", "")
+	s.tasks[task.ID].Status = "review"
+
+	problems := s.CheckIntegrity()
+	if len(problems) != 1 || problems[0].Kind != IntegrityInvalidStatus || problems[0].TaskID != task.ID || problems[0].Fixable {
+		t.Fatalf("Expected an unfixable invalid status problem, got %+v", problems)
+	}
+	if n, _ := s.Repair(problems); n != 0 {
+		t.Errorf("Invalid statuses must not be auto-repaired")
+	}
+}
+
+func TestCheckIntegrityRepairsOrphanLinks(t *testing.T) {
+	s := newTestStore()
+	task := s.AddTask("A", "")
+	s.AddLink(task.ID, "https://example.com/kept", "")
+	s.links[42] = []*Link{{ID: 9, TaskID: 42, URL: "https://example.com/orphan"}}
+
+	problems := s.CheckIntegrity()
+	if len(problems) != 1 || problems[0].Kind != IntegrityOrphanLinks || problems[0].TaskID != 42 || !problems[0].Fixable {
+		t.Fatalf("Expected a fixable orphan links problem, got %+v", problems)
+	}
+	if fixed, _ := s.Repair(problems); fixed != 1 {
+		t.Errorf("Expected 1 fix, got %d", fixed)
+	}
+	if _, ok := s.links[42]; ok {
+		t.Errorf("Expected orphaned links to be removed")
+	}
+	if links, _ := s.Links(task.ID); len(links) != 1 {
+		t.Errorf("Links of existing tasks must be kept")
+	}
+	// Repairing again finds nothing left to fix
+	if n, _ := s.Repair(problems); n != 0 {
+		t.Errorf("Expected a second repair to be a no-op")
+	}
+}
+
+func TestCheckIntegrityRepairsDanglingDependencies(t *testing.T) {
+	s := newTestStore()
+	first := s.AddTask("First", "")
+	second := s.AddTask("Second", "")
+	third := s.AddTask("Third", "")
+	s.SetDependencies(third.ID, []int{first.ID, second.ID})
+	s.DeleteTask(second.ID)
+
+	problems := s.CheckIntegrity()
+	if len(problems) != 1 || problems[0].Kind != IntegrityDanglingDeps || problems[0].TaskID != third.ID || !problems[0].Fixable {
+		t.Fatalf("Expected a fixable dangling dependency on task 3, got %+v", problems)
+	}
+	if fixed, _ := s.Repair(problems); fixed != 1 {
+		t.Errorf("Expected 1 fix, got %d", fixed)
+	}
+	if task, _ := s.GetTask(third.ID); !equalIDs(task.DependsOn, []int{first.ID}) {
+		t.Errorf("Expected only the dependency on task 1 to be kept, got %v", task.DependsOn)
+	}
+	if order, err := s.TopologicalSort(); err != nil || !equalIDs(order, []int{first.ID, third.ID}) {
+		t.Errorf("Expected the dependency graph to follow the repair, got %v, %v", order, err)
+	}
+	if problems := s.CheckIntegrity(); len(problems) != 0 {
+		t.Errorf("Expected nothing left to fix, got %+v", problems)
+	}
+}
+
+func TestCheckIntegrityRepairsOrphanSubtasks(t *testing.T) {
+	s := newTestStore()
+	parent := s.AddTask("Release", "")
+	child := s.AddTask("Tag the build", "")
+	s.AddChecklistItem(parent.ID, "Write notes")
+	s.ConvertToSubtask(child.ID, parent.ID)
+	s.DeleteTask(child.ID)
+
+	problems := s.CheckIntegrity()
+	if len(problems) != 1 || problems[0].Kind != IntegrityOrphanSubtask || problems[0].TaskID != parent.ID || !problems[0].Fixable {
+		t.Fatalf("Expected a fixable orphan subtask on task 1, got %+v", problems)
+	}
+	if fixed, _ := s.Repair(problems); fixed != 1 {
+		t.Errorf("Expected 1 fix, got %d", fixed)
+	}
+	task, _ := s.GetTask(parent.ID)
+	if len(task.Checklist) != 2 || task.Checklist[1].Text != "Tag the build" || task.Checklist[1].TaskID != 0 {
+		t.Errorf("Expected the subtask to stay as a plain checklist item, got %+v", task.Checklist)
+	}
+	if problems := s.CheckIntegrity(); len(problems) != 0 {
+		t.Errorf("Expected nothing left to fix, got %+v", problems)
+	}
+}
+
+func TestCheckIntegrityHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("A", "")
+	s.nextID = 1
+
+	var body struct {
+		Errors   []IntegrityError `json:"errors"`
+		Repaired int              `json:"repaired"`
+	}
+	w := httptest.NewRecorder()
+	NewServer(s).checkIntegrityHandler(w, httptest.NewRequest(http.MethodPost, "/admin/check-integrity", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || len(body.Errors) != 1 || body.Repaired != 0 {
+		t.Fatalf("Expected one unrepaired problem, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	NewServer(s).checkIntegrityHandler(w, httptest.NewRequest(http.MethodPost, "/admin/check-integrity?repair=true", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Repaired != 1 {
+		t.Errorf("Expected one repair, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	NewServer(s).checkIntegrityHandler(w, httptest.NewRequest(http.MethodGet, "/admin/check-integrity", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", w.Code)
+	}
+}
+
+func TestCheckIntegrityRequiresAdminKey(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("A", "")
+	s.nextID = 1
+	t.Setenv("KANBAN_ADMIN_KEY", "s3cret")
+
+	w := httptest.NewRecorder()
+	requireAdminKey(NewServer(s).checkIntegrityHandler)(w, httptest.NewRequest(http.MethodPost, "/admin/check-integrity?repair=true", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 without the key, got %d", w.Code)
+	}
+	if s.nextID != 1 {
+		t.Errorf("Expected nothing to be repaired without the key, next ID is %d", s.nextID)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/check-integrity", nil)
+	req.Header.Set(adminKeyHeader, "s3cret")
+	w = httptest.NewRecorder()
+	requireAdminKey(NewServer(s).checkIntegrityHandler)(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 with the key, got %d", w.Code)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultBoardID names the server's board. The server hosts a single board,
+// so it is the only one invitations can be created for.
+const defaultBoardID = "default"
+
+const invitationTTL = 48 * time.Hour
+
+var (
+	errInvitationNotFound = errors.New("invitation not found")
+	errInvitationExpired  = errors.New("invitation has expired")
+	errInvitationUsed     = errors.New("invitation has already been used")
+)
+
+// Board records who has joined a board through an invitation. Boards
+// cloned from another also have a name and the columns they were given.
+type Board struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name,omitempty"`
+	Members []string        `json:"members"`
+	Columns []columnSetting `json:"columns,omitempty"`
+}
+
+// Invitation is a single-use token granting InviteeEmail membership of a board
+type Invitation struct {
+	BoardID      string    `json:"board_id"`
+	InviteeEmail string    `json:"invitee_email"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Used         bool      `json:"used"`
+}
+
+// InvitationStore keeps invitations and board memberships in memory
+type InvitationStore struct {
+	mu          sync.Mutex
+	invitations map[string]Invitation
+	boards      map[string]*Board
+	now         func() time.Time // overridable clock for tests
+}
+
+var invitations = NewInvitationStore()
+
+// NewInvitationStore returns a store holding the default board
+func NewInvitationStore() *InvitationStore {
+	return &InvitationStore{
+		invitations: make(map[string]Invitation),
+		boards:      map[string]*Board{defaultBoardID: {ID: defaultBoardID, Members: []string{}}},
+		now:         time.Now,
+	}
+}
+
+// Invite creates an invitation for email to join a board and returns its token.
+// It returns false if the board does not exist.
+func (is *InvitationStore) Invite(boardID, email string) (string, Invitation, bool, error) {
+	token, err := newInviteToken()
+	if err != nil {
+		return "", Invitation{}, true, err
+	}
+
+	is.mu.Lock()
+	defer is.mu.Unlock()
+	if _, ok := is.boards[boardID]; !ok {
+		return "", Invitation{}, false, nil
+	}
+	inv := Invitation{BoardID: boardID, InviteeEmail: email, ExpiresAt: is.now().Add(invitationTTL)}
+	is.invitations[token] = inv
+	return token, inv, true, nil
+}
+
+// Accept redeems a token, adding the invitee to the board's members
+func (is *InvitationStore) Accept(token string) (Invitation, error) {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	inv, ok := is.invitations[token]
+	switch {
+	case !ok:
+		return inv, errInvitationNotFound
+	case inv.Used:
+		return inv, errInvitationUsed
+	case !is.now().Before(inv.ExpiresAt):
+		return inv, errInvitationExpired
+	}
+	inv.Used = true
+	is.invitations[token] = inv
+
+	board := is.boards[inv.BoardID]
+	for _, member := range board.Members {
+		if member == inv.InviteeEmail {
+			return inv, nil
+		}
+	}
+	board.Members = append(board.Members, inv.InviteeEmail)
+	return inv, nil
+}
+
+// Board returns a copy of a board
+func (is *InvitationStore) Board(id string) (Board, bool) {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+	board, ok := is.boards[id]
+	if !ok {
+		return Board{}, false
+	}
+	return Board{
+		ID:      board.ID,
+		Name:    board.Name,
+		Members: append([]string{}, board.Members...),
+		Columns: append([]columnSetting(nil), board.Columns...),
+	}, true
+}
+
+// newInviteToken returns a random 32-character hex token
+func newInviteToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// boardInviteHandler serves POST /boards/{id}/invite with an email form field
+// and returns the token and the URL the invitee should open
+func boardInviteHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/boards/"), "/"), "/")
+	if len(parts) != 2 || parts[1] != "invite" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	addr, err := mail.ParseAddress(r.FormValue("email"))
+	if err != nil {
+		http.Error(w, "Invalid email address", http.StatusBadRequest)
+		return
+	}
+
+	token, inv, ok, err := invitations.Invite(parts[0], addr.Address)
+	if !ok {
+		http.Error(w, "Board not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Could not create invitation", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"token":      token,
+		"accept_url": "/accept-invite?token=" + token,
+		"expires_at": inv.ExpiresAt,
+	})
+}
+
+// acceptInviteHandler redeems ?token= and redirects to the board. Expired or
+// already used tokens get 410 Gone.
+func acceptInviteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	_, err := invitations.Accept(r.URL.Query().Get("token"))
+	switch {
+	case errors.Is(err, errInvitationNotFound):
+		http.Error(w, "Invitation not found", http.StatusNotFound)
+	case errors.Is(err, errInvitationExpired):
+		http.Error(w, "Invitation has expired", http.StatusGone)
+	case errors.Is(err, errInvitationUsed):
+		http.Error(w, "Invitation has already been used", http.StatusGone)
+	default:
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withTestInvitations(t *testing.T) *InvitationStore {
+	orig := invitations
+	t.Cleanup(func() { invitations = orig })
+	invitations = NewInvitationStore()
+	return invitations
+}
+
+func postInvite(path, email string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("email="+email))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	boardInviteHandler(w, req)
+	return w
+}
+
+func acceptInvite(token string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	acceptInviteHandler(w, httptest.NewRequest(http.MethodGet, "/accept-invite?token="+token, nil))
+	return w
+}
+
+func TestInviteAndAccept(t *testing.T) {
+	is := withTestInvitations(t)
+
+	w := postInvite("/boards/default/invite", "dana@example.com")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Token     string    `json:"token"`
+		AcceptURL string    `json:"accept_url"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if len(resp.Token) != 32 || resp.AcceptURL != "/accept-invite?token="+resp.Token {
+		t.Errorf("Unexpected token response %+v", resp)
+	}
+	if ttl := time.Until(resp.ExpiresAt); ttl < 47*time.Hour || ttl > invitationTTL {
+		t.Errorf("Expected invitation to expire in 48h, got %v", ttl)
+	}
+
+	w = acceptInvite(resp.Token)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/" {
+		t.Errorf("Expected redirect to the board, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+	if board, _ := is.Board(defaultBoardID); len(board.Members) != 1 || board.Members[0] != "dana@example.com" {
+		t.Errorf("Expected dana to be a member, got %v", board.Members)
+	}
+
+	if w := acceptInvite(resp.Token); w.Code != http.StatusGone {
+		t.Errorf("Expected 410 when reusing a token, got %d", w.Code)
+	}
+	if w := acceptInvite("unknown"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown token, got %d", w.Code)
+	}
+}
+
+func TestInvitationExpiry(t *testing.T) {
+	is := withTestInvitations(t)
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	is.now = func() time.Time { return now }
+
+	token, _, _, err := is.Invite(defaultBoardID, "lee@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(invitationTTL)
+	if w := acceptInvite(token); w.Code != http.StatusGone {
+		t.Errorf("Expected 410 for an expired token, got %d", w.Code)
+	}
+	if board, _ := is.Board(defaultBoardID); len(board.Members) != 0 {
+		t.Errorf("Expired invitation must not add a member, got %v", board.Members)
+	}
+}
+
+func TestInviteValidation(t *testing.T) {
+	withTestInvitations(t)
+	if w := postInvite("/boards/default/invite", "not-an-email"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid email, got %d", w.Code)
+	}
+	if w := postInvite("/boards/other/invite", "a@example.com"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown board, got %d", w.Code)
+	}
+	w := httptest.NewRecorder()
+	boardInviteHandler(w, httptest.NewRequest(http.MethodGet, "/boards/default/invite", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", w.Code)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// maxJIRAImportBytes caps the export file accepted by /import/jira
+const maxJIRAImportBytes = 10 << 20
+
+// defaultJIRAStatusMap assigns a column from JIRA's default workflow
+// statuses. Keys are matched case-insensitively.
+var defaultJIRAStatusMap = map[string]string{
+	"to do":       "todo",
+	"open":        "todo",
+	"backlog":     "todo",
+	"in progress": "doing",
+	"in review":   "doing",
+	"done":        "done",
+	"closed":      "done",
+	"resolved":    "done",
+}
+
+// loadJIRAStatusMap reads KANBAN_JIRA_STATUS_MAP, a JSON object of JIRA
+// status to column, falling back to the default
+func loadJIRAStatusMap() map[string]string {
+	raw := os.Getenv("KANBAN_JIRA_STATUS_MAP")
+	if raw == "" {
+		return defaultJIRAStatusMap
+	}
+	m, err := parseStringMap(raw)
+	if err != nil {
+		log.Printf("Ignoring KANBAN_JIRA_STATUS_MAP: %v", err)
+		return defaultJIRAStatusMap
+	}
+	return lowerKeys(m)
+}
+
+var jiraStatusMap = loadJIRAStatusMap()
+
+// parseJIRAStatusMap decodes a JSON status map, lowercasing its keys and
+// checking each value names a board column
+func parseJIRAStatusMap(raw string) (map[string]string, error) {
+	parsed, err := parseStringMap(raw)
+	if err != nil {
+		return nil, err
+	}
+	for jiraStatus, status := range parsed {
+		if !isValidStatus(status) {
+			return nil, fmt.Errorf("invalid status %q for JIRA status %q", status, jiraStatus)
+		}
+	}
+	return lowerKeys(parsed), nil
+}
+
+// lowerKeys returns a copy of m with lowercased keys
+func lowerKeys(m map[string]string) map[string]string {
+	lowered := make(map[string]string, len(m))
+	for k, v := range m {
+		lowered[strings.ToLower(k)] = v
+	}
+	return lowered
+}
+
+// jiraExport is the subset of a JIRA XML (RSS) export used for import
+type jiraExport struct {
+	Items []jiraIssue `xml:"channel>item"`
+}
+
+// jiraIssue is one <item> of a JIRA export
+type jiraIssue struct {
+	Key         string `xml:"key"`
+	Summary     string `xml:"summary"`
+	Description string `xml:"description"`
+	Priority    string `xml:"priority"`
+	Status      string `xml:"status"`
+	Assignee    struct {
+		Username string `xml:"username,attr"`
+		Name     string `xml:",chardata"`
+	} `xml:"assignee"`
+	Labels []string `xml:"labels>label"`
+}
+
+// ParseJIRAExport turns a JIRA XML export into task specs, one per issue,
+// mapping statuses through KANBAN_JIRA_STATUS_MAP or the default map
+func ParseJIRAExport(r io.Reader) ([]TaskSpec, error) {
+	return parseJIRAExport(r, jiraStatusMap)
+}
+
+// parseJIRAExport is ParseJIRAExport with an explicit status map. Issues in
+// a status missing from the map, or mapped to a column the board doesn't
+// have, go to To Do.
+func parseJIRAExport(r io.Reader, statusMap map[string]string) ([]TaskSpec, error) {
+	var export jiraExport
+	if err := xml.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid JIRA export: %w", err)
+	}
+
+	specs := make([]TaskSpec, 0, len(export.Items))
+	for _, issue := range export.Items {
+		spec := TaskSpec{
+			Title:       strings.TrimSpace(issue.Summary),
+			Description: strings.TrimSpace(issue.Description),
+			Priority:    jiraPriority(issue.Priority),
+			Status:      statusMap[strings.ToLower(strings.TrimSpace(issue.Status))],
+		}
+		if key := strings.TrimSpace(issue.Key); key != "" {
+			spec.Title = key + ": " + spec.Title
+		}
+		if !isValidStatus(spec.Status) {
+			spec.Status = "todo"
+		}
+		spec.Assignee = jiraAssignee(issue)
+		for _, label := range issue.Labels {
+			if label = strings.TrimSpace(label); label != "" && !containsString(spec.Labels, label) {
+				spec.Labels = append(spec.Labels, label)
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// jiraAssignee returns the assignee's username, or their display name in
+// exports without one. JIRA lists unassigned issues as username "-1".
+func jiraAssignee(issue jiraIssue) string {
+	username := strings.TrimSpace(issue.Assignee.Username)
+	if username == "-1" {
+		return ""
+	}
+	if username != "" {
+		return username
+	}
+	return strings.TrimSpace(issue.Assignee.Name)
+}
+
+// jiraPriority maps JIRA's default priority names onto the board's
+func jiraPriority(name string) int {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "highest", "high", "blocker", "critical":
+		return PriorityHigh
+	case "medium", "major":
+		return PriorityMedium
+	case "low", "lowest", "minor", "trivial":
+		return PriorityLow
+	}
+	return PriorityNone
+}
+
+// importJIRAHandler creates a task for each issue in a JIRA XML export
+// uploaded as the multipart "file" field. An optional "status_map" field
+// overrides the JIRA status to column mapping.
+func (srv *Server) importJIRAHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxJIRAImportBytes)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Upload the JIRA export as a multipart \"file\" field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	statusMap := jiraStatusMap
+	if raw := r.FormValue("status_map"); raw != "" {
+		if statusMap, err = parseJIRAStatusMap(raw); err != nil {
+			http.Error(w, "Invalid status_map: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	specs, err := parseJIRAExport(file, statusMap)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(specs) > maxBulkSize {
+		http.Error(w, fmt.Sprintf("Import of %d tasks exceeds the maximum of %d", len(specs), maxBulkSize),
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+	created, err := srv.CreateTasks(specs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ids := []int{}
+	for _, task := range created {
+		ids = append(ids, task.ID)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"imported": len(ids),
+		"created":  ids,
+	})
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseJIRAExport(t *testing.T) {
+	f, err := os.Open("testdata/jira-export.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	specs, err := ParseJIRAExport(f)
+	if err != nil {
+		t.Fatalf("ParseJIRAExport failed: %v", err)
+	}
+	if len(specs) != 3 {
+		t.Fatalf("Expected 3 issues, got %d", len(specs))
+	}
+
+	checkout := specs[0]
+	if checkout.Title != "SHOP-12: Checkout fails for saved cards" {
+		t.Errorf("Expected the key as a title prefix, got %q", checkout.Title)
+	}
+	if checkout.Description != "Payment returns a 500 when a saved card is used." || checkout.Assignee != "alice" ||
+		checkout.Status != "doing" || len(checkout.Labels) != 2 || checkout.Labels[1] != "payments" {
+		t.Errorf("Unexpected first issue %+v", checkout)
+	}
+	if gift := specs[1]; gift.Assignee != "" || gift.Status != "todo" || len(gift.Labels) != 0 {
+		t.Errorf("Expected an unassigned, unlabelled To Do task, got %+v", gift)
+	}
+	if catalogue := specs[2]; catalogue.Assignee != "carol" || catalogue.Status != "done" {
+		t.Errorf("Expected the assignee's name and a done task, got %+v", catalogue)
+	}
+
+	for i, want := range []int{PriorityHigh, PriorityLow, PriorityMedium} {
+		if specs[i].Priority != want {
+			t.Errorf("Issue %d: expected priority %d, got %d", i, want, specs[i].Priority)
+		}
+	}
+}
+
+func TestParseJIRAExportStatusMap(t *testing.T) {
+	export := `<rss><channel>
+		<item><key>A-1</key><summary>Triage</summary><status>Triage</status></item>
+		<item><key>A-2</key><summary>Shipped</summary><status>Shipped</status></item>
+	</channel></rss>`
+	specs, err := parseJIRAExport(strings.NewReader(export), map[string]string{"triage": "doing", "shipped": "released"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if specs[0].Status != "doing" {
+		t.Errorf("Expected the mapped status, got %q", specs[0].Status)
+	}
+	if specs[1].Status != "todo" {
+		t.Errorf("Expected a status mapped to an unknown column to fall back to todo, got %q", specs[1].Status)
+	}
+}
+
+func TestParseJIRAExportInvalidXML(t *testing.T) {
+	for _, input := range []string{"", "not xml", "<rss><channel><item><summary>Cut off"} {
+		if _, err := ParseJIRAExport(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestImportJIRAHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	fixture, err := os.ReadFile("testdata/jira-export.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upload := func(data []byte, statusMap string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, _ := mw.CreateFormFile("file", "jira-export.xml")
+		part.Write(data)
+		if statusMap != "" {
+			mw.WriteField("status_map", statusMap)
+		}
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/import/jira", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+		NewServer(s).importJIRAHandler(w, req)
+		return w
+	}
+
+	w := upload(fixture, `{"In Progress": "done"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Imported int   `json:"imported"`
+		Created  []int `json:"created"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Imported != 3 {
+		t.Fatalf("Expected 3 imported issues, got %d", resp.Imported)
+	}
+	if task, _ := s.GetTask(resp.Created[0]); task.Status != "done" {
+		t.Errorf("Expected the request's status map to apply, got %q", task.Status)
+	}
+
+	if w := upload([]byte("<rss><channel>"), ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a broken export, got %d", w.Code)
+	}
+	if w := upload(fixture, `{"To Do": "someday"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a status map naming an unknown column, got %d", w.Code)
+	}
+	if len(s.GetAllTasks()) != 3 {
+		t.Errorf("Expected rejected uploads to create nothing, got %d tasks", len(s.GetAllTasks()))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/import/jira", strings.NewReader("x"))
+	w = httptest.NewRecorder()
+	NewServer(s).importJIRAHandler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a file upload, got %d", w.Code)
+	}
+}
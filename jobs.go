@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultJobQueueSize is how many jobs can wait for a worker
+	defaultJobQueueSize = 100
+	// defaultJobWorkers is how many jobs run at once
+	defaultJobWorkers = 4
+	// shutdownTimeout is how long shutdown waits for open requests and
+	// queued jobs before cancelling them
+	shutdownTimeout = 10 * time.Second
+)
+
+var (
+	// ErrQueueFull is returned by Submit when every slot in the queue's
+	// buffer is taken
+	ErrQueueFull    = errors.New("job queue is full")
+	errQueueStopped = errors.New("job queue is stopped")
+)
+
+// Job is a piece of background work, such as delivering a webhook
+type Job interface {
+	Run(ctx context.Context) error
+	Name() string // for logs
+}
+
+// funcJob is a Job that calls a function
+type funcJob struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// newJob returns a job named name that calls run
+func newJob(name string, run func(ctx context.Context) error) Job {
+	return funcJob{name: name, run: run}
+}
+
+func (j funcJob) Run(ctx context.Context) error { return j.run(ctx) }
+
+func (j funcJob) Name() string { return j.name }
+
+// JobQueue runs submitted jobs on a fixed pool of worker goroutines. Its
+// buffer is bounded, so when workers fall behind Submit fails fast instead
+// of piling up goroutines. Jobs that fail are logged. Jobs should give up
+// once the context they run with is cancelled, which Stop does at shutdown.
+type JobQueue struct {
+	jobs   chan Job
+	ctx    context.Context // cancelled by Stop
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.RWMutex // held to send on jobs, and to close it
+	stopped bool
+}
+
+// jobs is the server's queue, nil until main starts it. Before then, and in
+// tests, runInBackground falls back to a goroutine per job.
+var jobs *JobQueue
+
+// NewJobQueue returns a queue holding up to size waiting jobs, or
+// defaultJobQueueSize if size isn't positive
+func NewJobQueue(size int) *JobQueue {
+	if size < 1 {
+		size = defaultJobQueueSize
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobQueue{jobs: make(chan Job, size), ctx: ctx, cancel: cancel}
+}
+
+// Submit queues a job without waiting. It returns ErrQueueFull when the
+// buffer is full and errQueueStopped after Stop.
+func (q *JobQueue) Submit(job Job) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.stopped {
+		return errQueueStopped
+	}
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Start starts workers goroutines taking jobs off the queue
+func (q *JobQueue) Start(workers int) {
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+}
+
+// work runs jobs until the queue is stopped and empty. Jobs still queued
+// once the context is cancelled are dropped without running.
+func (q *JobQueue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		if err := q.ctx.Err(); err != nil {
+			log.Printf("Dropping job %s: %v", job.Name(), err)
+			continue
+		}
+		if err := job.Run(q.ctx); err != nil {
+			log.Printf("Job %s failed: %v", job.Name(), err)
+		}
+	}
+}
+
+// Stop refuses new jobs and waits for the workers to finish the ones
+// already queued. If ctx is done first, it cancels the context jobs run
+// with, so running jobs give up and queued ones are dropped, and waits for
+// the workers to exit.
+func (q *JobQueue) Stop(ctx context.Context) {
+	q.mu.Lock()
+	if !q.stopped {
+		q.stopped = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	q.cancel()
+	<-done
+}
+
+// runInBackground runs fn as a job named name on the server's queue,
+// logging and returning the error if the queue can't take it. Without a
+// queue it runs fn in a goroutine of its own.
+func runInBackground(name string, fn func(ctx context.Context) error) error {
+	job := newJob(name, fn)
+	if jobs == nil {
+		go func() {
+			if err := job.Run(context.Background()); err != nil {
+				log.Printf("Job %s failed: %v", name, err)
+			}
+		}()
+		return nil
+	}
+	if err := jobs.Submit(job); err != nil {
+		log.Printf("Dropping job %s: %v", name, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJobQueueRunsEveryJob(t *testing.T) {
+	q := NewJobQueue(200)
+	var ran atomic.Int64
+	for i := 0; i < 200; i++ {
+		if err := q.Submit(newJob("count", func(context.Context) error {
+			ran.Add(1)
+			return nil
+		})); err != nil {
+			t.Fatalf("Submit %d failed: %v", i, err)
+		}
+	}
+	q.Start(2)
+	q.Stop(context.Background())
+	if got := ran.Load(); got != 200 {
+		t.Errorf("Expected all 200 jobs to run, got %d", got)
+	}
+}
+
+func TestJobQueueFull(t *testing.T) {
+	q := NewJobQueue(2)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	block := newJob("block", func(context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+	noop := newJob("noop", func(context.Context) error { return nil })
+
+	q.Start(1)
+	q.Submit(block)
+	<-started
+	q.Submit(noop)
+	q.Submit(noop)
+	if err := q.Submit(noop); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull with the worker busy and the buffer full, got %v", err)
+	}
+
+	close(release)
+	q.Stop(context.Background())
+	if err := q.Submit(noop); !errors.Is(err, errQueueStopped) {
+		t.Errorf("Expected jobs to be refused after Stop, got %v", err)
+	}
+}
+
+func TestJobQueueStopCancelsContext(t *testing.T) {
+	q := NewJobQueue(0)
+	var ctx context.Context
+	q.Submit(newJob("keep", func(c context.Context) error {
+		ctx = c
+		return errors.New("logged, not fatal")
+	}))
+	q.Start(1)
+	q.Stop(context.Background())
+	if ctx == nil || ctx.Err() == nil {
+		t.Errorf("Expected the job's context to be cancelled after Stop")
+	}
+	if cap(q.jobs) != defaultJobQueueSize {
+		t.Errorf("Expected the default size %d, got %d", defaultJobQueueSize, cap(q.jobs))
+	}
+}
+
+func TestJobQueueStopCancelsRunningJobs(t *testing.T) {
+	q := NewJobQueue(10)
+	started := make(chan struct{})
+	var cancelled, ranQueued atomic.Bool
+	q.Submit(newJob("slow", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		cancelled.Store(true)
+		return ctx.Err()
+	}))
+	q.Submit(newJob("queued", func(context.Context) error {
+		ranQueued.Store(true)
+		return nil
+	}))
+	q.Start(1)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	q.Stop(ctx)
+	if !cancelled.Load() {
+		t.Errorf("Expected Stop to cancel the running job once its context expired")
+	}
+	if ranQueued.Load() {
+		t.Errorf("Expected the job still queued to be dropped")
+	}
+}
+
+func TestRunInBackgroundUsesQueue(t *testing.T) {
+	orig := jobs
+	t.Cleanup(func() { jobs = orig })
+	jobs = NewJobQueue(10)
+
+	done := make(chan string, 1)
+	runInBackground("queued", func(context.Context) error {
+		done <- "queued"
+		return nil
+	})
+	select {
+	case <-done:
+		t.Fatalf("Expected the job to wait for a worker")
+	case <-time.After(20 * time.Millisecond):
+	}
+	jobs.Start(1)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the job to run once a worker started")
+	}
+	jobs.Stop(context.Background())
+}
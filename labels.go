@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// labelSettingPrefix is the settings key prefix holding each label's color
+const labelSettingPrefix = "label."
+
+const maxLabelNameLength = 50
+
+// Label is a label name with the color its badges are drawn in
+type Label struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// ErrLabelInUse is returned when deleting a label still on some tasks
+type ErrLabelInUse struct {
+	Name    string
+	TaskIDs []int
+}
+
+func (e *ErrLabelInUse) Error() string {
+	return fmt.Sprintf("label %q is used by %d tasks", e.Name, len(e.TaskIDs))
+}
+
+// LabelRegistry maps label names to colors, kept in settings.json. Tasks
+// may carry labels that aren't registered; those are drawn uncolored.
+// Names are compared case-insensitively.
+type LabelRegistry struct {
+	settings *SettingsStore
+}
+
+// labels returns the registry backed by the store's settings
+func (s *TaskStore) labels() *LabelRegistry {
+	return &LabelRegistry{settings: s.settings}
+}
+
+// validateLabel checks a label's name and #RRGGBB color
+func validateLabel(name, color string) error {
+	if name == "" || len(name) > maxLabelNameLength || strings.Contains(name, ",") || name != strings.TrimSpace(name) {
+		return fmt.Errorf("name must be 1-%d characters without commas or surrounding spaces", maxLabelNameLength)
+	}
+	if !hexColorPattern.MatchString(color) {
+		return fmt.Errorf("color must be #RRGGBB")
+	}
+	return nil
+}
+
+// List returns every registered label sorted by name
+func (lr *LabelRegistry) List() []Label {
+	labels := []Label{}
+	if lr.settings == nil {
+		return labels
+	}
+	for key, color := range lr.settings.All() {
+		if name, ok := strings.CutPrefix(key, labelSettingPrefix); ok {
+			labels = append(labels, Label{Name: name, Color: color})
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
+
+// Get returns the registered label matching name, ignoring case
+func (lr *LabelRegistry) Get(name string) (Label, bool) {
+	if color, ok := lr.settings.Get(labelSettingPrefix + name); ok {
+		return Label{Name: name, Color: color}, true
+	}
+	for _, label := range lr.List() {
+		if strings.EqualFold(label.Name, name) {
+			return label, true
+		}
+	}
+	return Label{}, false
+}
+
+// Create registers a label. It fails if a label with that name, in any
+// case, already exists.
+func (lr *LabelRegistry) Create(name, color string) (Label, error) {
+	if lr.settings == nil {
+		return Label{}, fmt.Errorf("labels need a settings file")
+	}
+	if err := validateLabel(name, color); err != nil {
+		return Label{}, err
+	}
+	if existing, ok := lr.Get(name); ok {
+		return Label{}, fmt.Errorf("label %q already exists", existing.Name)
+	}
+	color = strings.ToLower(color)
+	if err := lr.settings.Merge(map[string]string{labelSettingPrefix + name: color}); err != nil {
+		return Label{}, err
+	}
+	return Label{Name: name, Color: color}, nil
+}
+
+// GetTasksByLabel returns the tasks carrying a registered label, matching
+// their labels against the registry name ignoring case. It returns false if
+// the label isn't registered.
+func (s *TaskStore) GetTasksByLabel(name string) ([]*Task, bool) {
+	label, ok := s.labels().Get(name)
+	if !ok {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tasksWithLabel(label.Name), true
+}
+
+// tasksWithLabel returns the tasks with a label, ignoring case, ordered by
+// ID (must be called with lock held)
+func (s *TaskStore) tasksWithLabel(name string) []*Task {
+	tasks := []*Task{}
+	for _, task := range s.tasks {
+		for _, label := range task.Labels {
+			if strings.EqualFold(label, name) {
+				tasks = append(tasks, task)
+				break
+			}
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
+// ListLabels returns every registered label sorted by name
+func (s *TaskStore) ListLabels() []Label {
+	return s.labels().List()
+}
+
+// CreateLabel registers a label with a #RRGGBB color
+func (s *TaskStore) CreateLabel(name, color string) (Label, error) {
+	return s.labels().Create(name, color)
+}
+
+// DeleteLabel unregisters a label. It returns ErrLabelInUse, listing the
+// tasks, while any task still carries it, and false if it isn't registered.
+func (s *TaskStore) DeleteLabel(name string) (bool, error) {
+	label, ok := s.labels().Get(name)
+	if !ok {
+		return false, nil
+	}
+	// Hold the lock so no task is given the label while it is removed
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkLocked(); err != nil {
+		return true, err
+	}
+	if tasks := s.tasksWithLabel(label.Name); len(tasks) > 0 {
+		err := &ErrLabelInUse{Name: label.Name}
+		for _, task := range tasks {
+			err.TaskIDs = append(err.TaskIDs, task.ID)
+		}
+		return true, err
+	}
+	_, err := s.settings.Delete(labelSettingPrefix + label.Name)
+	return true, err
+}
+
+// labelColor returns the color labels registers for name, ignoring case, or
+// "" if it isn't registered
+func labelColor(labels []Label, name string) string {
+	for _, label := range labels {
+		if strings.EqualFold(label.Name, name) {
+			return label.Color
+		}
+	}
+	return ""
+}
+
+// labelTextColor returns black or white, whichever reads better on a label
+// badge of the given #RRGGBB background
+func labelTextColor(background string) string {
+	if !hexColorPattern.MatchString(background) {
+		return ""
+	}
+	r, _ := strconv.ParseUint(background[1:3], 16, 8)
+	g, _ := strconv.ParseUint(background[3:5], 16, 8)
+	b, _ := strconv.ParseUint(background[5:7], 16, 8)
+	// Perceived brightness, per the W3C's contrast guidance
+	if (r*299+g*587+b*114)/1000 > 150 {
+		return "#000000"
+	}
+	return "#ffffff"
+}
+
+// labelsAPIHandler serves /api/v1/labels: GET lists the labels, POST creates
+// one from {"name", "color"}, and DELETE /api/v1/labels/{name} removes one
+func (srv *Server) labelsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/labels"), "/")
+
+	switch {
+	case name == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, srv.ListLabels())
+	case name == "" && r.Method == http.MethodPost:
+		var in Label
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		label, err := srv.CreateLabel(in.Name, in.Color)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, label)
+	case name != "" && r.Method == http.MethodDelete:
+		found, err := srv.DeleteLabel(name)
+		var (
+			inUse  *ErrLabelInUse
+			locked *ErrBoardLocked
+		)
+		switch {
+		case errors.As(err, &locked):
+			writeError(w, err)
+		case !found:
+			http.Error(w, "Label not found", http.StatusNotFound)
+		case errors.As(err, &inUse):
+			writeJSON(w, http.StatusConflict, map[string]interface{}{
+				"error":    "label in use",
+				"task_ids": inUse.TaskIDs,
+			})
+		case err != nil:
+			http.Error(w, "Could not save settings", http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
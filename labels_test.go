@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func labelsRequest(method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	NewServer(store).labelsAPIHandler(w, req)
+	return w
+}
+
+func TestLabelsAPILifecycle(t *testing.T) {
+	s := withTestGlobals(t)
+	s.settings = withTestSettings(t)
+
+	w := labelsRequest(http.MethodPost, "/api/v1/labels", `{"name": "bug", "color": "#EF4444"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	for _, body := range []string{
+		`{"name": "Bug", "color": "#000000"}`,
+		`{"name": "a,b", "color": "#000000"}`,
+		`{"name": "docs", "color": "blue"}`,
+	} {
+		if w := labelsRequest(http.MethodPost, "/api/v1/labels", body); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, w.Code)
+		}
+	}
+	labelsRequest(http.MethodPost, "/api/v1/labels", `{"name": "docs", "color": "#fde68a"}`)
+
+	var labels []Label
+	if err := json.Unmarshal(labelsRequest(http.MethodGet, "/api/v1/labels", "").Body.Bytes(), &labels); err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 2 || labels[0] != (Label{Name: "bug", Color: "#ef4444"}) || labels[1].Name != "docs" {
+		t.Errorf("Unexpected labels %+v", labels)
+	}
+
+	s.CreateTask(TaskSpec{Title: "Crash", Labels: []string{"bug"}})
+	s.AddTask("Unlabeled", "")
+	s.CreateTask(TaskSpec{Title: "Crash again", Labels: []string{"BUG", "urgent"}})
+
+	w = labelsRequest(http.MethodDelete, "/api/v1/labels/bug", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected 409 while the label is used, got %d", w.Code)
+	}
+	var conflict struct {
+		TaskIDs []int `json:"task_ids"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &conflict)
+	if !equalIDs(conflict.TaskIDs, []int{1, 3}) {
+		t.Errorf("Expected tasks 1 and 3 to be reported, got %s", w.Body.String())
+	}
+	if _, ok := s.labels().Get("bug"); !ok {
+		t.Errorf("Expected the rejected delete to keep the label")
+	}
+
+	if w := labelsRequest(http.MethodDelete, "/api/v1/labels/docs", ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 for an unused label, got %d", w.Code)
+	}
+	if w := labelsRequest(http.MethodDelete, "/api/v1/labels/docs", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 once deleted, got %d", w.Code)
+	}
+}
+
+func TestGetTasksByLabel(t *testing.T) {
+	s := newTestStore()
+	s.settings = withTestSettings(t)
+	s.labels().Create("Backend", "#3b82f6")
+	s.CreateTask(TaskSpec{Title: "API", Labels: []string{"backend"}})
+	s.CreateTask(TaskSpec{Title: "UI", Labels: []string{"frontend"}})
+
+	tasks, ok := s.GetTasksByLabel("BACKEND")
+	if !ok || len(tasks) != 1 || tasks[0].Title != "API" {
+		t.Errorf("Expected the backend task, got %v, %v", taskIDs(tasks), ok)
+	}
+	if _, ok := s.GetTasksByLabel("frontend"); ok {
+		t.Errorf("Expected an unregistered label not to be found")
+	}
+
+	s.CreateTask(TaskSpec{Title: "DB", Labels: []string{"Backend"}})
+	var inUse *ErrLabelInUse
+	if _, err := s.DeleteLabel("backend"); !errors.As(err, &inUse) || !equalIDs(inUse.TaskIDs, []int{1, 3}) {
+		t.Errorf("Expected ErrLabelInUse for tasks 1 and 3, got %v", err)
+	}
+}
+
+func TestLabelBadgeColors(t *testing.T) {
+	s := withTestGlobals(t)
+	s.settings = withTestSettings(t)
+	s.labels().Create("urgent", "#ef4444")
+	s.labels().Create("idea", "#fef08a")
+	s.CreateTask(TaskSpec{Title: "Labeled", Labels: []string{"urgent", "idea", "plain"}})
+
+	rr := httptest.NewRecorder()
+	NewServer(s).indexHandler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rr.Body.String()
+	for _, want := range []string{
+		`<span class="task-label" style="background-color: #ef4444; color: #ffffff">🏷️ urgent</span>`,
+		`<span class="task-label" style="background-color: #fef08a; color: #000000">🏷️ idea</span>`,
+		`<span class="task-label">🏷️ plain</span>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %s in the board", want)
+		}
+	}
+}
+
+func TestLabelSettingValidation(t *testing.T) {
+	if err := validateSetting("label.bug", "#123abc"); err != nil {
+		t.Errorf("Expected a valid label setting, got %v", err)
+	}
+	if err := validateSetting("label.bug", "red"); err == nil {
+		t.Errorf("Expected a non-hex label color to be rejected")
+	}
+}
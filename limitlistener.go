@@ -0,0 +1,87 @@
+package main
+
+import (
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+const defaultMaxConns = 1000
+
+// limitListener caps the number of simultaneously open connections. Once the
+// limit is reached Accept waits for a connection to close; if none closes
+// within acceptTimeout the waiting connection is dropped. A zero timeout
+// waits indefinitely.
+type limitListener struct {
+	net.Listener
+	sem           chan struct{}
+	acceptTimeout time.Duration
+}
+
+// newLimitListener wraps l to allow at most maxConns open connections
+func newLimitListener(l net.Listener, maxConns int, acceptTimeout time.Duration) *limitListener {
+	return &limitListener{
+		Listener:      l,
+		sem:           make(chan struct{}, maxConns),
+		acceptTimeout: acceptTimeout,
+	}
+}
+
+// Accept waits for the next connection and a free slot to serve it in
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.acquire() {
+			return &limitListenerConn{Conn: conn, release: l.release}, nil
+		}
+		log.Printf("Dropping connection from %s: %d connections already open", conn.RemoteAddr(), cap(l.sem))
+		conn.Close()
+	}
+}
+
+// acquire takes a slot, giving up after acceptTimeout
+func (l *limitListener) acquire() bool {
+	select {
+	case l.sem <- struct{}{}:
+		return true
+	default:
+	}
+	if l.acceptTimeout <= 0 {
+		l.sem <- struct{}{}
+		return true
+	}
+	timer := time.NewTimer(l.acceptTimeout)
+	defer timer.Stop()
+	select {
+	case l.sem <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (l *limitListener) release() {
+	<-l.sem
+}
+
+// CurrentConns returns the number of connections currently open
+func (l *limitListener) CurrentConns() int {
+	return len(l.sem)
+}
+
+// limitListenerConn frees its listener slot when closed
+type limitListenerConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (c *limitListenerConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimitListenerCapsConcurrentConnections(t *testing.T) {
+	const maxConns = 3
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := newLimitListener(ln, maxConns, 0)
+
+	var active, peak int32
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		if open := listener.CurrentConns(); open > maxConns {
+			t.Errorf("CurrentConns reported %d, above the limit of %d", open, maxConns)
+		}
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+	})}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	// Disable keep-alives so each request's connection closes when it's done
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	var wg sync.WaitGroup
+	for i := 0; i < maxConns+5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get("http://" + ln.Addr().String())
+			if err != nil {
+				t.Errorf("Request failed: %v", err)
+				return
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if peak > maxConns {
+		t.Errorf("Served %d connections at once, limit is %d", peak, maxConns)
+	}
+	if peak < 2 {
+		t.Errorf("Expected connections to be served concurrently, peak was %d", peak)
+	}
+}
+
+func TestLimitListenerAcceptTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := newLimitListener(ln, 1, 20*time.Millisecond)
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	first, _ := net.Dial("tcp", ln.Addr().String())
+	defer first.Close()
+	held := <-accepted
+	if listener.CurrentConns() != 1 {
+		t.Errorf("Expected 1 open connection, got %d", listener.CurrentConns())
+	}
+
+	// With the only slot held, the next connection is dropped after the timeout
+	second, _ := net.Dial("tcp", ln.Addr().String())
+	defer second.Close()
+	second.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := second.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Expected the waiting connection to be closed, got %v", err)
+	}
+
+	// Closing a connection frees its slot
+	held.Close()
+	held.Close()
+	third, _ := net.Dial("tcp", ln.Addr().String())
+	defer third.Close()
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	case <-time.After(time.Second):
+		t.Errorf("Expected a connection to be accepted once the slot was freed")
+	}
+	if n := listener.CurrentConns(); n != 0 {
+		t.Errorf("Expected double Close to release the slot once, got %d open", n)
+	}
+}
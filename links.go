@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxLinkLabelLength = 100
+
+// Link attaches an external URL, such as a design doc or pull request, to a task
+type Link struct {
+	ID      int       `json:"id"`
+	TaskID  int       `json:"task_id"`
+	URL     string    `json:"url"`
+	Label   string    `json:"label"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// validateLinkURL accepts absolute http and https URLs
+func validateLinkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q, must be an absolute http or https URL", raw)
+	}
+	return nil
+}
+
+// AddLink attaches a URL to a task. It returns false if the task does not exist.
+func (s *TaskStore) AddLink(taskID int, rawURL, label string) (*Link, bool, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if err := validateLinkURL(rawURL); err != nil {
+		return nil, true, err
+	}
+	label = strings.TrimSpace(label)
+	if len(label) > maxLinkLabelLength {
+		return nil, true, fmt.Errorf("label must be at most %d characters", maxLinkLabelLength)
+	}
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, true, err
+	}
+	task, ok := s.tasks[taskID]
+	if !ok {
+		s.mu.Unlock()
+		return nil, false, nil
+	}
+	if s.links == nil {
+		s.links = make(map[int][]*Link)
+	}
+	if s.nextLinkID < 1 {
+		s.nextLinkID = 1
+	}
+	link := &Link{ID: s.nextLinkID, TaskID: taskID, URL: rawURL, Label: label, AddedAt: s.clock()}
+	s.nextLinkID++
+	s.links[taskID] = append(s.links[taskID], link)
+	s.persistLinks()
+	updated := task.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskUpdated, Task: updated, Changes: []FieldChange{{Field: "link", New: rawURL}}})
+	return link, true, nil
+}
+
+// Links returns a task's links in the order they were added
+func (s *TaskStore) Links(taskID int) ([]*Link, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[taskID]; !ok {
+		return nil, false
+	}
+	links := []*Link{}
+	for _, link := range s.links[taskID] {
+		c := *link
+		links = append(links, &c)
+	}
+	return links, true
+}
+
+// DeleteLink removes one of a task's links, reporting whether it existed
+func (s *TaskStore) DeleteLink(taskID, linkID int) bool {
+	s.mu.Lock()
+	task, ok := s.tasks[taskID]
+	if !ok {
+		s.mu.Unlock()
+		return false
+	}
+	links := s.links[taskID]
+	for i, link := range links {
+		if link.ID != linkID {
+			continue
+		}
+		s.links[taskID] = append(links[:i:i], links[i+1:]...)
+		if len(s.links[taskID]) == 0 {
+			delete(s.links, taskID)
+		}
+		s.persistLinks()
+		updated := task.clone()
+		s.mu.Unlock()
+
+		s.publish(Event{Type: EventTaskUpdated, Task: updated, Changes: []FieldChange{{Field: "link", Old: link.URL}}})
+		return true
+	}
+	s.mu.Unlock()
+	return false
+}
+
+// linkCounts returns how many links each task has (must be called with lock held)
+func (s *TaskStore) linkCounts() map[int]int {
+	counts := make(map[int]int, len(s.links))
+	for id, links := range s.links {
+		counts[id] = len(links)
+	}
+	return counts
+}
+
+// allLinks flattens the links map ordered by ID for saving (must be called
+// with lock held)
+func (s *TaskStore) allLinks() []*Link {
+	var all []*Link
+	for _, links := range s.links {
+		all = append(all, links...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all
+}
+
+// setLinks replaces the links map with loaded links (must be called with lock held)
+func (s *TaskStore) setLinks(links []*Link, nextLinkID int) {
+	s.links = make(map[int][]*Link)
+	s.nextLinkID = nextLinkID
+	for _, link := range links {
+		s.links[link.TaskID] = append(s.links[link.TaskID], link)
+		if link.ID >= s.nextLinkID {
+			s.nextLinkID = link.ID + 1
+		}
+	}
+}
+
+// persistLinks saves the links (must be called with lock held). They go in
+// the data file, links.json with partitioned storage, or the external
+// backend's extras.
+func (s *TaskStore) persistLinks() {
+	switch {
+	case s.backend != nil:
+		s.saveExtras()
+	case s.partitions != nil:
+		if err := s.partitions.WriteLinks(s.allLinks(), s.nextLinkID); err != nil {
+			log.Printf("Error saving links: %v", err)
+		}
+	default:
+		s.saveToFile()
+	}
+}
+
+// taskAttachmentsHandler serves /api/v1/tasks/{id}/attachments: GET lists the
+// task's links, POST adds one from a JSON {"url", "label"} body, and DELETE
+// /api/v1/tasks/{id}/attachments/{linkID} removes one
+func (srv *Server) taskAttachmentsHandler(w http.ResponseWriter, r *http.Request, taskID int, rest []string) {
+	if len(rest) == 1 {
+		linkID, err := strconv.Atoi(rest[0])
+		if err != nil {
+			http.Error(w, "Invalid link ID", http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !srv.DeleteLink(taskID, linkID) {
+			http.Error(w, "Link not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if len(rest) > 1 {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		links, ok := srv.Links(taskID)
+		if !ok {
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, links)
+	case http.MethodPost:
+		if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+		var input struct {
+			URL   string `json:"url"`
+			Label string `json:"label"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		link, ok, err := srv.AddLink(taskID, input.URL, input.Label)
+		if !ok {
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, link)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
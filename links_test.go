@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAddListDeleteLinks(t *testing.T) {
+	store := newTestStore()
+	task := store.AddTask("Design", "")
+
+	first, ok, err := store.AddLink(task.ID, "https://example.com/spec", "Spec")
+	if !ok || err != nil {
+		t.Fatalf("AddLink failed: ok=%v err=%v", ok, err)
+	}
+	second, _, _ := store.AddLink(task.ID, "https://github.com/org/repo/pull/7", "")
+	if first.ID != 1 || second.ID != 2 || first.TaskID != task.ID || first.AddedAt.IsZero() {
+		t.Errorf("Unexpected links: %+v, %+v", first, second)
+	}
+
+	links, ok := store.Links(task.ID)
+	if !ok || len(links) != 2 || links[0].Label != "Spec" || links[1].URL != "https://github.com/org/repo/pull/7" {
+		t.Errorf("Expected both links in order, got %+v", links)
+	}
+
+	if !store.DeleteLink(task.ID, first.ID) || store.DeleteLink(task.ID, first.ID) {
+		t.Errorf("Expected delete to succeed once")
+	}
+	if links, _ := store.Links(task.ID); len(links) != 1 || links[0].ID != second.ID {
+		t.Errorf("Expected only link 2 to remain, got %+v", links)
+	}
+
+	if _, ok, _ := store.AddLink(99, "https://example.com", ""); ok {
+		t.Errorf("Expected missing task to report not found")
+	}
+	if _, ok := store.Links(99); ok {
+		t.Errorf("Expected listing links of a missing task to fail")
+	}
+}
+
+func TestAddLinkRejectsMalformedURLs(t *testing.T) {
+	store := newTestStore()
+	task := store.AddTask("Design", "")
+
+	for _, raw := range []string{"", "not a url", "example.com/page", "ftp://example.com/file", "http://", "https://exa mple.com", "https://example.com/%zz"} {
+		if _, _, err := store.AddLink(task.ID, raw, ""); err == nil {
+			t.Errorf("Expected %q to be rejected", raw)
+		}
+	}
+	if links, _ := store.Links(task.ID); len(links) != 0 {
+		t.Errorf("Rejected links must not be stored, got %+v", links)
+	}
+}
+
+func TestLinksPersistenceRoundTrip(t *testing.T) {
+	store := newTestStore()
+	task := store.AddTask("Design", "")
+	store.AddLink(task.ID, "https://example.com/a", "A")
+	store.AddLink(task.ID, "https://example.com/b", "B")
+	store.DeleteLink(task.ID, 1)
+
+	loaded := &TaskStore{tasks: make(map[int]*Task), filePath: store.filePath}
+	if err := loaded.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	links, _ := loaded.Links(task.ID)
+	if len(links) != 1 || links[0].ID != 2 || links[0].Label != "B" || links[0].TaskID != task.ID {
+		t.Errorf("Expected link 2 to round-trip, got %+v", links)
+	}
+	// Link IDs are not reused after a restart
+	if link, _, _ := loaded.AddLink(task.ID, "https://example.com/c", ""); link.ID != 3 {
+		t.Errorf("Expected next link ID 3, got %d", link.ID)
+	}
+
+	loaded.DeleteTask(task.ID)
+	if len(loaded.allLinks()) != 0 {
+		t.Errorf("Deleting a task must remove its links")
+	}
+}
+
+func TestPartitionedLinksRoundTrip(t *testing.T) {
+	store := newPartitionedTestStore(t)
+	store.AddTask("Gone", "")
+	task := store.AddTask("Design", "")
+	store.AddLink(task.ID, "https://example.com/a", "A")
+	store.DeleteTask(1)
+	store.Compact()
+
+	loaded := &TaskStore{tasks: make(map[int]*Task), nextID: 1, partitions: store.partitions}
+	if err := loaded.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	links, ok := loaded.Links(1)
+	if !ok || len(links) != 1 || links[0].TaskID != 1 || links[0].URL != "https://example.com/a" {
+		t.Errorf("Expected the link to follow its task through compaction, got %+v", links)
+	}
+}
+
+func TestTaskAttachmentsHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("Design", "")
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/1/attachments", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		NewServer(s).taskAPIHandler(w, req)
+		return w
+	}
+
+	if w := post(`{"url":"https://example.com/spec","label":"Spec"}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post(`{"url":"javascript:alert(1)"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed URL, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/tasks/1/attachments", nil))
+	var links []Link
+	if err := json.Unmarshal(w.Body.Bytes(), &links); err != nil || len(links) != 1 || links[0].Label != "Spec" {
+		t.Errorf("Expected one link listed, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/1/attachments/1", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/1/attachments/1", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a deleted link, got %d", w.Code)
+	}
+
+	s.AddLink(task.ID, "https://example.com/badge", "")
+	w = httptest.NewRecorder()
+	NewServer(s).columnHandler(w, httptest.NewRequest(http.MethodGet, "/column/todo", nil))
+	if !strings.Contains(w.Body.String(), `class="task-links">🔗 1<`) {
+		t.Errorf("Expected the card to show a link count badge")
+	}
+}
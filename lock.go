@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var errBoardNotLocked = errors.New("board is not locked")
+
+// LockBoard makes the board read-only until UnlockBoard, so maintenance sees
+// it unchanged. Every mutating store method returns ErrBoardLocked meanwhile,
+// which writeError reports as a 423; reads carry on as usual. Locking a
+// locked board returns ErrBoardLocked with the existing reason.
+func (s *TaskStore) LockBoard(reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkLocked(); err != nil {
+		return err
+	}
+	s.isLocked, s.lockReason = true, reason
+	return nil
+}
+
+// UnlockBoard makes the board writable again, or returns errBoardNotLocked
+func (s *TaskStore) UnlockBoard() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.isLocked {
+		return errBoardNotLocked
+	}
+	s.isLocked, s.lockReason = false, ""
+	return nil
+}
+
+// BoardLock returns ErrBoardLocked while the board is locked, otherwise nil
+func (s *TaskStore) BoardLock() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkLocked()
+}
+
+// checkLocked is BoardLock (must be called with lock held)
+func (s *TaskStore) checkLocked() error {
+	if s.isLocked {
+		return &ErrBoardLocked{Reason: s.lockReason}
+	}
+	return nil
+}
+
+// adminLockHandler serves POST /admin/lock, locking the board with the
+// optional reason form field
+func (srv *Server) adminLockHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	if reason == "" {
+		reason = "Maintenance"
+	}
+	if err := srv.LockBoard(reason); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"locked": true, "reason": reason})
+}
+
+// adminUnlockHandler serves POST /admin/unlock
+func (srv *Server) adminUnlockHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := srv.UnlockBoard(); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"locked": false})
+}
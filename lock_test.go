@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockBoardBlocksMutations(t *testing.T) {
+	s := newTestStore()
+	task := s.AddTask("Design", "")
+	other := s.AddTask("Build", "")
+
+	if err := s.LockBoard("Importing"); err != nil {
+		t.Fatalf("LockBoard failed: %v", err)
+	}
+	var locked *ErrBoardLocked
+	if err := s.LockBoard("Again"); !errors.As(err, &locked) || locked.Reason != "Importing" {
+		t.Errorf("Expected locking twice to report the first reason, got %v", err)
+	}
+
+	if _, _, err := s.MoveTask(task.ID, "doing"); !errors.As(err, &locked) || locked.Reason != "Importing" {
+		t.Errorf("Expected MoveTask to be refused, got %v", err)
+	}
+	if _, err := s.SetDependencies(task.ID, []int{other.ID}); !errors.As(err, &locked) {
+		t.Errorf("Expected SetDependencies to be refused, got %v", err)
+	}
+	if _, _, err := s.AddComment(task.ID, "alice", "Hi"); !errors.As(err, &locked) {
+		t.Errorf("Expected AddComment to be refused, got %v", err)
+	}
+	if _, _, err := s.SetPinned(task.ID, true); !errors.As(err, &locked) {
+		t.Errorf("Expected SetPinned to be refused, got %v", err)
+	}
+	if err := s.ReorderColumn("todo", []int{other.ID, task.ID}); !errors.As(err, &locked) {
+		t.Errorf("Expected ReorderColumn to be refused, got %v", err)
+	}
+	if _, err := s.AssignTask(task.ID, "alice"); !errors.As(err, &locked) {
+		t.Errorf("Expected AssignTask to be refused, got %v", err)
+	}
+	if got, _ := s.GetTask(task.ID); got.Status != "todo" || len(got.DependsOn) != 0 || got.Pinned {
+		t.Errorf("Expected the task to be unchanged while locked, got %+v", got)
+	}
+
+	if err := s.UnlockBoard(); err != nil {
+		t.Fatalf("UnlockBoard failed: %v", err)
+	}
+	if err := s.UnlockBoard(); !errors.Is(err, errBoardNotLocked) {
+		t.Errorf("Expected unlocking twice to fail, got %v", err)
+	}
+	if _, _, err := s.MoveTask(task.ID, "doing"); err != nil {
+		t.Errorf("Expected MoveTask to succeed after unlocking, got %v", err)
+	}
+	if _, _, err := s.AddComment(task.ID, "alice", "Hi"); err != nil {
+		t.Errorf("Expected AddComment to succeed after unlocking, got %v", err)
+	}
+}
+
+func TestBoardHandlersWhileLocked(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("Design", "")
+	srv := NewServer(s)
+	s.LockBoard("Nightly backup")
+
+	post := func(handler http.HandlerFunc, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+	requests := []struct {
+		name    string
+		handler http.HandlerFunc
+		form    url.Values
+	}{
+		{"add", srv.addTaskHandler, url.Values{"title": {"New"}}},
+		{"move", srv.moveTaskHandler, url.Values{"id": {"1"}, "status": {"doing"}}},
+		{"delete", srv.deleteTaskHandler, url.Values{"id": {"1"}}},
+	}
+	for _, req := range requests {
+		w := post(req.handler, req.form)
+		if w.Code != http.StatusLocked || !strings.Contains(w.Body.String(), "Nightly backup") {
+			t.Errorf("%s: expected 423 with the reason, got %d: %s", req.name, w.Code, w.Body.String())
+		}
+	}
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/1", strings.NewReader(`{"title": "Renamed"}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	w := httptest.NewRecorder()
+	srv.taskAPIHandler(w, req)
+	if w.Code != http.StatusLocked || !strings.Contains(w.Body.String(), "Nightly backup") {
+		t.Errorf("merge patch: expected 423 with the reason, got %d: %s", w.Code, w.Body.String())
+	}
+	if tasks := s.GetAllTasks(); len(tasks) != 1 || tasks[0].Status != "todo" || tasks[0].Title != "Design" {
+		t.Errorf("Expected the board to be unchanged, got %+v", tasks)
+	}
+
+	s.UnlockBoard()
+	for _, req := range requests {
+		if w := post(req.handler, req.form); w.Code != http.StatusOK {
+			t.Errorf("%s: expected 200 after unlocking, got %d: %s", req.name, w.Code, w.Body.String())
+		}
+	}
+	if _, ok := s.GetTask(task.ID); ok {
+		t.Errorf("Expected the task to be deleted after unlocking")
+	}
+}
+
+func TestAdminLockHandlers(t *testing.T) {
+	s := withTestGlobals(t)
+	t.Setenv("KANBAN_ADMIN_KEY", "s3cret")
+	post := func(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set(adminKeyHeader, "s3cret")
+		w := httptest.NewRecorder()
+		requireAdminKey(handler)(w, req)
+		return w
+	}
+
+	if w := post(NewServer(s).adminLockHandler, "reason=Migrating"); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := s.BoardLock(); err == nil || err.Error() != "board is locked: Migrating" {
+		t.Errorf("Expected the board to be locked for migrating, got %v", err)
+	}
+	if w := post(NewServer(s).adminLockHandler, ""); w.Code != http.StatusLocked {
+		t.Errorf("Expected locking a locked board to be a 423, got %d", w.Code)
+	}
+
+	// Exports only read, so they run during maintenance and leave the lock alone
+	w := httptest.NewRecorder()
+	NewServer(s).exportMarkdownHandler(w, httptest.NewRequest(http.MethodGet, "/board/export/markdown", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the export to succeed while locked, got %d", w.Code)
+	}
+	if err := s.BoardLock(); err == nil {
+		t.Errorf("Expected the export to leave the admin lock in place")
+	}
+
+	if w := post(NewServer(s).adminUnlockHandler, ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post(NewServer(s).adminUnlockHandler, ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected unlocking an unlocked board to be a 409, got %d", w.Code)
+	}
+}
+
+func TestLockedBoardRefusesBulkImportAndSweepers(t *testing.T) {
+	s := withTestGlobals(t)
+	s.settings = withTestSettings(t)
+	if _, err := s.labels().Create("bug", "#ff0000"); err != nil {
+		t.Fatalf("Create label failed: %v", err)
+	}
+	old := time.Now().Add(-90 * 24 * time.Hour)
+	expired := s.AddTask("Expired", "")
+	stale := s.AddTask("Stale", "")
+	done := s.AddTask("Done", "")
+	s.MoveTask(stale.ID, "doing")
+	s.MoveTask(done.ID, "done")
+	s.UpdateTask(expired.ID, func(task *Task) { task.ExpiresAt = &old })
+	s.UpdateTask(stale.ID, func(task *Task) { task.StatusChangedAt = old })
+	s.UpdateTask(done.ID, func(task *Task) { task.StatusChangedAt = old })
+	s.AddChecklistItem(done.ID, "Ship")
+	before, _ := json.Marshal(s.Snapshot())
+
+	s.LockBoard("Importing")
+	var locked *ErrBoardLocked
+	check := func(name string, err error) {
+		t.Helper()
+		if !errors.As(err, &locked) {
+			t.Errorf("%s: expected ErrBoardLocked, got %v", name, err)
+		}
+	}
+	_, err := s.CreateTask(TaskSpec{Title: "New"})
+	check("CreateTask", err)
+	_, err = s.DeleteTask(done.ID)
+	check("DeleteTask", err)
+	_, err = s.CreateTasks([]TaskSpec{{Title: "A"}, {Title: "B"}})
+	check("CreateTasks", err)
+	_, err = s.BulkLabel([]int{done.ID}, []string{"bug"}, nil)
+	check("BulkLabel", err)
+	_, err = s.BatchMove("todo", "doing", FilterOptions{})
+	check("BatchMove", err)
+	_, err = s.BulkArchive("done", 0)
+	check("BulkArchive", err)
+	_, _, err = s.SplitTask(done.ID, []string{"A", "B"})
+	check("SplitTask", err)
+	check("Restore", s.Restore(PersistentData{}))
+	_, _, err = s.AddChecklistItem(done.ID, "Test")
+	check("AddChecklistItem", err)
+	_, _, err = s.ToggleChecklistItem(done.ID, 0)
+	check("ToggleChecklistItem", err)
+	_, _, err = s.DeleteChecklistItem(done.ID, 0)
+	check("DeleteChecklistItem", err)
+	_, _, err = s.SetWatching(done.ID, "alice", true)
+	check("SetWatching", err)
+	check("AddColumn", s.AddColumn("review", "Review", 0, ""))
+	name := "Backlog"
+	check("UpdateColumn", s.UpdateColumn("todo", &name, nil, nil))
+	check("RemoveColumn", s.RemoveColumn("done"))
+	_, err = s.DeleteLabel("bug")
+	check("DeleteLabel", err)
+	_, err = s.MoveToBottom(expired.ID)
+	check("MoveToBottom", err)
+	check("NormalizePositions", s.NormalizePositions("todo"))
+	_, err = s.DeleteExpiredTasks()
+	check("DeleteExpiredTasks", err)
+	_, err = s.ReturnStaleTasks(time.Hour)
+	check("ReturnStaleTasks", err)
+	_, err = s.Compact()
+	check("Compact", err)
+	_, err = s.Repair(s.CheckIntegrity())
+	check("Repair", err)
+
+	// The import handlers report the lock rather than creating anything
+	imports := []struct {
+		name        string
+		handler     http.HandlerFunc
+		contentType string
+		body        string
+	}{
+		{"bulk", NewServer(s).bulkCreateHandler, "application/json", `[{"title":"A"}]`},
+		{"text", NewServer(s).importTextHandler, "text/plain", "- A\n- B"},
+		{"template", NewServer(s).boardTemplateHandler, "application/json", `{"template":"software-kanban"}`},
+	}
+	for _, imp := range imports {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(imp.body))
+		req.Header.Set("Content-Type", imp.contentType)
+		w := httptest.NewRecorder()
+		imp.handler(w, req)
+		if w.Code != http.StatusLocked {
+			t.Errorf("%s import: expected 423, got %d: %s", imp.name, w.Code, w.Body.String())
+		}
+	}
+
+	if after, _ := json.Marshal(s.Snapshot()); string(after) != string(before) {
+		t.Errorf("Expected the board to be unchanged while locked\nbefore: %s\nafter:  %s", before, after)
+	}
+	if labels := s.labels().List(); len(labels) != 1 {
+		t.Errorf("Expected the label to survive, got %v", labels)
+	}
+	if cols := s.Columns(); len(cols) != len(boardColumns) {
+		t.Errorf("Expected the columns to be unchanged, got %v", cols)
+	}
+}
@@ -1,31 +1,111 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"html/template"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Task represents a single task in the kanban board
 type Task struct {
-	ID          int
-	Title       string
-	Description string
-	Status      string // "todo", "doing", "done"
+	ID                int                `json:"id"`
+	Title             string             `json:"title"`
+	Description       string             `json:"description"`
+	Status            string             `json:"status"` // "todo", "doing", "done"
+	Assignee          string             `json:"assignee"`
+	Effort            int                `json:"effort"`   // story points
+	Priority          int                `json:"priority"` // see PriorityLow..PriorityHigh
+	DueDate           *time.Time         `json:"due_date"`
+	Labels            []string           `json:"labels"`
+	Checklist         []ChecklistItem    `json:"checklist,omitempty"`
+	DependsOn         []int              `json:"depends_on,omitempty"`          // IDs of tasks that must finish first
+	Position          int                `json:"position"`                      // order within the column
+	Pinned            bool               `json:"pinned,omitempty"`              // shown first in its column
+	ReviewRequestedBy string             `json:"review_requested_by,omitempty"` // who approved the task's review
+	Watchers          []string           `json:"watchers,omitempty"`            // who is notified of changes
+	SprintID          int                `json:"sprint_id,omitempty"`           // the sprint it is planned into, if any
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	MovedAt           *time.Time         `json:"moved_at,omitempty"` // last move, nil until the first one
+	StatusChangedAt   time.Time          `json:"status_changed_at"`  // when the task entered its current column
+	ArchivedAt        *time.Time         `json:"archived_at,omitempty"`
+	ExpiresAt         *time.Time         `json:"expires_at,omitempty"`     // deleted once past, see KANBAN_TASK_RETENTION
+	StatusHistory     []StatusTransition `json:"status_history,omitempty"` // every move, oldest first
+}
+
+// Task priorities, from least to most important
+const (
+	PriorityNone = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+// PriorityLabel returns a display name for the task's priority
+func (t *Task) PriorityLabel() string {
+	switch t.Priority {
+	case PriorityLow:
+		return "Low"
+	case PriorityMedium:
+		return "Medium"
+	case PriorityHigh:
+		return "High"
+	}
+	return ""
+}
+
+// IsOverdue reports whether the task is past its due date and not yet done
+func (t *Task) IsOverdue(now time.Time) bool {
+	return t.DueDate != nil && t.Status != "done" && now.After(*t.DueDate)
 }
 
 // TaskStore holds all tasks with thread-safe access
 type TaskStore struct {
-	mu       sync.Mutex
-	tasks    map[int]*Task
-	nextID   int
-	filePath string
+	mu                 sync.Mutex
+	tasks              map[int]*Task
+	nextID             int
+	filePath           string // "" keeps the board in memory only
+	workflow           *WorkflowConfig
+	wipLimits          map[string]int
+	retention          time.Duration    // how long new tasks live before expiring, zero for ever
+	now                func() time.Time // overridable clock for tests
+	events             *EventBus        // receives task events, may be nil
+	partitions         *ColumnStore     // per-column files, nil for a single file
+	cache              ReadCache        // per-column read model, refreshed by persist
+	settings           *SettingsStore   // runtime overrides, may be nil
+	ownColumns         []columnDef      // a cloned board's columns, guarded by columnsMu; nil for boardColumns
+	backend            Backend          // external store, nil for local files
+	links              map[int][]*Link  // attachments by task ID
+	nextLinkID         int
+	relationships      []Relationship // loose links between tasks, oldest first
+	nextRelationshipID int
+	comments           map[int][]*Comment // by task ID, oldest first
+	nextCommentID      int
+	reactions          map[int][]Reaction // by comment ID
+	encryptKey         []byte             // encrypts titles and descriptions on disk, may be nil
+	hooks              []TransitionHook   // run by MoveTask, in registration order
+	revision           atomic.Uint64      // bumped on every change, see invalidatePages
+	taskSubscribers    TaskSubscriptions  // per-task event streams, see SubscribeToTask
+	deps               DependencyGraph    // depends_on as an adjacency list
+	deletedTasks       map[int]*Task      // last state of deleted tasks, see TasksSince
+	isLocked           bool               // read-only, see LockBoard
+	lockReason         string
 }
 
 // getDataFilePath returns the data file path from env var or default
@@ -43,77 +123,388 @@ var store = &TaskStore{
 	tasks:    make(map[int]*Task),
 	nextID:   1,
 	filePath: getDataFilePath(),
+	events:   bus,
+	settings: settings,
+}
+
+// clock returns the current time, using the injected clock if set
+func (s *TaskStore) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// Now returns the store's current time
+func (s *TaskStore) Now() time.Time {
+	return s.clock()
+}
+
+// TaskSpec describes a task to be created
+type TaskSpec struct {
+	Title       string
+	Description string
+	Status      string // defaults to "todo"
+	Assignee    string
+	Effort      int
+	Priority    int
+	DueDate     *time.Time
+	Labels      []string
 }
 
-// AddTask adds a new task to the store
+// AddTask adds a new task to the store. It returns nil if the board is
+// locked; CreateTask reports why.
 func (s *TaskStore) AddTask(title, description string) *Task {
+	task, _ := s.CreateTask(TaskSpec{Title: title, Description: description})
+	return task
+}
+
+// CreateTask adds a new task built from a spec to the store, or returns
+// ErrBoardLocked
+func (s *TaskStore) CreateTask(spec TaskSpec) (*Task, error) {
+	return s.CreateTaskContext(context.Background(), spec)
+}
+
+// CreateTaskContext is CreateTask, tracing it as part of ctx
+func (s *TaskStore) CreateTaskContext(ctx context.Context, spec TaskSpec) (*Task, error) {
+	span := s.startSpan(ctx, "CreateTask")
+	defer span.End()
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	task := s.newTask(spec)
+	span.SetAttributes(attribute.Int("task.id", task.ID))
+	s.persist(task.Status)
+	created := task.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskCreated, Task: created, ToStatus: created.Status})
+	return task, nil
+}
 
+// newTask builds a task from a spec and adds it to the map without saving
+// (must be called with lock held)
+func (s *TaskStore) newTask(spec TaskSpec) *Task {
+	status := spec.Status
+	if status == "" {
+		status = "todo"
+	}
 	task := &Task{
-		ID:          s.nextID,
-		Title:       title,
-		Description: description,
-		Status:      "todo",
+		ID:          s.allocateTaskID(),
+		Title:       spec.Title,
+		Description: spec.Description,
+		Status:      status,
+		Assignee:    spec.Assignee,
+		Effort:      spec.Effort,
+		Priority:    spec.Priority,
+		DueDate:     spec.DueDate,
+		Labels:      spec.Labels,
+		Position:    s.nextPosition(status),
+		CreatedAt:   s.clock(),
+	}
+	task.UpdatedAt = task.CreatedAt
+	task.StatusChangedAt = task.CreatedAt
+	if s.retention > 0 {
+		expires := task.CreatedAt.Add(s.retention)
+		task.ExpiresAt = &expires
 	}
 	s.tasks[task.ID] = task
-	s.nextID++
-	s.saveToFile()
+	s.deps.add(task.ID)
 	return task
 }
 
+// allocateTaskID returns the next free task ID, reserving it from the
+// backend when it shares IDs across instances (must be called with lock held)
+func (s *TaskStore) allocateTaskID() int {
+	id := s.nextID
+	if allocator, ok := s.backend.(idAllocator); ok {
+		shared, err := allocator.NextID()
+		if err != nil {
+			log.Printf("Error allocating task ID, using local counter: %v", err)
+		} else if shared >= id {
+			id = shared
+		}
+	}
+	s.nextID = id + 1
+	return id
+}
+
 // GetTask retrieves a task by ID
 func (s *TaskStore) GetTask(id int) (*Task, bool) {
+	span := s.startSpan(context.Background(), "GetTask", attribute.Int("task.id", id))
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	task, ok := s.tasks[id]
 	return task, ok
 }
 
-// GetTasksByStatus returns all tasks with a specific status
+// GetTasksByStatus returns all tasks with a specific status in board order,
+// served from the read cache
 func (s *TaskStore) GetTasksByStatus(status string) []*Task {
+	span := s.startSpan(context.Background(), "GetTasksByStatus", attribute.String("task.status", status))
+	defer span.End()
+
+	if tasks, ok := s.cache.get(status); ok {
+		return tasks
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache.rebuild(s.tasks, status)
+	tasks, _ := s.cache.get(status)
+	return tasks
+}
+
+// GetAllTasks returns every task ordered by ID
+func (s *TaskStore) GetAllTasks() []*Task {
+	span := s.startSpan(context.Background(), "GetAllTasks")
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	var tasks []*Task
+	tasks := make([]*Task, 0, len(s.tasks))
 	for _, task := range s.tasks {
-		if task.Status == status {
-			tasks = append(tasks, task)
-		}
+		tasks = append(tasks, task)
 	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
 	return tasks
 }
 
-// MoveTask changes the status of a task
-func (s *TaskStore) MoveTask(id int, newStatus string) (*Task, bool) {
+// MoveTask changes the status of a task. It returns false if the task does
+// not exist, ErrInvalidStatus for a status that isn't a column,
+// ErrTransitionNotAllowed if the workflow forbids the move,
+// PolicyViolation if the destination column doesn't accept it, and otherwise
+// the first error from a transition hook, such as ErrWIPLimitReached, wrapped
+// in ErrHookRejected. When review is required, moves into done are refused
+// with ErrReviewRequired; ApproveTask completes them instead.
+func (s *TaskStore) MoveTask(id int, newStatus string) (*Task, bool, error) {
+	return s.moveTask(context.Background(), id, newStatus, "")
+}
+
+// MoveTaskContext is MoveTask, tracing it as part of ctx
+func (s *TaskStore) MoveTaskContext(ctx context.Context, id int, newStatus string) (*Task, bool, error) {
+	return s.moveTask(ctx, id, newStatus, "")
+}
+
+// moveTask is MoveTask, approving the task's review on behalf of approver
+// unless it is empty
+func (s *TaskStore) moveTask(ctx context.Context, id int, newStatus, approver string) (*Task, bool, error) {
+	span := s.startSpan(ctx, "MoveTask", attribute.Int("task.id", id), attribute.String("task.status", newStatus))
+	defer span.End()
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, true, err
+	}
 	task, ok := s.tasks[id]
 	if !ok {
-		return nil, false
+		s.mu.Unlock()
+		return nil, false, nil
+	}
+	hooked, err := s.checkMove(task, newStatus, approver)
+	if err != nil {
+		s.mu.Unlock()
+		return task, true, err
+	}
+	*task = *hooked
+	if approver != "" {
+		task.ReviewRequestedBy = approver
+	}
+	oldStatus := task.Status
+	s.applyMove(task, newStatus)
+	s.persist(oldStatus, newStatus)
+	moved := task.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskMoved, Task: moved, FromStatus: oldStatus, ToStatus: newStatus})
+	return task, true, nil
+}
+
+// checkMove runs every check a move of task to newStatus must pass, from the
+// status itself through the transition hooks, without changing the task.
+// It returns the task as the hooks left it (must be called with lock held).
+func (s *TaskStore) checkMove(task *Task, newStatus, approver string) (*Task, error) {
+	if !s.hasColumn(newStatus) {
+		return nil, fmt.Errorf("%w %q", ErrInvalidStatus, newStatus)
+	}
+	if err := checkReview(task, newStatus, approver); err != nil {
+		return nil, err
+	}
+	if err := s.workflow.checkTransition(task.Status, newStatus); err != nil {
+		return nil, err
+	}
+	if err := s.settings.TransitionPolicy().check(task.Status, newStatus); err != nil {
+		return nil, err
+	}
+	return s.runTransitionHooks(task, task.Status, newStatus)
+}
+
+// applyMove puts a task in newStatus, at the bottom of the column when it
+// changes, and records the transition (must be called with lock held)
+func (s *TaskStore) applyMove(task *Task, newStatus string) {
+	oldStatus := task.Status
+	task.UpdatedAt = s.clock()
+	if newStatus != oldStatus {
+		task.Position = s.nextPosition(newStatus)
+		movedAt := task.UpdatedAt
+		task.MovedAt = &movedAt
+		task.StatusChangedAt = task.UpdatedAt
+		task.recordTransition(oldStatus, newStatus, task.UpdatedAt)
 	}
 	task.Status = newStatus
-	s.saveToFile()
-	return task, true
+}
+
+// UpdateTask applies update to a task's content fields and publishes the
+// resulting changes. Status changes must go through MoveTask instead. It
+// returns ErrTaskNotFound for an unknown task and ErrBoardLocked while the
+// board is locked.
+func (s *TaskStore) UpdateTask(id int, update func(task *Task)) (*Task, error) {
+	span := s.startSpan(context.Background(), "UpdateTask", attribute.Int("task.id", id))
+	defer span.End()
+
+	return s.updateTask(id, func(task *Task) error {
+		update(task)
+		return nil
+	})
+}
+
+// updateTask applies update, which may refuse the change by returning an
+// error before touching the task, and publishes the resulting changes
+func (s *TaskStore) updateTask(id int, update func(task *Task) error) (*Task, error) {
+	s.mu.Lock()
+
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	task, ok := s.tasks[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrTaskNotFound
+	}
+	before := task.clone()
+	if err := update(task); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	task.ID = before.ID
+	task.Status = before.Status
+	changes := taskFieldChanges(before, task)
+	if len(changes) == 0 {
+		s.mu.Unlock()
+		return task, nil
+	}
+	if formatIDs(before.DependsOn) != formatIDs(task.DependsOn) {
+		s.deps.set(id, task.DependsOn)
+	}
+	task.UpdatedAt = s.clock()
+	s.persist(task.Status)
+	updated := task.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskUpdated, Task: updated, Changes: changes})
+	if updated.Assignee != "" && updated.Assignee != before.Assignee {
+		s.publish(Event{Type: EventTaskAssigned, Task: updated, Changes: []FieldChange{
+			{Field: "assignee", Old: before.Assignee, New: updated.Assignee},
+		}})
+	}
+	return task, nil
+}
+
+// DeleteTask removes a task from the store. It returns false if the task
+// does not exist and ErrBoardLocked while the board is locked.
+func (s *TaskStore) DeleteTask(id int) (bool, error) {
+	return s.DeleteTaskContext(context.Background(), id)
+}
+
+// DeleteTaskContext is DeleteTask, tracing it as part of ctx
+func (s *TaskStore) DeleteTaskContext(ctx context.Context, id int) (bool, error) {
+	span := s.startSpan(ctx, "DeleteTask", attribute.Int("task.id", id))
+	defer span.End()
+
+	s.mu.Lock()
+
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return true, err
+	}
+	task, ok := s.tasks[id]
+	if !ok {
+		s.mu.Unlock()
+		return false, nil
+	}
+	delete(s.tasks, id)
+	s.deps.remove(id)
+	s.recordDeletion(task)
+	hadLinks := len(s.links[id]) > 0
+	delete(s.links, id)
+	hadComments := s.deleteComments(id)
+	hadRelationships := s.deleteRelationships(id)
+	s.persist(task.Status)
+	if hadLinks && s.partitions != nil {
+		s.persistLinks()
+	}
+	if hadComments && s.partitions != nil {
+		s.persistComments()
+	}
+	if hadRelationships && s.partitions != nil {
+		s.persistRelationships()
+	}
+	if (hadLinks || hadComments || hadRelationships) && s.backend != nil {
+		s.saveExtras()
+	}
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskDeleted, Task: task, FromStatus: task.Status})
+	return true, nil
 }
 
 // Persistence structures
 type PersistentData struct {
-	Tasks  []*Task `json:"tasks"`
-	NextID int     `json:"next_id"`
+	Tasks              []*Task        `json:"tasks"`
+	NextID             int            `json:"next_id"`
+	Links              []*Link        `json:"links,omitempty"`
+	NextLinkID         int            `json:"next_link_id,omitempty"`
+	Comments           []*Comment     `json:"comments,omitempty"`
+	NextCommentID      int            `json:"next_comment_id,omitempty"`
+	Reactions          []Reaction     `json:"reactions,omitempty"`
+	Relationships      []Relationship `json:"relationships,omitempty"`
+	NextRelationshipID int            `json:"next_relationship_id,omitempty"`
 }
 
 // saveToFile saves tasks to JSON file (must be called with lock held)
 func (s *TaskStore) saveToFile() {
+	if s.filePath == "" {
+		return
+	}
+	tasks, err := s.storedTasks()
+	if err != nil {
+		log.Printf("Error encrypting tasks: %v", err)
+		return
+	}
 	var taskList []*Task
-	for _, task := range s.tasks {
+	for _, task := range tasks {
 		taskList = append(taskList, task)
 	}
 
 	data := PersistentData{
-		Tasks:  taskList,
-		NextID: s.nextID,
+		Tasks:              taskList,
+		NextID:             s.nextID,
+		Links:              s.allLinks(),
+		NextLinkID:         s.nextLinkID,
+		Comments:           s.allComments(),
+		NextCommentID:      s.nextCommentID,
+		Reactions:          s.allReactions(),
+		Relationships:      s.relationships,
+		NextRelationshipID: s.nextRelationshipID,
 	}
 
 	// Ensure directory exists
@@ -143,6 +534,57 @@ func (s *TaskStore) saveToFile() {
 func (s *TaskStore) LoadFromFile() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.cache.reset()
+	defer s.invalidatePages()
+
+	if s.backend != nil {
+		tasks, nextID, err := s.backend.Load()
+		if err != nil {
+			return err
+		}
+		extras, err := s.backend.loadExtras()
+		if err != nil {
+			return err
+		}
+		s.tasks = tasks
+		s.deps.reset(tasks)
+		s.nextID = nextID
+		s.setLinks(extras.Links, extras.NextLinkID)
+		s.setComments(extras.Comments, extras.NextCommentID, extras.Reactions)
+		s.setRelationships(extras.Relationships, extras.NextRelationshipID)
+		log.Printf("Loaded %d tasks from backend", len(s.tasks))
+		return nil
+	}
+
+	if s.partitions != nil {
+		tasks, nextID, err := s.partitions.Load()
+		if err != nil {
+			return err
+		}
+		if err := decryptTasks(tasks, s.encryptKey); err != nil {
+			return err
+		}
+		s.tasks = tasks
+		s.deps.reset(tasks)
+		s.nextID = nextID
+		links, nextLinkID, err := s.partitions.LoadLinks()
+		if err != nil {
+			return err
+		}
+		s.setLinks(links, nextLinkID)
+		comments, nextCommentID, reactions, err := s.partitions.LoadComments()
+		if err != nil {
+			return err
+		}
+		s.setComments(comments, nextCommentID, reactions)
+		relationships, nextRelationshipID, err := s.partitions.LoadRelationships()
+		if err != nil {
+			return err
+		}
+		s.setRelationships(relationships, nextRelationshipID)
+		log.Printf("Loaded %d tasks from partition files", len(s.tasks))
+		return nil
+	}
 
 	file, err := os.Open(s.filePath)
 	if err != nil {
@@ -159,57 +601,363 @@ func (s *TaskStore) LoadFromFile() error {
 		return err
 	}
 
-	s.tasks = make(map[int]*Task)
+	tasks := make(map[int]*Task)
 	for _, task := range data.Tasks {
-		s.tasks[task.ID] = task
+		tasks[task.ID] = task
 	}
+	if err := decryptTasks(tasks, s.encryptKey); err != nil {
+		return err
+	}
+	s.tasks = tasks
+	s.deps.reset(tasks)
 	s.nextID = data.NextID
+	s.setLinks(data.Links, data.NextLinkID)
+	s.setComments(data.Comments, data.NextCommentID, data.Reactions)
+	s.setRelationships(data.Relationships, data.NextRelationshipID)
 
 	log.Printf("Loaded %d tasks from file", len(s.tasks))
 	return nil
 }
 
-// Template data structures
-type PageData struct {
-	TodoTasks  []*Task
-	DoingTasks []*Task
-	DoneTasks  []*Task
-}
-
-var templates = template.Must(template.ParseGlob("templates/*.html"))
+var templates = mustLoadTemplates(assetFS(embeddedTemplates, "templates", false), "*.html")
 
 func main() {
-	// Load existing data from file
+	// Load kanban.yaml, with environment variables taking precedence
+	cfg, err := LoadConfig(getConfigFilePath())
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	// Report every bad setting before starting anything
+	if errs := ValidateConfig(cfg); len(errs) > 0 {
+		fmt.Fprintln(os.Stderr, "Invalid configuration:")
+		for _, e := range errs {
+			fmt.Fprintf(os.Stderr, "  %v\n", e)
+		}
+		os.Exit(1)
+	}
+	accessLogLevel, _ = parseLogLevel(cfg.Server.LogLevel)
+
+	// Keep the board in Redis, SQLite or Postgres instead of local files
+	var board Store = store
+	switch cfg.Storage.Backend {
+	case "file":
+		store.filePath = cfg.Storage.Path
+		// Split storage into one file per column if requested
+		if cfg.Storage.Partitioned {
+			store.partitions = NewColumnStore(filepath.Dir(store.filePath))
+		}
+	case "postgres":
+		pg, err := NewPostgresStore(store, cfg.Storage.URL, cfg.Storage.MaxConns)
+		if err != nil {
+			log.Fatalf("Could not open postgres backend: %v", err)
+		}
+		board = pg
+	default:
+		backend, err := OpenBackend(cfg.Storage)
+		if err != nil {
+			log.Fatalf("Could not open %s backend: %v", cfg.Storage.Backend, err)
+		}
+		store.attachBackend(backend)
+	}
+	defer store.Close()
+
+	// Load runtime settings, which override env configuration. They can add
+	// columns, so they are read before the tasks.
+	if err := settings.Load(); err != nil {
+		log.Printf("Warning: Could not load settings: %v", err)
+	}
+
+	// Tasks wait in a review column before done when review is required
+	if requireReview {
+		ensureReviewColumn()
+	}
+
+	// Encrypt task titles and descriptions on disk if a key is set
+	store.encryptKey, err = LoadEncryptKey()
+	if err != nil {
+		log.Fatalf("Invalid encryption key: %v", err)
+	}
+
+	// Load existing data from file. Starting empty after a decryption
+	// failure would overwrite the encrypted tasks, so that is fatal.
 	if err := store.LoadFromFile(); err != nil {
+		if errors.Is(err, errNoEncryptKey) || errors.Is(err, errDecryptField) {
+			log.Fatalf("Could not load data: %v", err)
+		}
 		log.Printf("Warning: Could not load data: %v", err)
 	}
 
-	// Serve static files (for htmx)
-	http.HandleFunc("/", indexHandler)
-	http.HandleFunc("/add-task", addTaskHandler)
-	http.HandleFunc("/move-task", moveTaskHandler)
-	http.HandleFunc("/column/", columnHandler)
+	// Load the status transition workflow, falling back to workflow.json
+	if cfg.Features.Workflow != nil {
+		store.workflow = &WorkflowConfig{Transitions: cfg.Features.Workflow}
+	} else {
+		workflow, err := LoadWorkflow()
+		if err != nil {
+			log.Fatalf("Could not load workflow: %v", err)
+		}
+		store.workflow = workflow
+	}
+
+	// Experimental features, which can also be toggled at /admin/features
+	flags, err := LoadFeatureFlags()
+	if err != nil {
+		log.Fatalf("Could not load feature flags: %v", err)
+	}
+	features.Set(flags)
+
+	// Per-column WIP limits
+	store.wipLimits = cfg.Features.WIPLimits
+
+	// New tasks expire after KANBAN_TASK_RETENTION, if set
+	store.retention = loadTaskRetention()
+
+	// Export traces if an OTLP endpoint is configured
+	shutdownTracing, err := setupTracing(context.Background())
+	if err != nil {
+		log.Fatalf("Could not set up tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
+	// Wire up event subscribers
+	subscribeDefaultHandlers(bus, store, auditLog)
+
+	// Append every event to the on-disk audit log
+	auditFile, err = OpenAuditFile(getAuditFilePath())
+	if err != nil {
+		log.Fatalf("Could not open audit log: %v", err)
+	}
+	bus.Subscribe(EventAll, auditFile.Record)
+
+	// Send notifications from a pool of background workers
+	jobs = NewJobQueue(cfg.Server.JobQueueSize)
+	jobs.Start(cfg.Server.JobWorkers)
+
+	// Email assignees when SMTP is configured
+	emailNotifier := NewEmailNotifier(cfg.Notifications.SMTP)
+	if emailNotifier != nil {
+		emailNotifier.BaseURL = strings.TrimSuffix(cfg.Notifications.Slack.BaseURL, "/")
+	}
+	subscribeEmailNotifier(bus, emailNotifier)
 
-	log.Println("Starting server on http://localhost:8080")
-	log.Printf("Your tasks are saved to: %s\n", store.filePath)
+	// Email a daily board digest when recipients are configured
+	digest, err := LoadDigestSchedule()
+	if err != nil {
+		log.Fatalf("Could not load digest schedule: %v", err)
+	}
+	if digest != nil && emailNotifier != nil {
+		go digest.run(store, emailNotifier, make(chan struct{}))
+	} else if digest != nil {
+		log.Println("KANBAN_DIGEST_TO is set but SMTP is not configured; no digest will be sent")
+	}
+
+	// POST task events to configured webhooks
+	webhooks = NewWebhookNotifier(cfg.Notifications.Webhooks)
+	subscribeWebhooks(bus, webhooks)
+
+	// Announce completed tasks in Slack
+	subscribeSlackNotifier(bus, NewSlackNotifier(cfg.Notifications.Slack))
+
+	// In development, re-parse templates and reload open pages when they change
+	if cfg.Server.Env == "development" {
+		templates = mustLoadTemplates(assetFS(embeddedTemplates, "templates", true), "*.html")
+		staticFiles = assetFS(embeddedStatic, "static", true)
+		devReload, err = NewDevReloadServer("templates", templates.reload)
+		if err != nil {
+			log.Fatalf("Could not start live reload: %v", err)
+		}
+		defer devReload.Close()
+		http.Handle("/dev/reload", devReload)
+		log.Println("Development mode: pages reload when templates change, profiles at /debug/pprof/")
+	}
+
+	// Push task changes to WebSocket clients
+	go wsHub.Run(make(chan struct{}))
+	bus.Subscribe(EventAll, wsHub.PublishEvent)
+
+	// Rebuild the full-text search index shortly after the board changes
+	searchIndex = NewSearchIndex(store, defaultSearchIndexDebounce)
+	bus.Subscribe(EventAll, func(Event) { searchIndex.Changed() })
+
+	// Periodically return tasks stuck in "doing" to "todo"
+	staleThreshold, err := LoadStaleThreshold()
+	if err != nil {
+		log.Fatalf("Could not load stale threshold: %v", err)
+	}
+	if staleThreshold > 0 {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		go store.runStaleSweeper(ticker.C, staleThreshold, make(chan struct{}))
+	}
+
+	// Delete tasks past their retention period every hour
+	expiryTicker := time.NewTicker(time.Hour)
+	defer expiryTicker.Stop()
+	go store.runExpirySweeper(expiryTicker.C, make(chan struct{}))
+
+	// Periodically nudge the assignees of tasks nobody has touched
+	idleTicker := time.NewTicker(idleCheckInterval)
+	defer idleTicker.Stop()
+	go store.runIdleChecker(idleTicker.C, idleThreshold, make(chan struct{}))
+
+	// Serve the board, plus its CSS and JS from static/
+	srv := NewServer(board)
+	http.HandleFunc("/", srv.indexHandler)
+	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.FS(staticFiles))))
+	http.HandleFunc("/add-task", srv.addTaskHandler)
+	http.HandleFunc("/move-task", srv.moveTaskHandler)
+	http.HandleFunc("/delete-task", srv.deleteTaskHandler)
+	http.HandleFunc("/column/", srv.columnHandler)
+	http.HandleFunc("/task/", srv.taskRouter)
+	http.HandleFunc("/reorder/", srv.reorderHandler)
+	http.HandleFunc("/snapshots", srv.createSnapshotHandler)
+	http.HandleFunc("/ws", srv.wsHandler)
+	http.HandleFunc("/settings", settingsHandler)
+	http.HandleFunc("/settings/", deleteSettingHandler)
+	http.HandleFunc("/settings/theme", themeHandler)
+	http.HandleFunc("/settings/keyboard-shortcuts", keyboardShortcutsHandler)
+	http.HandleFunc("/boards/", srv.boardsRouter)
+	http.HandleFunc("/api/v1/boards/", srv.boardsAPIHandler)
+	http.HandleFunc("/accept-invite", acceptInviteHandler)
+	http.HandleFunc("/board/template", srv.boardTemplateHandler)
+	http.HandleFunc("/board/export/markdown", srv.exportMarkdownHandler)
+	http.HandleFunc("/board/stats", boardStatsHandler)
+	http.HandleFunc("/board/stats/heatmap", heatmapHandler)
+	http.HandleFunc("/metrics/custom", srv.customMetricsHandler)
+	http.HandleFunc("/api/v1/diff", diffHandler)
+	http.HandleFunc("/api/v1/swimlanes", srv.swimLanesHandler)
+	http.HandleFunc("/api/v1/columns", srv.columnsAPIHandler)
+	http.HandleFunc("/api/v1/columns/", srv.columnsAPIHandler)
+	http.HandleFunc("/api/v1/labels", srv.labelsAPIHandler)
+	http.HandleFunc("/api/v1/labels/", srv.labelsAPIHandler)
+	http.HandleFunc("/api/v1/sprints", srv.sprintsAPIHandler)
+	http.HandleFunc("/api/v1/tasks", srv.tasksAPIHandler)
+	http.HandleFunc("/api/v1/tasks/", srv.taskAPIHandler)
+	http.HandleFunc("/api/v1/tasks/bulk", srv.bulkCreateHandler)
+	http.HandleFunc("/api/v1/tasks/bulk-label", srv.bulkLabelHandler)
+	http.HandleFunc("/api/v1/tasks/bulk-archive", srv.bulkArchiveHandler)
+	http.HandleFunc("/api/v1/tasks/batch-move", srv.batchMoveHandler)
+	http.HandleFunc("/api/v1/tasks/since", srv.tasksSinceHandler)
+	http.HandleFunc("/api/v1/relationships/", srv.relationshipHandler)
+	http.HandleFunc("/api/v1/velocity", srv.velocityHandler)
+	http.HandleFunc("/api/v1/metrics/cycle-time", srv.cycleTimeHandler)
+	http.HandleFunc("/api/v1/mobile/board", srv.mobileBoardHandler)
+	http.HandleFunc("/api/v1/mobile/column/", srv.mobileColumnHandler)
+	http.HandleFunc("/api/v1/search", searchAPIHandler)
+	http.HandleFunc("/api/v1/search/advanced", srv.advancedSearchHandler)
+	http.HandleFunc("/export/ical", srv.exportICalHandler)
+	http.HandleFunc("/api/v1/export/gantt", srv.ganttExportHandler)
+	http.Handle("/import/github", gzipRequestMiddleware(http.HandlerFunc(srv.importGitHubHandler)))
+	http.Handle("/import/jira", gzipRequestMiddleware(http.HandlerFunc(srv.importJIRAHandler)))
+	http.Handle("/import/text", gzipRequestMiddleware(http.HandlerFunc(srv.importTextHandler)))
+	http.Handle("/import/validate", gzipRequestMiddleware(http.HandlerFunc(srv.importValidateHandler)))
+	http.HandleFunc("/add-task-from-url", srv.addTaskFromURLHandler)
+	http.HandleFunc("/healthz", srv.healthHandler)
+	http.HandleFunc("/search", srv.searchHandler)
+	http.HandleFunc("/print", srv.printHandler)
+	http.HandleFunc("/admin", requireAdminKey(srv.adminHandler))
+	http.HandleFunc("/admin/check-integrity", requireAdminKey(srv.checkIntegrityHandler))
+	http.HandleFunc("/admin/features", requireAdminKey(featuresHandler))
+	http.HandleFunc("/admin/restore", requireAdminKey(srv.restoreHandler))
+	http.HandleFunc("/admin/lock", requireAdminKey(srv.adminLockHandler))
+	http.HandleFunc("/admin/unlock", requireAdminKey(srv.adminUnlockHandler))
+	http.HandleFunc("/audit/export", auditExportHandler)
+
+	// Profiling endpoints, only in development
+	mux := http.NewServeMux()
+	registerDebugHandlers(mux, cfg.Server.Env)
+	mux.Handle("/", http.DefaultServeMux)
+
+	server := &http.Server{
+		Addr:         cfg.Server.Addr,
+		Handler:      withMiddleware(mux, cfg.Features.RateLimit.RequestsPerMinute),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+	useTLS := cfg.Server.TLS.CertFile != ""
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	log.Printf("Starting server on %s://%s\n", scheme, displayAddr(cfg.Server.Addr))
+	switch {
+	case store.backend != nil:
+		log.Printf("Your tasks are saved to the %s backend\n", cfg.Storage.Backend)
+	case store.partitions != nil:
+		log.Printf("Your tasks are saved to one file per column in: %s\n", store.partitions.dir)
+	default:
+		log.Printf("Your tasks are saved to: %s\n", store.filePath)
+	}
 	if os.Getenv("KANBAN_DATA_FILE") != "" {
 		log.Println("Using custom data location from KANBAN_DATA_FILE environment variable")
 	}
-	log.Fatal(http.ListenAndServe(":8080", nil))
+
+	ln, err := net.Listen("tcp", cfg.Server.Addr)
+	if err != nil {
+		log.Fatalf("Could not listen on %s: %v", cfg.Server.Addr, err)
+	}
+	listener := newLimitListener(ln, cfg.Server.MaxConns, cfg.Server.AcceptTimeout)
+	serveErr := make(chan error, 1)
+	go func() {
+		if useTLS {
+			serveErr <- server.ServeTLS(listener, cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile)
+			return
+		}
+		serveErr <- server.Serve(listener)
+	}()
+
+	// On SIGINT or SIGTERM, let open requests and queued jobs finish, then
+	// cancel whatever is left
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-serveErr:
+		log.Fatal(err)
+	case <-interrupt:
+	}
+	log.Println("Shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Error shutting down the server: %v", err)
+	}
+	jobs.Stop(ctx)
 }
 
-// indexHandler serves the main page
-func indexHandler(w http.ResponseWriter, r *http.Request) {
-	data := PageData{
-		TodoTasks:  store.GetTasksByStatus("todo"),
-		DoingTasks: store.GetTasksByStatus("doing"),
-		DoneTasks:  store.GetTasksByStatus("done"),
+// displayAddr turns a listen address like ":8080" into "localhost:8080"
+func displayAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "localhost" + addr
 	}
-	templates.ExecuteTemplate(w, "index.html", data)
+	return addr
+}
+
+// indexHandler serves the main page, reusing the last rendering while the
+// board is unchanged
+func (srv *Server) indexHandler(w http.ResponseWriter, r *http.Request) {
+	key := srv.boardStateHash(r)
+	if contentType, body, ok := srv.pages.Load(key); ok {
+		writePage(w, r, contentType, body)
+		return
+	}
+
+	data := withCollapsed(r, srv.GetBoardDataContext(r.Context()))
+	if r.URL.Query().Get("view") == "swimlane" {
+		data.View = "swimlane"
+		data.SwimLanes = srv.GetSwimLanes()
+	}
+	body, err := renderPage(r, "board.html", data)
+	if err != nil {
+		log.Printf("Error rendering board.html: %v", err)
+		http.Error(w, "Could not render page", http.StatusInternalServerError)
+		return
+	}
+	srv.pages.Store(key, htmlContentType, body)
+	writePage(w, r, htmlContentType, body)
 }
 
 // addTaskHandler handles adding a new task
-func addTaskHandler(w http.ResponseWriter, r *http.Request) {
+func (srv *Server) addTaskHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -217,24 +965,73 @@ func addTaskHandler(w http.ResponseWriter, r *http.Request) {
 
 	title := r.FormValue("title")
 	description := r.FormValue("description")
+	assignee := r.FormValue("assignee")
 
-	if title == "" {
+	if strings.TrimSpace(title) == "" {
 		http.Error(w, "Title is required", http.StatusBadRequest)
 		return
 	}
+	if len(title) > maxTitleLength {
+		http.Error(w, fmt.Sprintf("Title is longer than %d characters", maxTitleLength), http.StatusBadRequest)
+		return
+	}
 
-	store.AddTask(title, description)
+	effort := 0
+	if effortStr := r.FormValue("effort"); effortStr != "" {
+		var err error
+		effort, err = strconv.Atoi(effortStr)
+		if err != nil || effort < 0 {
+			http.Error(w, "Invalid effort", http.StatusBadRequest)
+			return
+		}
+	}
 
-	// Return the updated "To Do" column
-	tasks := store.GetTasksByStatus("todo")
-	templates.ExecuteTemplate(w, "column-content.html", map[string]interface{}{
-		"Status": "todo",
-		"Tasks":  tasks,
-	})
+	priority := PriorityNone
+	if priorityStr := r.FormValue("priority"); priorityStr != "" {
+		var err error
+		priority, err = strconv.Atoi(priorityStr)
+		if err != nil || priority < PriorityNone || priority > PriorityHigh {
+			http.Error(w, "Invalid priority", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var dueDate *time.Time
+	if dueStr := r.FormValue("due_date"); dueStr != "" {
+		due, err := time.Parse("2006-01-02", dueStr)
+		if err != nil {
+			http.Error(w, "Invalid due date", http.StatusBadRequest)
+			return
+		}
+		dueDate = &due
+	}
+
+	if _, err := srv.CreateTaskContext(r.Context(), TaskSpec{
+		Title:       title,
+		Description: description,
+		Assignee:    assignee,
+		Effort:      effort,
+		Priority:    priority,
+		DueDate:     dueDate,
+		Labels:      parseLabels(r.FormValue("labels")),
+	}); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Return the updated "To Do" column, refreshing its header badge out of
+	// band
+	data := withCollapsed(r, srv.GetBoardDataContext(r.Context()))
+	for _, col := range data.Columns {
+		if col.Status == "todo" {
+			templates.ExecuteTemplate(w, "column-content.html", col)
+		}
+	}
+	writeColumnBadgesOOB(w, data)
 }
 
 // moveTaskHandler handles moving tasks between columns
-func moveTaskHandler(w http.ResponseWriter, r *http.Request) {
+func (srv *Server) moveTaskHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -249,34 +1046,89 @@ func moveTaskHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, ok := store.MoveTask(id, newStatus)
+	task, ok, err := srv.MoveTaskContext(r.Context(), id, newStatus)
 	if !ok {
 		http.Error(w, "Task not found", http.StatusNotFound)
 		return
 	}
-
-	// Return all three columns to update the board
-	data := PageData{
-		TodoTasks:  store.GetTasksByStatus("todo"),
-		DoingTasks: store.GetTasksByStatus("doing"),
-		DoneTasks:  store.GetTasksByStatus("done"),
+	var violation *PolicyViolation
+	if errors.As(err, &violation) {
+		writePolicyViolation(w, violation)
+		return
 	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Return all three columns to update the board, with every header badge
+	// also sent out of band so the counts update wherever it is swapped
+	data := withCollapsed(r, srv.GetBoardDataContext(r.Context()))
 	templates.ExecuteTemplate(w, "all-columns.html", data)
+	writeColumnBadgesOOB(w, data)
 
 	fmt.Printf("Moved task %d (%s) to %s\n", task.ID, task.Title, task.Status)
 }
 
+// deleteTaskHandler handles removing a task from the board
+func (srv *Server) deleteTaskHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+	deleted, err := srv.DeleteTaskContext(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	// Return all three columns to update the board
+	templates.ExecuteTemplate(w, "all-columns.html", withCollapsed(r, srv.GetBoardDataContext(r.Context())))
+}
+
 // columnHandler returns a single column's content
-func columnHandler(w http.ResponseWriter, r *http.Request) {
+func (srv *Server) columnHandler(w http.ResponseWriter, r *http.Request) {
 	status := r.URL.Path[len("/column/"):]
-	if status != "todo" && status != "doing" && status != "done" {
+	if !isValidStatus(status) {
 		http.Error(w, "Invalid status", http.StatusBadRequest)
 		return
 	}
 
-	tasks := store.GetTasksByStatus(status)
-	templates.ExecuteTemplate(w, "column-content.html", map[string]interface{}{
-		"Status": status,
-		"Tasks":  tasks,
-	})
+	col := srv.GetColumnDataContext(r.Context(), status)
+	col.Collapsed = requestPreferences(r).CollapsedTasks
+	templates.ExecuteTemplate(w, "column-content.html", col)
+}
+
+// parseLabels splits a comma-separated label list, dropping blanks and duplicates
+func parseLabels(raw string) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, label := range strings.Split(raw, ",") {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// writeJSON encodes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
 }
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -16,6 +20,20 @@ func newTestStore() *TaskStore {
 	}
 }
 
+// withTestGlobals swaps the package-level store, bus, and audit log for
+// fresh, wired-up instances for the duration of a test
+func withTestGlobals(t *testing.T) *TaskStore {
+	origStore, origBus, origLog := store, bus, auditLog
+	t.Cleanup(func() { store, bus, auditLog = origStore, origBus, origLog })
+
+	store = newTestStore()
+	bus = NewEventBus()
+	auditLog = &EventLog{}
+	store.events = bus
+	subscribeDefaultHandlers(bus, store, auditLog)
+	return store
+}
+
 func TestAddTask(t *testing.T) {
 	store := newTestStore()
 	task := store.AddTask("Test Task", "Test Description")
@@ -48,14 +66,14 @@ func TestGetTasksByStatus(t *testing.T) {
 func TestMoveTask(t *testing.T) {
 	store := newTestStore()
 	task := store.AddTask("Move Me", "")
-	_, ok := store.MoveTask(task.ID, "doing")
+	_, ok, _ := store.MoveTask(task.ID, "doing")
 	if !ok {
 		t.Errorf("MoveTask failed")
 	}
 	if store.tasks[task.ID].Status != "doing" {
 		t.Errorf("Task status not updated")
 	}
-	_, ok = store.MoveTask(999, "done")
+	_, ok, _ = store.MoveTask(999, "done")
 	if ok {
 		t.Errorf("Should not move non-existent task")
 	}
@@ -90,3 +108,61 @@ func TestEmptyStore(t *testing.T) {
 		t.Errorf("Expected no tasks in empty store")
 	}
 }
+
+func TestHandlerValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name                 string
+		path                 string
+		method               string
+		formValues           map[string]string
+		expectedStatus       int
+		expectedBodyContains string
+	}{
+		{"empty title", "/add-task", http.MethodPost, map[string]string{"title": ""}, http.StatusBadRequest, "Title is required"},
+		{"blank title", "/add-task", http.MethodPost, map[string]string{"title": "   "}, http.StatusBadRequest, "Title is required"},
+		{"title of 200 characters", "/add-task", http.MethodPost, map[string]string{"title": strings.Repeat("a", 200)}, http.StatusOK, strings.Repeat("a", 200)},
+		{"title of 201 characters", "/add-task", http.MethodPost, map[string]string{"title": strings.Repeat("a", 201)}, http.StatusBadRequest, "longer than 200 characters"},
+		{"empty add body", "/add-task", http.MethodPost, nil, http.StatusBadRequest, "Title is required"},
+		{"empty status", "/move-task", http.MethodPost, map[string]string{"id": "1", "status": ""}, http.StatusBadRequest, "invalid status"},
+		{"SQL injection status", "/move-task", http.MethodPost, map[string]string{"id": "1", "status": "todo'; DROP TABLE tasks; --"}, http.StatusBadRequest, "invalid status"},
+		{"negative move id", "/move-task", http.MethodPost, map[string]string{"id": "-1", "status": "done"}, http.StatusNotFound, "Task not found"},
+		{"zero move id", "/move-task", http.MethodPost, map[string]string{"id": "0", "status": "done"}, http.StatusNotFound, "Task not found"},
+		{"max int64 move id", "/move-task", http.MethodPost, map[string]string{"id": "9223372036854775807", "status": "done"}, http.StatusNotFound, "Task not found"},
+		{"id past int64", "/move-task", http.MethodPost, map[string]string{"id": "9223372036854775808", "status": "done"}, http.StatusBadRequest, "Invalid task ID"},
+		{"non-numeric move id", "/move-task", http.MethodPost, map[string]string{"id": "abc", "status": "done"}, http.StatusBadRequest, "Invalid task ID"},
+		{"empty move body", "/move-task", http.MethodPost, nil, http.StatusBadRequest, "Invalid task ID"},
+		{"negative delete id", "/delete-task", http.MethodPost, map[string]string{"id": "-1"}, http.StatusNotFound, "Task not found"},
+		{"zero delete id", "/delete-task", http.MethodPost, map[string]string{"id": "0"}, http.StatusNotFound, "Task not found"},
+		{"max int64 delete id", "/delete-task", http.MethodPost, map[string]string{"id": "9223372036854775807"}, http.StatusNotFound, "Task not found"},
+		{"non-numeric delete id", "/delete-task", http.MethodPost, map[string]string{"id": "abc"}, http.StatusBadRequest, "Invalid task ID"},
+		{"empty delete body", "/delete-task", http.MethodPost, nil, http.StatusBadRequest, "Invalid task ID"},
+		{"GET add", "/add-task", http.MethodGet, nil, http.StatusMethodNotAllowed, "Method not allowed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := withTestGlobals(t)
+			s.AddTask("Existing", "")
+			srv := NewServer(s)
+			handlers := map[string]http.HandlerFunc{
+				"/add-task":    srv.addTaskHandler,
+				"/move-task":   srv.moveTaskHandler,
+				"/delete-task": srv.deleteTaskHandler,
+			}
+
+			form := url.Values{}
+			for k, v := range tc.formValues {
+				form.Set(k, v)
+			}
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			handlers[tc.path](w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected %d, got %d %q", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tc.expectedBodyContains) {
+				t.Errorf("Expected the body to contain %q, got %q", tc.expectedBodyContains, w.Body.String())
+			}
+		})
+	}
+}
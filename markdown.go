@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// markdownHeaders are the columns of each task table
+var markdownHeaders = []string{"Title", "Priority", "Assignee", "Due Date"}
+
+// RenderBoardMarkdown renders the board as a Markdown document with a heading
+// and a padded table per column. Tasks overdue at now get ⚠️ before their due
+// date; empty columns read "(no tasks)".
+func RenderBoardMarkdown(data BoardData, now time.Time) string {
+	var b strings.Builder
+	b.WriteString("# Kanban Board\n")
+	for _, col := range data.Columns {
+		fmt.Fprintf(&b, "\n## %s\n\n", col.DisplayName)
+		if len(col.Tasks) == 0 {
+			b.WriteString("(no tasks)\n")
+			continue
+		}
+
+		rows := [][]string{markdownHeaders}
+		for _, task := range col.Tasks {
+			due := formatDueDate(task.DueDate)
+			if task.IsOverdue(now) {
+				due = "⚠️ " + due
+			}
+			rows = append(rows, []string{
+				escapeMarkdownCell(task.Title),
+				task.PriorityLabel(),
+				escapeMarkdownCell(task.Assignee),
+				due,
+			})
+		}
+		writeMarkdownTable(&b, rows)
+	}
+	return b.String()
+}
+
+// writeMarkdownTable writes rows as a table whose cells are padded to line up,
+// treating the first row as the header
+func writeMarkdownTable(b *strings.Builder, rows [][]string) {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for i, cell := range cells {
+			fmt.Fprintf(b, " %s%s |", cell, strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		b.WriteString("\n")
+	}
+	writeRow(rows[0])
+	separator := make([]string, len(widths))
+	for i, w := range widths {
+		separator[i] = strings.Repeat("-", w)
+	}
+	writeRow(separator)
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+}
+
+// escapeMarkdownCell keeps text from breaking out of a table cell
+func escapeMarkdownCell(s string) string {
+	s = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// exportMarkdownHandler serves the board as a Markdown document, rendered
+// from a snapshot of the board taken in one read
+func (srv *Server) exportMarkdownHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	fmt.Fprint(w, RenderBoardMarkdown(srv.GetBoardDataContext(r.Context()), time.Now()))
+}
+
+// taskDescriptionRawHandler serves GET /api/v1/tasks/{id}/description/raw,
+// a task's Markdown description as plain text for curl and copy-paste. A
+// task without a description returns 204.
+func (srv *Server) taskDescriptionRawHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	task, ok := srv.GetTask(id)
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	if task.Description == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="task-%d.md"`, id))
+	fmt.Fprint(w, task.Description)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRenderBoardMarkdown(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	past := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	future := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	data := BoardData{Columns: []ColumnData{
+		{Status: "todo", DisplayName: "To Do", Tasks: []*Task{
+			{ID: 1, Title: "Write docs", Status: "todo", Priority: PriorityHigh, Assignee: "alice", DueDate: &past},
+			{ID: 2, Title: "A | B", Status: "todo", DueDate: &future},
+		}},
+		{Status: "doing", DisplayName: "Doing"},
+		{Status: "done", DisplayName: "Done", Tasks: []*Task{
+			{ID: 3, Title: "Shipped", Status: "done", DueDate: &past},
+		}},
+	}}
+
+	got := RenderBoardMarkdown(data, now)
+	want := `# Kanban Board
+
+## To Do
+
+| Title      | Priority | Assignee | Due Date      |
+| ---------- | -------- | -------- | ------------- |
+| Write docs | High     | alice    | ⚠️ 2024-03-01 |
+| A \| B     |          |          | 2024-04-01    |
+
+## Doing
+
+(no tasks)
+
+## Done
+
+| Title   | Priority | Assignee | Due Date   |
+| ------- | -------- | -------- | ---------- |
+| Shipped |          |          | 2024-03-01 |
+`
+	if got != want {
+		t.Errorf("Unexpected markdown:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderBoardMarkdownTablesAlign(t *testing.T) {
+	data := BoardData{Columns: []ColumnData{{DisplayName: "To Do", Tasks: []*Task{
+		{Title: "Short"}, {Title: "A much longer title", Assignee: "Zoë"},
+	}}}}
+	var width int
+	for _, line := range strings.Split(RenderBoardMarkdown(data, time.Now()), "\n") {
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+		if n := len([]rune(line)); width == 0 {
+			width = n
+		} else if n != width {
+			t.Errorf("Expected every table row to be %d characters, got %d: %q", width, n, line)
+		}
+	}
+}
+
+func TestExportMarkdownHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Exported", "")
+
+	w := httptest.NewRecorder()
+	NewServer(s).exportMarkdownHandler(w, httptest.NewRequest(http.MethodGet, "/board/export/markdown", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/markdown") {
+		t.Fatalf("Expected 200 markdown, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	for _, want := range []string{"## To Do", "| Exported |", "## Doing\n\n(no tasks)", "## Done"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in export:\n%s", want, body)
+		}
+	}
+}
+
+func TestConcurrentMarkdownExports(t *testing.T) {
+	s := withTestGlobals(t)
+	for i := 0; i < 50; i++ {
+		s.AddTask(fmt.Sprintf("Task %d", i), "")
+	}
+	srv := NewServer(s)
+
+	codes := make(chan int, 20)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			srv.exportMarkdownHandler(w, httptest.NewRequest(http.MethodGet, "/board/export/markdown", nil))
+			codes <- w.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("Expected every concurrent export to succeed, got %d", code)
+		}
+	}
+	if err := s.BoardLock(); err != nil {
+		t.Errorf("Expected exports to leave the board unlocked, got %v", err)
+	}
+}
+
+func TestTaskDescriptionRawHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Documented", "# Steps\n\n1. Run `make`\n")
+	s.AddTask("Bare", "")
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/api/v1/tasks/1/description/raw")
+	if w.Code != http.StatusOK || w.Body.String() != "# Steps\n\n1. Run `make`\n" {
+		t.Errorf("Expected the raw description, got %d: %q", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Unexpected Content-Type %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `inline; filename="task-1.md"` {
+		t.Errorf("Unexpected Content-Disposition %q", cd)
+	}
+
+	if w := get("/api/v1/tasks/2/description/raw"); w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("Expected 204 for an empty description, got %d: %q", w.Code, w.Body.String())
+	}
+	if w := get("/api/v1/tasks/99/description/raw"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown task, got %d", w.Code)
+	}
+}
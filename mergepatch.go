@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ApplyMergePatch applies an RFC 7396 JSON Merge Patch to task. Fields present
+// in the patch are replaced, absent fields are left alone, and null clears the
+// nullable fields (description, assignee, due_date, labels, depends_on,
+// effort, priority).
+// The task is only modified when the whole patch is valid; otherwise an
+// ErrValidation names the first bad field.
+func ApplyMergePatch(task *Task, patch map[string]interface{}) error {
+	updated := task.clone()
+
+	// Sort keys so the first error reported is deterministic
+	keys := make([]string, 0, len(patch))
+	for key := range patch {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := patch[key]
+		var err error
+		switch key {
+		case "title":
+			updated.Title, err = patchString(key, value, false)
+			if err == nil && strings.TrimSpace(updated.Title) == "" {
+				err = fmt.Errorf("title is required")
+			}
+		case "description":
+			updated.Description, err = patchString(key, value, true)
+		case "assignee":
+			updated.Assignee, err = patchString(key, value, true)
+		case "status":
+			updated.Status, err = patchString(key, value, false)
+			if err == nil && !isValidStatus(updated.Status) {
+				err = fmt.Errorf("invalid status %q", updated.Status)
+			}
+		case "effort":
+			updated.Effort, err = patchInt(key, value)
+			if err == nil && updated.Effort < 0 {
+				err = fmt.Errorf("effort must not be negative")
+			}
+		case "priority":
+			updated.Priority, err = patchInt(key, value)
+			if err == nil && (updated.Priority < PriorityNone || updated.Priority > PriorityHigh) {
+				err = fmt.Errorf("invalid priority %d", updated.Priority)
+			}
+		case "due_date":
+			updated.DueDate = nil
+			if value != nil {
+				raw, ok := value.(string)
+				if !ok {
+					err = fmt.Errorf("due_date must be a string or null")
+					break
+				}
+				due, parseErr := parseDueDate(raw)
+				if parseErr != nil {
+					err = parseErr
+					break
+				}
+				updated.DueDate = &due
+			}
+		case "labels":
+			updated.Labels, err = patchLabels(value)
+		case "depends_on":
+			updated.DependsOn, err = patchDependsOn(value, task.ID)
+		case "id", "human_id", "created_at", "updated_at":
+			err = fmt.Errorf("%s is read-only", key)
+		default:
+			err = fmt.Errorf("unknown field %q", key)
+		}
+		if err != nil {
+			return &ErrValidation{Fields: map[string]string{key: err.Error()}}
+		}
+	}
+
+	*task = *updated
+	return nil
+}
+
+// PatchTask applies a merge patch to a task and returns it. The patch is
+// checked in full, including the dependency cycle check and, for a status
+// change, every check MoveTask makes, before anything changes, so a rejected
+// patch leaves the task as it was. It returns ErrTaskNotFound, an
+// ErrValidation for a bad field, ErrUnknownDependency for a depends_on ID
+// that isn't a task or the error the first failed check gives.
+func (s *TaskStore) PatchTask(ctx context.Context, id int, patch map[string]interface{}) (*Task, error) {
+	span := s.startSpan(ctx, "PatchTask", attribute.Int("task.id", id))
+	defer span.End()
+
+	s.mu.Lock()
+
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	task, ok := s.tasks[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrTaskNotFound
+	}
+	candidate := task.clone()
+	if err := ApplyMergePatch(candidate, patch); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if _, ok := patch["depends_on"]; ok {
+		if err := s.checkDependencies(id, candidate.DependsOn); err != nil {
+			s.mu.Unlock()
+			return nil, err
+		}
+	}
+
+	// A move runs the transition hooks, and the patch's own fields win over
+	// any change they make
+	oldStatus, newStatus := task.Status, candidate.Status
+	updated := task.clone()
+	if newStatus != oldStatus {
+		hooked, err := s.checkMove(task, newStatus, "")
+		if err != nil {
+			s.mu.Unlock()
+			return nil, err
+		}
+		updated = hooked
+	}
+	ApplyMergePatch(updated, patch)
+	updated.Status = oldStatus
+	if len(updated.DependsOn) == 0 {
+		updated.DependsOn = nil
+	}
+
+	before := task.clone()
+	changes := taskFieldChanges(before, updated)
+	if len(changes) == 0 && newStatus == oldStatus {
+		s.mu.Unlock()
+		return task, nil
+	}
+	*task = *updated
+	if formatIDs(before.DependsOn) != formatIDs(task.DependsOn) {
+		s.deps.set(id, task.DependsOn)
+	}
+	s.applyMove(task, newStatus)
+	s.persist(oldStatus, newStatus)
+	patched := task.clone()
+	s.mu.Unlock()
+
+	if newStatus != oldStatus {
+		s.publish(Event{Type: EventTaskMoved, Task: patched, FromStatus: oldStatus, ToStatus: newStatus})
+	}
+	if len(changes) > 0 {
+		s.publish(Event{Type: EventTaskUpdated, Task: patched, Changes: changes})
+	}
+	if patched.Assignee != "" && patched.Assignee != before.Assignee {
+		s.publish(Event{Type: EventTaskAssigned, Task: patched, Changes: []FieldChange{
+			{Field: "assignee", Old: before.Assignee, New: patched.Assignee},
+		}})
+	}
+	return task, nil
+}
+
+// patchString reads a string field, mapping null to "" when nullable
+func patchString(key string, value interface{}, nullable bool) (string, error) {
+	if value == nil && nullable {
+		return "", nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	return s, nil
+}
+
+// patchInt reads an integer field, mapping null to 0
+func patchInt(key string, value interface{}) (int, error) {
+	if value == nil {
+		return 0, nil
+	}
+	n, ok := value.(float64)
+	if !ok || n != math.Trunc(n) {
+		return 0, fmt.Errorf("%s must be an integer", key)
+	}
+	return int(n), nil
+}
+
+// patchDependsOn reads the depends_on array of task IDs. A task can't depend
+// on itself.
+func patchDependsOn(value interface{}, id int) ([]int, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("depends_on must be an array of task IDs")
+	}
+	ids := []int{}
+	for _, item := range items {
+		n, ok := item.(float64)
+		if !ok || n != math.Trunc(n) || n < 1 {
+			return nil, fmt.Errorf("depends_on must be an array of task IDs")
+		}
+		if int(n) == id {
+			return nil, fmt.Errorf("a task can't depend on itself")
+		}
+		if !containsInt(ids, int(n)) {
+			ids = append(ids, int(n))
+		}
+	}
+	return ids, nil
+}
+
+// patchLabels reads the labels array. A patch replaces the whole list.
+func patchLabels(value interface{}) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("labels must be an array of strings")
+	}
+	labels := []string{}
+	for _, item := range items {
+		label, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("labels must be an array of strings")
+		}
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels, nil
+}
+
+// taskAPIHandler dispatches /api/v1/tasks/{id}/... requests
+func (srv *Server) taskAPIHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/tasks/"), "/"), "/")
+	id, err := ParseTaskID(parts[0], taskIDPrefix)
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		srv.taskMergePatchHandler(w, r, id)
+	case parts[1] == "move" && len(parts) == 2:
+		srv.taskMoveAPIHandler(w, r, id)
+	case parts[1] == "attachments":
+		srv.taskAttachmentsHandler(w, r, id, parts[2:])
+	case parts[1] == "similar" && len(parts) == 2:
+		srv.taskSimilarHandler(w, r, id)
+	case parts[1] == "pin" && len(parts) == 2:
+		srv.taskPinHandler(w, r, id)
+	case parts[1] == "related" && len(parts) == 2:
+		srv.taskRelatedHandler(w, r, id)
+	case parts[1] == "watch" && len(parts) == 2:
+		srv.taskWatchHandler(w, r, id)
+	case parts[1] == "move-to-top" && len(parts) == 2:
+		srv.taskMoveToEdgeHandler(w, r, id, true)
+	case parts[1] == "move-to-bottom" && len(parts) == 2:
+		srv.taskMoveToEdgeHandler(w, r, id, false)
+	case parts[1] == "duplicate-to-sprint" && len(parts) == 2:
+		srv.taskDuplicateToSprintHandler(w, r, id)
+	case parts[1] == "convert-to-subtask" && len(parts) == 2:
+		srv.taskConvertToSubtaskHandler(w, r, id)
+	case parts[1] == "estimated-completion" && len(parts) == 2:
+		srv.taskEstimateHandler(w, r, id)
+	case parts[1] == "description" && len(parts) == 3 && parts[2] == "raw":
+		srv.taskDescriptionRawHandler(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// taskMergePatchHandler serves PUT /api/v1/tasks/{id}, which takes an
+// application/merge-patch+json body and returns the updated task
+func (srv *Server) taskMergePatchHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/merge-patch+json") {
+		http.Error(w, "Content-Type must be application/merge-patch+json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var patch map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := patch["depends_on"]; ok && !featureEnabled(r.Context(), "dependencies") {
+		http.NotFound(w, r)
+		return
+	}
+
+	task, err := srv.PatchTask(r.Context(), id, patch)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodePatch(t *testing.T, raw string) map[string]interface{} {
+	var patch map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &patch); err != nil {
+		t.Fatalf("Invalid patch %s: %v", raw, err)
+	}
+	return patch
+}
+
+func TestApplyMergePatchLeavesAbsentFields(t *testing.T) {
+	due := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	task := &Task{ID: 1, Title: "Old", Description: "Keep me", Status: "todo", Assignee: "alice",
+		Effort: 3, Priority: PriorityLow, DueDate: &due, Labels: []string{"bug"}}
+
+	if err := ApplyMergePatch(task, decodePatch(t, `{"title":"New","priority":3}`)); err != nil {
+		t.Fatalf("ApplyMergePatch failed: %v", err)
+	}
+	if task.Title != "New" || task.Priority != PriorityHigh {
+		t.Errorf("Expected patched title and priority, got %+v", task)
+	}
+	if task.Description != "Keep me" || task.Assignee != "alice" || task.Effort != 3 ||
+		!task.DueDate.Equal(due) || len(task.Labels) != 1 {
+		t.Errorf("Unmentioned fields must be left intact, got %+v", task)
+	}
+}
+
+func TestApplyMergePatchNullClears(t *testing.T) {
+	due := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	task := &Task{ID: 1, Title: "T", Status: "todo", Assignee: "alice", DueDate: &due, Labels: []string{"bug"}}
+
+	if err := ApplyMergePatch(task, decodePatch(t, `{"assignee":null,"due_date":null,"labels":null}`)); err != nil {
+		t.Fatalf("ApplyMergePatch failed: %v", err)
+	}
+	if task.Assignee != "" || task.DueDate != nil || task.Labels != nil {
+		t.Errorf("Expected null to clear the fields, got %+v", task)
+	}
+	if task.Title != "T" {
+		t.Errorf("Expected title to be kept, got %q", task.Title)
+	}
+}
+
+func TestApplyMergePatchValidation(t *testing.T) {
+	for _, raw := range []string{
+		`{"title":null}`,
+		`{"title":"  "}`,
+		`{"status":"archived"}`,
+		`{"priority":7}`,
+		`{"effort":1.5}`,
+		`{"effort":-1}`,
+		`{"due_date":"tomorrow"}`,
+		`{"labels":"bug"}`,
+		`{"depends_on":[1]}`,
+		`{"depends_on":[0]}`,
+		`{"depends_on":"2"}`,
+		`{"id":5}`,
+		`{"colour":"red"}`,
+	} {
+		task := &Task{ID: 1, Title: "T", Status: "todo", Effort: 2}
+		if err := ApplyMergePatch(task, decodePatch(t, `{"effort":4,`+raw[1:])); err == nil {
+			t.Errorf("Expected %s to be rejected", raw)
+		}
+		if task.Effort != 2 {
+			t.Errorf("A rejected patch must not modify the task, got effort %d for %s", task.Effort, raw)
+		}
+	}
+}
+
+func putMergePatch(path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	w := httptest.NewRecorder()
+	NewServer(store).taskAPIHandler(w, req)
+	return w
+}
+
+func TestTaskAPIHandlerMergePatch(t *testing.T) {
+	s := withTestGlobals(t)
+	task, _ := s.CreateTask(TaskSpec{Title: "Patch me", Description: "Stays", Assignee: "bob", Effort: 5})
+
+	w := putMergePatch("/api/v1/tasks/1", `{"status":"doing","assignee":null,"due_date":"2024-06-01"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var got Task
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if got.Status != "doing" || got.Assignee != "" || got.DueDate == nil || got.Description != "Stays" || got.Effort != 5 {
+		t.Errorf("Unexpected patched task %+v", got)
+	}
+	if stored, _ := s.GetTask(task.ID); stored.Status != "doing" || stored.Assignee != "" {
+		t.Errorf("Expected patch to be saved, got %+v", stored)
+	}
+
+	if w := putMergePatch("/api/v1/tasks/1", `{"priority":9}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid priority, got %d", w.Code)
+	}
+	if w := putMergePatch("/api/v1/tasks/99", `{"title":"x"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing task, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/1", strings.NewReader(`{"title":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	NewServer(s).taskAPIHandler(w, req)
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected 415 without merge-patch content type, got %d", w.Code)
+	}
+}
+
+func TestTaskAPIHandlerMergePatchDependsOn(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("First", "")
+	s.AddTask("Second", "")
+
+	withTestFeatures(t, DefaultFeatureFlags())
+	if w := putMergePatch("/api/v1/tasks/2", `{"depends_on":[1]}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 while dependencies are disabled, got %d", w.Code)
+	}
+
+	withTestFeatures(t, FeatureFlags{Dependencies: true})
+	if w := putMergePatch("/api/v1/tasks/2", `{"depends_on":[1,1]}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if task, _ := s.GetTask(2); !equalIDs(task.DependsOn, []int{1}) {
+		t.Errorf("Expected task 2 to depend on task 1 once, got %v", task.DependsOn)
+	}
+	if w := putMergePatch("/api/v1/tasks/2", `{"depends_on":null}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if task, _ := s.GetTask(2); task.DependsOn != nil {
+		t.Errorf("Expected null to clear dependencies, got %v", task.DependsOn)
+	}
+}
+
+func TestTaskAPIHandlerMergePatchRejectedLeavesTaskUnchanged(t *testing.T) {
+	s := withTestGlobals(t)
+	withTestFeatures(t, FeatureFlags{Dependencies: true})
+	s.wipLimits = map[string]int{"doing": 1}
+	s.AddTask("Busy", "")
+	s.MoveTask(1, "doing")
+	s.AddTask("Blocked", "")
+	before, _ := s.GetTask(2)
+	before = before.clone()
+
+	w := putMergePatch("/api/v1/tasks/2", `{"depends_on":[1],"status":"doing","title":"Renamed"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected 409 for a full column, got %d: %s", w.Code, w.Body.String())
+	}
+	if after, _ := s.GetTask(2); !reflect.DeepEqual(after, before) {
+		t.Errorf("Expected the rejected patch to change nothing, got %+v", after)
+	}
+
+	s.SetDependencies(1, []int{2})
+	if w := putMergePatch("/api/v1/tasks/2", `{"depends_on":[1],"title":"Renamed"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422 for a dependency cycle, got %d: %s", w.Code, w.Body.String())
+	}
+	if after, _ := s.GetTask(2); after.Title != "Blocked" || after.DependsOn != nil {
+		t.Errorf("Expected the cyclic patch to change nothing, got %+v", after)
+	}
+}
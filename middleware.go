@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// accessLogger receives one entry per request from loggingMiddleware
+var accessLogger = slog.Default()
+
+// accessLogLevel is the lowest level of request entry logged, from
+// server.log_level
+var accessLogLevel = slog.LevelInfo
+
+// responseRecorder captures the status code written by a handler
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rr *responseRecorder) WriteHeader(code int) {
+	rr.status = code
+	rr.ResponseWriter.WriteHeader(code)
+}
+
+// Push forwards server pushes, so handlers behind the middleware can still
+// push over HTTP/2
+func (rr *responseRecorder) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := rr.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	return rr.ResponseWriter.Write(b)
+}
+
+// Flush forwards flushes, so SSE handlers behind the middleware can stream
+func (rr *responseRecorder) Flush() {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	if flusher, ok := rr.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack forwards connection takeovers, so WebSocket upgrades work behind
+// the middleware
+func (rr *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rr.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	rr.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
+// withMiddleware wraps the server's routes in access logging, tracing, rate
+// limiting and feature flags, outermost first
+func withMiddleware(next http.Handler, requestsPerMinute int) http.Handler {
+	return loggingMiddleware(otelMiddleware(NewRateLimiter(requestsPerMinute).Middleware(FlagMiddleware(next))))
+}
+
+// loggingMiddleware logs method, path, status, duration, and request ID for every request
+func loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case rec.status >= 500:
+			level = slog.LevelError
+		case rec.status >= 400:
+			level = slog.LevelWarn
+		}
+		if level < accessLogLevel {
+			return
+		}
+		accessLogger.Log(r.Context(), level, "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"request_id", requestID,
+		)
+	})
+}
+
+// newRequestID returns a random 16-character hex identifier
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// RateLimiter caps requests per client IP in fixed one-minute windows
+type RateLimiter struct {
+	mu          sync.Mutex
+	limit       int
+	windowStart time.Time
+	counts      map[string]int
+	now         func() time.Time
+}
+
+// NewRateLimiter allows perMinute requests per client IP. It returns nil, which
+// lets every request through, when perMinute is zero.
+func NewRateLimiter(perMinute int) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &RateLimiter{limit: perMinute, counts: make(map[string]int), now: time.Now}
+}
+
+// allow counts a request from ip and reports whether it is within the limit,
+// along with the time until the window resets
+func (rl *RateLimiter) allow(ip string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	if now.Sub(rl.windowStart) >= time.Minute {
+		rl.windowStart = now
+		rl.counts = make(map[string]int)
+	}
+	rl.counts[ip]++
+	return rl.counts[ip] <= rl.limit, rl.windowStart.Add(time.Minute).Sub(now)
+}
+
+// Middleware rejects requests over the limit with 429 Too Many Requests
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	if rl == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		if ok, retry := rl.allow(ip); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// maxDecompressedBodyBytes caps a gzip request body once decompressed, so a
+// small compressed upload can't expand without bound
+const maxDecompressedBodyBytes = 10 << 20
+
+var errDecompressedTooLarge = errors.New("decompressed request body too large")
+
+// gzipBody reads a decompressed request body. It reads up to one byte past
+// maxDecompressedBodyBytes, so an oversized body fails instead of being
+// silently truncated.
+type gzipBody struct {
+	gz         *gzip.Reader
+	limited    io.Reader
+	read       int64
+	compressed io.ReadCloser
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	n, err := b.limited.Read(p)
+	b.read += int64(n)
+	if b.read > maxDecompressedBodyBytes {
+		return n, errDecompressedTooLarge
+	}
+	return n, err
+}
+
+func (b *gzipBody) Close() error {
+	b.gz.Close()
+	return b.compressed.Close()
+}
+
+// gzipRequestMiddleware decompresses request bodies sent with
+// Content-Encoding: gzip. Other requests pass through unchanged.
+func gzipRequestMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid gzip body", http.StatusBadRequest)
+			return
+		}
+		body := &gzipBody{
+			gz:         gz,
+			limited:    io.LimitReader(gz, maxDecompressedBodyBytes+1),
+			compressed: r.Body,
+		}
+		defer body.Close()
+
+		r.Body = body
+		r.Header.Del("Content-Encoding")
+		r.Header.Del("Content-Length")
+		r.ContentLength = -1
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLoggingMiddlewareWarnsOn404(t *testing.T) {
+	var buf bytes.Buffer
+	origLogger := accessLogger
+	defer func() { accessLogger = origLogger }()
+	accessLogger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := loggingMiddleware(http.NotFoundHandler())
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON log entry, got %q: %v", buf.String(), err)
+	}
+	if entry["level"] != "WARN" {
+		t.Errorf("Expected WARN level, got %v", entry["level"])
+	}
+	if entry["path"] != "/missing" {
+		t.Errorf("Expected path /missing, got %v", entry["path"])
+	}
+	if entry["status"] != float64(http.StatusNotFound) {
+		t.Errorf("Expected status 404, got %v", entry["status"])
+	}
+	if entry["method"] != http.MethodGet || entry["request_id"] == "" {
+		t.Errorf("Expected method and request_id fields, got %v", entry)
+	}
+	if _, ok := entry["duration_ms"]; !ok {
+		t.Errorf("Expected duration_ms field")
+	}
+}
+
+func TestLoggingMiddlewareLevels(t *testing.T) {
+	origLogger := accessLogger
+	defer func() { accessLogger = origLogger }()
+
+	cases := map[int]string{
+		http.StatusOK:                  "INFO",
+		http.StatusFound:               "INFO",
+		http.StatusBadRequest:          "WARN",
+		http.StatusInternalServerError: "ERROR",
+	}
+	for status, want := range cases {
+		var buf bytes.Buffer
+		accessLogger = slog.New(slog.NewJSONHandler(&buf, nil))
+		code := status
+		handler := loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(code)
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Request-ID", "abc123")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry map[string]interface{}
+		json.Unmarshal(buf.Bytes(), &entry)
+		if entry["level"] != want {
+			t.Errorf("Status %d: expected level %s, got %v", status, want, entry["level"])
+		}
+		if entry["request_id"] != "abc123" {
+			t.Errorf("Expected incoming request ID to be reused, got %v", entry["request_id"])
+		}
+	}
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(2)
+	rl.now = func() time.Time { return now }
+	handler := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := request("10.0.0.1:1000"); rec.Code != http.StatusOK {
+			t.Errorf("Request %d: expected 200, got %d", i+1, rec.Code)
+		}
+	}
+	rec := request("10.0.0.1:2000")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" {
+		t.Errorf("Expected 429 with Retry-After 60, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := request("10.0.0.2:1000"); rec.Code != http.StatusOK {
+		t.Errorf("Other clients must have their own limit, got %d", rec.Code)
+	}
+
+	now = now.Add(time.Minute)
+	if rec := request("10.0.0.1:1000"); rec.Code != http.StatusOK {
+		t.Errorf("Expected limit to reset after a minute, got %d", rec.Code)
+	}
+
+	handler = NewRateLimiter(0).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for i := 0; i < 5; i++ {
+		if rec := request("10.0.0.1:1000"); rec.Code != http.StatusOK {
+			t.Errorf("Expected a zero limit to allow every request, got %d", rec.Code)
+		}
+	}
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestGzipRequestMiddlewareImport(t *testing.T) {
+	text := "First\n! Urgent\n[doing] Second\n"
+	importTasks := func(body []byte, gzipped bool) []*Task {
+		s := withTestGlobals(t)
+		handler := gzipRequestMiddleware(http.HandlerFunc(NewServer(s).importTextHandler))
+		req := httptest.NewRequest(http.MethodPost, "/import/text", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "text/plain")
+		if gzipped {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		return s.GetAllTasks()
+	}
+
+	plain := importTasks([]byte(text), false)
+	compressed := importTasks(gzipBytes(t, []byte(text)), true)
+	if len(plain) != 3 || len(compressed) != len(plain) {
+		t.Fatalf("Expected 3 tasks either way, got %d and %d", len(plain), len(compressed))
+	}
+	for i := range plain {
+		if plain[i].Title != compressed[i].Title || plain[i].Status != compressed[i].Status || plain[i].Priority != compressed[i].Priority {
+			t.Errorf("Task %d differs: %+v vs %+v", i, plain[i], compressed[i])
+		}
+	}
+}
+
+func TestGzipRequestMiddlewareLimits(t *testing.T) {
+	var readErr error
+	var encoding string
+	handler := gzipRequestMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	bomb := gzipBytes(t, make([]byte, maxDecompressedBodyBytes+1))
+	req := httptest.NewRequest(http.MethodPost, "/import/text", bytes.NewReader(bomb))
+	req.Header.Set("Content-Encoding", "gzip")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !errors.Is(readErr, errDecompressedTooLarge) {
+		t.Errorf("Expected the oversized body to fail, got %v", readErr)
+	}
+	if encoding != "" {
+		t.Errorf("Expected Content-Encoding to be removed after decompression, got %q", encoding)
+	}
+
+	exact := gzipBytes(t, make([]byte, maxDecompressedBodyBytes))
+	req = httptest.NewRequest(http.MethodPost, "/import/text", bytes.NewReader(exact))
+	req.Header.Set("Content-Encoding", "gzip")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if readErr != nil {
+		t.Errorf("Expected a body at the limit to be read, got %v", readErr)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/import/text", bytes.NewReader([]byte("not gzip")))
+	req.Header.Set("Content-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a corrupt gzip body, got %d", rec.Code)
+	}
+}
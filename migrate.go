@@ -0,0 +1,151 @@
+package main
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var sqliteMigrationsFS embed.FS
+
+// Postgres has its own scripts, as the two dialects' column types differ
+//
+//go:embed migrations/postgres/*.sql
+var postgresMigrationsFS embed.FS
+
+// Migration is one numbered SQL script. Files are named NNNN_description.sql.
+type Migration struct {
+	ID   int
+	Name string
+	SQL  string
+}
+
+// MigrationRunner applies schema migrations to a SQLite or Postgres
+// database, recording each one in the migrations table
+type MigrationRunner struct {
+	db   *sql.DB
+	fsys fs.FS
+	dir  string
+	now  func() time.Time
+
+	createTableSQL string // creates the migrations table if missing
+	recordSQL      string // inserts (id, applied_at) into it
+}
+
+// NewMigrationRunner returns a runner for the embedded SQLite migrations
+func NewMigrationRunner(db *sql.DB) *MigrationRunner {
+	return &MigrationRunner{
+		db: db, fsys: sqliteMigrationsFS, dir: "migrations", now: time.Now,
+		createTableSQL: `CREATE TABLE IF NOT EXISTS migrations (id INTEGER PRIMARY KEY, applied_at DATETIME NOT NULL)`,
+		recordSQL:      `INSERT INTO migrations (id, applied_at) VALUES (?, ?)`,
+	}
+}
+
+// NewPostgresMigrationRunner returns a runner for the embedded Postgres
+// migrations
+func NewPostgresMigrationRunner(db *sql.DB) *MigrationRunner {
+	return &MigrationRunner{
+		db: db, fsys: postgresMigrationsFS, dir: "migrations/postgres", now: time.Now,
+		createTableSQL: `CREATE TABLE IF NOT EXISTS migrations (id INTEGER PRIMARY KEY, applied_at TIMESTAMPTZ NOT NULL)`,
+		recordSQL:      `INSERT INTO migrations (id, applied_at) VALUES ($1, $2)`,
+	}
+}
+
+// Migrations returns every migration script ordered by ID
+func (m *MigrationRunner) Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(m.fsys, m.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		prefix, _, _ := strings.Cut(name, "_")
+		id, err := strconv.Atoi(prefix)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("migration %s: name must start with a positive number", name)
+		}
+		if other, ok := seen[id]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share number %d", other, name, id)
+		}
+		seen[id] = name
+
+		data, err := fs.ReadFile(m.fsys, path.Join(m.dir, name))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{ID: id, Name: name, SQL: string(data)})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].ID < migrations[j].ID })
+	return migrations, nil
+}
+
+// Applied returns the IDs of migrations already applied, in order
+func (m *MigrationRunner) Applied() ([]int, error) {
+	if _, err := m.db.Exec(m.createTableSQL); err != nil {
+		return nil, err
+	}
+	rows, err := m.db.Query(`SELECT id FROM migrations ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Run applies every unapplied migration in order within a single
+// transaction. If any migration fails, none of them are applied.
+func (m *MigrationRunner) Run() error {
+	migrations, err := m.Migrations()
+	if err != nil {
+		return err
+	}
+	appliedIDs, err := m.Applied()
+	if err != nil {
+		return err
+	}
+	applied := make(map[int]bool, len(appliedIDs))
+	for _, id := range appliedIDs {
+		applied[id] = true
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, migration := range migrations {
+		if applied[migration.ID] {
+			continue
+		}
+		if _, err := tx.Exec(migration.SQL); err != nil {
+			return fmt.Errorf("migration %s: %w", migration.Name, err)
+		}
+		if _, err := tx.Exec(m.recordSQL, migration.ID, m.now().UTC()); err != nil {
+			return fmt.Errorf("migration %s: %w", migration.Name, err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func openTestSQLiteDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "bare.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func sqliteColumnNames(t *testing.T, db *sql.DB, table string) map[string]bool {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		rows.Scan(&name)
+		names[name] = true
+	}
+	return names
+}
+
+func TestMigrationRunnerAppliesAllOnce(t *testing.T) {
+	db := openTestSQLiteDB(t)
+	runner := NewMigrationRunner(db)
+
+	migrations, err := runner.Migrations()
+	if err != nil || len(migrations) < 3 || migrations[0].ID != 1 {
+		t.Fatalf("Expected numbered embedded migrations, got %v (%v)", migrations, err)
+	}
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	columns := sqliteColumnNames(t, db, "tasks")
+	for _, want := range []string{"id", "title", "status", "labels", "checklist", "position", "created_at"} {
+		if !columns[want] {
+			t.Errorf("Expected tasks.%s after migrating, got %v", want, columns)
+		}
+	}
+	var indexes int
+	db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'tasks_status'`).Scan(&indexes)
+	if indexes != 1 {
+		t.Errorf("Expected every statement of a migration to run, tasks_status index missing")
+	}
+	applied, _ := runner.Applied()
+	if len(applied) != len(migrations) {
+		t.Errorf("Expected %d applied migrations, got %v", len(migrations), applied)
+	}
+
+	// Running again is a no-op
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+	if again, _ := runner.Applied(); len(again) != len(applied) {
+		t.Errorf("Expected second run to apply nothing, got %v", again)
+	}
+}
+
+func TestMigrationRunnerRollsBackOnFailure(t *testing.T) {
+	db := openTestSQLiteDB(t)
+	runner := NewMigrationRunner(db)
+	runner.fsys = fstest.MapFS{
+		"migrations/0001_create.sql": {Data: []byte(`CREATE TABLE notes (id INTEGER PRIMARY KEY);`)},
+		"migrations/0002_broken.sql": {Data: []byte(`ALTER TABLE missing ADD COLUMN x TEXT;`)},
+	}
+
+	if err := runner.Run(); err == nil {
+		t.Fatalf("Expected the broken migration to fail")
+	}
+	if columns := sqliteColumnNames(t, db, "notes"); len(columns) != 0 {
+		t.Errorf("Expected the earlier migration to be rolled back, found notes table")
+	}
+	if applied, _ := runner.Applied(); len(applied) != 0 {
+		t.Errorf("Expected no recorded migrations, got %v", applied)
+	}
+}
+
+func TestMigrationRunnerRejectsBadNames(t *testing.T) {
+	runner := NewMigrationRunner(openTestSQLiteDB(t))
+	runner.fsys = fstest.MapFS{"migrations/initial.sql": {Data: []byte(`SELECT 1;`)}}
+	if _, err := runner.Migrations(); err == nil {
+		t.Errorf("Expected an error for a migration without a number")
+	}
+	runner.fsys = fstest.MapFS{
+		"migrations/0001_a.sql": {Data: []byte(`SELECT 1;`)},
+		"migrations/0001_b.sql": {Data: []byte(`SELECT 1;`)},
+	}
+	if _, err := runner.Migrations(); err == nil {
+		t.Errorf("Expected an error for duplicate migration numbers")
+	}
+}
@@ -0,0 +1,14 @@
+CREATE TABLE tasks (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	title       TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	status      TEXT NOT NULL,
+	assignee    TEXT NOT NULL DEFAULT '',
+	effort      INTEGER NOT NULL DEFAULT 0,
+	priority    INTEGER NOT NULL DEFAULT 0,
+	due_date    TEXT,
+	labels      TEXT NOT NULL DEFAULT '[]',
+	created_at  TEXT NOT NULL,
+	updated_at  TEXT NOT NULL
+);
+CREATE INDEX tasks_status ON tasks (status);
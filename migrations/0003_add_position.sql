@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
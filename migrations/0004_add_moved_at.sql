@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN moved_at TEXT;
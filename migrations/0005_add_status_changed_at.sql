@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN status_changed_at TEXT;
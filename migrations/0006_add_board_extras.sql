@@ -0,0 +1,4 @@
+CREATE TABLE board_extras (
+	id   INTEGER PRIMARY KEY CHECK (id = 1),
+	data TEXT NOT NULL
+);
@@ -0,0 +1,18 @@
+CREATE TABLE tasks (
+	id                BIGSERIAL PRIMARY KEY,
+	title             TEXT NOT NULL,
+	description       TEXT NOT NULL DEFAULT '',
+	status            TEXT NOT NULL,
+	assignee          TEXT NOT NULL DEFAULT '',
+	effort            INTEGER NOT NULL DEFAULT 0,
+	priority          INTEGER NOT NULL DEFAULT 0,
+	due_date          TIMESTAMPTZ,
+	labels            JSONB NOT NULL DEFAULT '[]',
+	checklist         JSONB NOT NULL DEFAULT 'null',
+	position          INTEGER NOT NULL DEFAULT 0,
+	created_at        TIMESTAMPTZ NOT NULL,
+	updated_at        TIMESTAMPTZ NOT NULL,
+	moved_at          TIMESTAMPTZ,
+	status_changed_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX tasks_status ON tasks (status);
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// mobilePreviewSize is how many tasks /api/v1/mobile/board shows per
+	// column
+	mobilePreviewSize = 5
+	// mobilePageSize is how many tasks a page of /api/v1/mobile/column has
+	mobilePageSize = 20
+)
+
+// MobileTask is the part of a task a phone needs to draw its card. It leaves
+// out descriptions, checklists, history and timestamps; clients fetch the
+// full task when it is opened.
+type MobileTask struct {
+	ID       int        `json:"id"`
+	Title    string     `json:"title"`
+	Assignee string     `json:"assignee,omitempty"`
+	Priority int        `json:"priority,omitempty"`
+	DueDate  *time.Time `json:"due_date,omitempty"`
+	Labels   []string   `json:"labels,omitempty"`
+	Pinned   bool       `json:"pinned,omitempty"`
+}
+
+// MobileColumn is a column of the mobile board: its first tasks and how many
+// there are in all
+type MobileColumn struct {
+	Status  string       `json:"status"`
+	Name    string       `json:"name"`
+	Count   int          `json:"count"`
+	Tasks   []MobileTask `json:"tasks,omitempty"`
+	HasMore bool         `json:"has_more,omitempty"`
+}
+
+// newMobileTask trims a task to a MobileTask
+func newMobileTask(task *Task) MobileTask {
+	return MobileTask{
+		ID:       task.ID,
+		Title:    task.Title,
+		Assignee: task.Assignee,
+		Priority: task.Priority,
+		DueDate:  task.DueDate,
+		Labels:   task.Labels,
+		Pinned:   task.Pinned,
+	}
+}
+
+// mobileBoard summarizes each column of data with its first
+// mobilePreviewSize tasks in board order
+func mobileBoard(data BoardData) []MobileColumn {
+	columns := make([]MobileColumn, 0, len(data.Columns))
+	for _, col := range data.Columns {
+		column := MobileColumn{Status: col.Status, Name: col.DisplayName, Count: len(col.Tasks)}
+		for i, task := range col.Tasks {
+			if i == mobilePreviewSize {
+				column.HasMore = true
+				break
+			}
+			column.Tasks = append(column.Tasks, newMobileTask(task))
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// mobileBoardHandler serves GET /api/v1/mobile/board, a compact JSON summary
+// of the board for clients on slow connections
+func (srv *Server) mobileBoardHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"columns": mobileBoard(srv.GetBoardData())})
+}
+
+// mobileColumnHandler serves GET /api/v1/mobile/column/{status}?page=, the
+// column's tasks in full, mobilePageSize at a time from page 1, as
+// {"status", "page", "tasks", "has_more"}
+func (srv *Server) mobileColumnHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	status := strings.TrimPrefix(r.URL.Path, "/api/v1/mobile/column/")
+	if !isValidStatus(status) {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+	page := 1
+	if raw := r.URL.Query().Get("page"); raw != "" {
+		var err error
+		page, err = strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			http.Error(w, "page must be a positive number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	tasks := srv.GetColumnData(status).Tasks
+	start := min((page-1)*mobilePageSize, len(tasks))
+	end := min(start+mobilePageSize, len(tasks))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   status,
+		"page":     page,
+		"tasks":    append([]*Task{}, tasks[start:end]...),
+		"has_more": end < len(tasks),
+	})
+}
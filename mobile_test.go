@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMobileBoardHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	for i := 1; i <= 7; i++ {
+		s.CreateTask(TaskSpec{Title: fmt.Sprintf("Task %d", i)})
+	}
+	s.CreateTask(TaskSpec{Title: "Started", Status: "doing", Assignee: "alice", Priority: PriorityHigh})
+
+	w := httptest.NewRecorder()
+	NewServer(s).mobileBoardHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/mobile/board", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Columns []MobileColumn `json:"columns"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(resp.Columns) != 3 {
+		t.Fatalf("Expected 3 columns, got %+v", resp.Columns)
+	}
+	todo, doing, done := resp.Columns[0], resp.Columns[1], resp.Columns[2]
+	if todo.Status != "todo" || todo.Name == "" || todo.Count != 7 || len(todo.Tasks) != mobilePreviewSize || !todo.HasMore {
+		t.Errorf("Expected the first 5 of 7 todo tasks with has_more, got %+v", todo)
+	}
+	if doing.Count != 1 || doing.HasMore || doing.Tasks[0].Assignee != "alice" || doing.Tasks[0].Priority != PriorityHigh {
+		t.Errorf("Expected the one doing task, got %+v", doing)
+	}
+	if done.Count != 0 || done.Tasks != nil {
+		t.Errorf("Expected an empty done column, got %+v", done)
+	}
+}
+
+func TestMobileColumnHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	for i := 1; i <= 25; i++ {
+		s.CreateTask(TaskSpec{Title: fmt.Sprintf("Task %d", i), Description: "Details"})
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		NewServer(s).mobileColumnHandler(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	var resp struct {
+		Page    int     `json:"page"`
+		Tasks   []*Task `json:"tasks"`
+		HasMore bool    `json:"has_more"`
+	}
+	w := get("/api/v1/mobile/column/todo?page=2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Page != 2 || len(resp.Tasks) != 5 || resp.HasMore || resp.Tasks[0].ID != 21 || resp.Tasks[0].Description != "Details" {
+		t.Errorf("Expected the last 5 full tasks on page 2, got %+v", resp)
+	}
+
+	resp.Tasks = nil
+	json.Unmarshal(get("/api/v1/mobile/column/todo").Body.Bytes(), &resp)
+	if resp.Page != 1 || len(resp.Tasks) != mobilePageSize || !resp.HasMore {
+		t.Errorf("Expected a full first page with more to come, got %+v", resp)
+	}
+	resp.Tasks = nil
+	json.Unmarshal(get("/api/v1/mobile/column/todo?page=9").Body.Bytes(), &resp)
+	if len(resp.Tasks) != 0 || resp.HasMore {
+		t.Errorf("Expected an empty page past the end, got %+v", resp)
+	}
+
+	for _, path := range []string{"/api/v1/mobile/column/nope", "/api/v1/mobile/column/todo?page=0", "/api/v1/mobile/column/todo?page=x"} {
+		if w := get(path); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestMobileBoardIsCompact(t *testing.T) {
+	s := withTestGlobals(t)
+	statuses := []string{"todo", "doing", "done"}
+	due := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 50; i++ {
+		task, _ := s.CreateTask(TaskSpec{
+			Title:       fmt.Sprintf("Task %d", i),
+			Description: "Investigate the report and write up what we find",
+			Assignee:    "alice",
+			Effort:      3,
+			Priority:    PriorityMedium,
+			DueDate:     &due,
+			Labels:      []string{"backend"},
+		})
+		if status := statuses[i%3]; status != "todo" {
+			s.MoveTask(task.ID, status)
+		}
+	}
+
+	full := httptest.NewRecorder()
+	NewServer(s).tasksAPIHandler(full, httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil))
+	compact := httptest.NewRecorder()
+	NewServer(s).mobileBoardHandler(compact, httptest.NewRequest(http.MethodGet, "/api/v1/mobile/board", nil))
+
+	fullSize, compactSize := full.Body.Len(), compact.Body.Len()
+	if compactSize > fullSize*40/100 {
+		t.Errorf("Expected the mobile board to be at least 60%% smaller, got %d bytes against %d", compactSize, fullSize)
+	}
+}
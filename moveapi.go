@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// moveRequest is the body of POST /api/v1/tasks/{id}/move
+type moveRequest struct {
+	Status string `json:"status"`
+}
+
+// taskMoveAPIHandler serves POST /api/v1/tasks/{id}/move, the JSON
+// counterpart of /move-task. Refused moves answer with a JSON body describing
+// why: 422 for the workflow or a column's accept policy, 409 for a WIP limit.
+func (srv *Server) taskMoveAPIHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req moveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if !isValidStatus(req.Status) {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	task, ok, err := srv.MoveTask(id, req.Status)
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	var transitionErr *ErrTransitionNotAllowed
+	var violation *PolicyViolation
+	var wipErr *ErrWIPLimitReached
+	switch {
+	case errors.As(err, &transitionErr):
+		allowed := transitionErr.Allowed
+		if allowed == nil {
+			allowed = []string{}
+		}
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":   "transition not allowed",
+			"from":    transitionErr.From,
+			"to":      transitionErr.To,
+			"allowed": allowed,
+		})
+	case errors.As(err, &violation):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error": violation.Reason,
+			"from":  violation.From,
+			"to":    violation.To,
+		})
+	case errors.As(err, &wipErr):
+		writeJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":  "wip limit reached",
+			"status": wipErr.Status,
+			"limit":  wipErr.Limit,
+		})
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		writeJSON(w, http.StatusOK, task)
+	}
+}
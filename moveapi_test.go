@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func postMove(id, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+id+"/move", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	NewServer(store).taskAPIHandler(w, req)
+	return w
+}
+
+func TestTaskMoveAPI(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("Ship", "")
+
+	w := postMove("1", `{"status": "doing"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var moved Task
+	if err := json.Unmarshal(w.Body.Bytes(), &moved); err != nil || moved.ID != task.ID || moved.Status != "doing" {
+		t.Errorf("Expected the moved task as JSON, got %s", w.Body.String())
+	}
+	if got, _ := s.GetTask(task.ID); got.Status != "doing" {
+		t.Errorf("Expected the move to be stored, got %s", got.Status)
+	}
+
+	if w := postMove("99", `{"status": "done"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing task, got %d", w.Code)
+	}
+	if w := postMove("1", `{"status": "archived"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown status, got %d", w.Code)
+	}
+	if w := postMove("1", `not json`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed body, got %d", w.Code)
+	}
+}
+
+func TestTaskMoveAPIForbiddenTransition(t *testing.T) {
+	s := withTestGlobals(t)
+	wf, err := ParseWorkflow([]byte(strictWorkflow))
+	if err != nil {
+		t.Fatalf("ParseWorkflow error: %v", err)
+	}
+	s.workflow = wf
+	s.AddTask("Ship", "")
+
+	w := postMove("1", `{"status": "done"}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	want := `{"allowed":["doing"],"error":"transition not allowed","from":"todo","to":"done"}`
+	if got := strings.TrimSpace(w.Body.String()); got != want {
+		t.Errorf("Expected body %s, got %s", want, got)
+	}
+}
+
+func TestTaskMoveAPIWIPLimit(t *testing.T) {
+	s := withTestGlobals(t)
+	s.wipLimits = map[string]int{"doing": 1}
+	s.CreateTask(TaskSpec{Title: "Busy", Status: "doing"})
+	s.AddTask("Waiting", "")
+
+	w := postMove("2", `{"status": "doing"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+	want := `{"error":"wip limit reached","limit":1,"status":"doing"}`
+	if got := strings.TrimSpace(w.Body.String()); got != want {
+		t.Errorf("Expected body %s, got %s", want, got)
+	}
+	if got, _ := s.GetTask(2); got.Status != "todo" {
+		t.Errorf("Refused move must leave the task in todo, got %s", got.Status)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// adjacentStatuses returns the statuses a task in current can step to in
+// the board's column order: the columns after it when forward is set,
+// otherwise those before it. Without a workflow graph that is just the
+// neighbouring column; with one it is every allowed move in that direction.
+func adjacentStatuses(current string, workflow WorkflowConfig, forward bool) []string {
+	cols := columns()
+	pos := -1
+	for i, col := range cols {
+		if col.Status == current {
+			pos = i
+		}
+	}
+	if pos < 0 {
+		return nil
+	}
+
+	var candidates []columnDef
+	if forward {
+		candidates = cols[pos+1:]
+	} else {
+		for i := pos - 1; i >= 0; i-- {
+			candidates = append(candidates, cols[i])
+		}
+	}
+	if workflow.Transitions == nil {
+		if len(candidates) == 0 {
+			return nil
+		}
+		return []string{candidates[0].Status}
+	}
+	var statuses []string
+	for _, col := range candidates {
+		if workflow.Allows(current, col.Status) {
+			statuses = append(statuses, col.Status)
+		}
+	}
+	return statuses
+}
+
+// NextStatus returns the one status after currentStatus in a linear
+// workflow. It reports false at the last step, and where the workflow
+// branches to more than one later status.
+func NextStatus(currentStatus string, workflow WorkflowConfig) (string, bool) {
+	return singleStatus(adjacentStatuses(currentStatus, workflow, true))
+}
+
+// PrevStatus returns the one status before currentStatus in a linear
+// workflow. It reports false at the first step, and where the workflow
+// allows going back to more than one earlier status.
+func PrevStatus(currentStatus string, workflow WorkflowConfig) (string, bool) {
+	return singleStatus(adjacentStatuses(currentStatus, workflow, false))
+}
+
+func singleStatus(statuses []string) (string, bool) {
+	if len(statuses) != 1 {
+		return "", false
+	}
+	return statuses[0], true
+}
+
+// taskMoveStepHandler serves POST /task/{id}/move-next and
+// /task/{id}/move-prev, moving a task one step along the workflow and
+// returning the board like /move-task. It answers 409 when there is no
+// single step to take.
+func (srv *Server) taskMoveStepHandler(w http.ResponseWriter, r *http.Request, id int, forward bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	task, ok := srv.GetTask(id)
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	statuses := adjacentStatuses(task.Status, srv.Workflow(), forward)
+	direction := "previous"
+	if forward {
+		direction = "next"
+	}
+	switch {
+	case len(statuses) == 0:
+		http.Error(w, fmt.Sprintf("Task is already in %q and has no %s status", task.Status, direction), http.StatusConflict)
+		return
+	case len(statuses) > 1:
+		http.Error(w, fmt.Sprintf("The workflow is not linear from %q: the %s status could be any of %v", task.Status, direction, statuses),
+			http.StatusConflict)
+		return
+	}
+
+	task, ok, err := srv.MoveTask(id, statuses[0])
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	var violation *PolicyViolation
+	if errors.As(err, &violation) {
+		writePolicyViolation(w, violation)
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	data := withCollapsed(r, srv.GetBoardData())
+	templates.ExecuteTemplate(w, "all-columns.html", data)
+	writeColumnBadgesOOB(w, data)
+
+	fmt.Printf("Moved task %d (%s) to %s\n", task.ID, task.Title, task.Status)
+}
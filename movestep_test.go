@@ -0,0 +1,103 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNextAndPrevStatus(t *testing.T) {
+	linear, _ := ParseWorkflow([]byte(`{"todo":["doing"], "doing":["todo","done"], "done":["doing"]}`))
+	for _, wf := range []WorkflowConfig{{}, *linear} {
+		for _, tc := range []struct {
+			from, next, prev string
+		}{
+			{"todo", "doing", ""},
+			{"doing", "done", "todo"},
+			{"done", "", "doing"},
+		} {
+			next, ok := NextStatus(tc.from, wf)
+			if next != tc.next || ok != (tc.next != "") {
+				t.Errorf("NextStatus(%s, %v) = %q, %t; want %q", tc.from, wf.Transitions, next, ok, tc.next)
+			}
+			prev, ok := PrevStatus(tc.from, wf)
+			if prev != tc.prev || ok != (tc.prev != "") {
+				t.Errorf("PrevStatus(%s, %v) = %q, %t; want %q", tc.from, wf.Transitions, prev, ok, tc.prev)
+			}
+		}
+	}
+
+	branching, _ := ParseWorkflow([]byte(`{"todo":["doing","done"], "doing":["done"], "done":[]}`))
+	if next, ok := NextStatus("todo", *branching); ok {
+		t.Errorf("Expected no single next status from a branch, got %q", next)
+	}
+	if next, ok := NextStatus("doing", *branching); !ok || next != "done" {
+		t.Errorf("Expected doing to lead to done, got %q %t", next, ok)
+	}
+	if prev, ok := PrevStatus("doing", *branching); ok {
+		t.Errorf("Expected no way back when the workflow forbids it, got %q", prev)
+	}
+	if _, ok := NextStatus("archived", WorkflowConfig{}); ok {
+		t.Errorf("Expected no next status for an unknown status")
+	}
+}
+
+func TestTaskMoveStepHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Step", "")
+	var hooked []string
+	s.RegisterTransitionHook(func(task *Task, from, to string) error {
+		hooked = append(hooked, from+">"+to)
+		return nil
+	})
+
+	post := func(action string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		NewServer(s).taskRouter(w, httptest.NewRequest(http.MethodPost, "/task/1/"+action, nil))
+		return w
+	}
+
+	if w := post("move-prev"); w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "no previous status") {
+		t.Errorf("Expected 409 going back from the first column, got %d: %s", w.Code, w.Body.String())
+	}
+	for _, want := range []string{"doing", "done"} {
+		if w := post("move-next"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `id="done-tasks"`) {
+			t.Fatalf("Expected the board after moving to %s, got %d: %s", want, w.Code, w.Body.String())
+		}
+		if task, _ := s.GetTask(1); task.Status != want {
+			t.Fatalf("Expected the task in %s, got %s", want, task.Status)
+		}
+	}
+	if w := post("move-next"); w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "no next status") {
+		t.Errorf("Expected 409 moving past the last column, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("move-prev"); w.Code != http.StatusOK {
+		t.Errorf("Expected to step back to doing, got %d", w.Code)
+	}
+	if strings.Join(hooked, ",") != "todo>doing,doing>done,done>doing" {
+		t.Errorf("Expected the transition hook on every step, got %v", hooked)
+	}
+
+	s.workflow, _ = ParseWorkflow([]byte(`{"todo":["doing","done"], "doing":["todo","done"], "done":[]}`))
+	s.AddTask("Branch", "")
+	w := httptest.NewRecorder()
+	NewServer(s).taskRouter(w, httptest.NewRequest(http.MethodPost, "/task/2/move-next", nil))
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "not linear") {
+		t.Errorf("Expected 409 for a branching workflow, got %d: %s", w.Code, w.Body.String())
+	}
+
+	s.workflow = nil
+	s.wipLimits = map[string]int{"done": 1}
+	w = httptest.NewRecorder()
+	NewServer(s).taskRouter(w, httptest.NewRequest(http.MethodPost, "/task/1/move-next", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the move into an empty done column to succeed, got %d", w.Code)
+	}
+	s.MoveTask(2, "doing")
+	w = httptest.NewRecorder()
+	NewServer(s).taskRouter(w, httptest.NewRequest(http.MethodPost, "/task/2/move-next", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected the WIP limit to refuse the move with 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
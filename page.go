@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"net/http"
+)
+
+const pageTitle = "Mini Kanban Board"
+
+// PageData fills layout.html, the full page wrapped around a page's content
+type PageData struct {
+	Title     string
+	Content   template.HTML
+	DevReload bool   // reload the page when templates change
+	Theme     string // the viewer's theme, from requestTheme
+	Shortcuts []KeyboardShortcut
+}
+
+// isHTMXRequest reports whether htmx made the request and will swap the
+// response into the current page. History restores, which htmx makes when
+// its cache misses, want the full page.
+func isHTMXRequest(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true" && r.Header.Get("HX-History-Restore-Request") != "true"
+}
+
+// htmlContentType is the Content-Type of rendered pages
+const htmlContentType = "text/html; charset=utf-8"
+
+// renderPartial renders a page's main content template. HTMX requests, such
+// as boosted links, get only the content, with HX-Push-Url set so the
+// address bar follows; other requests get it inside layout.html.
+func renderPartial(w http.ResponseWriter, r *http.Request, name string, data interface{}) {
+	body, err := renderPage(r, name, data)
+	if err != nil {
+		log.Printf("Error rendering %s: %v", name, err)
+		http.Error(w, "Could not render page", http.StatusInternalServerError)
+		return
+	}
+	writePage(w, r, htmlContentType, body)
+}
+
+// renderPage renders the response body renderPartial sends for r
+func renderPage(r *http.Request, name string, data interface{}) ([]byte, error) {
+	var content bytes.Buffer
+	if err := templates.ExecuteTemplate(&content, name, data); err != nil {
+		return nil, err
+	}
+	if isHTMXRequest(r) {
+		return content.Bytes(), nil
+	}
+	var page bytes.Buffer
+	err := templates.ExecuteTemplate(&page, "layout.html", PageData{
+		Title:     pageTitle,
+		Content:   template.HTML(content.String()),
+		DevReload: devReload != nil,
+		Theme:     requestTheme(r),
+		Shortcuts: keyboardShortcuts(settings),
+	})
+	return page.Bytes(), err
+}
+
+// writePage sends a body from renderPage, with the headers renderPartial
+// sets
+func writePage(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Push-Url", r.URL.RequestURI())
+	} else {
+		pushPageAssets(w, r)
+	}
+	w.Write(body)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func htmxGet(path string, htmx bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if htmx {
+		req.Header.Set("HX-Request", "true")
+	}
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/task/") {
+			NewServer(store).taskRouter(w, r)
+		} else {
+			NewServer(store).indexHandler(w, r)
+		}
+	}).ServeHTTP(rr, req)
+	return rr
+}
+
+func TestIndexPartialForHTMXRequests(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Card", "")
+
+	full := htmxGet("/", false)
+	if !strings.HasPrefix(full.Body.String(), "<!DOCTYPE html>") || full.Header().Get("HX-Push-Url") != "" {
+		t.Errorf("Expected the full page without HX-Push-Url for a normal request")
+	}
+
+	partial := htmxGet("/?view=swimlane", true)
+	body := partial.Body.String()
+	if !strings.HasPrefix(body, `<div class="container" id="main-content">`) || strings.Contains(body, "<html") {
+		t.Errorf("Expected only the main content div, got %.80q", body)
+	}
+	if got := partial.Header().Get("HX-Push-Url"); got != "/?view=swimlane" {
+		t.Errorf("Expected HX-Push-Url /?view=swimlane, got %q", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("HX-Request", "true")
+	req.Header.Set("HX-History-Restore-Request", "true")
+	rr := httptest.NewRecorder()
+	NewServer(s).indexHandler(rr, req)
+	if !strings.Contains(rr.Body.String(), "<html") {
+		t.Errorf("Expected a history restore to get the full page")
+	}
+}
+
+func TestTaskDetailPage(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("Write docs", "User guide")
+	other := s.AddTask("Review docs", "")
+	s.AddRelationship(other.ID, task.ID, RelationBlocks)
+
+	board := htmxGet("/", false).Body.String()
+	if !strings.Contains(board, `<a href="/task/1" hx-boost="true" hx-target="#main-content" hx-swap="outerHTML">Write docs</a>`) {
+		t.Errorf("Expected a boosted link from the card to its detail page")
+	}
+
+	partial := htmxGet("/task/1", true)
+	body := partial.Body.String()
+	if partial.Code != http.StatusOK || strings.Contains(body, "<html") || !strings.Contains(body, "<h1>Write docs</h1>") {
+		t.Errorf("Expected the detail partial, got %d: %.80q", partial.Code, body)
+	}
+	if got := partial.Header().Get("HX-Push-Url"); got != "/task/1" {
+		t.Errorf("Expected HX-Push-Url /task/1, got %q", got)
+	}
+	if !strings.Contains(body, "Review docs") || !strings.Contains(body, "To Do") {
+		t.Errorf("Expected the column and related task on the detail page")
+	}
+
+	full := htmxGet("/task/1", false)
+	if !strings.Contains(full.Body.String(), "<h1>Write docs</h1>") || !strings.Contains(full.Body.String(), "<html") {
+		t.Errorf("Expected a direct visit to get the full detail page")
+	}
+	if rr := htmxGet("/task/99", true); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing task, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ResponseCache keeps the last rendered response along with the key it was
+// rendered for
+type ResponseCache struct {
+	mu          sync.RWMutex
+	key         string
+	contentType string
+	body        []byte
+}
+
+// Store replaces the cached response
+func (c *ResponseCache) Store(key, contentType string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.key, c.contentType, c.body = key, contentType, body
+}
+
+// Load returns the cached response if it was stored for key
+func (c *ResponseCache) Load(key string) (contentType string, body []byte, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.body == nil || c.key != key {
+		return "", nil, false
+	}
+	return c.contentType, c.body, true
+}
+
+// Invalidate drops the cached response
+func (c *ResponseCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.key, c.contentType, c.body = "", "", nil
+}
+
+// invalidatePages marks the board as changed, so pages rendered from an
+// older revision are stale. publish calls it for every mutation.
+func (s *TaskStore) invalidatePages() {
+	s.revision.Add(1)
+}
+
+// Revision returns a counter that goes up whenever the board changes
+func (s *TaskStore) Revision() uint64 {
+	return s.revision.Load()
+}
+
+// Settings returns a copy of the board's settings
+func (s *TaskStore) Settings() map[string]string {
+	if s.settings == nil {
+		return nil
+	}
+	return s.settings.All()
+}
+
+// boardStateHash identifies everything the index page for r is rendered
+// from: the tasks, by revision, the settings and columns, the feature
+// flags, the viewer's theme, and which view and response shape were asked
+// for. Card ages are shown to the minute, so the hash changes every minute
+// too.
+func (srv *Server) boardStateHash(r *http.Request) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%s|%t|%t|%d|%+v|%v|%v|%s\n",
+		srv.Revision(), r.URL.Query().Get("view"), isHTMXRequest(r), devReload != nil,
+		srv.Now().Truncate(time.Minute).Unix(), features.Get(), columns(),
+		requestPreferences(r).collapsedIDs(), requestTheme(r))
+	values := srv.Settings()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(h, "%q=%q\n", key, values[key])
+	}
+	return fmt.Sprintf(`"%x"`, h.Sum64())
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseCache(t *testing.T) {
+	var c ResponseCache
+	if _, _, ok := c.Load(""); ok {
+		t.Errorf("Expected an empty cache to miss")
+	}
+	c.Store("a", "text/html", []byte("page"))
+	if contentType, body, ok := c.Load("a"); !ok || contentType != "text/html" || string(body) != "page" {
+		t.Errorf("Expected the stored page, got %q %q %v", contentType, body, ok)
+	}
+	if _, _, ok := c.Load("b"); ok {
+		t.Errorf("Expected another key to miss")
+	}
+	c.Invalidate()
+	if _, _, ok := c.Load("a"); ok {
+		t.Errorf("Expected a miss after Invalidate")
+	}
+}
+
+func TestIndexResponseCache(t *testing.T) {
+	s := withTestGlobals(t)
+	task := s.AddTask("Original", "")
+	srv := NewServer(s)
+
+	get := func(htmx bool) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if htmx {
+			req.Header.Set("HX-Request", "true")
+		}
+		rr := httptest.NewRecorder()
+		srv.indexHandler(rr, req)
+		if ct := rr.Header().Get("Content-Type"); ct != htmlContentType {
+			t.Errorf("Expected Content-Type %q, got %q", htmlContentType, ct)
+		}
+		return rr.Body.String()
+	}
+	first := get(false)
+
+	// Changing a task behind the store's back doesn't invalidate the cache,
+	// so the cached page is served
+	s.tasks[task.ID].Title = "Sneaky"
+	if get(false) != first {
+		t.Errorf("Expected the cached page while the board hash is unchanged")
+	}
+	if partial := get(true); strings.Contains(partial, "<html") || !strings.Contains(partial, "Sneaky") {
+		t.Errorf("Expected the HTMX partial to be rendered and cached separately")
+	}
+
+	s.UpdateTask(task.ID, func(t *Task) { t.Title = "Renamed" })
+	if body := get(false); !strings.Contains(body, "Renamed") {
+		t.Errorf("Expected a mutation to invalidate the cached page")
+	}
+	s.settings = withTestSettings(t)
+	s.settings.Merge(map[string]string{"column_name.todo": "Backlog"})
+	if body := get(false); !strings.Contains(body, "Backlog") {
+		t.Errorf("Expected a settings change to change the board hash")
+	}
+}
+
+// benchmarkIndex renders the index page of a 200 task board, invalidating
+// the cached page before each request unless cached
+func benchmarkIndex(b *testing.B, cached bool) {
+	orig := store
+	b.Cleanup(func() { store = orig })
+	store = newBenchmarkStore(b, 200)
+	srv := NewServer(store)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !cached {
+			srv.pages.Invalidate()
+		}
+		srv.indexHandler(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkIndexCached(b *testing.B)   { benchmarkIndex(b, true) }
+func BenchmarkIndexUncached(b *testing.B) { benchmarkIndex(b, false) }
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ColumnStore persists each column to its own JSON file (todo.json,
+// doing.json, done.json) so a write only rewrites the columns it touches
+type ColumnStore struct {
+	dir string
+}
+
+// NewColumnStore returns a ColumnStore keeping its partition files in dir
+func NewColumnStore(dir string) *ColumnStore {
+	return &ColumnStore{dir: dir}
+}
+
+// partitionPath returns the file holding tasks with the given status
+func (cs *ColumnStore) partitionPath(status string) string {
+	return filepath.Join(cs.dir, status+".json")
+}
+
+// WritePartitions rewrites the partition file of each given status. Each file
+// is written to a temp file first and renamed into place.
+func (cs *ColumnStore) WritePartitions(tasks map[int]*Task, nextID int, statuses ...string) error {
+	if err := os.MkdirAll(cs.dir, 0755); err != nil {
+		return err
+	}
+
+	written := make(map[string]bool)
+	for _, status := range statuses {
+		if written[status] {
+			continue
+		}
+		written[status] = true
+
+		data := PersistentData{Tasks: []*Task{}, NextID: nextID}
+		for _, task := range tasks {
+			if task.Status == status {
+				data.Tasks = append(data.Tasks, task)
+			}
+		}
+		sort.Slice(data.Tasks, func(i, j int) bool { return data.Tasks[i].ID < data.Tasks[j].ID })
+		if err := writeJSONFileAtomic(cs.partitionPath(status), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load reads and merges every partition file. It fails if the same task ID
+// appears more than once, which means the files disagree.
+func (cs *ColumnStore) Load() (map[int]*Task, int, error) {
+	tasks := make(map[int]*Task)
+	nextID := 1
+	for _, col := range columns() {
+		file, err := os.Open(cs.partitionPath(col.Status))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, 0, err
+		}
+		var data PersistentData
+		err = json.NewDecoder(file).Decode(&data)
+		file.Close()
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", cs.partitionPath(col.Status), err)
+		}
+
+		for _, task := range data.Tasks {
+			if existing, ok := tasks[task.ID]; ok {
+				return nil, 0, fmt.Errorf("task ID %d appears in both %s and %s partitions",
+					task.ID, existing.Status, col.Status)
+			}
+			tasks[task.ID] = task
+			if task.ID >= nextID {
+				nextID = task.ID + 1
+			}
+		}
+		if data.NextID > nextID {
+			nextID = data.NextID
+		}
+	}
+	return tasks, nextID, nil
+}
+
+// linksPath returns the file holding every task's links
+func (cs *ColumnStore) linksPath() string {
+	return filepath.Join(cs.dir, "links.json")
+}
+
+// WriteLinks rewrites the links file
+func (cs *ColumnStore) WriteLinks(links []*Link, nextLinkID int) error {
+	return cs.writeSidecar(cs.linksPath(), PersistentData{Links: links, NextLinkID: nextLinkID})
+}
+
+// LoadLinks reads the links file, returning nothing if it doesn't exist yet
+func (cs *ColumnStore) LoadLinks() ([]*Link, int, error) {
+	data, err := cs.loadSidecar(cs.linksPath())
+	return data.Links, data.NextLinkID, err
+}
+
+// commentsPath returns the file holding every task's comments and reactions
+func (cs *ColumnStore) commentsPath() string {
+	return filepath.Join(cs.dir, "comments.json")
+}
+
+// WriteComments rewrites the comments file
+func (cs *ColumnStore) WriteComments(comments []*Comment, nextCommentID int, reactions []Reaction) error {
+	return cs.writeSidecar(cs.commentsPath(), PersistentData{Comments: comments, NextCommentID: nextCommentID, Reactions: reactions})
+}
+
+// LoadComments reads the comments file, returning nothing if it doesn't
+// exist yet
+func (cs *ColumnStore) LoadComments() ([]*Comment, int, []Reaction, error) {
+	data, err := cs.loadSidecar(cs.commentsPath())
+	return data.Comments, data.NextCommentID, data.Reactions, err
+}
+
+func (cs *ColumnStore) relationshipsPath() string {
+	return filepath.Join(cs.dir, "relationships.json")
+}
+
+// WriteRelationships rewrites the relationships file
+func (cs *ColumnStore) WriteRelationships(rels []Relationship, nextRelationshipID int) error {
+	return cs.writeSidecar(cs.relationshipsPath(), PersistentData{Relationships: rels, NextRelationshipID: nextRelationshipID})
+}
+
+// LoadRelationships reads the relationships file, returning nothing if it
+// doesn't exist yet
+func (cs *ColumnStore) LoadRelationships() ([]Relationship, int, error) {
+	data, err := cs.loadSidecar(cs.relationshipsPath())
+	return data.Relationships, data.NextRelationshipID, err
+}
+
+// writeSidecar writes data that isn't kept in the column files, such as
+// links, to its own file
+func (cs *ColumnStore) writeSidecar(path string, data PersistentData) error {
+	if err := os.MkdirAll(cs.dir, 0755); err != nil {
+		return err
+	}
+	data.Tasks = []*Task{}
+	return writeJSONFileAtomic(path, data)
+}
+
+// loadSidecar reads a file written by writeSidecar, returning empty data if
+// it doesn't exist yet
+func (cs *ColumnStore) loadSidecar(path string) (PersistentData, error) {
+	var data PersistentData
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return data, nil
+		}
+		return data, err
+	}
+	defer file.Close()
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		return data, fmt.Errorf("%s: %w", path, err)
+	}
+	return data, nil
+}
+
+// writeJSONFileAtomic encodes v to a temp file next to path, then renames it over path
+func writeJSONFileAtomic(path string, v interface{}) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(tmp)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// persist refreshes the read cache for and saves the columns touched by a
+// change (must be called with lock held). Without partitioned storage the
+// whole board is saved. With an external backend nothing is written here;
+// the backend's mirror applies each change from the event bus instead.
+func (s *TaskStore) persist(statuses ...string) {
+	s.cache.rebuild(s.tasks, statuses...)
+	if s.backend != nil {
+		return
+	}
+	if s.partitions == nil {
+		s.saveToFile()
+		return
+	}
+	tasks, err := s.storedTasks()
+	if err != nil {
+		log.Printf("Error encrypting tasks: %v", err)
+		return
+	}
+	if err := s.partitions.WritePartitions(tasks, s.nextID, statuses...); err != nil {
+		log.Printf("Error saving partitions: %v", err)
+	}
+}
+
+// allStatuses returns every status currently in use plus the board's columns
+func (s *TaskStore) allStatuses() []string {
+	var statuses []string
+	seen := make(map[string]bool)
+	for _, col := range s.columnList() {
+		seen[col.Status] = true
+		statuses = append(statuses, col.Status)
+	}
+	for _, task := range s.tasks {
+		if !seen[task.Status] {
+			seen[task.Status] = true
+			statuses = append(statuses, task.Status)
+		}
+	}
+	return statuses
+}
+
+// Compact renumbers tasks 1..N in their current ID order, removing gaps left
+// by deletions, and returns how many tasks changed ID, or ErrBoardLocked
+func (s *TaskStore) Compact() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkLocked(); err != nil {
+		return 0, err
+	}
+
+	ids := make([]int, 0, len(s.tasks))
+	for id := range s.tasks {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	renumbered := make(map[int]*Task, len(ids))
+	relinked := make(map[int][]*Link, len(s.links))
+	recommented := make(map[int][]*Comment, len(s.comments))
+	oldIDs := make(map[int]int, len(ids)) // old ID to new, for depends_on and subtasks
+	changed := 0
+	for i, id := range ids {
+		oldIDs[id] = i + 1
+		task := s.tasks[id]
+		if task.ID != i+1 {
+			task.ID = i + 1
+			changed++
+		}
+		renumbered[task.ID] = task
+		if links, ok := s.links[id]; ok {
+			for _, link := range links {
+				link.TaskID = task.ID
+			}
+			relinked[task.ID] = links
+		}
+		if comments, ok := s.comments[id]; ok {
+			for _, comment := range comments {
+				comment.TaskID = task.ID
+			}
+			recommented[task.ID] = comments
+		}
+	}
+	// Renumber dependencies, dropping ones on deleted tasks since their old
+	// IDs may now belong to other tasks
+	for _, task := range renumbered {
+		if task.DependsOn == nil {
+			continue
+		}
+		deps := []int{}
+		for _, dep := range task.DependsOn {
+			if moved, ok := oldIDs[dep]; ok {
+				deps = append(deps, moved)
+			}
+		}
+		task.DependsOn = deps
+	}
+	// Point subtasks at their archived tasks' new IDs, forgetting ones
+	// that were deleted
+	for _, task := range renumbered {
+		for i := range task.Checklist {
+			if item := &task.Checklist[i]; item.TaskID != 0 {
+				item.TaskID = oldIDs[item.TaskID]
+			}
+		}
+	}
+	for i := range s.relationships {
+		s.relationships[i].FromID = oldIDs[s.relationships[i].FromID]
+		s.relationships[i].ToID = oldIDs[s.relationships[i].ToID]
+	}
+	s.tasks = renumbered
+	s.deps.reset(renumbered)
+	s.links = relinked
+	s.comments = recommented
+	s.nextID = len(ids) + 1
+	s.persist(s.allStatuses()...)
+	if s.partitions != nil {
+		s.persistLinks()
+		s.persistComments()
+		s.persistRelationships()
+	}
+	// Every task's ID may have changed, so the backend is rewritten whole
+	s.mirrorBoard(ids)
+	return changed, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newPartitionedTestStore(t *testing.T) *TaskStore {
+	dir := t.TempDir()
+	return &TaskStore{
+		tasks:      make(map[int]*Task),
+		nextID:     1,
+		filePath:   filepath.Join(dir, "tasks.json"),
+		partitions: NewColumnStore(dir),
+	}
+}
+
+func readPartition(t *testing.T, store *TaskStore, status string) []int {
+	t.Helper()
+	raw, err := os.ReadFile(store.partitions.partitionPath(status))
+	if err != nil {
+		t.Fatalf("Reading %s partition: %v", status, err)
+	}
+	var data PersistentData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatalf("Decoding %s partition: %v", status, err)
+	}
+	return taskIDs(data.Tasks)
+}
+
+func TestPartitionedAddWritesOnlyTodo(t *testing.T) {
+	store := newPartitionedTestStore(t)
+	store.AddTask("First", "")
+
+	if ids := readPartition(t, store, "todo"); !equalIDs(ids, []int{1}) {
+		t.Errorf("Expected todo.json to hold task 1, got %v", ids)
+	}
+	for _, status := range []string{"doing", "done"} {
+		if _, err := os.Stat(store.partitions.partitionPath(status)); !os.IsNotExist(err) {
+			t.Errorf("%s.json should not be written by AddTask", status)
+		}
+	}
+	if _, err := os.Stat(store.filePath); !os.IsNotExist(err) {
+		t.Errorf("The single data file should not be written in partitioned mode")
+	}
+}
+
+func TestPartitionedMoveWritesSourceAndDestination(t *testing.T) {
+	store := newPartitionedTestStore(t)
+	store.AddTask("Mover", "")
+	store.AddTask("Stayer", "")
+	store.MoveTask(1, "doing")
+
+	if ids := readPartition(t, store, "todo"); !equalIDs(ids, []int{2}) {
+		t.Errorf("Expected todo.json to hold task 2, got %v", ids)
+	}
+	if ids := readPartition(t, store, "doing"); !equalIDs(ids, []int{1}) {
+		t.Errorf("Expected doing.json to hold task 1, got %v", ids)
+	}
+	if _, err := os.Stat(store.partitions.partitionPath("done")); !os.IsNotExist(err) {
+		t.Errorf("done.json should not be touched by a todo -> doing move")
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(store.partitions.dir, "*.tmp*"))
+	if len(matches) != 0 {
+		t.Errorf("Temp files left behind: %v", matches)
+	}
+}
+
+func TestPartitionedLoadMergesFiles(t *testing.T) {
+	store := newPartitionedTestStore(t)
+	store.AddTask("A", "")
+	store.AddTask("B", "")
+	store.AddTask("C", "")
+	store.MoveTask(2, "doing")
+	store.MoveTask(3, "done")
+
+	loaded := &TaskStore{tasks: make(map[int]*Task), nextID: 1, partitions: store.partitions}
+	if err := loaded.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile error: %v", err)
+	}
+	if len(loaded.tasks) != 3 || loaded.tasks[3].Status != "done" {
+		t.Errorf("Expected 3 merged tasks, got %d", len(loaded.tasks))
+	}
+	if loaded.nextID != 4 {
+		t.Errorf("Expected nextID 4, got %d", loaded.nextID)
+	}
+}
+
+func TestPartitionedLoadDetectsIDConflicts(t *testing.T) {
+	store := newPartitionedTestStore(t)
+	store.AddTask("A", "")
+	store.tasks[1].Status = "done"
+	store.persist("done")
+
+	loaded := &TaskStore{tasks: make(map[int]*Task), nextID: 1, partitions: store.partitions}
+	if err := loaded.LoadFromFile(); err == nil {
+		t.Errorf("Expected an error when a task ID appears in two partitions")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	store := newPartitionedTestStore(t)
+	for _, title := range []string{"1", "2", "3", "4"} {
+		store.AddTask(title, "")
+	}
+	store.MoveTask(4, "done")
+	store.DeleteTask(1)
+	store.DeleteTask(3)
+
+	if changed, _ := store.Compact(); changed != 2 {
+		t.Errorf("Expected 2 tasks to be renumbered, got %d", changed)
+	}
+	if store.tasks[1].Title != "2" || store.tasks[2].Title != "4" || store.nextID != 3 {
+		t.Errorf("Unexpected tasks after compaction: %v, nextID %d", store.tasks, store.nextID)
+	}
+	if ids := readPartition(t, store, "done"); !equalIDs(ids, []int{2}) {
+		t.Errorf("Expected done.json to hold renumbered task 2, got %v", ids)
+	}
+}
+
+func TestCompactRenumbersDependencies(t *testing.T) {
+	store := newPartitionedTestStore(t)
+	for _, title := range []string{"1", "2", "3", "4"} {
+		store.AddTask(title, "")
+	}
+	store.UpdateTask(4, func(task *Task) { task.DependsOn = []int{1, 3} })
+	store.DeleteTask(1)
+	store.DeleteTask(2)
+
+	store.Compact()
+	if deps := store.tasks[2].DependsOn; !equalIDs(deps, []int{1}) {
+		t.Errorf("Expected task 3 (now 1) as the only dependency, got %v", deps)
+	}
+}
+
+func TestCompactRenumbersSubtasks(t *testing.T) {
+	store := newPartitionedTestStore(t)
+	for _, title := range []string{"1", "2", "3", "4", "5"} {
+		store.AddTask(title, "")
+	}
+	store.ConvertToSubtask(3, 5)
+	store.ConvertToSubtask(4, 5)
+	store.DeleteTask(1)
+	store.DeleteTask(4)
+
+	store.Compact()
+	parent := store.tasks[3]
+	if parent.Title != "5" || len(parent.Checklist) != 2 {
+		t.Fatalf("Expected task 5 (now 3) with two subtasks, got %+v", parent)
+	}
+	if got := parent.Checklist[0].TaskID; got != 2 || store.tasks[got].Title != "3" {
+		t.Errorf("Expected the first subtask to point at task 3 (now 2), got %d", got)
+	}
+	if got := parent.Checklist[1].TaskID; got != 0 {
+		t.Errorf("Expected the deleted subtask's task ID to be cleared, got %d", got)
+	}
+}
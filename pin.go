@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const defaultMaxPinsPerColumn = 3
+
+// loadMaxPinsPerColumn reads KANBAN_MAX_PINS_PER_COLUMN, falling back to the
+// default
+func loadMaxPinsPerColumn() int {
+	if raw := os.Getenv("KANBAN_MAX_PINS_PER_COLUMN"); raw != "" {
+		if limit, err := strconv.Atoi(raw); err == nil && limit > 0 {
+			return limit
+		}
+	}
+	return defaultMaxPinsPerColumn
+}
+
+var maxPinsPerColumn = loadMaxPinsPerColumn()
+
+// ErrPinLimitReached is returned when pinning a task in a column that
+// already has the maximum number of pinned tasks
+type ErrPinLimitReached struct {
+	Status string
+	Limit  int
+}
+
+func (e *ErrPinLimitReached) Error() string {
+	return fmt.Sprintf("column %q already has %d pinned tasks", e.Status, e.Limit)
+}
+
+// pinnedCount counts the pinned tasks in a column (must be called with lock
+// held)
+func (s *TaskStore) pinnedCount(status string) int {
+	count := 0
+	for _, task := range s.tasks {
+		if task.Pinned && task.Status == status && task.ArchivedAt == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// SetPinned pins a task to the top of its column or unpins it. It returns
+// false if the task does not exist and ErrPinLimitReached if its column
+// already has maxPinsPerColumn pinned tasks.
+func (s *TaskStore) SetPinned(id int, pinned bool) (*Task, bool, error) {
+	span := s.startSpan(context.Background(), "SetPinned", attribute.Int("task.id", id))
+	defer span.End()
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, true, err
+	}
+	task, ok := s.tasks[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, false, nil
+	}
+	if task.Pinned == pinned {
+		unchanged := task.clone()
+		s.mu.Unlock()
+		return unchanged, true, nil
+	}
+	if pinned && s.pinnedCount(task.Status) >= maxPinsPerColumn {
+		s.mu.Unlock()
+		return nil, true, &ErrPinLimitReached{Status: task.Status, Limit: maxPinsPerColumn}
+	}
+
+	task.Pinned = pinned
+	task.UpdatedAt = s.clock()
+	s.persist(task.Status)
+	updated := task.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{
+		Type:    EventTaskUpdated,
+		Task:    updated,
+		Changes: []FieldChange{{Field: "pinned", Old: strconv.FormatBool(!pinned), New: strconv.FormatBool(pinned)}},
+	})
+	return updated, true, nil
+}
+
+// unpinIfColumnFull is the built-in hook that drops a moved task's pin when
+// its new column already has maxPinsPerColumn pinned tasks, rather than
+// refusing the move
+func (s *TaskStore) unpinIfColumnFull(task *Task, fromStatus, toStatus string) error {
+	if task.Pinned && s.pinnedCount(toStatus) >= maxPinsPerColumn {
+		task.Pinned = false
+	}
+	return nil
+}
+
+// taskPinHandler serves /api/v1/tasks/{id}/pin: POST pins the task and
+// DELETE unpins it. Both return the task as JSON.
+func (srv *Server) taskPinHandler(w http.ResponseWriter, r *http.Request, id int) {
+	var pinned bool
+	switch r.Method {
+	case http.MethodPost:
+		pinned = true
+	case http.MethodDelete:
+		pinned = false
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	task, ok, err := srv.SetPinned(id, pinned)
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	var limitErr *ErrPinLimitReached
+	if errors.As(err, &limitErr) {
+		writeJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":  "pin limit reached",
+			"status": limitErr.Status,
+			"limit":  limitErr.Limit,
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPinnedTasksSortFirst(t *testing.T) {
+	s := newTestStore()
+	for _, title := range []string{"A", "B", "C", "D"} {
+		s.AddTask(title, "")
+	}
+	s.SetPinned(3, true)
+	s.SetPinned(4, true)
+
+	if got := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(got, []int{3, 4, 1, 2}) {
+		t.Errorf("Expected pinned tasks first in board order, got %v", got)
+	}
+	if data := s.GetColumnData("todo"); data.Tasks[0].ID != 3 || data.Tasks[1].ID != 4 {
+		t.Errorf("Expected the rendered column to start with the pinned tasks, got %v", taskIDs(data.Tasks))
+	}
+
+	task, ok, err := s.SetPinned(3, false)
+	if !ok || err != nil || task.Pinned {
+		t.Fatalf("Expected task 3 to be unpinned, got %+v, %v, %v", task, ok, err)
+	}
+	if got := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(got, []int{4, 1, 2, 3}) {
+		t.Errorf("Expected the unpinned task back in position, got %v", got)
+	}
+}
+
+func TestPinLimit(t *testing.T) {
+	orig := maxPinsPerColumn
+	maxPinsPerColumn = 2
+	defer func() { maxPinsPerColumn = orig }()
+
+	s := newTestStore()
+	for _, title := range []string{"A", "B", "C", "D"} {
+		s.AddTask(title, "")
+	}
+	s.SetPinned(1, true)
+	s.SetPinned(2, true)
+
+	var limitErr *ErrPinLimitReached
+	if _, _, err := s.SetPinned(3, true); !errors.As(err, &limitErr) || limitErr.Limit != 2 {
+		t.Errorf("Expected ErrPinLimitReached, got %v", err)
+	}
+	if _, _, err := s.SetPinned(1, true); err != nil {
+		t.Errorf("Expected re-pinning a pinned task to succeed, got %v", err)
+	}
+	if _, ok, _ := s.SetPinned(99, true); ok {
+		t.Errorf("Expected a missing task to return false")
+	}
+
+	// Other columns have their own limit, and a pinned task moved into a
+	// full column loses its pin instead of being refused
+	s.SetPinned(3, false)
+	s.MoveTask(3, "doing")
+	s.MoveTask(4, "doing")
+	s.SetPinned(3, true)
+	s.SetPinned(4, true)
+	moved, _, err := s.MoveTask(4, "todo")
+	if err != nil || moved.Pinned {
+		t.Errorf("Expected the move to unpin the task, got %+v, %v", moved, err)
+	}
+}
+
+func TestTaskPinHandler(t *testing.T) {
+	orig := maxPinsPerColumn
+	maxPinsPerColumn = 1
+	defer func() { maxPinsPerColumn = orig }()
+
+	s := withTestGlobals(t)
+	s.AddTask("Critical", "")
+	s.AddTask("Also critical", "")
+	pin := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		NewServer(s).taskAPIHandler(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := pin(http.MethodPost, "/api/v1/tasks/1/pin")
+	var task Task
+	if json.Unmarshal(w.Body.Bytes(), &task); w.Code != http.StatusOK || !task.Pinned {
+		t.Fatalf("Expected the task to be pinned, got %d: %s", w.Code, w.Body.String())
+	}
+	w = pin(http.MethodPost, "/api/v1/tasks/2/pin")
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), `"pin limit reached"`) {
+		t.Errorf("Expected 409 over the pin limit, got %d: %s", w.Code, w.Body.String())
+	}
+
+	rr := httptest.NewRecorder()
+	NewServer(s).indexHandler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(rr.Body.String(), `<span class="task-pin" title="Pinned">📌</span> <a href="/task/1"`) {
+		t.Errorf("Expected a pin icon on the pinned card")
+	}
+
+	if w := pin(http.MethodDelete, "/api/v1/tasks/1/pin"); w.Code != http.StatusOK || strings.Contains(w.Body.String(), `"pinned"`) {
+		t.Errorf("Expected the task to be unpinned, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := pin(http.MethodDelete, "/api/v1/tasks/9/pin"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing task, got %d", w.Code)
+	}
+	if w := pin(http.MethodGet, "/api/v1/tasks/1/pin"); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", w.Code)
+	}
+}
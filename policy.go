@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// acceptFromPrefix is the settings prefix of a column's accept policy, e.g.
+// "accept_from.done": "doing" lets "done" accept tasks only from "doing"
+const acceptFromPrefix = "accept_from"
+
+// TransitionPolicy lists the source statuses each destination column
+// accepts tasks from. Columns without an entry accept tasks from anywhere.
+type TransitionPolicy map[string][]string
+
+// PolicyViolation is returned when a column's accept policy refuses a move
+type PolicyViolation struct {
+	From   string
+	To     string
+	Reason string
+}
+
+func (e *PolicyViolation) Error() string {
+	return e.Reason
+}
+
+// parseAcceptFrom splits a comma-separated list of source statuses. An empty
+// value means the column accepts moves from no other column.
+func parseAcceptFrom(value string) ([]string, error) {
+	sources := []string{}
+	for _, status := range strings.Split(value, ",") {
+		status = strings.TrimSpace(status)
+		if status == "" {
+			continue
+		}
+		if !isValidStatus(status) {
+			return nil, fmt.Errorf("unknown status %q", status)
+		}
+		sources = append(sources, status)
+	}
+	return sources, nil
+}
+
+// TransitionPolicy builds the accept policy from the accept_from.<status>
+// settings. A nil store has no policy.
+func (ss *SettingsStore) TransitionPolicy() TransitionPolicy {
+	if ss == nil {
+		return nil
+	}
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	var policy TransitionPolicy
+	for key, value := range ss.values {
+		prefix, status, _ := strings.Cut(key, ".")
+		if prefix != acceptFromPrefix {
+			continue
+		}
+		sources, err := parseAcceptFrom(value)
+		if err != nil {
+			continue // rejected by validateSetting, so only from a stale file
+		}
+		if policy == nil {
+			policy = make(TransitionPolicy)
+		}
+		policy[status] = sources
+	}
+	return policy
+}
+
+// check returns a PolicyViolation if the destination refuses tasks from the
+// source column
+func (p TransitionPolicy) check(from, to string) error {
+	sources, ok := p[to]
+	if !ok || from == to {
+		return nil
+	}
+	for _, source := range sources {
+		if source == from {
+			return nil
+		}
+	}
+	reason := fmt.Sprintf("%q does not accept tasks from any other column", to)
+	if len(sources) > 0 {
+		reason = fmt.Sprintf("%q only accepts tasks from %s, not %q", to, quoteStatuses(sources), from)
+	}
+	return &PolicyViolation{From: from, To: to, Reason: reason}
+}
+
+// quoteStatuses renders statuses as "a", "b" or "c"
+func quoteStatuses(statuses []string) string {
+	quoted := make([]string, len(statuses))
+	for i, status := range statuses {
+		quoted[i] = fmt.Sprintf("%q", status)
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
+// writePolicyViolation renders the move-error partial with a 422, retargeted
+// at the board's error banner
+func writePolicyViolation(w http.ResponseWriter, violation *PolicyViolation) {
+	w.Header().Set("HX-Retarget", "#move-error")
+	w.Header().Set("HX-Reswap", "innerHTML")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	templates.ExecuteTemplate(w, "move-error.html", violation)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestMoveTaskEnforcesAcceptPolicy(t *testing.T) {
+	s := newTestStore()
+	s.settings = withTestSettings(t)
+	task := s.AddTask("Review me", "")
+
+	if err := s.settings.Merge(map[string]string{"accept_from.done": "doing"}); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	_, _, err := s.MoveTask(task.ID, "done")
+	var violation *PolicyViolation
+	if !errors.As(err, &violation) || violation.From != "todo" || violation.To != "done" {
+		t.Fatalf("Expected a PolicyViolation from todo to done, got %v", err)
+	}
+	if violation.Reason != `"done" only accepts tasks from "doing", not "todo"` {
+		t.Errorf("Unexpected reason %q", violation.Reason)
+	}
+	if got, _ := s.GetTask(task.ID); got.Status != "todo" {
+		t.Errorf("Refused move must leave the task in todo")
+	}
+
+	if _, _, err := s.MoveTask(task.ID, "doing"); err != nil {
+		t.Fatalf("Columns without a policy accept any move: %v", err)
+	}
+	if _, _, err := s.MoveTask(task.ID, "done"); err != nil {
+		t.Errorf("Expected done to accept a task from doing: %v", err)
+	}
+
+	// The policy is checked before WIP limits
+	other := s.AddTask("Other", "")
+	s.AddTask("Filler", "")
+	s.MoveTask(other.ID, "doing")
+	s.settings.Merge(map[string]string{"accept_from.todo": "", "wip_limit.todo": "1"})
+	if _, _, err := s.MoveTask(other.ID, "todo"); !errors.As(err, &violation) || !strings.Contains(violation.Reason, "any other column") {
+		t.Errorf("Expected todo to refuse every move, got %v", err)
+	}
+}
+
+func TestAcceptPolicySettingValidation(t *testing.T) {
+	ss := withTestSettings(t)
+	if err := ss.Merge(map[string]string{"accept_from.done": "doing,review"}); err == nil {
+		t.Errorf("Expected an unknown source status to be rejected")
+	}
+	if err := ss.Merge(map[string]string{"accept_from.done": "todo, doing"}); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if sources := ss.TransitionPolicy()["done"]; len(sources) != 2 || sources[1] != "doing" {
+		t.Errorf("Expected done to accept from todo and doing, got %v", sources)
+	}
+	var nilStore *SettingsStore
+	if nilStore.TransitionPolicy().check("todo", "done") != nil {
+		t.Errorf("A nil settings store has no policy")
+	}
+}
+
+func TestMoveTaskHandlerReturnsPolicyErrorPartial(t *testing.T) {
+	s := withTestGlobals(t)
+	s.settings = withTestSettings(t)
+	s.settings.Merge(map[string]string{"accept_from.done": "doing"})
+	task := s.AddTask("Skipper", "")
+
+	form := url.Values{"id": {"1"}, "status": {"done"}}
+	req := httptest.NewRequest(http.MethodPost, "/move-task", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	NewServer(s).moveTaskHandler(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422, got %d", w.Code)
+	}
+	if w.Header().Get("HX-Retarget") != "#move-error" {
+		t.Errorf("Expected the response to retarget the error banner, got %q", w.Header().Get("HX-Retarget"))
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `class="move-error-message"`) || !strings.Contains(body, "&#34;done&#34; only accepts tasks from &#34;doing&#34;, not &#34;todo&#34;") {
+		t.Errorf("Expected the error partial with the reason, got %s", body)
+	}
+	if got, _ := s.GetTask(task.ID); got.Status != "todo" {
+		t.Errorf("Refused move must leave the task in todo")
+	}
+}
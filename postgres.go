@@ -0,0 +1,336 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+const postgresColumns = `id, title, description, status, assignee, effort, priority, due_date, labels, checklist, position, created_at, updated_at, moved_at, status_changed_at`
+
+// defaultPostgresMaxConns is the connection pool size when
+// KANBAN_DB_MAX_CONNS is not set
+const defaultPostgresMaxConns = 10
+
+// PostgresBackend keeps tasks in a Postgres database. Unlike SQLite, Postgres
+// handles concurrent writers, so it keeps a pool of connections and several
+// server instances can share one database. Like TaskStore, errors are logged
+// and reported as a missing result.
+type PostgresBackend struct {
+	db  *sql.DB
+	now func() time.Time // overridable clock for tests
+}
+
+var (
+	_ TaskRepository = (*PostgresBackend)(nil)
+	_ Backend        = (*PostgresBackend)(nil)
+	_ idAllocator    = (*PostgresBackend)(nil)
+	_ Store          = (*PostgresStore)(nil)
+)
+
+// PostgresStore is the full Store kept in Postgres: a TaskStore that loads
+// the board from a PostgresBackend and mirrors every change back to it, so
+// handlers serve it from memory like any other Store while the database
+// holds the board for every instance sharing it
+type PostgresStore struct {
+	*TaskStore
+}
+
+// NewPostgresStore keeps s's board in the database at url, with a pool of up
+// to maxConns connections. The board is read by s.LoadFromFile as usual.
+func NewPostgresStore(s *TaskStore, url string, maxConns int) (*PostgresStore, error) {
+	backend, err := NewPostgresBackend(url, maxConns)
+	if err != nil {
+		return nil, err
+	}
+	s.attachBackend(backend)
+	return &PostgresStore{TaskStore: s}, nil
+}
+
+// NewPostgresBackend connects to the database at url with a pool of up to
+// maxConns connections and migrates it to the latest schema
+func NewPostgresBackend(url string, maxConns int) (*PostgresBackend, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+	if maxConns < 1 {
+		maxConns = defaultPostgresMaxConns
+	}
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxConns)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := NewPostgresMigrationRunner(db).Run(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not migrate schema: %w", err)
+	}
+	return &PostgresBackend{db: db}, nil
+}
+
+// Close closes the connection pool
+func (p *PostgresBackend) Close() error {
+	return p.db.Close()
+}
+
+func (p *PostgresBackend) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
+
+// NextID reserves a task ID from the tasks sequence, so instances sharing
+// the database never hand out the same ID
+func (p *PostgresBackend) NextID() (int, error) {
+	var id int
+	err := p.db.QueryRow(`SELECT nextval(pg_get_serial_sequence('tasks', 'id'))`).Scan(&id)
+	return id, err
+}
+
+// AddTask adds a new task to the "todo" column, returning nil if the
+// backend rejects it
+func (p *PostgresBackend) AddTask(title, description string) *Task {
+	task, err := p.CreateTask(TaskSpec{Title: title, Description: description})
+	if err != nil {
+		log.Printf("Error creating task: %v", err)
+	}
+	return task
+}
+
+// CreateTask inserts a task built from a spec, letting Postgres assign the ID
+func (p *PostgresBackend) CreateTask(spec TaskSpec) (*Task, error) {
+	status := spec.Status
+	if status == "" {
+		status = "todo"
+	}
+	task := &Task{
+		Title:       spec.Title,
+		Description: spec.Description,
+		Status:      status,
+		Assignee:    spec.Assignee,
+		Effort:      spec.Effort,
+		Priority:    spec.Priority,
+		DueDate:     spec.DueDate,
+		Labels:      spec.Labels,
+		CreatedAt:   p.clock(),
+	}
+	task.UpdatedAt = task.CreatedAt
+	task.StatusChangedAt = task.CreatedAt
+
+	args, err := postgresTaskArgs(task)
+	if err != nil {
+		return nil, fmt.Errorf("encoding task: %w", err)
+	}
+	err = p.db.QueryRow(`INSERT INTO tasks (title, description, status, assignee, effort, priority, due_date, labels, checklist, position, created_at, updated_at, moved_at, status_changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`, args[1:]...).Scan(&task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("inserting task: %w", err)
+	}
+	return task, nil
+}
+
+// SaveTask inserts or replaces a task, keeping its ID
+func (p *PostgresBackend) SaveTask(task *Task) error {
+	args, err := postgresTaskArgs(task)
+	if err != nil {
+		return err
+	}
+	_, err = p.db.Exec(`INSERT INTO tasks (`+postgresColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (id) DO UPDATE SET
+			title = EXCLUDED.title, description = EXCLUDED.description, status = EXCLUDED.status,
+			assignee = EXCLUDED.assignee, effort = EXCLUDED.effort, priority = EXCLUDED.priority,
+			due_date = EXCLUDED.due_date, labels = EXCLUDED.labels, checklist = EXCLUDED.checklist,
+			position = EXCLUDED.position, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at,
+			moved_at = EXCLUDED.moved_at, status_changed_at = EXCLUDED.status_changed_at`, args...)
+	return err
+}
+
+// GetTask retrieves a task by ID
+func (p *PostgresBackend) GetTask(id int) (*Task, bool) {
+	task, err := scanPostgresTask(p.db.QueryRow(`SELECT `+postgresColumns+` FROM tasks WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Error reading task %d: %v", id, err)
+		return nil, false
+	}
+	return task, true
+}
+
+// GetTasksByStatus returns all tasks with a specific status in board order
+func (p *PostgresBackend) GetTasksByStatus(status string) []*Task {
+	return p.queryTasks(`SELECT `+postgresColumns+` FROM tasks WHERE status = $1 ORDER BY position, id`, status)
+}
+
+// GetAllTasks returns every task ordered by ID
+func (p *PostgresBackend) GetAllTasks() []*Task {
+	return p.queryTasks(`SELECT ` + postgresColumns + ` FROM tasks ORDER BY id`)
+}
+
+func (p *PostgresBackend) queryTasks(query string, args ...interface{}) []*Task {
+	tasks := []*Task{}
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error querying tasks: %v", err)
+		return tasks
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := scanPostgresTask(rows)
+		if err != nil {
+			log.Printf("Error reading task: %v", err)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error querying tasks: %v", err)
+	}
+	return tasks
+}
+
+// MoveTask changes the status of a task in a single statement, so concurrent
+// moves can't leave it half-updated
+func (p *PostgresBackend) MoveTask(id int, newStatus string) (*Task, bool, error) {
+	task, err := scanPostgresTask(p.db.QueryRow(`UPDATE tasks SET
+			moved_at = CASE WHEN status = $1 THEN moved_at ELSE $2 END,
+			status_changed_at = CASE WHEN status = $1 THEN status_changed_at ELSE $2 END,
+			status = $1, updated_at = $2
+		WHERE id = $3 RETURNING `+postgresColumns, newStatus, p.clock(), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	return task, true, nil
+}
+
+// DeleteTask removes a task
+func (p *PostgresBackend) DeleteTask(id int) (bool, error) {
+	res, err := p.db.Exec(`DELETE FROM tasks WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("deleting task %d: %w", id, err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Load reads the whole board along with the next ID to hand out. New IDs
+// come from NextID, so the value returned here only covers existing tasks.
+func (p *PostgresBackend) Load() (map[int]*Task, int, error) {
+	tasks := make(map[int]*Task)
+	next := 1
+	for _, task := range p.GetAllTasks() {
+		tasks[task.ID] = task
+		if task.ID >= next {
+			next = task.ID + 1
+		}
+	}
+	return tasks, next, nil
+}
+
+// saveExtras replaces the board's links, comments, reactions and
+// relationships
+func (p *PostgresBackend) saveExtras(data PersistentData) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = p.db.Exec(`INSERT INTO board_extras (id, data) VALUES (1, $1)
+		ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data`, string(encoded))
+	return err
+}
+
+// loadExtras reads what saveExtras last saved, or nothing if it never ran
+func (p *PostgresBackend) loadExtras() (PersistentData, error) {
+	var data PersistentData
+	var encoded string
+	err := p.db.QueryRow(`SELECT data FROM board_extras WHERE id = 1`).Scan(&encoded)
+	if errors.Is(err, sql.ErrNoRows) {
+		return data, nil
+	}
+	if err != nil {
+		return data, err
+	}
+	return data, json.Unmarshal([]byte(encoded), &data)
+}
+
+// mirror applies a TaskStore event to the database
+func (p *PostgresBackend) mirror(e Event) {
+	if e.Task == nil {
+		return
+	}
+	var err error
+	switch e.Type {
+	case EventTaskCreated, EventTaskUpdated, EventTaskMoved:
+		err = p.SaveTask(e.Task)
+	case EventTaskDeleted:
+		_, err = p.db.Exec(`DELETE FROM tasks WHERE id = $1`, e.Task.ID)
+	}
+	if err != nil {
+		log.Printf("Error mirroring %s for task %d to postgres: %v", e.Type, e.Task.ID, err)
+	}
+}
+
+// postgresTaskArgs returns a task's values in postgresColumns order
+func postgresTaskArgs(task *Task) ([]interface{}, error) {
+	labels, err := json.Marshal(task.Labels)
+	if err != nil {
+		return nil, err
+	}
+	checklist, err := json.Marshal(task.Checklist)
+	if err != nil {
+		return nil, err
+	}
+	var due, moved interface{}
+	if task.DueDate != nil {
+		due = *task.DueDate
+	}
+	if task.MovedAt != nil {
+		moved = *task.MovedAt
+	}
+	return []interface{}{
+		task.ID, task.Title, task.Description, task.Status, task.Assignee,
+		task.Effort, task.Priority, due, string(labels), string(checklist),
+		task.Position, task.CreatedAt, task.UpdatedAt, moved, task.StatusChangedAt,
+	}, nil
+}
+
+// scanPostgresTask reads a row selected with postgresColumns
+func scanPostgresTask(row rowScanner) (*Task, error) {
+	var (
+		task              Task
+		due, moved        sql.NullTime
+		labels, checklist []byte
+	)
+	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.Assignee,
+		&task.Effort, &task.Priority, &due, &labels, &checklist, &task.Position,
+		&task.CreatedAt, &task.UpdatedAt, &moved, &task.StatusChangedAt)
+	if err != nil {
+		return nil, err
+	}
+	if due.Valid {
+		task.DueDate = &due.Time
+	}
+	if moved.Valid {
+		task.MovedAt = &moved.Time
+	}
+	if err := json.Unmarshal(labels, &task.Labels); err != nil {
+		return nil, fmt.Errorf("task %d: invalid labels", task.ID)
+	}
+	if err := json.Unmarshal(checklist, &task.Checklist); err != nil {
+		return nil, fmt.Errorf("task %d: invalid checklist", task.ID)
+	}
+	return &task, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestPostgresBackend connects to TEST_POSTGRES_URL, skipping the test when
+// it isn't set. The database's tasks and migrations tables are dropped first,
+// so point it at a throwaway database.
+func newTestPostgresBackend(t *testing.T) *PostgresBackend {
+	url := os.Getenv("TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("TEST_POSTGRES_URL not set")
+	}
+	pg, err := NewPostgresBackend(url, 2)
+	if err != nil {
+		t.Fatalf("NewPostgresBackend failed: %v", err)
+	}
+	if _, err := pg.db.Exec(`DROP TABLE IF EXISTS tasks, board_extras, migrations`); err != nil {
+		t.Fatal(err)
+	}
+	pg.Close()
+
+	pg, err = NewPostgresBackend(url, 2)
+	if err != nil {
+		t.Fatalf("NewPostgresBackend failed: %v", err)
+	}
+	t.Cleanup(func() { pg.Close() })
+	return pg
+}
+
+func TestPostgresMigrationsAreSeparate(t *testing.T) {
+	pgMigrations, err := NewPostgresMigrationRunner(nil).Migrations()
+	if err != nil || len(pgMigrations) == 0 || pgMigrations[0].ID != 1 {
+		t.Fatalf("Expected postgres migrations starting at 1, got %v (%v)", pgMigrations, err)
+	}
+	if !strings.Contains(pgMigrations[0].SQL, "BIGSERIAL") {
+		t.Errorf("Expected the postgres schema to use postgres types")
+	}
+	sqliteMigrations, _ := NewMigrationRunner(nil).Migrations()
+	for _, m := range sqliteMigrations {
+		if strings.Contains(m.SQL, "BIGSERIAL") {
+			t.Errorf("SQLite migrations must not include the postgres scripts, found %s", m.Name)
+		}
+	}
+}
+
+func TestPostgresStoreCRUD(t *testing.T) {
+	pg := newTestPostgresBackend(t)
+	due := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	a := pg.AddTask("A", "first")
+	b, _ := pg.CreateTask(TaskSpec{Title: "B", Status: "doing", Priority: PriorityHigh, DueDate: &due, Labels: []string{"x", "y"}})
+	if a == nil || b == nil || a.ID != 1 || b.ID != 2 {
+		t.Fatalf("Expected IDs 1 and 2, got %+v and %+v", a, b)
+	}
+
+	got, ok := pg.GetTask(b.ID)
+	if !ok || got.Priority != PriorityHigh || !got.DueDate.Equal(due) || len(got.Labels) != 2 || got.Labels[1] != "y" {
+		t.Errorf("Task did not round-trip: %+v", got)
+	}
+	if got, _ := pg.GetTask(a.ID); got.DueDate != nil {
+		t.Errorf("Expected NULL due_date to load as nil, got %v", got.DueDate)
+	}
+
+	moved, ok, err := pg.MoveTask(a.ID, "done")
+	if !ok || err != nil {
+		t.Fatalf("MoveTask failed: ok=%v err=%v", ok, err)
+	}
+	if moved.MovedAt == nil || moved.Status != "done" {
+		t.Errorf("Expected MoveTask to record moved_at, got %+v", moved)
+	}
+	if done := taskIDs(pg.GetTasksByStatus("done")); !equalIDs(done, []int{1}) {
+		t.Errorf("Expected done [1], got %v", done)
+	}
+	if _, ok, _ := pg.MoveTask(99, "done"); ok {
+		t.Errorf("Expected missing task to report not found")
+	}
+
+	if ok, err := pg.DeleteTask(b.ID); !ok || err != nil {
+		t.Errorf("Expected delete to succeed, got %v, %v", ok, err)
+	}
+	if ok, _ := pg.DeleteTask(b.ID); ok {
+		t.Errorf("Expected a second delete to find nothing")
+	}
+	if all := taskIDs(pg.GetAllTasks()); !equalIDs(all, []int{1}) {
+		t.Errorf("Expected all tasks [1], got %v", all)
+	}
+}
+
+func TestTaskStoreMirrorsToPostgres(t *testing.T) {
+	pg := newTestPostgresBackend(t)
+	s := withTestGlobals(t)
+	s.backend = pg
+	bus.Subscribe(EventAll, pg.mirror)
+
+	task := s.AddTask("Stored", "")
+	s.MoveTask(task.ID, "doing")
+	s.AssignTask(task.ID, "alice")
+	s.AddChecklistItem(task.ID, "Check")
+	other := s.AddTask("Temporary", "")
+	s.DeleteTask(other.ID)
+
+	// A second instance sharing the database sees the same board
+	second := newTestStore()
+	second.backend = pg
+	if err := second.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	loaded, ok := second.GetTask(task.ID)
+	if !ok || loaded.Status != "doing" || loaded.Assignee != "alice" || len(loaded.Checklist) != 1 {
+		t.Errorf("Expected mirrored task in doing assigned to alice, got %+v", loaded)
+	}
+	if len(second.GetAllTasks()) != 1 {
+		t.Errorf("Expected deleted task to be gone, got %d tasks", len(second.GetAllTasks()))
+	}
+
+	// IDs come from the shared sequence, so instances never collide
+	if created := second.AddTask("From second", ""); created.ID != 3 {
+		t.Errorf("Expected ID 3 from the shared sequence, got %d", created.ID)
+	}
+}
+
+func TestPostgresStoreServesHandlers(t *testing.T) {
+	newTestPostgresBackend(t) // start from an empty database
+	pg, err := NewPostgresStore(withTestGlobals(t), os.Getenv("TEST_POSTGRES_URL"), 2)
+	if err != nil {
+		t.Fatalf("NewPostgresStore failed: %v", err)
+	}
+	t.Cleanup(func() { pg.Close() })
+	srv := NewServer(pg)
+
+	form := url.Values{"title": {"Through the handler"}}
+	req := httptest.NewRequest(http.MethodPost, "/add-task", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	srv.addTaskHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// The task reached the database, not just the store's memory
+	second := newTestStore()
+	second.backend = pg.backend
+	if err := second.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	if tasks := second.GetAllTasks(); len(tasks) != 1 || tasks[0].Title != "Through the handler" {
+		t.Errorf("Expected the handler's task in postgres, got %+v", tasks)
+	}
+}
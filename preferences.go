@@ -0,0 +1,134 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// preferencesCookie names the cookie identifying a browser's preferences
+const preferencesCookie = "kanban_session"
+
+// UserPreferences is one browser's display preferences
+type UserPreferences struct {
+	CollapsedTasks map[int]bool
+}
+
+// PreferenceStore keeps preferences in memory, keyed by session ID. Sessions
+// are only created once someone changes a preference.
+type PreferenceStore struct {
+	mu       sync.Mutex
+	sessions map[string]*UserPreferences
+}
+
+var preferences = NewPreferenceStore()
+
+// NewPreferenceStore returns a store with no sessions
+func NewPreferenceStore() *PreferenceStore {
+	return &PreferenceStore{sessions: make(map[string]*UserPreferences)}
+}
+
+// Get returns a copy of a session's preferences
+func (ps *PreferenceStore) Get(session string) UserPreferences {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	prefs := UserPreferences{CollapsedTasks: make(map[int]bool)}
+	if stored, ok := ps.sessions[session]; ok {
+		for id := range stored.CollapsedTasks {
+			prefs.CollapsedTasks[id] = true
+		}
+	}
+	return prefs
+}
+
+// SetCollapsed collapses or expands a task's card for a session
+func (ps *PreferenceStore) SetCollapsed(session string, taskID int, collapsed bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	prefs, ok := ps.sessions[session]
+	if !ok {
+		prefs = &UserPreferences{CollapsedTasks: make(map[int]bool)}
+		ps.sessions[session] = prefs
+	}
+	if collapsed {
+		prefs.CollapsedTasks[taskID] = true
+	} else {
+		delete(prefs.CollapsedTasks, taskID)
+	}
+}
+
+// requestPreferences returns the preferences of the request's session, empty
+// if it has none
+func requestPreferences(r *http.Request) UserPreferences {
+	cookie, err := r.Cookie(preferencesCookie)
+	if err != nil {
+		return UserPreferences{CollapsedTasks: map[int]bool{}}
+	}
+	return preferences.Get(cookie.Value)
+}
+
+// ensureSession returns the request's session ID, starting a session with a
+// new cookie if it has none
+func ensureSession(w http.ResponseWriter, r *http.Request) (string, error) {
+	if cookie, err := r.Cookie(preferencesCookie); err == nil && cookie.Value != "" {
+		return cookie.Value, nil
+	}
+	session, err := newInviteToken()
+	if err != nil {
+		return "", err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     preferencesCookie,
+		Value:    session,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return session, nil
+}
+
+// collapsedIDs returns the collapsed task IDs in order, for cache keys
+func (p UserPreferences) collapsedIDs() []int {
+	ids := make([]int, 0, len(p.CollapsedTasks))
+	for id := range p.CollapsedTasks {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// withCollapsed marks which of the board's cards the request's session has
+// collapsed
+func withCollapsed(r *http.Request, data BoardData) BoardData {
+	collapsed := requestPreferences(r).CollapsedTasks
+	for i := range data.Columns {
+		data.Columns[i].Collapsed = collapsed
+	}
+	return data
+}
+
+// taskCollapseHandler serves POST /task/{id}/collapse and /task/{id}/expand,
+// remembering the choice for the session and returning the task's column
+func (srv *Server) taskCollapseHandler(w http.ResponseWriter, r *http.Request, id int, collapsed bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	task, ok := srv.GetTask(id)
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	session, err := ensureSession(w, r)
+	if err != nil {
+		http.Error(w, "Could not start a session", http.StatusInternalServerError)
+		return
+	}
+	preferences.SetCollapsed(session, id, collapsed)
+
+	col := srv.GetColumnData(task.Status)
+	col.Collapsed = preferences.Get(session).CollapsedTasks
+	templates.ExecuteTemplate(w, "column-content.html", col)
+}
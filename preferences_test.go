@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCollapseTaskCard(t *testing.T) {
+	s := withTestGlobals(t)
+	origPreferences := preferences
+	defer func() { preferences = origPreferences }()
+	preferences = NewPreferenceStore()
+
+	s.AddTask("Verbose", "A very long description")
+	s.AddTask("Short", "Also described")
+
+	rr := httptest.NewRecorder()
+	NewServer(s).taskRouter(rr, httptest.NewRequest(http.MethodPost, "/task/1/collapse", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != preferencesCookie {
+		t.Fatalf("Expected a session cookie, got %v", cookies)
+	}
+
+	column := func(cookie *http.Cookie) string {
+		req := httptest.NewRequest(http.MethodGet, "/column/todo", nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		rr := httptest.NewRecorder()
+		NewServer(s).columnHandler(rr, req)
+		return rr.Body.String()
+	}
+	body := column(cookies[0])
+	if !strings.Contains(body, `class="task-card collapsed" data-id="1"`) || !strings.Contains(body, `hx-post="/task/1/expand"`) {
+		t.Errorf("Expected task 1 to render collapsed:\n%s", body)
+	}
+	if strings.Contains(body, "A very long description") {
+		t.Errorf("Expected the collapsed card to hide its description")
+	}
+	if !strings.Contains(body, "Also described") || !strings.Contains(body, `hx-post="/task/2/collapse"`) {
+		t.Errorf("Expected task 2 to stay expanded")
+	}
+	if body := column(nil); strings.Contains(body, "collapsed") {
+		t.Errorf("Expected another browser to see every card expanded")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/task/1/expand", nil)
+	req.AddCookie(cookies[0])
+	rr = httptest.NewRecorder()
+	NewServer(s).taskRouter(rr, req)
+	if len(rr.Result().Cookies()) != 0 {
+		t.Errorf("Expected the existing session to be reused")
+	}
+	if body := column(cookies[0]); strings.Contains(body, "collapsed") {
+		t.Errorf("Expected task 1 to be expanded again")
+	}
+
+	rr = httptest.NewRecorder()
+	NewServer(s).taskRouter(rr, httptest.NewRequest(http.MethodPost, "/task/99/collapse", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown task, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// printExcerptLength caps the description shown on a printed card
+const printExcerptLength = 100
+
+// PrintCard is one task laid out for a printed card
+type PrintCard struct {
+	*Task
+	Excerpt string
+	URL     string // printed in place of a QR code
+}
+
+// PrintColumn groups the printed cards of one column
+type PrintColumn struct {
+	DisplayName string
+	Cards       []PrintCard
+}
+
+// truncateText shortens s to at most max characters, ending in "…" when cut
+func truncateText(s string, max int) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:max])) + "…"
+}
+
+// printColumns builds the cards of the given statuses, or every column when
+// none are given. baseURL prefixes each card's task link.
+func printColumns(data BoardData, statuses []string, baseURL string) []PrintColumn {
+	var columns []PrintColumn
+	for _, col := range data.Columns {
+		if len(statuses) > 0 && !containsString(statuses, col.Status) {
+			continue
+		}
+		column := PrintColumn{DisplayName: col.DisplayName}
+		for _, task := range col.Tasks {
+			column.Cards = append(column.Cards, PrintCard{
+				Task:    task,
+				Excerpt: truncateText(task.Description, printExcerptLength),
+				URL:     fmt.Sprintf("%s/task/%d/history", baseURL, task.ID),
+			})
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// printHandler serves GET /print, a page of uniform cards for physical
+// boards. ?status=todo,doing limits it to those columns.
+func (srv *Server) printHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	statuses := parseLabels(r.URL.Query().Get("status"))
+	for _, status := range statuses {
+		if !isValidStatus(status) {
+			http.Error(w, fmt.Sprintf("Invalid status %q", status), http.StatusBadRequest)
+			return
+		}
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	templates.ExecuteTemplate(w, "print.html", printColumns(srv.GetBoardData(), statuses, scheme+"://"+r.Host))
+}
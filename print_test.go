@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrintHandlerFiltersByStatus(t *testing.T) {
+	s := withTestGlobals(t)
+	s.CreateTask(TaskSpec{Title: "Todo card", Description: strings.Repeat("d", 150), Priority: PriorityHigh})
+	s.CreateTask(TaskSpec{Title: "Doing card", Status: "doing"})
+	s.CreateTask(TaskSpec{Title: "Done card", Status: "done"})
+
+	w := httptest.NewRecorder()
+	NewServer(s).printHandler(w, httptest.NewRequest(http.MethodGet, "/print?status=todo,doing", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "Todo card") || !strings.Contains(body, "Doing card") || strings.Contains(body, "Done card") {
+		t.Errorf("Expected only todo and doing cards, got %s", body)
+	}
+	if !strings.Contains(body, strings.Repeat("d", printExcerptLength)+"…") || strings.Contains(body, strings.Repeat("d", printExcerptLength+1)) {
+		t.Errorf("Expected the description truncated to %d characters", printExcerptLength)
+	}
+	if !strings.Contains(body, `<span class="print-card-qr">http://example.com/task/1/history</span>`) {
+		t.Errorf("Expected a QR placeholder with the task URL")
+	}
+	if !strings.Contains(body, "#1") || !strings.Contains(body, "High") || strings.Contains(body, "<script") {
+		t.Errorf("Expected the ID and priority on a script-free page")
+	}
+
+	w = httptest.NewRecorder()
+	NewServer(s).printHandler(w, httptest.NewRequest(http.MethodGet, "/print?status=review", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown status, got %d", w.Code)
+	}
+}
+
+func TestPrintHandlerEmptyBoard(t *testing.T) {
+	withTestGlobals(t)
+
+	w := httptest.NewRecorder()
+	NewServer(store).printHandler(w, httptest.NewRequest(http.MethodGet, "/print", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if n := strings.Count(w.Body.String(), `class="print-column"`); n != len(boardColumns) {
+		t.Errorf("Expected every column on an unfiltered print, got %d", n)
+	}
+}
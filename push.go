@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+)
+
+// pushAssets are the files the board page links to, pushed ahead of the HTML
+// over HTTP/2. htmx and SortableJS load from a CDN, which can't be pushed.
+var pushAssets = []string{"/static/style.css", "/static/app.js"}
+
+// canPush returns the connection's http.Pusher, if it supports server push.
+// HTTP/2 connections do, which Go only serves over TLS.
+func canPush(w http.ResponseWriter) (http.Pusher, bool) {
+	pusher, ok := w.(http.Pusher)
+	return pusher, ok
+}
+
+// pushPageAssets pushes the board's static files unless the client opted out
+// with "Accept-Push: false"
+func pushPageAssets(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept-Push") == "false" {
+		return
+	}
+	pusher, ok := canPush(w)
+	if !ok {
+		return
+	}
+	for _, asset := range pushAssets {
+		if err := pusher.Push(asset, nil); err != nil {
+			if !errors.Is(err, http.ErrNotSupported) {
+				log.Printf("Error pushing %s: %v", asset, err)
+			}
+			return
+		}
+	}
+}
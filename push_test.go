@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// pushRecorder is a ResponseWriter that supports server push
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+}
+
+func (p *pushRecorder) Push(target string, opts *http.PushOptions) error {
+	p.pushed = append(p.pushed, target)
+	return nil
+}
+
+func TestIndexHandlerPushesAssets(t *testing.T) {
+	withTestGlobals(t)
+
+	w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	NewServer(store).indexHandler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.Join(w.pushed, " ") != "/static/style.css /static/app.js" {
+		t.Errorf("Expected the CSS and JS to be pushed, got %v", w.pushed)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the page to render after pushing, got %d", w.Code)
+	}
+
+	// Pushes survive the logging middleware's wrapper
+	w = &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	loggingMiddleware(http.HandlerFunc(NewServer(store).indexHandler)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if len(w.pushed) != 2 {
+		t.Errorf("Expected pushes through the middleware, got %v", w.pushed)
+	}
+}
+
+func TestIndexHandlerPushOptOut(t *testing.T) {
+	withTestGlobals(t)
+
+	w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Push", "false")
+	NewServer(store).indexHandler(w, req)
+	if len(w.pushed) != 0 {
+		t.Errorf("Expected no pushes with Accept-Push: false, got %v", w.pushed)
+	}
+
+	if _, ok := canPush(httptest.NewRecorder()); ok {
+		t.Errorf("Expected a plain ResponseWriter not to support push")
+	}
+}
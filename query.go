@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxQueryValueLength caps each filter value accepted by /metrics/custom
+const maxQueryValueLength = 100
+
+// QueryParams is a parsed /metrics/custom query. Within a dimension any value
+// matches; every non-empty dimension must match.
+type QueryParams struct {
+	Statuses   []string
+	Priorities []int
+	Assignees  []string
+	Labels     []string
+	AgeGT      time.Duration // time since last update
+	AgeLT      time.Duration
+}
+
+// Explain describes the parsed query for ?explain=true, with ages rendered
+// as durations rather than nanoseconds
+func (p QueryParams) Explain() map[string]interface{} {
+	explained := map[string]interface{}{
+		"statuses":   p.Statuses,
+		"priorities": p.Priorities,
+		"assignees":  p.Assignees,
+		"labels":     p.Labels,
+	}
+	if p.AgeGT > 0 {
+		explained["age_gt"] = p.AgeGT.String()
+	}
+	if p.AgeLT > 0 {
+		explained["age_lt"] = p.AgeLT.String()
+	}
+	return explained
+}
+
+// QueryResult summarises the tasks matched by a query
+type QueryResult struct {
+	Count       int     `json:"count"`
+	TotalEffort int     `json:"total_effort"`
+	Tasks       []*Task `json:"tasks"`
+}
+
+// ParseQueryParams reads filter=field:value,... plus age_gt and age_lt.
+// Supported fields are status, priority, assignee, and label.
+func ParseQueryParams(q url.Values) (QueryParams, error) {
+	var params QueryParams
+	for _, term := range strings.Split(q.Get("filter"), ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		field, value, ok := strings.Cut(term, ":")
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			return params, fmt.Errorf("invalid filter term %q, expected field:value", term)
+		}
+		if len(value) > maxQueryValueLength {
+			return params, fmt.Errorf("filter value for %s is too long", field)
+		}
+
+		switch strings.TrimSpace(field) {
+		case "status":
+			if !isValidStatus(value) {
+				return params, fmt.Errorf("invalid status %q", value)
+			}
+			params.Statuses = append(params.Statuses, value)
+		case "priority":
+			priority, err := strconv.Atoi(value)
+			if err != nil || priority < PriorityNone || priority > PriorityHigh {
+				return params, fmt.Errorf("invalid priority %q", value)
+			}
+			params.Priorities = append(params.Priorities, priority)
+		case "assignee":
+			params.Assignees = append(params.Assignees, value)
+		case "label":
+			params.Labels = append(params.Labels, value)
+		default:
+			return params, fmt.Errorf("unknown filter field %q", field)
+		}
+	}
+
+	var err error
+	if params.AgeGT, err = parseQueryDuration(q, "age_gt"); err != nil {
+		return params, err
+	}
+	if params.AgeLT, err = parseQueryDuration(q, "age_lt"); err != nil {
+		return params, err
+	}
+	return params, nil
+}
+
+// parseQueryDuration reads an optional non-negative Go duration such as 72h
+func parseQueryDuration(q url.Values, key string) (time.Duration, error) {
+	raw := q.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, raw)
+	}
+	return d, nil
+}
+
+// QueryEngine answers ad-hoc KPI queries against a task store
+type QueryEngine struct {
+	store Store
+}
+
+// Run returns the tasks matching params along with their count and effort.
+// Age is measured from each task's last update, which includes its last move.
+func (qe *QueryEngine) Run(params QueryParams) QueryResult {
+	tasks := qe.store.FilterTasks(FilterOptions{
+		Statuses:  params.Statuses,
+		Labels:    params.Labels,
+		Assignees: params.Assignees,
+	})
+	now := qe.store.Now()
+
+	result := QueryResult{Tasks: []*Task{}}
+	for _, task := range tasks {
+		if len(params.Priorities) > 0 && !containsInt(params.Priorities, task.Priority) {
+			continue
+		}
+		age := now.Sub(task.UpdatedAt)
+		if params.AgeGT > 0 && age <= params.AgeGT {
+			continue
+		}
+		if params.AgeLT > 0 && age >= params.AgeLT {
+			continue
+		}
+		result.Tasks = append(result.Tasks, task)
+		result.TotalEffort += task.Effort
+	}
+	result.Count = len(result.Tasks)
+	return result
+}
+
+func containsInt(list []int, value int) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// customMetricsHandler serves GET /metrics/custom
+func (srv *Server) customMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params, err := ParseQueryParams(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if explain, _ := strconv.ParseBool(r.URL.Query().Get("explain")); explain {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"query": params.Explain()})
+		return
+	}
+
+	engine := &QueryEngine{store: srv.Store}
+	writeJSON(w, http.StatusOK, engine.Run(params))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// newQueryTestStore builds a board whose tasks were last updated at
+// different ages relative to a fixed clock
+func newQueryTestStore() *TaskStore {
+	s := newTestStore()
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	clock := now.Add(-5 * 24 * time.Hour)
+	s.now = func() time.Time { return clock }
+
+	old, _ := s.CreateTask(TaskSpec{Title: "Old high", Priority: PriorityHigh, Effort: 5, Assignee: "alice", Labels: []string{"bug"}})
+	s.MoveTask(old.ID, "doing")
+	s.CreateTask(TaskSpec{Title: "Old low", Priority: PriorityLow, Effort: 1, Status: "doing", Assignee: "bob"})
+
+	clock = now.Add(-time.Hour)
+	s.CreateTask(TaskSpec{Title: "New high", Priority: PriorityHigh, Effort: 3, Status: "doing", Assignee: "bob", Labels: []string{"bug"}})
+	s.CreateTask(TaskSpec{Title: "Todo high", Priority: PriorityHigh, Effort: 2, Assignee: "alice"})
+
+	clock = now
+	return s
+}
+
+func runQuery(t *testing.T, s *TaskStore, raw string) QueryResult {
+	t.Helper()
+	q, _ := url.ParseQuery(raw)
+	params, err := ParseQueryParams(q)
+	if err != nil {
+		t.Fatalf("%s: unexpected error %v", raw, err)
+	}
+	return (&QueryEngine{store: s}).Run(params)
+}
+
+func TestQueryEngineDimensions(t *testing.T) {
+	s := newQueryTestStore()
+
+	cases := []struct {
+		query  string
+		ids    []int
+		effort int
+	}{
+		{"", []int{1, 2, 3, 4}, 11},
+		{"filter=status:doing", []int{1, 2, 3}, 9},
+		{"filter=priority:3", []int{1, 3, 4}, 10},
+		{"filter=priority:1,priority:3", []int{1, 2, 3, 4}, 11},
+		{"filter=assignee:alice", []int{1, 4}, 7},
+		{"filter=label:bug", []int{1, 3}, 8},
+		{"age_gt=72h", []int{1, 2}, 6},
+		{"age_lt=2h", []int{3, 4}, 5},
+		{"filter=status:doing,priority:3&age_gt=72h", []int{1}, 5},
+		{"filter=status:todo,label:bug", []int{}, 0},
+	}
+	for _, tc := range cases {
+		result := runQuery(t, s, tc.query)
+		if got := taskIDs(result.Tasks); !equalIDs(got, tc.ids) {
+			t.Errorf("%q: expected %v, got %v", tc.query, tc.ids, got)
+		}
+		if result.Count != len(tc.ids) || result.TotalEffort != tc.effort {
+			t.Errorf("%q: expected count %d effort %d, got %d/%d",
+				tc.query, len(tc.ids), tc.effort, result.Count, result.TotalEffort)
+		}
+	}
+}
+
+func TestParseQueryParamsRejectsInvalid(t *testing.T) {
+	invalid := []string{
+		"filter=status",
+		"filter=status:archived",
+		"filter=priority:9",
+		"filter=owner:alice",
+		"filter=assignee:",
+		"age_gt=3days",
+		"age_lt=-1h",
+	}
+	for _, raw := range invalid {
+		q, _ := url.ParseQuery(raw)
+		if _, err := ParseQueryParams(q); err == nil {
+			t.Errorf("Expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestCustomMetricsHandler(t *testing.T) {
+	orig := store
+	store = newQueryTestStore()
+	defer func() { store = orig }()
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics/custom?filter=status:doing,priority:3&age_gt=72h", nil)
+	w := httptest.NewRecorder()
+	NewServer(store).customMetricsHandler(w, req)
+	var result QueryResult
+	json.Unmarshal(w.Body.Bytes(), &result)
+	if w.Code != http.StatusOK || result.Count != 1 || result.TotalEffort != 5 {
+		t.Errorf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/metrics/custom?filter=status:doing&age_gt=72h&explain=true", nil)
+	w = httptest.NewRecorder()
+	NewServer(store).customMetricsHandler(w, req)
+	var explained struct {
+		Query map[string]interface{} `json:"query"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &explained)
+	if explained.Query["age_gt"] != "72h0m0s" {
+		t.Errorf("Expected explained age_gt, got %s", w.Body.String())
+	}
+	if _, ok := explained.Query["count"]; ok {
+		t.Errorf("Explain should not run the query")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/metrics/custom?filter=bogus:1", nil)
+	w = httptest.NewRecorder()
+	NewServer(store).customMetricsHandler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid filter, got %d", w.Code)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryAST is a parsed advanced search query. A task matches when it
+// matches every predicate; an empty query matches every task.
+type QueryAST struct {
+	Predicates []QueryPredicate
+}
+
+// QueryPredicate is one space-separated term of a query, such as
+// label:urgent or priority:>=2. Field is empty for a bare word, which
+// matches like the quick search.
+type QueryPredicate struct {
+	Field string
+	Op    string // "<", "<=", ">", ">=" or "=" for due and priority
+	Value string
+	Pos   int // 1-based column of the term in the query
+}
+
+// QueryError reports where a query stopped making sense
+type QueryError struct {
+	Pos    int // 1-based column
+	Reason string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos, e.Reason)
+}
+
+// queryFields are the fields a predicate can name
+var queryFields = []string{"title", "label", "assignee", "status", "due", "priority"}
+
+// queryOps are the comparisons due and priority accept, longest first so
+// that >= isn't read as >
+var queryOps = []string{"<=", ">=", "<", ">", "="}
+
+// ParseQuery parses a query like
+//
+//	title:"fix bug" label:urgent assignee:alice status:doing due:<2025-06-01 priority:>=2
+//
+// into predicates. Values containing spaces are double-quoted.
+func ParseQuery(q string) (QueryAST, error) {
+	var ast QueryAST
+	i := 0
+	for {
+		for i < len(q) && q[i] == ' ' {
+			i++
+		}
+		if i == len(q) {
+			return ast, nil
+		}
+
+		pred := QueryPredicate{Pos: i + 1}
+		if colon := strings.IndexByte(q[i:], ':'); colon > 0 && !strings.ContainsAny(q[i:i+colon], " \"") {
+			pred.Field = strings.ToLower(q[i : i+colon])
+			if !containsString(queryFields, pred.Field) {
+				return QueryAST{}, &QueryError{Pos: i + 1, Reason: fmt.Sprintf("unknown field %q, valid fields: %s",
+					pred.Field, strings.Join(queryFields, ", "))}
+			}
+			i += colon + 1
+		}
+		if pred.Field == "due" || pred.Field == "priority" {
+			pred.Op = "="
+			for _, op := range queryOps {
+				if strings.HasPrefix(q[i:], op) {
+					pred.Op = op
+					i += len(op)
+					break
+				}
+			}
+		}
+
+		valuePos := i + 1
+		value, next, err := readQueryValue(q, i)
+		if err != nil {
+			return QueryAST{}, err
+		}
+		i = next
+		if value == "" {
+			return QueryAST{}, &QueryError{Pos: valuePos, Reason: fmt.Sprintf("missing value for %s", pred.Field)}
+		}
+		pred.Value = value
+		if err := pred.check(); err != nil {
+			return QueryAST{}, &QueryError{Pos: valuePos, Reason: err.Error()}
+		}
+		ast.Predicates = append(ast.Predicates, pred)
+	}
+}
+
+// readQueryValue reads a bare or double-quoted value starting at i and
+// returns it with the index just past it
+func readQueryValue(q string, i int) (string, int, error) {
+	if i < len(q) && q[i] == '"' {
+		end := strings.IndexByte(q[i+1:], '"')
+		if end < 0 {
+			return "", 0, &QueryError{Pos: i + 1, Reason: "unterminated quote"}
+		}
+		return q[i+1 : i+1+end], i + end + 2, nil
+	}
+	end := strings.IndexByte(q[i:], ' ')
+	if end < 0 {
+		end = len(q) - i
+	}
+	if quote := strings.IndexByte(q[i:i+end], '"'); quote >= 0 {
+		return "", 0, &QueryError{Pos: i + quote + 1, Reason: "unexpected quote"}
+	}
+	return q[i : i+end], i + end, nil
+}
+
+// check validates a predicate's value for its field
+func (p QueryPredicate) check() error {
+	switch p.Field {
+	case "status":
+		if !isValidStatus(p.Value) {
+			return fmt.Errorf("invalid status %q", p.Value)
+		}
+	case "due":
+		if _, err := time.Parse("2006-01-02", p.Value); err != nil {
+			return fmt.Errorf("due must be a date like 2025-06-01, got %q", p.Value)
+		}
+	case "priority":
+		if n, err := strconv.Atoi(p.Value); err != nil || n < PriorityNone || n > PriorityHigh {
+			return fmt.Errorf("priority must be between %d and %d, got %q", PriorityNone, PriorityHigh, p.Value)
+		}
+	}
+	return nil
+}
+
+// EvalQuery reports whether task matches every predicate of ast. Text
+// comparisons are case-insensitive; title and bare words match substrings,
+// label and assignee whole values. Tasks without a due date never match a
+// due predicate.
+func EvalQuery(ast QueryAST, task *Task) bool {
+	for _, pred := range ast.Predicates {
+		if !pred.matches(task) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p QueryPredicate) matches(task *Task) bool {
+	value := strings.ToLower(p.Value)
+	switch p.Field {
+	case "":
+		return task.matchesQuery(value)
+	case "title":
+		return strings.Contains(strings.ToLower(task.Title), value)
+	case "label":
+		for _, label := range task.Labels {
+			if strings.ToLower(label) == value {
+				return true
+			}
+		}
+		return false
+	case "assignee":
+		return strings.ToLower(task.Assignee) == value
+	case "status":
+		return task.Status == p.Value
+	case "due":
+		if task.DueDate == nil {
+			return false
+		}
+		// Dates in YYYY-MM-DD order the same as strings
+		return compareQuery(strings.Compare(task.DueDate.Format("2006-01-02"), p.Value), p.Op)
+	case "priority":
+		n, _ := strconv.Atoi(p.Value)
+		switch {
+		case task.Priority < n:
+			return compareQuery(-1, p.Op)
+		case task.Priority > n:
+			return compareQuery(1, p.Op)
+		}
+		return compareQuery(0, p.Op)
+	}
+	return false
+}
+
+// compareQuery reports whether a comparison result (-1, 0 or 1) satisfies op
+func compareQuery(cmp int, op string) bool {
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return cmp == 0
+}
+
+// advancedSearchHandler serves GET /api/v1/search/advanced?q=, the tasks
+// matching the query ordered by ID. A malformed query is a 400 naming the
+// column where parsing failed.
+func (srv *Server) advancedSearchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ast, err := ParseQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		http.Error(w, "Invalid query at "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	tasks := []*Task{}
+	for _, task := range srv.GetAllTasks() {
+		if EvalQuery(ast, task) {
+			tasks = append(tasks, task)
+		}
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	ast, err := ParseQuery(`title:"fix bug" label:urgent  assignee:alice status:doing due:<2025-06-01 priority:>=2 login`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []QueryPredicate{
+		{Field: "title", Value: "fix bug", Pos: 1},
+		{Field: "label", Value: "urgent", Pos: 17},
+		{Field: "assignee", Value: "alice", Pos: 31},
+		{Field: "status", Value: "doing", Pos: 46},
+		{Field: "due", Op: "<", Value: "2025-06-01", Pos: 59},
+		{Field: "priority", Op: ">=", Value: "2", Pos: 75},
+		{Value: "login", Pos: 88},
+	}
+	if len(ast.Predicates) != len(want) {
+		t.Fatalf("Expected %d predicates, got %+v", len(want), ast.Predicates)
+	}
+	for i, pred := range ast.Predicates {
+		if pred != want[i] {
+			t.Errorf("Predicate %d: expected %+v, got %+v", i, want[i], pred)
+		}
+	}
+
+	if ast, err := ParseQuery("   "); err != nil || len(ast.Predicates) != 0 {
+		t.Errorf("Expected a blank query to parse to nothing, got %+v, %v", ast, err)
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		pos   int
+	}{
+		{`owner:bob`, 1},
+		{`label:urgent title:"fix bug`, 20},
+		{`label:`, 7},
+		{`status:blocked`, 8},
+		{`label:a due:>=June`, 15},
+		{`priority:9`, 10},
+		{`priority:>high`, 11},
+		{`title:fix"bug"`, 10},
+	} {
+		_, err := ParseQuery(tc.query)
+		qerr, ok := err.(*QueryError)
+		if !ok {
+			t.Errorf("%q: expected a QueryError, got %v", tc.query, err)
+			continue
+		}
+		if qerr.Pos != tc.pos {
+			t.Errorf("%q: expected an error at column %d, got %v", tc.query, tc.pos, qerr)
+		}
+	}
+}
+
+func TestEvalQuery(t *testing.T) {
+	due := time.Date(2025, 6, 1, 17, 0, 0, 0, time.UTC)
+	task := &Task{
+		Title:       "Fix bug in login",
+		Description: "Session expires early",
+		Status:      "doing",
+		Assignee:    "Alice",
+		Priority:    PriorityMedium,
+		DueDate:     &due,
+		Labels:      []string{"Urgent", "backend"},
+	}
+	for _, tc := range []struct {
+		query string
+		want  bool
+	}{
+		{`title:"fix bug"`, true},
+		{`title:"bug fix"`, false},
+		{`label:urgent`, true},
+		{`label:urg`, false},
+		{`assignee:alice`, true},
+		{`assignee:ali`, false},
+		{`status:doing`, true},
+		{`status:todo`, false},
+		{`due:2025-06-01`, true},
+		{`due:<2025-06-01`, false},
+		{`due:<=2025-06-01`, true},
+		{`due:>2025-05-31`, true},
+		{`priority:2`, true},
+		{`priority:>=2`, true},
+		{`priority:>2`, false},
+		{`priority:<3`, true},
+		{`session`, true},
+		{`"session expires"`, true},
+		{`title:"fix bug" label:urgent assignee:alice status:doing due:<2025-06-02 priority:>=2`, true},
+		{`title:"fix bug" label:urgent status:done`, false},
+		{``, true},
+	} {
+		ast, err := ParseQuery(tc.query)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.query, err)
+		}
+		if got := EvalQuery(ast, task); got != tc.want {
+			t.Errorf("%q: expected %v, got %v", tc.query, tc.want, got)
+		}
+	}
+
+	ast, _ := ParseQuery("due:>2025-01-01")
+	if EvalQuery(ast, &Task{Title: "Undated"}) {
+		t.Errorf("Expected a task without a due date not to match a due predicate")
+	}
+}
+
+func TestAdvancedSearchHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.CreateTask(TaskSpec{Title: "Fix bug", Assignee: "alice", Priority: PriorityHigh, Labels: []string{"urgent"}})
+	s.CreateTask(TaskSpec{Title: "Fix typo", Assignee: "alice", Priority: PriorityLow})
+	s.CreateTask(TaskSpec{Title: "Write docs", Assignee: "bob", Priority: PriorityHigh, Labels: []string{"urgent"}})
+
+	search := func(q string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		NewServer(s).advancedSearchHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/search/advanced?q="+url.QueryEscape(q), nil))
+		return w
+	}
+
+	w := search("assignee:alice priority:>=2")
+	var tasks []*Task
+	if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", w.Body.String(), err)
+	}
+	if !equalIDs(taskIDs(tasks), []int{1}) {
+		t.Errorf("Expected only task 1, got %v", taskIDs(tasks))
+	}
+
+	w = search("label:urgent")
+	tasks = nil
+	json.Unmarshal(w.Body.Bytes(), &tasks)
+	if !equalIDs(taskIDs(tasks), []int{1, 3}) {
+		t.Errorf("Expected tasks 1 and 3, got %v", taskIDs(tasks))
+	}
+
+	w = search("assignee:carol")
+	if strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("Expected an empty list, got %q", w.Body.String())
+	}
+
+	w = search(`label:urgent title:"fix`)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "column 20") {
+		t.Errorf("Expected 400 naming column 20, got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,492 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisNextIDKey holds the shared task ID counter
+const redisNextIDKey = "kanban:next_id"
+
+// redisMaxTxRetries bounds optimistic transaction retries under contention
+const redisMaxTxRetries = 5
+
+// redisExtrasKey holds the board's links, comments, reactions and
+// relationships as one JSON document
+const redisExtrasKey = "kanban:extras"
+
+// redisChangesChannel is where each instance announces the tasks it changes
+const redisChangesChannel = "kanban:changes"
+
+var (
+	_ TaskRepository = (*RedisStore)(nil)
+	_ Backend        = (*RedisStore)(nil)
+	_ idAllocator    = (*RedisStore)(nil)
+	_ changeFeed     = (*RedisStore)(nil)
+)
+
+// RedisStore keeps tasks in Redis so several server instances can share one
+// board. Each task is a hash at task:<id> holding its JSON and its status,
+// and each status has a set of task IDs at status:<status>. Like TaskStore, errors are logged and reported as
+// a missing result. Every change mirrored to Redis is announced on
+// redisChangesChannel, so other instances can reread the task.
+type RedisStore struct {
+	client   *redis.Client
+	instance string           // tells this instance's announcements from others'
+	now      func() time.Time // overridable clock for tests
+}
+
+// redisChange announces a changed task on redisChangesChannel
+type redisChange struct {
+	Instance string `json:"instance"`
+	TaskID   int    `json:"task_id"`
+}
+
+// NewRedisStore connects to the Redis server at url, e.g. redis://localhost:6379/0
+func NewRedisStore(url string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KANBAN_REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("could not reach redis: %w", err)
+	}
+	instance := fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano())
+	return &RedisStore{client: client, instance: instance}, nil
+}
+
+func redisTaskKey(id int) string {
+	return "task:" + strconv.Itoa(id)
+}
+
+func redisStatusKey(status string) string {
+	return "status:" + status
+}
+
+func (r *RedisStore) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// Close closes the Redis connection pool
+func (r *RedisStore) Close() error {
+	return r.client.Close()
+}
+
+// NextID atomically reserves a task ID with INCR
+func (r *RedisStore) NextID() (int, error) {
+	id, err := r.client.Incr(context.Background(), redisNextIDKey).Result()
+	return int(id), err
+}
+
+// AddTask adds a new task to the "todo" column, returning nil if the
+// backend rejects it
+func (r *RedisStore) AddTask(title, description string) *Task {
+	task, err := r.CreateTask(TaskSpec{Title: title, Description: description})
+	if err != nil {
+		log.Printf("Error creating task: %v", err)
+	}
+	return task
+}
+
+// CreateTask adds a new task built from a spec
+func (r *RedisStore) CreateTask(spec TaskSpec) (*Task, error) {
+	id, err := r.NextID()
+	if err != nil {
+		return nil, fmt.Errorf("allocating task ID: %w", err)
+	}
+	status := spec.Status
+	if status == "" {
+		status = "todo"
+	}
+	task := &Task{
+		ID:          id,
+		Title:       spec.Title,
+		Description: spec.Description,
+		Status:      status,
+		Assignee:    spec.Assignee,
+		Effort:      spec.Effort,
+		Priority:    spec.Priority,
+		DueDate:     spec.DueDate,
+		Labels:      spec.Labels,
+		CreatedAt:   r.clock(),
+	}
+	task.UpdatedAt = task.CreatedAt
+	task.StatusChangedAt = task.CreatedAt
+	if err := r.SaveTask(task); err != nil {
+		return nil, fmt.Errorf("saving task %d: %w", id, err)
+	}
+	return task, nil
+}
+
+// SaveTask writes a task's hash and adds it to its status set
+func (r *RedisStore) SaveTask(task *Task) error {
+	fields, err := encodeRedisTask(task)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		// Replace the whole hash, so no fields of an older encoding linger
+		pipe.Del(ctx, redisTaskKey(task.ID))
+		pipe.HSet(ctx, redisTaskKey(task.ID), fields)
+		pipe.SAdd(ctx, redisStatusKey(task.Status), task.ID)
+		return nil
+	})
+	return err
+}
+
+// GetTask retrieves a task by ID
+func (r *RedisStore) GetTask(id int) (*Task, bool) {
+	fields, err := r.client.HGetAll(context.Background(), redisTaskKey(id)).Result()
+	if err != nil {
+		log.Printf("Error reading task %d: %v", id, err)
+		return nil, false
+	}
+	if len(fields) == 0 {
+		return nil, false
+	}
+	task, err := decodeRedisTask(fields)
+	if err != nil {
+		log.Printf("Error decoding task %d: %v", id, err)
+		return nil, false
+	}
+	return task, true
+}
+
+// GetTasksByStatus returns the tasks in a status set in board order
+func (r *RedisStore) GetTasksByStatus(status string) []*Task {
+	ctx := context.Background()
+	ids, err := r.client.SMembers(ctx, redisStatusKey(status)).Result()
+	if err != nil {
+		log.Printf("Error reading %s tasks: %v", status, err)
+		return []*Task{}
+	}
+
+	cmds, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			pipe.HGetAll(ctx, "task:"+id)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error reading %s tasks: %v", status, err)
+		return []*Task{}
+	}
+
+	tasks := []*Task{}
+	for _, cmd := range cmds {
+		fields := cmd.(*redis.MapStringStringCmd).Val()
+		if len(fields) == 0 {
+			continue // removed between SMEMBERS and HGETALL
+		}
+		task, err := decodeRedisTask(fields)
+		if err != nil {
+			log.Printf("Error decoding task: %v", err)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	sortByPosition(tasks)
+	return tasks
+}
+
+// GetAllTasks returns every task on the board ordered by ID
+func (r *RedisStore) GetAllTasks() []*Task {
+	var tasks []*Task
+	for _, col := range columns() {
+		tasks = append(tasks, r.GetTasksByStatus(col.Status)...)
+	}
+	if tasks == nil {
+		tasks = []*Task{}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
+// MoveTask moves a task between status sets in a WATCH/MULTI/EXEC
+// transaction, retrying if another client changes the task concurrently
+func (r *RedisStore) MoveTask(id int, newStatus string) (*Task, bool, error) {
+	ctx := context.Background()
+	key := redisTaskKey(id)
+	found := true
+
+	move := func(tx *redis.Tx) error {
+		stored, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if len(stored) == 0 {
+			found = false
+			return nil
+		}
+		task, err := decodeRedisTask(stored)
+		if err != nil {
+			return err
+		}
+		oldStatus := task.Status
+		now := r.clock()
+		task.Status = newStatus
+		task.UpdatedAt = now
+		if newStatus != oldStatus {
+			task.MovedAt = &now
+			task.StatusChangedAt = now
+		}
+		fields, err := encodeRedisTask(task)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SRem(ctx, redisStatusKey(oldStatus), id)
+			pipe.SAdd(ctx, redisStatusKey(newStatus), id)
+			pipe.Del(ctx, key)
+			pipe.HSet(ctx, key, fields)
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < redisMaxTxRetries; i++ {
+		err := r.client.Watch(ctx, move, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, true, err
+		}
+		if !found {
+			return nil, false, nil
+		}
+		task, ok := r.GetTask(id)
+		return task, ok, nil
+	}
+	return nil, true, fmt.Errorf("task %d changed concurrently, giving up", id)
+}
+
+// DeleteTask removes a task's hash and its status set membership
+func (r *RedisStore) DeleteTask(id int) (bool, error) {
+	ctx := context.Background()
+	key := redisTaskKey(id)
+	deleted := false
+
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		status, err := tx.HGet(ctx, key, "status").Result()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, key)
+			pipe.SRem(ctx, redisStatusKey(status), id)
+			return nil
+		})
+		deleted = err == nil
+		return err
+	}, key)
+	if err != nil {
+		return false, fmt.Errorf("deleting task %d: %w", id, err)
+	}
+	return deleted, nil
+}
+
+// Load reads the whole board along with the next ID to hand out
+func (r *RedisStore) Load() (map[int]*Task, int, error) {
+	next, err := r.client.Get(context.Background(), redisNextIDKey).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, 0, err
+	}
+	tasks := make(map[int]*Task)
+	for _, task := range r.GetAllTasks() {
+		tasks[task.ID] = task
+		if task.ID > next {
+			next = task.ID
+		}
+	}
+	return tasks, next + 1, nil
+}
+
+// saveExtras replaces the board's links, comments, reactions and
+// relationships
+func (r *RedisStore) saveExtras(data PersistentData) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(context.Background(), redisExtrasKey, encoded, 0).Err()
+}
+
+// loadExtras reads what saveExtras last saved, or nothing if it never ran
+func (r *RedisStore) loadExtras() (PersistentData, error) {
+	var data PersistentData
+	encoded, err := r.client.Get(context.Background(), redisExtrasKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return data, nil
+	}
+	if err != nil {
+		return data, err
+	}
+	return data, json.Unmarshal(encoded, &data)
+}
+
+// mirror applies a TaskStore event to Redis, keeping the shared board in
+// step with this instance's changes
+func (r *RedisStore) mirror(e Event) {
+	if e.Task == nil {
+		return
+	}
+	var err error
+	switch e.Type {
+	case EventTaskCreated, EventTaskUpdated:
+		err = r.SaveTask(e.Task)
+	case EventTaskMoved:
+		// Move between the status sets, then save the new position
+		if _, _, err = r.MoveTask(e.Task.ID, e.ToStatus); err == nil {
+			err = r.SaveTask(e.Task)
+		}
+	case EventTaskDeleted:
+		_, err = r.DeleteTask(e.Task.ID)
+	default:
+		return
+	}
+	if err != nil {
+		log.Printf("Error mirroring %s for task %d to redis: %v", e.Type, e.Task.ID, err)
+		return
+	}
+	r.announce(e.Task.ID)
+}
+
+// announce tells other instances that a task changed
+func (r *RedisStore) announce(id int) {
+	payload, _ := json.Marshal(redisChange{Instance: r.instance, TaskID: id})
+	if err := r.client.Publish(context.Background(), redisChangesChannel, payload).Err(); err != nil {
+		log.Printf("Error announcing task %d: %v", id, err)
+	}
+}
+
+// Changes subscribes to redisChangesChannel and sends the ID of each task
+// another instance changes. The subscription is in place by the time it
+// returns, so no change made after that is missed.
+func (r *RedisStore) Changes(stop <-chan struct{}) <-chan int {
+	ctx := context.Background()
+	pubsub := r.client.Subscribe(ctx, redisChangesChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		log.Printf("Error subscribing to %s: %v", redisChangesChannel, err)
+	}
+	ids := make(chan int)
+	go func() {
+		defer close(ids)
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-stop:
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var change redisChange
+				if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil || change.Instance == r.instance {
+					continue
+				}
+				select {
+				case ids <- change.TaskID:
+				case <-stop:
+					return
+				}
+			}
+		}
+	}()
+	return ids
+}
+
+// encodeRedisTask stores a task as hash fields: the whole task as JSON in
+// "task", plus "status" on its own so moves and deletes can find the task's
+// status set without decoding it
+func encodeRedisTask(task *Task) (map[string]interface{}, error) {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"status": task.Status,
+		"task":   string(data),
+	}, nil
+}
+
+// decodeRedisTask rebuilds a task from its hash fields. Hashes written
+// before the "task" field was added hold the task as separate fields, which
+// decodeLegacyRedisTask reads.
+func decodeRedisTask(fields map[string]string) (*Task, error) {
+	raw, ok := fields["task"]
+	if !ok {
+		return decodeLegacyRedisTask(fields)
+	}
+	var task Task
+	if err := json.Unmarshal([]byte(raw), &task); err != nil {
+		return nil, fmt.Errorf("invalid task: %w", err)
+	}
+	task.Status = fields["status"]
+	return &task, nil
+}
+
+// decodeLegacyRedisTask rebuilds a task from the separate fields hashes
+// used to hold
+func decodeLegacyRedisTask(fields map[string]string) (*Task, error) {
+	task := &Task{
+		Title:       fields["title"],
+		Description: fields["description"],
+		Status:      fields["status"],
+		Assignee:    fields["assignee"],
+	}
+	var err error
+	if task.ID, err = strconv.Atoi(fields["id"]); err != nil {
+		return nil, fmt.Errorf("invalid id %q", fields["id"])
+	}
+	task.Effort, _ = strconv.Atoi(fields["effort"])
+	task.Priority, _ = strconv.Atoi(fields["priority"])
+	task.Position, _ = strconv.Atoi(fields["position"])
+	if raw := fields["due_date"]; raw != "" {
+		due, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, fmt.Errorf("task %d: invalid due_date %q", task.ID, raw)
+		}
+		task.DueDate = &due
+	}
+	if raw := fields["labels"]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &task.Labels); err != nil {
+			return nil, fmt.Errorf("task %d: invalid labels", task.ID)
+		}
+	}
+	if raw := fields["checklist"]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &task.Checklist); err != nil {
+			return nil, fmt.Errorf("task %d: invalid checklist", task.ID)
+		}
+	}
+	task.CreatedAt, _ = time.Parse(time.RFC3339Nano, fields["created_at"])
+	task.UpdatedAt, _ = time.Parse(time.RFC3339Nano, fields["updated_at"])
+	task.StatusChangedAt, _ = time.Parse(time.RFC3339Nano, fields["status_changed_at"])
+	if raw := fields["moved_at"]; raw != "" {
+		moved, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, fmt.Errorf("task %d: invalid moved_at %q", task.ID, raw)
+		}
+		task.MovedAt = &moved
+	}
+	return task, nil
+}
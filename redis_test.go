@@ -0,0 +1,241 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	r, err := NewRedisStore("redis://" + mr.Addr())
+	if err != nil {
+		t.Fatalf("NewRedisStore failed: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r, mr
+}
+
+func TestRedisStoreCRUD(t *testing.T) {
+	r, mr := newTestRedisStore(t)
+	due := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	a := r.AddTask("A", "first")
+	b, _ := r.CreateTask(TaskSpec{Title: "B", Status: "doing", Priority: PriorityHigh, DueDate: &due, Labels: []string{"x", "y"}})
+	if a.ID != 1 || b.ID != 2 {
+		t.Fatalf("Expected IDs 1 and 2 from INCR, got %d and %d", a.ID, b.ID)
+	}
+	if got, _ := mr.Get(redisNextIDKey); got != "2" {
+		t.Errorf("Expected next_id counter 2, got %q", got)
+	}
+	if mr.HGet("task:2", "status") != "doing" || !strings.Contains(mr.HGet("task:2", "task"), `"title":"B"`) {
+		t.Errorf("Expected task stored as a hash of its status and JSON")
+	}
+	if ok, _ := mr.SIsMember("status:doing", "2"); !ok {
+		t.Errorf("Expected task 2 in status:doing")
+	}
+
+	got, ok := r.GetTask(b.ID)
+	if !ok || got.Priority != PriorityHigh || !got.DueDate.Equal(due) || len(got.Labels) != 2 || got.Labels[1] != "y" {
+		t.Errorf("Task did not round-trip: %+v", got)
+	}
+	if all := taskIDs(r.GetAllTasks()); !equalIDs(all, []int{1, 2}) {
+		t.Errorf("Expected all tasks [1 2], got %v", all)
+	}
+
+	moved, ok, err := r.MoveTask(a.ID, "done")
+	if !ok || err != nil {
+		t.Fatalf("MoveTask failed: ok=%v err=%v", ok, err)
+	}
+	if moved.MovedAt == nil {
+		t.Errorf("Expected MoveTask to record moved_at")
+	}
+	if todo := r.GetTasksByStatus("todo"); len(todo) != 0 {
+		t.Errorf("Expected todo to be empty after move, got %v", taskIDs(todo))
+	}
+	if done := taskIDs(r.GetTasksByStatus("done")); !equalIDs(done, []int{1}) {
+		t.Errorf("Expected done [1], got %v", done)
+	}
+	if _, ok, _ := r.MoveTask(99, "done"); ok {
+		t.Errorf("Expected missing task to report not found")
+	}
+
+	if ok, err := r.DeleteTask(b.ID); !ok || err != nil {
+		t.Errorf("Expected delete to succeed, got %v, %v", ok, err)
+	}
+	if ok, _ := r.DeleteTask(b.ID); ok {
+		t.Errorf("Expected a second delete to find nothing")
+	}
+	if ok, _ := mr.SIsMember("status:doing", "2"); ok || mr.Exists("task:2") {
+		t.Errorf("Deleted task must leave no keys behind")
+	}
+}
+
+func TestTaskStoreMirrorsToRedis(t *testing.T) {
+	r, _ := newTestRedisStore(t)
+	s := withTestGlobals(t)
+	s.backend = r
+	bus.Subscribe(EventAll, r.mirror)
+
+	task := s.AddTask("Shared", "")
+	s.MoveTask(task.ID, "doing")
+	s.AssignTask(task.ID, "alice")
+	s.AddChecklistItem(task.ID, "Check")
+	s.AddComment(task.ID, "alice", "On it")
+	other := s.AddTask("Temporary", "")
+	s.DeleteTask(other.ID)
+
+	// A second instance sharing the same Redis sees the same board
+	second := newTestStore()
+	second.backend = r
+	if err := second.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	loaded, ok := second.GetTask(task.ID)
+	if !ok || loaded.Status != "doing" || loaded.Assignee != "alice" || len(loaded.Checklist) != 1 {
+		t.Errorf("Expected mirrored task in doing assigned to alice, got %+v", loaded)
+	}
+	if len(second.GetAllTasks()) != 1 {
+		t.Errorf("Expected deleted task to be gone, got %d tasks", len(second.GetAllTasks()))
+	}
+	if comments, _ := second.Comments(task.ID); len(comments) != 1 || comments[0].Body != "On it" {
+		t.Errorf("Expected the comment saved to Redis, got %+v", comments)
+	}
+
+	// IDs come from the shared counter, so instances never collide
+	created := second.AddTask("From second", "")
+	if created.ID != 3 {
+		t.Errorf("Expected ID 3 from the shared counter, got %d", created.ID)
+	}
+}
+
+func TestRedisMoveTaskConcurrent(t *testing.T) {
+	r, mr := newTestRedisStore(t)
+	task := r.AddTask("Contended", "")
+
+	done := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		status := boardColumns[i%3].Status
+		go func() {
+			r.MoveTask(task.ID, status)
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+
+	final := mr.HGet("task:1", "status")
+	for _, col := range boardColumns {
+		member, _ := mr.SIsMember(redisStatusKey(col.Status), "1")
+		if member != (col.Status == final) {
+			t.Errorf("Task must be in exactly its own status set, found in %s while status is %s", col.Status, final)
+		}
+	}
+}
+
+func TestRedisTaskRoundTrip(t *testing.T) {
+	r, _ := newTestRedisStore(t)
+	at := func(day int) *time.Time {
+		tm := time.Date(2024, 5, day, 9, 30, 0, 0, time.UTC)
+		return &tm
+	}
+	task := &Task{
+		ID:                4,
+		Title:             "Everything",
+		Description:       "every field set",
+		Status:            "doing",
+		Assignee:          "ana",
+		Effort:            5,
+		Priority:          PriorityHigh,
+		DueDate:           at(20),
+		Labels:            []string{"bug", "ui"},
+		Checklist:         []ChecklistItem{{Index: 0, Text: "Spec", Checked: true, TaskID: 9}},
+		DependsOn:         []int{1, 2},
+		Position:          3,
+		Pinned:            true,
+		ReviewRequestedBy: "bo",
+		Watchers:          []string{"cy"},
+		SprintID:          2,
+		CreatedAt:         *at(1),
+		UpdatedAt:         *at(3),
+		MovedAt:           at(2),
+		StatusChangedAt:   *at(2),
+		ArchivedAt:        at(4),
+		ExpiresAt:         at(30),
+		StatusHistory:     []StatusTransition{{FromStatus: "todo", ToStatus: "doing", At: *at(2)}},
+	}
+	v := reflect.ValueOf(*task)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsZero() {
+			t.Fatalf("Fill in Task.%s so the round trip covers it", v.Type().Field(i).Name)
+		}
+	}
+
+	if err := r.SaveTask(task); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := r.GetTask(task.ID)
+	if !ok || !reflect.DeepEqual(got, task) {
+		t.Errorf("Task did not round-trip:\n got %+v\nwant %+v", got, task)
+	}
+}
+
+func TestRedisReadsLegacyTaskHashes(t *testing.T) {
+	r, mr := newTestRedisStore(t)
+	mr.HSet("task:1", "id", "1", "title", "Old", "status", "todo", "priority", "2",
+		"labels", `["x"]`, "created_at", "2024-01-02T03:04:05Z")
+	mr.SAdd("status:todo", "1")
+
+	got, ok := r.GetTask(1)
+	if !ok || got.Title != "Old" || got.Priority != 2 || len(got.Labels) != 1 || got.CreatedAt.Year() != 2024 {
+		t.Fatalf("Expected the legacy hash decoded, got %+v", got)
+	}
+	moved, ok, err := r.MoveTask(1, "done")
+	if !ok || err != nil || moved.Title != "Old" || moved.Status != "done" {
+		t.Errorf("Expected a move to keep the legacy task's fields, got %+v %v", moved, err)
+	}
+	if mr.HGet("task:1", "title") != "" {
+		t.Errorf("Expected the move to rewrite the hash in the current encoding")
+	}
+}
+
+func TestRedisInstancesSeeEachOthersChanges(t *testing.T) {
+	_, mr := newTestRedisStore(t)
+	instance := func() *TaskStore {
+		r, err := NewRedisStore("redis://" + mr.Addr())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { r.Close() })
+		s := newTestStore()
+		s.backend = r
+		s.events = NewEventBus()
+		s.events.Subscribe(EventAll, r.mirror)
+		stop := make(chan struct{})
+		t.Cleanup(func() { close(stop) })
+		go s.followChanges(r.Changes(stop))
+		return s
+	}
+	a, b := instance(), instance()
+	eventually := func(what string, ok func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(2 * time.Second); !ok(); time.Sleep(5 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting until %s", what)
+			}
+		}
+	}
+
+	task := a.AddTask("Shared", "")
+	eventually("b sees the new task", func() bool { _, ok := b.GetTask(task.ID); return ok })
+	a.MoveTask(task.ID, "doing")
+	eventually("b sees the move", func() bool { return len(b.GetTasksByStatus("doing")) == 1 })
+	b.AssignTask(task.ID, "ana")
+	eventually("a sees b's change", func() bool { got, _ := a.GetTask(task.ID); return got.Assignee == "ana" })
+	a.DeleteTask(task.ID)
+	eventually("b sees the delete", func() bool { return len(b.GetAllTasks()) == 0 })
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Relationship types
+const (
+	RelationBlocks     = "blocks"
+	RelationRelatesTo  = "relates_to"
+	RelationDuplicates = "duplicates"
+)
+
+// Relationship loosely links two tasks, unlike depends_on which gates work
+type Relationship struct {
+	ID        int       `json:"id"`
+	FromID    int       `json:"from_id"`
+	ToID      int       `json:"to_id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RelatedTask is a task on the other end of one of a task's relationships.
+// Outgoing is true when the task asked about is the relationship's FromID.
+type RelatedTask struct {
+	RelationshipID int    `json:"relationship_id"`
+	Type           string `json:"type"`
+	Outgoing       bool   `json:"outgoing"`
+	Task           *Task  `json:"task"`
+}
+
+func isValidRelationType(relType string) bool {
+	switch relType {
+	case RelationBlocks, RelationRelatesTo, RelationDuplicates:
+		return true
+	}
+	return false
+}
+
+// AddRelationship relates two tasks. Both must exist, and a pair of tasks
+// can only be related once per type, in either direction.
+func (s *TaskStore) AddRelationship(fromID, toID int, relType string) (*Relationship, error) {
+	if !isValidRelationType(relType) {
+		return nil, fieldError("type", "type must be %s, %s or %s", RelationBlocks, RelationRelatesTo, RelationDuplicates)
+	}
+	if fromID == toID {
+		return nil, fieldError("related_id", "a task can't be related to itself")
+	}
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	from, ok := s.tasks[fromID]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrTaskNotFound
+	}
+	if _, ok := s.tasks[toID]; !ok {
+		s.mu.Unlock()
+		return nil, fieldError("related_id", "task %d does not exist", toID)
+	}
+	for _, rel := range s.relationships {
+		if rel.Type == relType && (rel.FromID == fromID && rel.ToID == toID || rel.FromID == toID && rel.ToID == fromID) {
+			s.mu.Unlock()
+			return nil, fieldError("related_id", "tasks %d and %d are already related as %s", fromID, toID, relType)
+		}
+	}
+	if s.nextRelationshipID < 1 {
+		s.nextRelationshipID = 1
+	}
+	rel := Relationship{ID: s.nextRelationshipID, FromID: fromID, ToID: toID, Type: relType, CreatedAt: s.clock()}
+	s.nextRelationshipID++
+	s.relationships = append(s.relationships, rel)
+	s.persistRelationships()
+	updated := from.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskUpdated, Task: updated, Changes: []FieldChange{{Field: "relationship", New: relType + " #" + strconv.Itoa(toID)}}})
+	return &rel, nil
+}
+
+// GetRelated returns the tasks related to a task in either direction, in
+// the order the relationships were added
+func (s *TaskStore) GetRelated(taskID int) []RelatedTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	related := []RelatedTask{}
+	for _, rel := range s.relationships {
+		otherID := rel.ToID
+		if rel.ToID == taskID {
+			otherID = rel.FromID
+		} else if rel.FromID != taskID {
+			continue
+		}
+		if other, ok := s.tasks[otherID]; ok {
+			related = append(related, RelatedTask{
+				RelationshipID: rel.ID,
+				Type:           rel.Type,
+				Outgoing:       rel.FromID == taskID,
+				Task:           other.clone(),
+			})
+		}
+	}
+	return related
+}
+
+// DeleteRelationship removes a relationship, reporting whether it existed
+func (s *TaskStore) DeleteRelationship(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, rel := range s.relationships {
+		if rel.ID == id {
+			s.relationships = append(s.relationships[:i:i], s.relationships[i+1:]...)
+			s.persistRelationships()
+			return true
+		}
+	}
+	return false
+}
+
+// deleteRelationships drops every relationship involving a task, reporting
+// whether there were any (must be called with lock held)
+func (s *TaskStore) deleteRelationships(taskID int) bool {
+	kept := s.relationships[:0]
+	for _, rel := range s.relationships {
+		if rel.FromID != taskID && rel.ToID != taskID {
+			kept = append(kept, rel)
+		}
+	}
+	removed := len(kept) < len(s.relationships)
+	s.relationships = kept
+	return removed
+}
+
+// setRelationships replaces the relationships with loaded ones (must be
+// called with lock held)
+func (s *TaskStore) setRelationships(rels []Relationship, nextID int) {
+	sort.Slice(rels, func(i, j int) bool { return rels[i].ID < rels[j].ID })
+	s.relationships = rels
+	s.nextRelationshipID = nextID
+	for _, rel := range rels {
+		if rel.ID >= s.nextRelationshipID {
+			s.nextRelationshipID = rel.ID + 1
+		}
+	}
+}
+
+// persistRelationships saves the relationships (must be called with lock
+// held). Like links, they go in the data file, relationships.json with
+// partitioned storage, or the external backend's extras.
+func (s *TaskStore) persistRelationships() {
+	switch {
+	case s.backend != nil:
+		s.saveExtras()
+	case s.partitions != nil:
+		if err := s.partitions.WriteRelationships(s.relationships, s.nextRelationshipID); err != nil {
+			log.Printf("Error saving relationships: %v", err)
+		}
+	default:
+		s.saveToFile()
+	}
+}
+
+// taskRelatedHandler serves /api/v1/tasks/{id}/related: GET lists the
+// related tasks, optionally only those of ?type=, and POST relates the task
+// to {"related_id", "type"}
+func (srv *Server) taskRelatedHandler(w http.ResponseWriter, r *http.Request, taskID int) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := srv.GetTask(taskID); !ok {
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		related := srv.GetRelated(taskID)
+		if relType := r.URL.Query().Get("type"); relType != "" {
+			filtered := []RelatedTask{}
+			for _, rt := range related {
+				if rt.Type == relType {
+					filtered = append(filtered, rt)
+				}
+			}
+			related = filtered
+		}
+		writeJSON(w, http.StatusOK, related)
+	case http.MethodPost:
+		if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+		var input struct {
+			RelatedID int    `json:"related_id"`
+			Type      string `json:"type"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		rel, err := srv.AddRelationship(taskID, input.RelatedID, input.Type)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, rel)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// relationshipHandler serves DELETE /api/v1/relationships/{id}
+func (srv *Server) relationshipHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/relationships/"), "/"))
+	if err != nil {
+		http.Error(w, "Invalid relationship ID", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !srv.DeleteRelationship(id) {
+		http.Error(w, "Relationship not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
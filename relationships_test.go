@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func relatedIDs(related []RelatedTask) []int {
+	ids := []int{}
+	for _, rt := range related {
+		ids = append(ids, rt.Task.ID)
+	}
+	return ids
+}
+
+func TestGetRelatedBothDirections(t *testing.T) {
+	s := newTestStore()
+	a := s.AddTask("A", "")
+	b := s.AddTask("B", "")
+	c := s.AddTask("C", "")
+
+	first, err := s.AddRelationship(a.ID, b.ID, RelationBlocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.AddRelationship(c.ID, a.ID, RelationRelatesTo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("Expected sequential IDs 1 and 2, got %d and %d", first.ID, second.ID)
+	}
+
+	related := s.GetRelated(a.ID)
+	if !equalIDs(relatedIDs(related), []int{b.ID, c.ID}) {
+		t.Fatalf("Expected B and C related to A, got %v", relatedIDs(related))
+	}
+	if !related[0].Outgoing || related[1].Outgoing || related[1].Type != RelationRelatesTo {
+		t.Errorf("Unexpected directions or types %+v", related)
+	}
+	if back := s.GetRelated(b.ID); len(back) != 1 || back[0].Task.ID != a.ID || back[0].Outgoing {
+		t.Errorf("Expected B to see A as an incoming blocker, got %+v", back)
+	}
+
+	if _, err := s.AddRelationship(b.ID, a.ID, RelationBlocks); err == nil {
+		t.Errorf("Expected a duplicate relationship to be rejected")
+	}
+	if _, err := s.AddRelationship(a.ID, a.ID, RelationRelatesTo); err == nil {
+		t.Errorf("Expected a self relationship to be rejected")
+	}
+	if _, err := s.AddRelationship(a.ID, b.ID, "parent_of"); err == nil {
+		t.Errorf("Expected an unknown type to be rejected")
+	}
+
+	if !s.DeleteRelationship(first.ID) || s.DeleteRelationship(first.ID) {
+		t.Errorf("Expected the relationship to be deleted once")
+	}
+	if len(s.GetRelated(b.ID)) != 0 {
+		t.Errorf("Expected B to have no relationships left")
+	}
+}
+
+func TestDeleteTaskRemovesRelationships(t *testing.T) {
+	s := newTestStore()
+	a := s.AddTask("A", "")
+	b := s.AddTask("B", "")
+	c := s.AddTask("C", "")
+	s.AddRelationship(a.ID, b.ID, RelationDuplicates)
+	s.AddRelationship(b.ID, c.ID, RelationRelatesTo)
+	s.AddRelationship(a.ID, c.ID, RelationBlocks)
+
+	s.DeleteTask(b.ID)
+	if len(s.relationships) != 1 || s.relationships[0].Type != RelationBlocks {
+		t.Errorf("Expected only the A-C relationship to remain, got %+v", s.relationships)
+	}
+	if !equalIDs(relatedIDs(s.GetRelated(c.ID)), []int{a.ID}) {
+		t.Errorf("Expected C related only to A, got %v", relatedIDs(s.GetRelated(c.ID)))
+	}
+}
+
+func TestRelationshipsPersist(t *testing.T) {
+	s := newTestStore()
+	a := s.AddTask("A", "")
+	b := s.AddTask("B", "")
+	s.AddRelationship(a.ID, b.ID, RelationRelatesTo)
+
+	loaded := &TaskStore{tasks: make(map[int]*Task), filePath: s.filePath}
+	if err := loaded.LoadFromFile(); err != nil {
+		t.Fatal(err)
+	}
+	if !equalIDs(relatedIDs(loaded.GetRelated(b.ID)), []int{a.ID}) {
+		t.Errorf("Expected the relationship to survive a reload")
+	}
+	if rel, err := loaded.AddRelationship(b.ID, a.ID, RelationBlocks); err != nil || rel.ID != 2 {
+		t.Errorf("Expected the next relationship to get ID 2, got %+v, %v", rel, err)
+	}
+}
+
+func TestRelatedAPI(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("A", "")
+	s.AddTask("B", "")
+	s.AddTask("C", "")
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		NewServer(s).taskAPIHandler(w, req)
+		return w
+	}
+	if w := post("/api/v1/tasks/1/related", `{"related_id": 2, "type": "blocks"}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	post("/api/v1/tasks/3/related", `{"related_id": 1, "type": "relates_to"}`)
+	if w := post("/api/v1/tasks/1/related", `{"related_id": 9, "type": "blocks"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a missing related task, got %d", w.Code)
+	}
+	if w := post("/api/v1/tasks/9/related", `{"related_id": 1, "type": "blocks"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing task, got %d", w.Code)
+	}
+
+	get := func(path string) []RelatedTask {
+		w := httptest.NewRecorder()
+		NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var related []RelatedTask
+		if err := json.Unmarshal(w.Body.Bytes(), &related); err != nil {
+			t.Fatalf("Invalid JSON from %s: %v", path, err)
+		}
+		return related
+	}
+	if ids := relatedIDs(get("/api/v1/tasks/1/related")); !equalIDs(ids, []int{2, 3}) {
+		t.Errorf("Expected tasks 2 and 3, got %v", ids)
+	}
+	if ids := relatedIDs(get("/api/v1/tasks/1/related?type=relates_to")); !equalIDs(ids, []int{3}) {
+		t.Errorf("Expected only task 3 for relates_to, got %v", ids)
+	}
+
+	w := httptest.NewRecorder()
+	NewServer(s).relationshipHandler(w, httptest.NewRequest(http.MethodDelete, "/api/v1/relationships/1", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	NewServer(s).relationshipHandler(w, httptest.NewRequest(http.MethodDelete, "/api/v1/relationships/1", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 once deleted, got %d", w.Code)
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNotInColumn is returned when a reorder lists a task from another column
+type ErrNotInColumn struct {
+	ID     int
+	Status string
+}
+
+func (e *ErrNotInColumn) Error() string {
+	return fmt.Sprintf("task %d is not in %q", e.ID, e.Status)
+}
+
+// sortByPosition orders a column's tasks by position, pinned tasks first,
+// breaking ties by creation time and then ID, so cards keep their order
+// across reloads even though tasks come from a map
+func sortByPosition(tasks []*Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Pinned != tasks[j].Pinned {
+			return tasks[i].Pinned
+		}
+		if tasks[i].Position != tasks[j].Position {
+			return tasks[i].Position < tasks[j].Position
+		}
+		if !tasks[i].CreatedAt.Equal(tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+}
+
+// nextPosition returns the position after the last task in a column, so new
+// and moved tasks go to the bottom (must be called with lock held)
+func (s *TaskStore) nextPosition(status string) int {
+	next := 0
+	for _, task := range s.tasks {
+		if task.Status == status && task.Position >= next {
+			next = task.Position + 1
+		}
+	}
+	return next
+}
+
+// ReorderColumn sets each listed task's position to its index in ids. Tasks
+// in the column that aren't listed keep their relative order after them.
+// Every ID must belong to the column.
+func (s *TaskStore) ReorderColumn(status string, ids []int) error {
+	s.mu.Lock()
+
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	listed := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		task, ok := s.tasks[id]
+		if !ok || task.Status != status {
+			s.mu.Unlock()
+			return &ErrNotInColumn{ID: id, Status: status}
+		}
+		if listed[id] {
+			s.mu.Unlock()
+			return fmt.Errorf("task %d is listed more than once", id)
+		}
+		listed[id] = true
+	}
+
+	order := make([]*Task, 0, len(ids))
+	for _, id := range ids {
+		order = append(order, s.tasks[id])
+	}
+	var rest []*Task
+	for _, task := range s.tasks {
+		if task.Status == status && !listed[task.ID] {
+			rest = append(rest, task)
+		}
+	}
+	sortByPosition(rest)
+	order = append(order, rest...)
+
+	var events []Event
+	now := s.clock()
+	for i, task := range order {
+		if task.Position == i {
+			continue
+		}
+		change := FieldChange{Field: "position", Old: strconv.Itoa(task.Position), New: strconv.Itoa(i)}
+		task.Position = i
+		task.UpdatedAt = now
+		events = append(events, Event{Type: EventTaskUpdated, Task: task.clone(), Changes: []FieldChange{change}})
+	}
+	if len(events) > 0 {
+		s.persist(status)
+	}
+	s.mu.Unlock()
+
+	for _, e := range events {
+		s.publish(e)
+	}
+	return nil
+}
+
+// MoveToTop puts a task above every other task in its column, then
+// renumbers the column. Pinned tasks still come first.
+func (s *TaskStore) MoveToTop(id int) (*Task, error) {
+	return s.moveToEdge(id, true)
+}
+
+// MoveToBottom puts a task below every other task in its column, then
+// renumbers the column
+func (s *TaskStore) MoveToBottom(id int) (*Task, error) {
+	return s.moveToEdge(id, false)
+}
+
+func (s *TaskStore) moveToEdge(id int, top bool) (*Task, error) {
+	s.mu.Lock()
+
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	task, ok := s.tasks[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrTaskNotFound
+	}
+	before := s.columnPositions(task.Status)
+	edge := task.Position
+	for _, other := range s.tasks {
+		if other.Status != task.Status {
+			continue
+		}
+		if top && other.Position < edge {
+			edge = other.Position
+		}
+		if !top && other.Position > edge {
+			edge = other.Position
+		}
+	}
+	if top {
+		task.Position = edge - 1
+	} else {
+		task.Position = edge + 1
+	}
+	events := s.normalizePositions(task.Status, before)
+	moved := task.clone()
+	s.mu.Unlock()
+
+	for _, e := range events {
+		s.publish(e)
+	}
+	return moved, nil
+}
+
+// NormalizePositions renumbers a column's tasks 1, 2, 3... in board order,
+// closing any gaps left by moves and deletes, or returns ErrBoardLocked
+func (s *TaskStore) NormalizePositions(status string) error {
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	events := s.normalizePositions(status, s.columnPositions(status))
+	s.mu.Unlock()
+
+	for _, e := range events {
+		s.publish(e)
+	}
+	return nil
+}
+
+// columnPositions returns the position of each task in a column, by ID
+// (must be called with lock held)
+func (s *TaskStore) columnPositions(status string) map[int]int {
+	positions := make(map[int]int)
+	for _, task := range s.tasks {
+		if task.Status == status {
+			positions[task.ID] = task.Position
+		}
+	}
+	return positions
+}
+
+// normalizePositions renumbers a column from 1 in board order and returns
+// an update event for each task whose position differs from before,
+// saving the column if any did (must be called with lock held)
+func (s *TaskStore) normalizePositions(status string, before map[int]int) []Event {
+	var column []*Task
+	for _, task := range s.tasks {
+		if task.Status == status {
+			column = append(column, task)
+		}
+	}
+	sortByPosition(column)
+
+	var events []Event
+	now := s.clock()
+	for i, task := range column {
+		task.Position = i + 1
+		if task.Position == before[task.ID] {
+			continue
+		}
+		change := FieldChange{Field: "position", Old: strconv.Itoa(before[task.ID]), New: strconv.Itoa(task.Position)}
+		task.UpdatedAt = now
+		events = append(events, Event{Type: EventTaskUpdated, Task: task.clone(), Changes: []FieldChange{change}})
+	}
+	if len(events) > 0 {
+		s.persist(status)
+	}
+	return events
+}
+
+// taskMoveToEdgeHandler serves POST /api/v1/tasks/{id}/move-to-top and
+// move-to-bottom, returning the moved task
+func (srv *Server) taskMoveToEdgeHandler(w http.ResponseWriter, r *http.Request, id int, top bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	move := srv.MoveToBottom
+	if top {
+		move = srv.MoveToTop
+	}
+	task, err := move(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+// reorderHandler serves POST /reorder/{status} with a comma-separated order
+// of task IDs and returns the re-rendered column
+func (srv *Server) reorderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := strings.Trim(strings.TrimPrefix(r.URL.Path, "/reorder/"), "/")
+	if !isValidStatus(status) {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	var ids []int
+	for _, raw := range strings.Split(r.FormValue("order"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid task ID %q", raw), http.StatusBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	if err := srv.ReorderColumn(status, ids); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	templates.ExecuteTemplate(w, "column-content.html", srv.GetColumnData(status))
+}
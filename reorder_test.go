@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReorderColumnAssignsPositions(t *testing.T) {
+	s := newTestStore()
+	for _, title := range []string{"A", "B", "C"} {
+		s.AddTask(title, "")
+	}
+	s.CreateTask(TaskSpec{Title: "Elsewhere", Status: "doing"})
+
+	if err := s.ReorderColumn("todo", []int{3, 1, 2}); err != nil {
+		t.Fatalf("ReorderColumn failed: %v", err)
+	}
+	for id, want := range map[int]int{3: 0, 1: 1, 2: 2} {
+		if task, _ := s.GetTask(id); task.Position != want {
+			t.Errorf("Task %d: expected position %d, got %d", id, want, task.Position)
+		}
+	}
+	if ids := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(ids, []int{3, 1, 2}) {
+		t.Errorf("Expected todo in order [3 1 2], got %v", ids)
+	}
+	if ids := taskIDs(s.GetColumnData("todo").Tasks); !equalIDs(ids, []int{3, 1, 2}) {
+		t.Errorf("Expected rendered column in order [3 1 2], got %v", ids)
+	}
+
+	// Unlisted tasks keep their order after the listed ones
+	if err := s.ReorderColumn("todo", []int{2}); err != nil {
+		t.Fatalf("ReorderColumn failed: %v", err)
+	}
+	if ids := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(ids, []int{2, 3, 1}) {
+		t.Errorf("Expected todo in order [2 3 1], got %v", ids)
+	}
+
+	// New and moved tasks go to the bottom of their column
+	added := s.AddTask("D", "")
+	s.MoveTask(4, "todo")
+	if ids := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(ids, []int{2, 3, 1, added.ID, 4}) {
+		t.Errorf("Expected new and moved tasks at the bottom, got %v", ids)
+	}
+}
+
+func TestReorderColumnRejectsForeignIDs(t *testing.T) {
+	s := newTestStore()
+	s.AddTask("A", "")
+	s.CreateTask(TaskSpec{Title: "B", Status: "doing"})
+
+	var notInColumn *ErrNotInColumn
+	if err := s.ReorderColumn("todo", []int{1, 2}); !errors.As(err, &notInColumn) || notInColumn.ID != 2 {
+		t.Errorf("Expected ErrNotInColumn for task 2, got %v", err)
+	}
+	if err := s.ReorderColumn("todo", []int{1, 99}); err == nil {
+		t.Errorf("Expected an error for a missing task")
+	}
+	if err := s.ReorderColumn("todo", []int{1, 1}); err == nil {
+		t.Errorf("Expected an error for a duplicated ID")
+	}
+}
+
+func postReorder(path, order string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("order="+order))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	NewServer(store).reorderHandler(w, req)
+	return w
+}
+
+func TestReorderHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("First", "")
+	s.AddTask("Second", "")
+	s.CreateTask(TaskSpec{Title: "Doing", Status: "doing"})
+
+	w := postReorder("/reorder/todo", "2,1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	if strings.Index(body, "Second") > strings.Index(body, "First") {
+		t.Errorf("Expected Second to render before First")
+	}
+
+	if w := postReorder("/reorder/todo", "1,3"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a task from another column, got %d", w.Code)
+	}
+	if w := postReorder("/reorder/todo", "1,x"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a non-numeric ID, got %d", w.Code)
+	}
+	if w := postReorder("/reorder/archived", "1"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid status, got %d", w.Code)
+	}
+}
+
+func TestGetTasksByStatusOrderIsStable(t *testing.T) {
+	s := newTestStore()
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	// Equal positions fall back to creation time, then ID
+	for i, created := range []time.Duration{3, 1, 2, 1, 0} {
+		task := s.AddTask(fmt.Sprintf("Task %d", i), "")
+		task.Position = 0
+		task.CreatedAt = base.Add(created * time.Minute)
+	}
+	s.cache.reset()
+
+	want := []int{5, 2, 4, 3, 1}
+	for i := 0; i < 10; i++ {
+		if got := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(got, want) {
+			t.Fatalf("Fetch %d: expected %v, got %v", i, want, got)
+		}
+		s.cache.reset()
+	}
+}
+
+func TestColumnRenderOrderIsStable(t *testing.T) {
+	s := withTestGlobals(t)
+	for i := 0; i < 5; i++ {
+		s.AddTask(fmt.Sprintf("Task %d", i), "")
+	}
+
+	render := func() string {
+		s.cache.reset()
+		w := httptest.NewRecorder()
+		NewServer(s).columnHandler(w, httptest.NewRequest(http.MethodGet, "/column/todo", nil))
+		return w.Body.String()
+	}
+	if first, second := render(), render(); first != second {
+		t.Errorf("Expected identical renders of the same column")
+	}
+}
+
+func TestMoveToTopAndBottom(t *testing.T) {
+	s := newTestStore()
+	for _, title := range []string{"A", "B", "C", "D"} {
+		s.AddTask(title, "")
+	}
+	s.CreateTask(TaskSpec{Title: "Elsewhere", Status: "doing"})
+
+	task, err := s.MoveToTop(3)
+	if err != nil {
+		t.Fatalf("MoveToTop failed: %v", err)
+	}
+	if task.Position != 1 {
+		t.Errorf("Expected the moved task at position 1, got %d", task.Position)
+	}
+	if ids := taskIDs(s.GetTasksByStatus("todo")); !equalIDs(ids, []int{3, 1, 2, 4}) {
+		t.Errorf("Expected todo in order [3 1 2 4], got %v", ids)
+	}
+
+	if _, err := s.MoveToBottom(1); err != nil {
+		t.Fatalf("MoveToBottom failed: %v", err)
+	}
+	todo := s.GetTasksByStatus("todo")
+	if ids := taskIDs(todo); !equalIDs(ids, []int{3, 2, 4, 1}) {
+		t.Errorf("Expected todo in order [3 2 4 1], got %v", ids)
+	}
+	for i, task := range todo {
+		if task.Position != i+1 {
+			t.Errorf("Expected contiguous positions from 1, task %d is at %d", task.ID, task.Position)
+		}
+	}
+	if other, _ := s.GetTask(5); other.Position != 0 {
+		t.Errorf("Expected other columns untouched, got position %d", other.Position)
+	}
+
+	if _, err := s.MoveToTop(99); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestNormalizePositions(t *testing.T) {
+	s := newTestStore()
+	for _, title := range []string{"A", "B", "C"} {
+		s.AddTask(title, "")
+	}
+	s.UpdateTask(1, func(task *Task) { task.Position = 40 })
+	s.UpdateTask(3, func(task *Task) { task.Position = -7 })
+
+	s.NormalizePositions("todo")
+	for id, want := range map[int]int{3: 1, 2: 2, 1: 3} {
+		if task, _ := s.GetTask(id); task.Position != want {
+			t.Errorf("Task %d: expected position %d, got %d", id, want, task.Position)
+		}
+	}
+}
+
+func TestMoveToEdgeHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	for _, title := range []string{"A", "B", "C"} {
+		s.AddTask(title, "")
+	}
+
+	w := httptest.NewRecorder()
+	NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodPost, "/api/v1/tasks/3/move-to-top", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"position":1`) {
+		t.Errorf("Expected the task at position 1, got %d %q", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodPost, "/api/v1/tasks/3/move-to-bottom", nil))
+	if ids := taskIDs(s.GetTasksByStatus("todo")); w.Code != http.StatusOK || !equalIDs(ids, []int{1, 2, 3}) {
+		t.Errorf("Expected todo in order [1 2 3], got %d %v", w.Code, ids)
+	}
+
+	for _, tc := range []struct {
+		method, path string
+		code         int
+	}{
+		{http.MethodGet, "/api/v1/tasks/3/move-to-top", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/api/v1/tasks/9/move-to-bottom", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		NewServer(s).taskAPIHandler(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.code {
+			t.Errorf("%s %s: expected %d, got %d", tc.method, tc.path, tc.code, w.Code)
+		}
+	}
+}
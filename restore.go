@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// restoreTokenTTL is how long a restore confirmation token stays valid
+const restoreTokenTTL = 60 * time.Second
+
+var (
+	errRestoreTokenNotFound = errors.New("confirm token not found or already used")
+	errRestoreTokenExpired  = errors.New("confirm token has expired")
+	errRestoreTokenMismatch = errors.New("confirm token was issued for another snapshot")
+)
+
+// pendingRestore is a restore waiting on confirmation
+type pendingRestore struct {
+	SnapshotID string
+	ExpiresAt  time.Time
+}
+
+// RestoreConfirmations hands out single-use tokens confirming a restore, so
+// a restore takes two deliberate requests
+type RestoreConfirmations struct {
+	mu      sync.Mutex
+	pending map[string]pendingRestore
+	now     func() time.Time // overridable clock for tests
+}
+
+var restoreConfirmations = NewRestoreConfirmations()
+
+// NewRestoreConfirmations returns a store with no pending restores
+func NewRestoreConfirmations() *RestoreConfirmations {
+	return &RestoreConfirmations{pending: make(map[string]pendingRestore), now: time.Now}
+}
+
+// Issue returns a new token confirming a restore of the named snapshot
+func (rc *RestoreConfirmations) Issue(snapshotID string) (string, time.Time, error) {
+	token, err := newInviteToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	now := rc.now()
+	for t, p := range rc.pending {
+		if !now.Before(p.ExpiresAt) {
+			delete(rc.pending, t)
+		}
+	}
+	expires := now.Add(restoreTokenTTL)
+	rc.pending[token] = pendingRestore{SnapshotID: snapshotID, ExpiresAt: expires}
+	return token, expires, nil
+}
+
+// Redeem uses up a token for the named snapshot. A token works once, so
+// repeating a confirmed request can't restore twice.
+func (rc *RestoreConfirmations) Redeem(token, snapshotID string) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	p, ok := rc.pending[token]
+	switch {
+	case !ok:
+		return errRestoreTokenNotFound
+	case !rc.now().Before(p.ExpiresAt):
+		delete(rc.pending, token)
+		return errRestoreTokenExpired
+	case p.SnapshotID != snapshotID:
+		return errRestoreTokenMismatch
+	}
+	delete(rc.pending, token)
+	return nil
+}
+
+// Restore replaces every task with copies of the snapshot's and saves the
+// board. Links, comments and relationships of tasks the snapshot doesn't
+// have are dropped. IDs keep counting from the higher of the two next IDs
+// so new tasks never reuse the ID of one that was restored away. It returns
+// ErrBoardLocked while the board is locked.
+func (s *TaskStore) Restore(data PersistentData) error {
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	defer s.invalidatePages()
+	defer s.mu.Unlock()
+
+	// Save the columns the old tasks were in too, so they're emptied
+	statuses := s.allStatuses()
+	removed := make([]int, 0, len(s.tasks))
+	for id := range s.tasks {
+		removed = append(removed, id)
+	}
+	tasks := make(map[int]*Task, len(data.Tasks))
+	for _, task := range data.Tasks {
+		tasks[task.ID] = task.clone()
+	}
+	for id := range s.tasks {
+		if _, ok := tasks[id]; !ok {
+			delete(s.links, id)
+			s.deleteComments(id)
+			s.deleteRelationships(id)
+		}
+	}
+	s.tasks = tasks
+	s.deps.reset(tasks)
+	if data.NextID > s.nextID {
+		s.nextID = data.NextID
+	}
+	seen := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		seen[status] = true
+	}
+	for _, status := range s.allStatuses() {
+		if !seen[status] {
+			statuses = append(statuses, status)
+		}
+	}
+	s.cache.reset()
+	s.persist(statuses...)
+	if s.partitions != nil {
+		s.persistLinks()
+		s.persistComments()
+		s.persistRelationships()
+	}
+	s.mirrorBoard(removed)
+	return nil
+}
+
+// restoreHandler serves POST /admin/restore?snapshot_id=<name>. Without a
+// confirm_token it returns 202 with a token valid for a minute; repeating
+// the request with that token backs the current board up as a
+// "pre-restore-<timestamp>" snapshot and then restores the named one.
+func (srv *Server) restoreHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("snapshot_id")
+	if id == "" {
+		http.Error(w, "snapshot_id is required", http.StatusBadRequest)
+		return
+	}
+	snap, ok := snapshots.Get(id)
+	if !ok {
+		http.Error(w, "Snapshot not found", http.StatusNotFound)
+		return
+	}
+
+	token := r.FormValue("confirm_token")
+	if token == "" {
+		issued, expires, err := restoreConfirmations.Issue(id)
+		if err != nil {
+			http.Error(w, "Could not create confirm token", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{
+			"snapshot_id":   id,
+			"task_count":    len(snap.Data.Tasks),
+			"confirm_token": issued,
+			"expires_at":    expires,
+		})
+		return
+	}
+	if err := restoreConfirmations.Redeem(token, id); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if err := srv.BoardLock(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	now := time.Now()
+	backup := &BoardSnapshot{
+		Name:      "pre-restore-" + now.UTC().Format("20060102T150405.000Z"),
+		CreatedAt: now,
+		Data:      srv.Snapshot(),
+	}
+	if !snapshots.Add(backup) {
+		http.Error(w, "Backup snapshot already exists", http.StatusConflict)
+		return
+	}
+	if err := srv.Restore(snap.Data); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"snapshot_id": id,
+		"backup":      backup.Name,
+		"task_count":  len(snap.Data.Tasks),
+	})
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func restoreRequest(query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/admin/restore?"+query, nil)
+	req.Header.Set(adminKeyHeader, "s3cret")
+	rr := httptest.NewRecorder()
+	requireAdminKey(NewServer(store).restoreHandler)(rr, req)
+	return rr
+}
+
+func TestRestoreHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	origSnapshots, origConfirmations := snapshots, restoreConfirmations
+	defer func() { snapshots, restoreConfirmations = origSnapshots, origConfirmations }()
+	snapshots = &SnapshotStore{limit: maxSnapshots}
+	restoreConfirmations = NewRestoreConfirmations()
+	t.Setenv("KANBAN_ADMIN_KEY", "s3cret")
+
+	s.AddTask("Keep", "")
+	s.AddTask("Doomed", "")
+	snapshots.Add(&BoardSnapshot{Name: "good", Data: s.Snapshot()})
+	s.DeleteTask(2)
+	s.MoveTask(1, "done")
+	s.AddTask("Later", "")
+
+	if rr := restoreRequest("snapshot_id=missing"); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown snapshot, got %d", rr.Code)
+	}
+
+	rr := restoreRequest("snapshot_id=good")
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202 asking for confirmation, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var pending struct {
+		Token string `json:"confirm_token"`
+	}
+	json.Unmarshal(rr.Body.Bytes(), &pending)
+	if pending.Token == "" {
+		t.Fatalf("Expected a confirm token, got %s", rr.Body.String())
+	}
+	if len(s.GetAllTasks()) != 2 {
+		t.Errorf("Expected the unconfirmed request to leave the board alone")
+	}
+
+	rr = restoreRequest("snapshot_id=good&confirm_token=" + pending.Token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200 once confirmed, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var result struct {
+		Backup string `json:"backup"`
+	}
+	json.Unmarshal(rr.Body.Bytes(), &result)
+
+	tasks := s.GetAllTasks()
+	if len(tasks) != 2 || tasks[0].Title != "Keep" || tasks[0].Status != "todo" || tasks[1].Title != "Doomed" {
+		t.Errorf("Expected the snapshot's tasks back, got %+v", tasks)
+	}
+	if task := s.AddTask("New", ""); task.ID != 4 {
+		t.Errorf("Expected IDs to continue past the restored-away task, got %d", task.ID)
+	}
+
+	backup, ok := snapshots.Get(result.Backup)
+	if !ok || !strings.HasPrefix(result.Backup, "pre-restore-") {
+		t.Fatalf("Expected a pre-restore backup, got %q", result.Backup)
+	}
+	if len(backup.Data.Tasks) != 2 || backup.Data.Tasks[1].Title != "Later" {
+		t.Errorf("Expected the backup to hold the board as it was, got %+v", backup.Data.Tasks)
+	}
+
+	if rr := restoreRequest("snapshot_id=good&confirm_token=" + pending.Token); rr.Code != http.StatusConflict {
+		t.Errorf("Expected a reused token to be rejected, got %d", rr.Code)
+	}
+	if n := len(snapshots.List()); n != 2 {
+		t.Errorf("Expected the rejected repeat to make no second backup, got %d snapshots", n)
+	}
+}
+
+func TestRestoreConfirmationsExpire(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rc := NewRestoreConfirmations()
+	rc.now = func() time.Time { return now }
+
+	token, _, _ := rc.Issue("good")
+	if err := rc.Redeem(token, "other"); err != errRestoreTokenMismatch {
+		t.Errorf("Expected a token for another snapshot to be refused, got %v", err)
+	}
+	now = now.Add(restoreTokenTTL)
+	if err := rc.Redeem(token, "good"); err != errRestoreTokenExpired {
+		t.Errorf("Expected the token to expire after %v, got %v", restoreTokenTTL, err)
+	}
+
+	token, _, _ = rc.Issue("good")
+	now = now.Add(restoreTokenTTL - time.Second)
+	if err := rc.Redeem(token, "good"); err != nil {
+		t.Errorf("Expected a fresh token to work, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// reviewStatus is the column tasks wait in for approval when review is
+// required
+const reviewStatus = "review"
+
+// requireReview is set by KANBAN_REQUIRE_REVIEW=true. Tasks must then pass
+// through review, and be approved by someone other than their assignee,
+// before they are done.
+var requireReview = os.Getenv("KANBAN_REQUIRE_REVIEW") == "true"
+
+// ErrSelfApproval is returned when a task's assignee tries to approve it
+var ErrSelfApproval = errors.New("tasks must be approved by someone other than their assignee")
+
+// ErrReviewRequired is returned when a task is moved straight to done while
+// review is required
+type ErrReviewRequired struct {
+	ID   int
+	From string
+}
+
+func (e *ErrReviewRequired) Error() string {
+	if e.From == reviewStatus {
+		return fmt.Sprintf("task %d is waiting for review; approve it with POST /task/%d/approve", e.ID, e.ID)
+	}
+	return fmt.Sprintf("task %d needs review before it is done; move it to %q and have someone other than the assignee approve it", e.ID, reviewStatus)
+}
+
+// ensureReviewColumn adds the review column before done, or last if there
+// is no done column, unless the board already has one
+func ensureReviewColumn() {
+	if isValidStatus(reviewStatus) {
+		return
+	}
+	review := columnDef{Status: reviewStatus, DisplayName: "Review"}
+	var cols []columnDef
+	for _, col := range columns() {
+		if col.Status == "done" {
+			cols = append(cols, review)
+		}
+		cols = append(cols, col)
+	}
+	if len(cols) == len(columns()) {
+		cols = append(cols, review)
+	}
+	setColumns(cols)
+}
+
+// checkReview refuses moves into done that skip review. An approval must
+// come from someone other than the assignee, for a task in review.
+func checkReview(task *Task, toStatus, approver string) error {
+	if approver != "" {
+		if task.Status != reviewStatus {
+			return &ErrNotInColumn{ID: task.ID, Status: reviewStatus}
+		}
+		if strings.EqualFold(approver, task.Assignee) {
+			return ErrSelfApproval
+		}
+		return nil
+	}
+	if requireReview && toStatus == "done" && task.Status != "done" {
+		return &ErrReviewRequired{ID: task.ID, From: task.Status}
+	}
+	return nil
+}
+
+// ApproveTask moves a task in review to done, recording requestedBy as its
+// approver. It returns ErrTaskNotFound, ErrNotInColumn if the task isn't in
+// review, ErrSelfApproval if requestedBy is its assignee, and otherwise any
+// error MoveTask would.
+func (s *TaskStore) ApproveTask(id int, requestedBy string) (*Task, error) {
+	if strings.TrimSpace(requestedBy) == "" {
+		return nil, fieldError("requested_by", "requested_by is required")
+	}
+	task, found, err := s.moveTask(context.Background(), id, "done", strings.TrimSpace(requestedBy))
+	if !found {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task.clone(), nil
+}
+
+// taskApproveHandler serves POST /task/{id}/approve, approving the task's
+// review on behalf of {"requested_by"}
+func (srv *Server) taskApproveHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var input struct {
+		RequestedBy string `json:"requested_by"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	task, err := srv.ApproveTask(id, input.RequestedBy)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
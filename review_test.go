@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// withReviewRequired turns on KANBAN_REQUIRE_REVIEW for the test
+func withReviewRequired(t *testing.T) {
+	withTestColumns(t)
+	orig := requireReview
+	t.Cleanup(func() { requireReview = orig })
+	requireReview = true
+	ensureReviewColumn()
+}
+
+func TestEnsureReviewColumn(t *testing.T) {
+	withReviewRequired(t)
+	ensureReviewColumn()
+	var statuses []string
+	for _, col := range columns() {
+		statuses = append(statuses, col.Status)
+	}
+	if strings.Join(statuses, ",") != "todo,doing,review,done" {
+		t.Errorf("Expected review before done once, got %v", statuses)
+	}
+}
+
+func TestReviewTwoStepFlow(t *testing.T) {
+	withReviewRequired(t)
+	s := newTestStore()
+	task, _ := s.CreateTask(TaskSpec{Title: "Ship it", Assignee: "alice"})
+	s.MoveTask(task.ID, "doing")
+
+	var needsReview *ErrReviewRequired
+	if _, _, err := s.MoveTask(task.ID, "done"); !errors.As(err, &needsReview) {
+		t.Fatalf("Expected ErrReviewRequired, got %v", err)
+	}
+	if code, _ := errorToHTTP(needsReview); code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422, got %d", code)
+	}
+	if _, err := s.ApproveTask(task.ID, "bob"); err == nil {
+		t.Errorf("Expected approving a task outside review to fail")
+	}
+
+	if _, _, err := s.MoveTask(task.ID, reviewStatus); err != nil {
+		t.Fatalf("Expected the move to review to succeed, got %v", err)
+	}
+	if _, _, err := s.MoveTask(task.ID, "done"); !errors.As(err, &needsReview) {
+		t.Errorf("Expected moves out of review to need an approval, got %v", err)
+	}
+	approved, err := s.ApproveTask(task.ID, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != "done" || approved.ReviewRequestedBy != "bob" {
+		t.Errorf("Expected bob's approval to finish the task, got %s by %q", approved.Status, approved.ReviewRequestedBy)
+	}
+}
+
+func TestApproveOwnTask(t *testing.T) {
+	withReviewRequired(t)
+	s := withTestGlobals(t)
+	task, _ := s.CreateTask(TaskSpec{Title: "Mine", Assignee: "alice"})
+	s.MoveTask(task.ID, reviewStatus)
+
+	approve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/task/1/approve", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		NewServer(s).taskRouter(w, req)
+		return w
+	}
+	if w := approve(`{"requested_by": "Alice"}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for the assignee, got %d", w.Code)
+	}
+	if w := approve(`{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without requested_by, got %d", w.Code)
+	}
+	if got, _ := s.GetTask(task.ID); got.Status != reviewStatus {
+		t.Errorf("Expected the task to stay in review, got %s", got.Status)
+	}
+	if w := approve(`{"requested_by": "bob"}`); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"review_requested_by":"bob"`) {
+		t.Errorf("Expected 200 with bob recorded, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// matchesQuery reports whether a task's title, description, assignee or any
+// label contains the lowercased query
+func (t *Task) matchesQuery(query string) bool {
+	if strings.Contains(strings.ToLower(t.Title), query) ||
+		strings.Contains(strings.ToLower(t.Description), query) ||
+		strings.Contains(strings.ToLower(t.Assignee), query) {
+		return true
+	}
+	for _, label := range t.Labels {
+		if strings.Contains(strings.ToLower(label), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeQuery trims and lowercases a search query
+func normalizeQuery(query string) string {
+	return strings.ToLower(strings.TrimSpace(query))
+}
+
+// SearchTasks returns the tasks matching query case-insensitively, ordered
+// by ID. An empty query matches every task.
+func (s *TaskStore) SearchTasks(query string) []*Task {
+	query = normalizeQuery(query)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks := []*Task{}
+	for _, task := range s.tasks {
+		if task.matchesQuery(query) {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
+// SearchBoardData builds the board keeping only the tasks matching query,
+// each in its own column. Counts and effort cover the matches; WIP warnings
+// still reflect the whole column.
+func (s *TaskStore) SearchBoardData(query string) BoardData {
+	span := s.startSpan(context.Background(), "SearchBoardData")
+	defer span.End()
+
+	normalized := normalizeQuery(query)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var data BoardData
+	for _, col := range s.columnList() {
+		column := s.columnData(col)
+		column.Query = strings.TrimSpace(query)
+		var matches []*Task
+		column.TotalEffort = 0
+		for _, task := range column.Tasks {
+			if task.matchesQuery(normalized) {
+				matches = append(matches, task)
+				column.TotalEffort += task.Effort
+			}
+		}
+		column.Tasks = matches
+		column.Count = len(matches)
+		data.Columns = append(data.Columns, column)
+	}
+	return data
+}
+
+// searchHandler serves GET /search?q=, returning the board's columns with
+// only the matching tasks. An empty query returns the whole board.
+func (srv *Server) searchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data := srv.GetBoardData()
+	if query := strings.TrimSpace(r.URL.Query().Get("q")); query != "" {
+		data = srv.SearchBoardData(query)
+	}
+	templates.ExecuteTemplate(w, "all-columns.html", data)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSearchTasksMatchesFieldsCaseInsensitively(t *testing.T) {
+	s := newTestStore()
+	s.CreateTask(TaskSpec{Title: "Fix LOGIN bug"})
+	s.CreateTask(TaskSpec{Title: "Docs", Description: "Explain login flow", Status: "doing"})
+	s.CreateTask(TaskSpec{Title: "Refactor", Assignee: "Loginov", Status: "done"})
+	s.CreateTask(TaskSpec{Title: "Styling", Labels: []string{"frontend", "Login-page"}})
+	s.CreateTask(TaskSpec{Title: "Unrelated"})
+
+	if ids := taskIDs(s.SearchTasks("  login ")); !equalIDs(ids, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected tasks 1-4 to match, got %v", ids)
+	}
+	if got := s.SearchTasks("nothing matches this"); len(got) != 0 {
+		t.Errorf("Expected no matches, got %v", taskIDs(got))
+	}
+	if got := s.SearchTasks(""); len(got) != 5 {
+		t.Errorf("Expected an empty query to match everything, got %d", len(got))
+	}
+}
+
+func TestSearchHandlerKeepsMatchesInTheirColumns(t *testing.T) {
+	s := withTestGlobals(t)
+	s.CreateTask(TaskSpec{Title: "Write release notes"})
+	s.CreateTask(TaskSpec{Title: "Cut release", Status: "doing"})
+	s.CreateTask(TaskSpec{Title: "Plan sprint", Status: "doing"})
+
+	data := s.SearchBoardData("RELEASE")
+	for _, col := range data.Columns {
+		want := map[string][]int{"todo": {1}, "doing": {2}, "done": nil}[col.Status]
+		if ids := taskIDs(col.Tasks); !equalIDs(ids, want) || col.Count != len(want) {
+			t.Errorf("Column %s: expected %v, got %v (count %d)", col.Status, want, ids, col.Count)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	NewServer(s).searchHandler(w, httptest.NewRequest(http.MethodGet, "/search?q=release", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, "Write release notes") || !strings.Contains(body, "Cut release") || strings.Contains(body, "Plan sprint") {
+		t.Errorf("Expected only the matching tasks, got %s", body)
+	}
+	if !strings.Contains(body, `id="done-tasks"`) || !strings.Contains(body, `No tasks match "release"`) {
+		t.Errorf("Expected an empty placeholder for the done column")
+	}
+}
+
+func TestSearchHandlerNoMatches(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Only task", "")
+
+	w := httptest.NewRecorder()
+	NewServer(s).searchHandler(w, httptest.NewRequest(http.MethodGet, "/search?q=zzz", nil))
+	body := w.Body.String()
+	if strings.Contains(body, "Only task") {
+		t.Errorf("Expected no task cards, got %s", body)
+	}
+	if n := strings.Count(body, `No tasks match "zzz"`); n != len(boardColumns) {
+		t.Errorf("Expected a placeholder in every column, got %d", n)
+	}
+
+	w = httptest.NewRecorder()
+	NewServer(s).searchHandler(w, httptest.NewRequest(http.MethodGet, "/search?q=", nil))
+	if !strings.Contains(w.Body.String(), "Only task") {
+		t.Errorf("Expected an empty query to return the whole board")
+	}
+}
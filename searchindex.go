@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// defaultSearchIndexDebounce is how long the index waits after a change
+// before rebuilding, so a burst of changes costs one rebuild
+const defaultSearchIndexDebounce = 500 * time.Millisecond
+
+// SearchIndex is an inverted index from the words in tasks' titles,
+// descriptions, assignees and labels to the tasks containing them. It is
+// rebuilt by a background job a moment after the board changes, so searches
+// may miss changes made within the last debounce interval.
+type SearchIndex struct {
+	store    *TaskStore
+	debounce time.Duration
+	pending  atomic.Bool // a rebuild is scheduled and hasn't started yet
+	building sync.Mutex  // held by Rebuild so an older rebuild can't overwrite a newer one
+
+	mu     sync.RWMutex
+	tokens map[string][]int // token to task IDs, ascending
+}
+
+// searchIndex serves /api/v1/search, nil until main builds it
+var searchIndex *SearchIndex
+
+// NewSearchIndex returns an index of s, built straight away
+func NewSearchIndex(s *TaskStore, debounce time.Duration) *SearchIndex {
+	x := &SearchIndex{store: s, debounce: debounce}
+	x.Rebuild()
+	return x
+}
+
+// searchTokens splits text into lowercase words, dropping punctuation
+func searchTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// taskTokens returns the distinct words in the fields a search looks at
+func taskTokens(task *Task) []string {
+	fields := append([]string{task.Title, task.Description, task.Assignee}, task.Labels...)
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, field := range fields {
+		for _, token := range searchTokens(field) {
+			if !seen[token] {
+				seen[token] = true
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens
+}
+
+// Rebuild indexes every task now
+func (x *SearchIndex) Rebuild() {
+	x.building.Lock()
+	defer x.building.Unlock()
+
+	x.store.mu.Lock()
+	ids := make([]int, 0, len(x.store.tasks))
+	for id := range x.store.tasks {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	tokens := make(map[string][]int)
+	for _, id := range ids {
+		for _, token := range taskTokens(x.store.tasks[id]) {
+			tokens[token] = append(tokens[token], id)
+		}
+	}
+	x.store.mu.Unlock()
+
+	x.mu.Lock()
+	x.tokens = tokens
+	x.mu.Unlock()
+}
+
+// Changed schedules a rebuild on the job queue a debounce interval from
+// now. It never blocks: changes made before the scheduled rebuild starts
+// are folded into it.
+func (x *SearchIndex) Changed() {
+	if !x.pending.CompareAndSwap(false, true) {
+		return
+	}
+	time.AfterFunc(x.debounce, func() {
+		err := runInBackground("search index", func(ctx context.Context) error {
+			// Changes from here on need a rebuild of their own
+			x.pending.Store(false)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			x.Rebuild()
+			return nil
+		})
+		if err != nil {
+			x.pending.Store(false)
+		}
+	})
+}
+
+// Search returns the tasks containing every word of query, ordered by ID.
+// An empty query matches every task. Unlike SearchTasks, words match whole
+// words rather than any part of a field.
+func (x *SearchIndex) Search(query string) []*Task {
+	words := searchTokens(query)
+	if len(words) == 0 {
+		return x.store.GetAllTasks()
+	}
+
+	x.mu.RLock()
+	ids := x.tokens[words[0]]
+	for _, word := range words[1:] {
+		if len(ids) == 0 {
+			break
+		}
+		ids = intersectIDs(ids, x.tokens[word])
+	}
+	x.mu.RUnlock()
+
+	x.store.mu.Lock()
+	defer x.store.mu.Unlock()
+	tasks := []*Task{}
+	for _, id := range ids {
+		// Deleted since the last rebuild
+		if task, ok := x.store.tasks[id]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// intersectIDs returns the IDs in both ascending lists, ascending
+func intersectIDs(a, b []int) []int {
+	var both []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			both = append(both, a[i])
+			i++
+			j++
+		}
+	}
+	return both
+}
+
+// searchAPIHandler serves GET /api/v1/search?q=, returning the tasks
+// containing every word of q as JSON
+func searchAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if searchIndex == nil {
+		http.Error(w, "Search index not ready", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, http.StatusOK, searchIndex.Search(r.URL.Query().Get("q")))
+}
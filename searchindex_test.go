@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSearchTokens(t *testing.T) {
+	got := searchTokens("Fix LOGIN-page bug, (v2.1) café!")
+	want := []string{"fix", "login", "page", "bug", "v2", "1", "café"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestSearchIndex(t *testing.T) {
+	s := newTestStore()
+	s.CreateTask(TaskSpec{Title: "Fix login bug"})
+	s.CreateTask(TaskSpec{Title: "Docs", Description: "Explain the login flow.", Status: "doing"})
+	s.CreateTask(TaskSpec{Title: "Refactor", Assignee: "Loginov"})
+	s.CreateTask(TaskSpec{Title: "Styling", Labels: []string{"login-page"}})
+	x := NewSearchIndex(s, time.Hour)
+
+	if ids := taskIDs(x.Search("LOGIN")); !equalIDs(ids, []int{1, 2, 4}) {
+		t.Errorf("Expected tasks 1, 2 and 4, got %v", ids)
+	}
+	if ids := taskIDs(x.Search("login, flow")); !equalIDs(ids, []int{2}) {
+		t.Errorf("Expected only task 2 to have both words, got %v", ids)
+	}
+	if ids := taskIDs(x.Search("login nothing")); len(ids) != 0 {
+		t.Errorf("Expected no matches, got %v", ids)
+	}
+	if got := x.Search("  "); len(got) != 4 {
+		t.Errorf("Expected an empty query to match everything, got %d", len(got))
+	}
+
+	// Changes show up once the index is rebuilt, except that deleted tasks
+	// drop out straight away
+	s.CreateTask(TaskSpec{Title: "Login audit"})
+	s.UpdateTask(1, func(task *Task) { task.Title = "Fix signup bug" })
+	s.DeleteTask(4)
+	if ids := taskIDs(x.Search("login")); !equalIDs(ids, []int{1, 2}) {
+		t.Errorf("Expected the stale index without the deleted task, got %v", ids)
+	}
+	x.Rebuild()
+	if ids := taskIDs(x.Search("login")); !equalIDs(ids, []int{2, 5}) {
+		t.Errorf("Expected tasks 2 and 5 after a rebuild, got %v", ids)
+	}
+	if ids := taskIDs(x.Search("signup")); !equalIDs(ids, []int{1}) {
+		t.Errorf("Expected the renamed task, got %v", ids)
+	}
+}
+
+// waitForSearch polls until query finds want or a second passes
+func waitForSearch(t *testing.T, x *SearchIndex, query string, want []int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		ids := taskIDs(x.Search(query))
+		if equalIDs(ids, want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%q: expected %v, still got %v", query, want, ids)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSearchIndexRebuildsAfterChanges(t *testing.T) {
+	s := withTestGlobals(t)
+	x := NewSearchIndex(s, 10*time.Millisecond)
+	bus.Subscribe(EventAll, func(Event) { x.Changed() })
+
+	s.CreateTask(TaskSpec{Title: "Renew certificates"})
+	s.CreateTask(TaskSpec{Title: "Renew domain"})
+	waitForSearch(t, x, "renew", []int{1, 2})
+
+	s.UpdateTask(2, func(task *Task) { task.Title = "Transfer domain" })
+	waitForSearch(t, x, "renew", []int{1})
+	waitForSearch(t, x, "transfer", []int{2})
+}
+
+func TestSearchIndexRebuildsOnJobQueue(t *testing.T) {
+	orig := jobs
+	t.Cleanup(func() { jobs = orig })
+	jobs = NewJobQueue(10)
+
+	s := withTestGlobals(t)
+	x := NewSearchIndex(s, time.Millisecond)
+	s.CreateTask(TaskSpec{Title: "Renew certificates"})
+	x.Changed()
+	time.Sleep(20 * time.Millisecond)
+	if ids := taskIDs(x.Search("renew")); len(ids) != 0 {
+		t.Fatalf("Expected the rebuild to wait for a worker, got %v", ids)
+	}
+
+	jobs.Start(1)
+	waitForSearch(t, x, "renew", []int{1})
+	jobs.Stop(context.Background())
+}
+
+func TestSearchIndexConcurrentAccess(t *testing.T) {
+	s := newTestStore()
+	x := NewSearchIndex(s, time.Millisecond)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				s.CreateTask(TaskSpec{Title: fmt.Sprintf("Worker w%d shared task %d", w, i)})
+				x.Changed()
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				for _, task := range x.Search("shared task") {
+					if task.ID < 1 {
+						t.Errorf("Unexpected task %+v", task)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	x.Rebuild()
+	if got := len(x.Search("shared")); got != 200 {
+		t.Errorf("Expected all 200 tasks after the writers finish, got %d", got)
+	}
+	if got := len(x.Search("worker w3")); got != 50 {
+		t.Errorf("Expected worker 3's 50 tasks, got %d", got)
+	}
+}
+
+func TestSearchAPIHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.CreateTask(TaskSpec{Title: "Write release notes"})
+	s.CreateTask(TaskSpec{Title: "Cut release", Status: "doing"})
+	orig := searchIndex
+	searchIndex = NewSearchIndex(s, time.Hour)
+	t.Cleanup(func() { searchIndex = orig })
+
+	w := httptest.NewRecorder()
+	searchAPIHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/search?q=release+notes", nil))
+	var tasks []*Task
+	if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", w.Body.String(), err)
+	}
+	if !equalIDs(taskIDs(tasks), []int{1}) {
+		t.Errorf("Expected only task 1, got %v", taskIDs(tasks))
+	}
+
+	w = httptest.NewRecorder()
+	searchAPIHandler(w, httptest.NewRequest(http.MethodPost, "/api/v1/search", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", w.Code)
+	}
+}
+
+func newSearchBenchmarkStore(n int) *TaskStore {
+	s := newTestStore()
+	words := []string{"deploy", "login", "report", "billing", "cache", "docs", "api", "mobile"}
+	for i := 0; i < n; i++ {
+		s.newTask(TaskSpec{
+			Title:       fmt.Sprintf("%s %s task %d", words[i%len(words)], words[(i/8)%len(words)], i),
+			Description: "Some longer description of the work to be done",
+		})
+	}
+	return s
+}
+
+func BenchmarkSearchIndex(b *testing.B) {
+	s := newSearchBenchmarkStore(10000)
+	x := NewSearchIndex(s, time.Hour)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Search("billing")
+	}
+}
+
+func BenchmarkSearchScan(b *testing.B) {
+	s := newSearchBenchmarkStore(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.SearchTasks("billing")
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxColumnNameLength caps column display names set through settings
+const maxColumnNameLength = 40
+
+// settingRule validates values for keys of the form "<prefix>.<status>"
+type settingRule struct {
+	prefix   string
+	validate func(value string) error
+}
+
+// settingRules is the schema of known per-column settings
+var settingRules = []settingRule{
+	{prefix: "wip_limit", validate: func(value string) error {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return fmt.Errorf("must be a non-negative integer")
+		}
+		return nil
+	}},
+	{prefix: "column_name", validate: func(value string) error {
+		if strings.TrimSpace(value) == "" || len(value) > maxColumnNameLength {
+			return fmt.Errorf("must be 1-%d characters", maxColumnNameLength)
+		}
+		return nil
+	}},
+	{prefix: columnColorPrefix, validate: validateColumnColor},
+	{prefix: acceptFromPrefix, validate: func(value string) error {
+		_, err := parseAcceptFrom(value)
+		return err
+	}},
+}
+
+// validateSetting checks a key against the schema and its value against the
+// key's rule
+func validateSetting(key, value string) error {
+	if key == columnsSettingKey {
+		if _, err := parseColumns(value); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		return nil
+	}
+	if name, ok := strings.CutPrefix(key, labelSettingPrefix); ok {
+		if err := validateLabel(name, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		return nil
+	}
+	if id, ok := strings.CutPrefix(key, sprintSettingPrefix); ok {
+		if err := validateSprint(id, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		return nil
+	}
+	if action, ok := strings.CutPrefix(key, shortcutSettingPrefix); ok {
+		if err := validateShortcut(action, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		return nil
+	}
+	prefix, status, ok := strings.Cut(key, ".")
+	if !ok || !isValidStatus(status) {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	for _, rule := range settingRules {
+		if rule.prefix == prefix {
+			if err := rule.validate(value); err != nil {
+				return fmt.Errorf("invalid %s: %w", key, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown setting %q", key)
+}
+
+// SettingsStore holds board-level settings persisted to a JSON file. Unlike
+// env vars, settings can be changed while the server is running.
+type SettingsStore struct {
+	mu       sync.RWMutex
+	values   map[string]string
+	filePath string
+	sprintMu sync.Mutex // held while a new sprint's ID is picked and saved
+}
+
+// NewSettingsStore returns an empty store saving to filePath, or keeping its
+// settings in memory only if filePath is ""
+func NewSettingsStore(filePath string) *SettingsStore {
+	return &SettingsStore{values: make(map[string]string), filePath: filePath}
+}
+
+// Copy returns an in-memory store holding the same settings, which changes
+// to either store leave the other without
+func (ss *SettingsStore) Copy() *SettingsStore {
+	if ss == nil {
+		return nil
+	}
+	copied := NewSettingsStore("")
+	copied.values = ss.All()
+	return copied
+}
+
+// getSettingsFilePath returns the settings file path from env var or default
+func getSettingsFilePath() string {
+	if path := os.Getenv("KANBAN_SETTINGS_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(".", "settings.json")
+}
+
+var settings = NewSettingsStore(getSettingsFilePath())
+
+// Load reads settings from disk, dropping keys that no longer validate
+func (ss *SettingsStore) Load() error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	data, err := os.ReadFile(ss.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	values := make(map[string]string)
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("%s: %w", ss.filePath, err)
+	}
+	// Apply the column list first, so settings for added columns validate
+	if value, ok := values[columnsSettingKey]; ok {
+		if cols, err := parseColumns(value); err == nil {
+			setColumns(cols)
+		}
+	}
+	for key, value := range values {
+		if err := validateSetting(key, value); err != nil {
+			log.Printf("Ignoring setting: %v", err)
+			delete(values, key)
+		}
+	}
+	ss.values = values
+	return nil
+}
+
+// Get returns a setting's value and whether it is set
+func (ss *SettingsStore) Get(key string) (string, bool) {
+	if ss == nil {
+		return "", false
+	}
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	value, ok := ss.values[key]
+	return value, ok
+}
+
+// All returns a copy of every setting
+func (ss *SettingsStore) All() map[string]string {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	all := make(map[string]string, len(ss.values))
+	for key, value := range ss.values {
+		all[key] = value
+	}
+	return all
+}
+
+// validateSettings checks every update, reporting the first invalid key in
+// sorted order
+func validateSettings(updates map[string]string) error {
+	keys := make([]string, 0, len(updates))
+	for key := range updates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := validateSetting(key, updates[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Merge validates and applies every update, or none if any is invalid
+func (ss *SettingsStore) Merge(updates map[string]string) error {
+	if err := validateSettings(updates); err != nil {
+		return err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for key, value := range updates {
+		ss.values[key] = value
+	}
+	return ss.save()
+}
+
+// Delete removes a setting, reporting whether it was set
+func (ss *SettingsStore) Delete(key string) (bool, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if _, ok := ss.values[key]; !ok {
+		return false, nil
+	}
+	delete(ss.values, key)
+	return true, ss.save()
+}
+
+// save writes settings to disk (must be called with lock held)
+func (ss *SettingsStore) save() error {
+	if ss.filePath == "" {
+		return nil
+	}
+	return writeJSONFileAtomic(ss.filePath, ss.values)
+}
+
+// wipLimit returns a column's WIP limit, preferring the runtime setting over
+// KANBAN_WIP_LIMITS (must be called with lock held)
+func (s *TaskStore) wipLimit(status string) int {
+	if value, ok := s.settings.Get("wip_limit." + status); ok {
+		if limit, err := strconv.Atoi(value); err == nil {
+			return limit
+		}
+	}
+	return s.wipLimits[status]
+}
+
+// columnName returns a column's display name, honouring any setting
+func (s *TaskStore) columnName(col columnDef) string {
+	if name, ok := s.settings.Get("column_name." + col.Status); ok {
+		return name
+	}
+	return col.DisplayName
+}
+
+// settingsHandler serves GET and POST /settings
+func settingsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, settings.All())
+	case http.MethodPost:
+		var updates map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+			http.Error(w, "Body must be a JSON object of strings", http.StatusBadRequest)
+			return
+		}
+		if err := validateSettings(updates); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := settings.Merge(updates); err != nil {
+			log.Printf("Error saving settings: %v", err)
+			http.Error(w, "Could not save settings", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, settings.All())
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// deleteSettingHandler serves DELETE /settings/{key}
+func deleteSettingHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/settings/")
+	deleted, err := settings.Delete(key)
+	if err != nil {
+		log.Printf("Error saving settings: %v", err)
+		http.Error(w, "Could not save settings", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Setting not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withTestSettings swaps the global settings for an empty store in a temp dir
+func withTestSettings(t *testing.T) *SettingsStore {
+	orig := settings
+	t.Cleanup(func() { settings = orig })
+	settings = NewSettingsStore(filepath.Join(t.TempDir(), "settings.json"))
+	return settings
+}
+
+func TestMoveTaskRespectsWIPLimitSetting(t *testing.T) {
+	s := newTestStore()
+	s.settings = NewSettingsStore(filepath.Join(t.TempDir(), "settings.json"))
+	a := s.AddTask("A", "")
+	b := s.AddTask("B", "")
+
+	if err := s.settings.Merge(map[string]string{"wip_limit.doing": "1"}); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if _, _, err := s.MoveTask(a.ID, "doing"); err != nil {
+		t.Fatalf("First move should fit the limit: %v", err)
+	}
+	_, _, err := s.MoveTask(b.ID, "doing")
+	var wipErr *ErrWIPLimitReached
+	if !errors.As(err, &wipErr) || wipErr.Limit != 1 {
+		t.Fatalf("Expected ErrWIPLimitReached with limit 1, got %v", err)
+	}
+	if task, _ := s.GetTask(b.ID); task.Status != "todo" {
+		t.Errorf("Rejected move must leave the task in todo")
+	}
+
+	// Raising the limit at runtime lets the move through
+	s.settings.Merge(map[string]string{"wip_limit.doing": "2"})
+	if _, _, err := s.MoveTask(b.ID, "doing"); err != nil {
+		t.Errorf("Expected move to succeed after raising limit: %v", err)
+	}
+	if limit := s.GetColumnData("doing").WIPLimit; limit != 2 {
+		t.Errorf("Expected column to report limit 2, got %d", limit)
+	}
+}
+
+func TestSettingOverridesEnvWIPLimit(t *testing.T) {
+	s := newTestStore()
+	s.wipLimits = map[string]int{"doing": 1}
+	s.settings = NewSettingsStore(filepath.Join(t.TempDir(), "settings.json"))
+	s.settings.Merge(map[string]string{"wip_limit.doing": "0", "column_name.doing": "In Progress"})
+
+	a := s.AddTask("A", "")
+	b := s.AddTask("B", "")
+	s.MoveTask(a.ID, "doing")
+	if _, _, err := s.MoveTask(b.ID, "doing"); err != nil {
+		t.Errorf("A zero limit setting should lift the env limit: %v", err)
+	}
+	if name := s.GetColumnData("doing").DisplayName; name != "In Progress" {
+		t.Errorf("Expected renamed column, got %q", name)
+	}
+}
+
+func TestSettingsPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	ss := NewSettingsStore(path)
+	ss.Merge(map[string]string{"wip_limit.doing": "3", "column_name.todo": "Backlog"})
+	ss.Delete("column_name.todo")
+
+	loaded := NewSettingsStore(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	all := loaded.All()
+	if len(all) != 1 || all["wip_limit.doing"] != "3" {
+		t.Errorf("Unexpected settings after reload: %v", all)
+	}
+}
+
+func TestSettingsValidation(t *testing.T) {
+	ss := NewSettingsStore(filepath.Join(t.TempDir(), "settings.json"))
+	invalid := []map[string]string{
+		{"theme": "dark"},
+		{"wip_limit.archived": "2"},
+		{"wip_limit.doing": "-1"},
+		{"wip_limit.doing": "lots"},
+		{"column_name.done": " "},
+		{"wip_limit.doing": "2", "column_name.todo": ""},
+	}
+	for _, updates := range invalid {
+		if err := ss.Merge(updates); err == nil {
+			t.Errorf("Expected %v to be rejected", updates)
+		}
+	}
+	if len(ss.All()) != 0 {
+		t.Errorf("Rejected merges must not apply any keys, got %v", ss.All())
+	}
+}
+
+func TestSettingsHandlers(t *testing.T) {
+	withTestSettings(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/settings", strings.NewReader(`{"wip_limit.doing": "4"}`))
+	w := httptest.NewRecorder()
+	settingsHandler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/settings", strings.NewReader(`{"bogus": "1"}`))
+	w = httptest.NewRecorder()
+	settingsHandler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown key, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	settingsHandler(w, httptest.NewRequest(http.MethodGet, "/settings", nil))
+	var all map[string]string
+	json.Unmarshal(w.Body.Bytes(), &all)
+	if len(all) != 1 || all["wip_limit.doing"] != "4" {
+		t.Errorf("Unexpected settings %v", all)
+	}
+
+	w = httptest.NewRecorder()
+	deleteSettingHandler(w, httptest.NewRequest(http.MethodDelete, "/settings/wip_limit.doing", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	deleteSettingHandler(w, httptest.NewRequest(http.MethodDelete, "/settings/wip_limit.doing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing key, got %d", w.Code)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// shortcutSettingPrefix is the settings key prefix holding each overridden
+// shortcut, as "key" or "modifier+key"
+const shortcutSettingPrefix = "shortcut."
+
+const maxShortcutKeyLength = 20
+
+// shortcutModifiers are the modifiers a shortcut may need held, by their
+// KeyboardEvent property minus the "Key" suffix
+var shortcutModifiers = []string{"ctrl", "alt", "shift", "meta"}
+
+// KeyboardShortcut binds a key, optionally with a modifier held, to a board
+// action. Key is a KeyboardEvent.key value such as "n" or "ArrowDown".
+type KeyboardShortcut struct {
+	Key         string `json:"key"`
+	Modifier    string `json:"modifier,omitempty"`
+	Action      string `json:"action"`
+	Description string `json:"description"`
+}
+
+// defaultShortcuts are the shortcuts app.js handles, in the order they are
+// listed
+var defaultShortcuts = []KeyboardShortcut{
+	{Key: "n", Action: "new-task", Description: "Add a new task"},
+	{Key: "/", Action: "search", Description: "Search tasks"},
+	{Key: "m", Action: "move-task", Description: "Move the focused task to the next column"},
+	{Key: "ArrowDown", Action: "focus-down", Description: "Focus the next task in the column"},
+	{Key: "ArrowUp", Action: "focus-up", Description: "Focus the previous task in the column"},
+	{Key: "ArrowLeft", Action: "focus-left", Description: "Focus the first task in the column to the left"},
+	{Key: "ArrowRight", Action: "focus-right", Description: "Focus the first task in the column to the right"},
+}
+
+// defaultShortcut returns the default shortcut for an action
+func defaultShortcut(action string) (KeyboardShortcut, bool) {
+	for _, shortcut := range defaultShortcuts {
+		if shortcut.Action == action {
+			return shortcut, true
+		}
+	}
+	return KeyboardShortcut{}, false
+}
+
+// parseShortcutBinding splits a stored "modifier+key" or "key" binding
+func parseShortcutBinding(value string) (modifier, key string, err error) {
+	key = value
+	if m, k, ok := strings.Cut(value, "+"); ok && containsString(shortcutModifiers, m) {
+		modifier, key = m, k
+	}
+	if key == "" || len(key) > maxShortcutKeyLength || strings.ContainsAny(key, " \t\n") {
+		return "", "", fmt.Errorf("key must be 1-%d characters without spaces", maxShortcutKeyLength)
+	}
+	return modifier, key, nil
+}
+
+// shortcutBinding formats a shortcut's key and modifier for settings
+func shortcutBinding(shortcut KeyboardShortcut) string {
+	if shortcut.Modifier == "" {
+		return shortcut.Key
+	}
+	return shortcut.Modifier + "+" + shortcut.Key
+}
+
+// validateShortcut checks an override of the named action's shortcut
+func validateShortcut(action, value string) error {
+	if _, ok := defaultShortcut(action); !ok {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	_, _, err := parseShortcutBinding(value)
+	return err
+}
+
+// keyboardShortcuts returns the default shortcuts with any overrides in ss
+// applied
+func keyboardShortcuts(ss *SettingsStore) []KeyboardShortcut {
+	shortcuts := make([]KeyboardShortcut, len(defaultShortcuts))
+	copy(shortcuts, defaultShortcuts)
+	for i, shortcut := range shortcuts {
+		value, ok := ss.Get(shortcutSettingPrefix + shortcut.Action)
+		if !ok {
+			continue
+		}
+		if modifier, key, err := parseShortcutBinding(value); err == nil {
+			shortcuts[i].Modifier, shortcuts[i].Key = modifier, key
+		}
+	}
+	return shortcuts
+}
+
+// shortcutConflict returns an error naming two actions bound to the same
+// key and modifier, if any are
+func shortcutConflict(shortcuts []KeyboardShortcut) error {
+	bound := make(map[string]string, len(shortcuts))
+	for _, shortcut := range shortcuts {
+		binding := strings.ToLower(shortcutBinding(shortcut))
+		if other, ok := bound[binding]; ok {
+			return fmt.Errorf("%s is bound to both %s and %s", shortcutBinding(shortcut), other, shortcut.Action)
+		}
+		bound[binding] = shortcut.Action
+	}
+	return nil
+}
+
+// keyboardShortcutsHandler serves GET /settings/keyboard-shortcuts, listing
+// every shortcut with overrides applied, and POST, which overrides some from
+// [{"action": "new-task", "key": "a", "modifier": "alt"}] and returns them
+// all. DELETE /settings/shortcut.{action} restores a default.
+func keyboardShortcutsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, keyboardShortcuts(settings))
+	case http.MethodPost:
+		var overrides []KeyboardShortcut
+		if err := json.NewDecoder(r.Body).Decode(&overrides); err != nil {
+			http.Error(w, "Body must be a JSON array of shortcuts", http.StatusBadRequest)
+			return
+		}
+		updates := make(map[string]string, len(overrides))
+		for _, override := range overrides {
+			if override.Modifier != "" && !containsString(shortcutModifiers, override.Modifier) {
+				http.Error(w, fmt.Sprintf("modifier must be one of %s", strings.Join(shortcutModifiers, ", ")), http.StatusBadRequest)
+				return
+			}
+			updates[shortcutSettingPrefix+override.Action] = shortcutBinding(override)
+		}
+		if err := validateSettings(updates); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Check the result for clashes before saving anything
+		shortcuts := keyboardShortcuts(settings)
+		for i, shortcut := range shortcuts {
+			if value, ok := updates[shortcutSettingPrefix+shortcut.Action]; ok {
+				shortcuts[i].Modifier, shortcuts[i].Key, _ = parseShortcutBinding(value)
+			}
+		}
+		if err := shortcutConflict(shortcuts); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		if err := settings.Merge(updates); err != nil {
+			log.Printf("Error saving settings: %v", err)
+			http.Error(w, "Could not save settings", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, keyboardShortcuts(settings))
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func getShortcuts(t *testing.T, w *httptest.ResponseRecorder) map[string]KeyboardShortcut {
+	t.Helper()
+	var shortcuts []KeyboardShortcut
+	if err := json.Unmarshal(w.Body.Bytes(), &shortcuts); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", w.Body.String(), err)
+	}
+	byAction := make(map[string]KeyboardShortcut, len(shortcuts))
+	for _, shortcut := range shortcuts {
+		byAction[shortcut.Action] = shortcut
+	}
+	return byAction
+}
+
+func postShortcuts(body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	keyboardShortcutsHandler(w, httptest.NewRequest(http.MethodPost, "/settings/keyboard-shortcuts", strings.NewReader(body)))
+	return w
+}
+
+func TestKeyboardShortcutsDefaults(t *testing.T) {
+	withTestSettings(t)
+	w := httptest.NewRecorder()
+	keyboardShortcutsHandler(w, httptest.NewRequest(http.MethodGet, "/settings/keyboard-shortcuts", nil))
+
+	shortcuts := getShortcuts(t, w)
+	if len(shortcuts) != len(defaultShortcuts) {
+		t.Fatalf("Expected %d shortcuts, got %d", len(defaultShortcuts), len(shortcuts))
+	}
+	for _, want := range defaultShortcuts {
+		if got := shortcuts[want.Action]; got != want {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	}
+}
+
+func TestKeyboardShortcutOverride(t *testing.T) {
+	ss := withTestSettings(t)
+
+	w := postShortcuts(`[{"action": "new-task", "key": "a", "modifier": "alt"}]`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	shortcuts := getShortcuts(t, w)
+	if got := shortcuts["new-task"]; got.Key != "a" || got.Modifier != "alt" || got.Description != "Add a new task" {
+		t.Errorf("Expected new-task on alt+a, got %+v", got)
+	}
+	if got := shortcuts["move-task"]; got.Key != "m" || got.Modifier != "" {
+		t.Errorf("Expected move-task to keep its default, got %+v", got)
+	}
+	if value, _ := ss.Get("shortcut.new-task"); value != "alt+a" {
+		t.Errorf("Expected the override in settings, got %q", value)
+	}
+
+	// The default key is free again, so another action can take it
+	if w := postShortcuts(`[{"action": "move-task", "key": "N"}]`); w.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := postShortcuts(`[{"action": "search", "key": "n"}]`); w.Code != http.StatusConflict {
+		t.Errorf("Expected a clash with move-task to be 409, got %d", w.Code)
+	}
+
+	for _, body := range []string{
+		`[{"action": "launch-rocket", "key": "r"}]`,
+		`[{"action": "search", "key": ""}]`,
+		`[{"action": "search", "key": "s", "modifier": "hyper"}]`,
+		`{"search": "s"}`,
+	} {
+		if w := postShortcuts(body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+	if value, _ := ss.Get("shortcut.search"); value != "" {
+		t.Errorf("Expected rejected overrides not to be saved, got %q", value)
+	}
+}
+
+func TestLayoutInjectsKeyboardShortcuts(t *testing.T) {
+	ss := withTestSettings(t)
+	ss.Merge(map[string]string{"shortcut.search": "ctrl+k"})
+
+	body, err := renderPage(httptest.NewRequest(http.MethodGet, "/", nil), "themes.html", ThemesPage{})
+	if err != nil {
+		t.Fatalf("renderPage failed: %v", err)
+	}
+	if !strings.Contains(string(body), `window.keyboardShortcuts = [{"key":"n","action":"new-task"`) ||
+		!strings.Contains(string(body), `{"key":"k","modifier":"ctrl","action":"search"`) {
+		t.Errorf("Expected the shortcuts as JSON in the page, got %s", body)
+	}
+}
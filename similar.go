@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultSimilarityThreshold is used when ?threshold= is not given
+const defaultSimilarityThreshold = 0.5
+
+// trigrams returns the set of three-character sequences in s. Like
+// Postgres's pg_trgm, each lowercased word is padded with two spaces in front
+// and one behind, so short words and word starts still produce trigrams.
+func trigrams(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	}) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// trigramSimilarity returns the share of trigrams a and b have in common,
+// from 0 for none to 1 for the same set. Case and punctuation are ignored.
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// FindSimilarTasks returns the other tasks whose titles have a trigram
+// similarity to the given task's title of at least threshold, most similar
+// first. It returns nil if the task does not exist.
+func (s *TaskStore) FindSimilarTasks(id int, threshold float64) []*Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target, ok := s.tasks[id]
+	if !ok {
+		return nil
+	}
+	scores := make(map[int]float64)
+	similar := []*Task{}
+	for _, task := range s.tasks {
+		if task.ID == id {
+			continue
+		}
+		if score := trigramSimilarity(target.Title, task.Title); score >= threshold {
+			scores[task.ID] = score
+			similar = append(similar, task.clone())
+		}
+	}
+	sort.Slice(similar, func(i, j int) bool {
+		if scores[similar[i].ID] != scores[similar[j].ID] {
+			return scores[similar[i].ID] > scores[similar[j].ID]
+		}
+		return similar[i].ID < similar[j].ID
+	})
+	return similar
+}
+
+// taskSimilarHandler serves /api/v1/tasks/{id}/similar, returning possible
+// duplicates of a task as JSON
+func (srv *Server) taskSimilarHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	threshold := defaultSimilarityThreshold
+	if raw := r.URL.Query().Get("threshold"); raw != "" {
+		t, err := strconv.ParseFloat(raw, 64)
+		if err != nil || t < 0 || t > 1 {
+			http.Error(w, "Threshold must be between 0 and 1", http.StatusBadRequest)
+			return
+		}
+		threshold = t
+	}
+
+	similar := srv.FindSimilarTasks(id, threshold)
+	if similar == nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, similar)
+}
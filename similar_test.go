@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrigramSimilarity(t *testing.T) {
+	if got := trigramSimilarity("Fix login bug", "Fix login bug"); got != 1 {
+		t.Errorf("Expected identical titles to score 1, got %v", got)
+	}
+	if got := trigramSimilarity("Fix login bug", "fix LOGIN bug!"); got != 1 {
+		t.Errorf("Expected case and punctuation to be ignored, got %v", got)
+	}
+	if got := trigramSimilarity("Fix login bug", "Quarterly tax report"); got > 0.1 {
+		t.Errorf("Expected unrelated titles to score near 0, got %v", got)
+	}
+	partial := trigramSimilarity("Fix login bug", "Fix login page layout")
+	if partial <= 0.1 || partial >= 1 {
+		t.Errorf("Expected a partial match between 0 and 1, got %v", partial)
+	}
+	if closer := trigramSimilarity("Fix login bug", "Fix the login bug"); closer <= partial {
+		t.Errorf("Expected a closer title to score higher: %v <= %v", closer, partial)
+	}
+	if got := trigramSimilarity("", "Anything"); got != 0 {
+		t.Errorf("Expected an empty title to score 0, got %v", got)
+	}
+}
+
+func TestFindSimilarTasks(t *testing.T) {
+	s := newTestStore()
+	target := s.AddTask("Update onboarding docs", "")
+	s.AddTask("Update onboarding docs", "")
+	s.AddTask("Update the onboarding doc", "")
+	s.AddTask("Order team lunch", "")
+
+	similar := s.FindSimilarTasks(target.ID, 0.5)
+	if ids := taskIDs(similar); !equalIDs(ids, []int{2, 3}) {
+		t.Errorf("Expected tasks 2 and 3, most similar first, got %v", ids)
+	}
+	if got := s.FindSimilarTasks(target.ID, 1); len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("Expected only the exact duplicate at threshold 1, got %v", taskIDs(got))
+	}
+	if got := s.FindSimilarTasks(99, 0.5); got != nil {
+		t.Errorf("Expected nil for a missing task, got %v", got)
+	}
+}
+
+func TestTaskSimilarHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Renew TLS certificate", "")
+	s.AddTask("Renew TLS certificates", "")
+
+	w := httptest.NewRecorder()
+	NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodPost, "/api/v1/tasks/1/similar?threshold=0.7", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var similar []*Task
+	if err := json.Unmarshal(w.Body.Bytes(), &similar); err != nil {
+		t.Fatal(err)
+	}
+	if len(similar) != 1 || similar[0].ID != 2 {
+		t.Errorf("Expected task 2, got %s", w.Body.String())
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/tasks/9/similar":               http.StatusNotFound,
+		"/api/v1/tasks/1/similar?threshold=2":   http.StatusBadRequest,
+		"/api/v1/tasks/1/similar?threshold=abc": http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodPost, path, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+func BenchmarkTrigramSimilarity(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		trigramSimilarity("Investigate flaky checkout integration test", "Investigate the flaky checkout tests")
+	}
+}
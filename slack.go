@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// slackExcerptLength caps how much of a task's description a message quotes
+const slackExcerptLength = 200
+
+// slackRetryDelay is the pause before retrying a failed delivery
+var slackRetryDelay = time.Second
+
+// SlackNotifier posts a message to a Slack incoming webhook whenever a task
+// moves to "done", a watched task changes or an assigned task sits idle. A nil notifier is valid and
+// sends nothing.
+type SlackNotifier struct {
+	WebhookURL string
+	BaseURL    string // board address used for task links
+	Client     *http.Client
+}
+
+// slackMessage is the body of an incoming webhook request. Text is the
+// fallback shown in notifications; Blocks is the rendered message.
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// slackBlock is a section or context block
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackText is a mrkdwn text object
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// NewSlackNotifier returns a notifier for the configured webhook, or nil
+// when no webhook URL is set
+func NewSlackNotifier(c SlackConfig) *SlackNotifier {
+	if c.WebhookURL == "" {
+		return nil
+	}
+	return &SlackNotifier{
+		WebhookURL: c.WebhookURL,
+		BaseURL:    strings.TrimSuffix(c.BaseURL, "/"),
+		Client:     &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// taskURL returns the link to a task's detail page
+func (n *SlackNotifier) taskURL(id int) string {
+	return fmt.Sprintf("%s/task/%d/history", n.BaseURL, id)
+}
+
+// message builds the Slack message announcing a completed task
+func (n *SlackNotifier) message(task *Task) slackMessage {
+	link := n.taskURL(task.ID)
+	title := escapeSlack(task.Title)
+	msg := slackMessage{
+		Text: "Task done: " + task.Title,
+		Blocks: []slackBlock{{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf(":white_check_mark: *<%s|%s>* moved to *Done*", link, title)},
+		}},
+	}
+	if excerpt := truncateText(task.Description, slackExcerptLength); excerpt != "" {
+		msg.Blocks = append(msg.Blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: ">" + strings.ReplaceAll(escapeSlack(excerpt), "\n", "\n>")},
+		})
+	}
+	msg.Blocks = append(msg.Blocks, slackBlock{
+		Type:     "context",
+		Elements: []slackText{{Type: "mrkdwn", Text: fmt.Sprintf("Task %s · <%s|View task>", task.HumanID(), link)}},
+	})
+	return msg
+}
+
+// watchedMessage builds the Slack message telling watchers a task changed
+func (n *SlackNotifier) watchedMessage(e Event) slackMessage {
+	link := n.taskURL(e.Task.ID)
+	title := escapeSlack(e.Task.Title)
+	what := "was updated"
+	if e.ToStatus != "" {
+		what = "moved to *" + escapeSlack(e.ToStatus) + "*"
+	}
+	return slackMessage{
+		Text: "Watched task changed: " + e.Task.Title,
+		Blocks: []slackBlock{{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf(":eyes: *<%s|%s>* %s", link, title, what)},
+		}, {
+			Type:     "context",
+			Elements: []slackText{{Type: "mrkdwn", Text: "Watched by " + escapeSlack(strings.Join(e.Watchers, ", "))}},
+		}},
+	}
+}
+
+// idleMessage builds the Slack message nudging the assignee of an idle task
+func (n *SlackNotifier) idleMessage(task *Task) slackMessage {
+	link := n.taskURL(task.ID)
+	return slackMessage{
+		Text: "Idle task: " + task.Title,
+		Blocks: []slackBlock{{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf(":hourglass: *<%s|%s>* hasn't been updated since %s",
+				link, escapeSlack(task.Title), task.UpdatedAt.Format("Jan 2"))},
+		}, {
+			Type:     "context",
+			Elements: []slackText{{Type: "mrkdwn", Text: "Assigned to " + escapeSlack(task.Assignee) + " · " + escapeSlack(task.Status)}},
+		}},
+	}
+}
+
+// mentionMessage builds the Slack message telling a user they were
+// @-mentioned in a comment
+func (n *SlackNotifier) mentionMessage(e Event) slackMessage {
+	link := n.taskURL(e.Task.ID)
+	msg := slackMessage{
+		Text: e.Comment.Author + " mentioned " + e.Mentioned + " on " + e.Task.Title,
+		Blocks: []slackBlock{{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf(":speech_balloon: *%s* mentioned *@%s* on *<%s|%s>*",
+				escapeSlack(e.Comment.Author), escapeSlack(e.Mentioned), link, escapeSlack(e.Task.Title))},
+		}},
+	}
+	if excerpt := truncateText(e.Comment.Body, slackExcerptLength); excerpt != "" {
+		msg.Blocks = append(msg.Blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: ">" + strings.ReplaceAll(escapeSlack(excerpt), "\n", "\n>")},
+		})
+	}
+	msg.Blocks = append(msg.Blocks, slackBlock{
+		Type:     "context",
+		Elements: []slackText{{Type: "mrkdwn", Text: fmt.Sprintf("Task %s · <%s|View task>", e.Task.HumanID(), link)}},
+	})
+	return msg
+}
+
+// Send posts the completion message for a task, retrying once if the
+// request fails or Slack answers with a 429 or 5xx
+func (n *SlackNotifier) Send(task *Task) error {
+	if n == nil {
+		return nil
+	}
+	return n.sendMessage(context.Background(), n.message(task))
+}
+
+// sendMessage posts a message, retrying once like Send. It gives up once
+// ctx is done, including while waiting to retry.
+func (n *SlackNotifier) sendMessage(ctx context.Context, msg slackMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	err = n.post(ctx, body)
+	var permanent *slackStatusError
+	if err != nil && ctx.Err() == nil && !(errors.As(err, &permanent) && !permanent.transient()) {
+		select {
+		case <-time.After(slackRetryDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		err = n.post(ctx, body)
+	}
+	return err
+}
+
+// slackStatusError reports a non-2xx response from the webhook
+type slackStatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *slackStatusError) Error() string {
+	return "slack webhook returned " + e.Status
+}
+
+// transient reports whether the request is worth retrying
+func (e *slackStatusError) transient() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= 500
+}
+
+// post makes a single delivery attempt
+func (n *SlackNotifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return &slackStatusError{Code: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
+
+// escapeSlack escapes the characters Slack's mrkdwn treats as control
+// sequences
+func escapeSlack(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// subscribeSlackNotifier announces tasks moved to "done", changes to
+// watched tasks, idle tasks and @-mentions in comments as background jobs,
+// without blocking the publisher. A watched task moving to done only gets
+// the completion message.
+func subscribeSlackNotifier(b *EventBus, n *SlackNotifier) {
+	if n == nil {
+		return
+	}
+	send := func(task *Task, message func(ctx context.Context) error) {
+		runInBackground("slack", func(ctx context.Context) error {
+			if err := message(ctx); err != nil {
+				return fmt.Errorf("sending Slack notification for task %d: %w", task.ID, err)
+			}
+			return nil
+		})
+	}
+	b.Subscribe(EventTaskMoved, func(e Event) {
+		if e.ToStatus != "done" || e.Task == nil {
+			return
+		}
+		send(e.Task, func(ctx context.Context) error { return n.sendMessage(ctx, n.message(e.Task)) })
+	})
+	b.Subscribe(EventTaskWatched, func(e Event) {
+		if e.ToStatus == "done" || e.Task == nil {
+			return
+		}
+		send(e.Task, func(ctx context.Context) error { return n.sendMessage(ctx, n.watchedMessage(e)) })
+	})
+	b.Subscribe(EventTaskIdle, func(e Event) {
+		if e.Task == nil {
+			return
+		}
+		send(e.Task, func(ctx context.Context) error { return n.sendMessage(ctx, n.idleMessage(e.Task)) })
+	})
+	b.Subscribe(EventCommentMention, func(e Event) {
+		if e.Task == nil || e.Comment == nil {
+			return
+		}
+		send(e.Task, func(ctx context.Context) error { return n.sendMessage(ctx, n.mentionMessage(e)) })
+	})
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSlackNotifierPostsWhenTaskDone(t *testing.T) {
+	payloads := make(chan slackMessage, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON content type, got %q", r.Header.Get("Content-Type"))
+		}
+		var msg slackMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("Invalid payload: %v", err)
+		}
+		payloads <- msg
+	}))
+	defer srv.Close()
+
+	s := withTestGlobals(t)
+	subscribeSlackNotifier(bus, NewSlackNotifier(SlackConfig{WebhookURL: srv.URL, BaseURL: "https://kanban.example.com/"}))
+	task := s.AddTask("Ship <release>", strings.Repeat("x", 250))
+	s.MoveTask(task.ID, "doing")
+	s.MoveTask(task.ID, "done")
+
+	var msg slackMessage
+	select {
+	case msg = <-payloads:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Timed out waiting for the Slack message")
+	}
+	select {
+	case extra := <-payloads:
+		t.Errorf("Expected only the move to done to notify, also got %+v", extra)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if msg.Text != "Task done: Ship <release>" {
+		t.Errorf("Unexpected fallback text %q", msg.Text)
+	}
+	if len(msg.Blocks) != 3 {
+		t.Fatalf("Expected title, excerpt and context blocks, got %+v", msg.Blocks)
+	}
+	header := msg.Blocks[0]
+	if header.Type != "section" || header.Text == nil || header.Text.Type != "mrkdwn" {
+		t.Errorf("Expected a mrkdwn section first, got %+v", header)
+	}
+	if !strings.Contains(header.Text.Text, "<https://kanban.example.com/task/1/history|Ship &lt;release&gt;>") {
+		t.Errorf("Expected an escaped, linked title, got %q", header.Text.Text)
+	}
+	if excerpt := msg.Blocks[1].Text.Text; !strings.HasPrefix(excerpt, ">") || !strings.HasSuffix(excerpt, "…") || len([]rune(excerpt)) != slackExcerptLength+2 {
+		t.Errorf("Expected a quoted, truncated excerpt, got %q", excerpt)
+	}
+	if ctx := msg.Blocks[2]; ctx.Type != "context" || len(ctx.Elements) != 1 || !strings.Contains(ctx.Elements[0].Text, "View task") {
+		t.Errorf("Unexpected context block %+v", ctx)
+	}
+}
+
+func TestSlackNotifierRetriesOnce(t *testing.T) {
+	orig := slackRetryDelay
+	slackRetryDelay = 0
+	t.Cleanup(func() { slackRetryDelay = orig })
+
+	var calls atomic.Int32
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(status)
+		}
+	}))
+	defer srv.Close()
+
+	n := NewSlackNotifier(SlackConfig{WebhookURL: srv.URL})
+	if err := n.Send(&Task{ID: 1, Title: "Flaky"}); err != nil || calls.Load() != 2 {
+		t.Errorf("Expected a transient failure to succeed on retry, err=%v calls=%d", err, calls.Load())
+	}
+
+	calls.Store(0)
+	status = http.StatusBadRequest
+	if err := n.Send(&Task{ID: 1, Title: "Rejected"}); err == nil || calls.Load() != 1 {
+		t.Errorf("Expected a 400 to fail without retrying, err=%v calls=%d", err, calls.Load())
+	}
+
+	if NewSlackNotifier(SlackConfig{}) != nil {
+		t.Errorf("Expected nil notifier without a webhook URL")
+	}
+	if n.Client.Timeout != 5*time.Second {
+		t.Errorf("Expected a 5s client timeout, got %v", n.Client.Timeout)
+	}
+}
+
+func TestSlackNotifierPostsIdleTasks(t *testing.T) {
+	payloads := make(chan slackMessage, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		payloads <- msg
+	}))
+	defer srv.Close()
+
+	s := withTestGlobals(t)
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	current := start
+	s.now = func() time.Time { return current }
+	subscribeSlackNotifier(bus, NewSlackNotifier(SlackConfig{WebhookURL: srv.URL, BaseURL: "https://kanban.example.com"}))
+
+	s.CreateTask(TaskSpec{Title: "Renew certificates", Assignee: "dana"})
+	current = start.Add(4 * 24 * time.Hour)
+	s.CheckIdleTasks(72 * time.Hour)
+
+	select {
+	case msg := <-payloads:
+		if msg.Text != "Idle task: Renew certificates" {
+			t.Errorf("Unexpected fallback text %q", msg.Text)
+		}
+		if len(msg.Blocks) != 2 || !strings.Contains(msg.Blocks[0].Text.Text, "since Mar 3") ||
+			!strings.Contains(msg.Blocks[1].Elements[0].Text, "Assigned to dana") {
+			t.Errorf("Unexpected blocks %+v", msg.Blocks)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Timed out waiting for the Slack message")
+	}
+}
+
+func TestSlackNotifierPostsMentions(t *testing.T) {
+	payloads := make(chan slackMessage, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		payloads <- msg
+	}))
+	defer srv.Close()
+
+	s := withTestGlobals(t)
+	subscribeSlackNotifier(bus, NewSlackNotifier(SlackConfig{WebhookURL: srv.URL, BaseURL: "https://kanban.example.com"}))
+
+	task := s.AddTask("Renew certificates", "")
+	s.AddComment(task.ID, "alice", "@dana can you take this one?")
+
+	select {
+	case msg := <-payloads:
+		if msg.Text != "alice mentioned dana on Renew certificates" {
+			t.Errorf("Unexpected fallback text %q", msg.Text)
+		}
+		if len(msg.Blocks) != 3 ||
+			!strings.Contains(msg.Blocks[0].Text.Text, "<https://kanban.example.com/task/1/history|Renew certificates>") ||
+			msg.Blocks[1].Text.Text != ">@dana can you take this one?" {
+			t.Errorf("Unexpected blocks %+v", msg.Blocks)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Timed out waiting for the Slack message")
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSnapshots caps the number of snapshots kept in memory
+const maxSnapshots = 20
+
+// BoardSnapshot is a named, point-in-time copy of the board
+type BoardSnapshot struct {
+	Name      string         `json:"name"`
+	CreatedAt time.Time      `json:"created_at"`
+	Data      PersistentData `json:"data"`
+}
+
+// SnapshotStore keeps the most recent snapshots in memory
+type SnapshotStore struct {
+	mu        sync.Mutex
+	snapshots []*BoardSnapshot
+	limit     int
+}
+
+var snapshots = &SnapshotStore{limit: maxSnapshots}
+
+// Add stores a snapshot, evicting the oldest one once the limit is reached.
+// It returns false if a snapshot with the same name already exists.
+func (ss *SnapshotStore) Add(snap *BoardSnapshot) bool {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	for _, existing := range ss.snapshots {
+		if existing.Name == snap.Name {
+			return false
+		}
+	}
+	ss.snapshots = append(ss.snapshots, snap)
+	if len(ss.snapshots) > ss.limit {
+		ss.snapshots = ss.snapshots[len(ss.snapshots)-ss.limit:]
+	}
+	return true
+}
+
+// Get retrieves a snapshot by name
+func (ss *SnapshotStore) Get(name string) (*BoardSnapshot, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	for _, snap := range ss.snapshots {
+		if snap.Name == name {
+			return snap, true
+		}
+	}
+	return nil, false
+}
+
+// List returns all snapshots, oldest first
+func (ss *SnapshotStore) List() []*BoardSnapshot {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	list := make([]*BoardSnapshot, len(ss.snapshots))
+	copy(list, ss.snapshots)
+	return list
+}
+
+// Snapshot returns a deep copy of the store's current state
+func (s *TaskStore) Snapshot() PersistentData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := PersistentData{NextID: s.nextID}
+	for _, task := range s.tasks {
+		data.Tasks = append(data.Tasks, task.clone())
+	}
+	sort.Slice(data.Tasks, func(i, j int) bool { return data.Tasks[i].ID < data.Tasks[j].ID })
+	return data
+}
+
+// clone returns a copy of the task that shares no mutable state
+func (t *Task) clone() *Task {
+	c := *t
+	if t.DueDate != nil {
+		due := *t.DueDate
+		c.DueDate = &due
+	}
+	if t.MovedAt != nil {
+		moved := *t.MovedAt
+		c.MovedAt = &moved
+	}
+	if t.ArchivedAt != nil {
+		archived := *t.ArchivedAt
+		c.ArchivedAt = &archived
+	}
+	if t.ExpiresAt != nil {
+		expires := *t.ExpiresAt
+		c.ExpiresAt = &expires
+	}
+	if t.Labels != nil {
+		c.Labels = append([]string(nil), t.Labels...)
+	}
+	if t.Checklist != nil {
+		c.Checklist = append([]ChecklistItem(nil), t.Checklist...)
+	}
+	if t.StatusHistory != nil {
+		c.StatusHistory = append([]StatusTransition(nil), t.StatusHistory...)
+	}
+	if t.DependsOn != nil {
+		c.DependsOn = append([]int(nil), t.DependsOn...)
+	}
+	if t.Watchers != nil {
+		c.Watchers = append([]string(nil), t.Watchers...)
+	}
+	return &c
+}
+
+// MovedTask describes a task whose status changed between snapshots
+type MovedTask struct {
+	ID         int    `json:"id"`
+	Title      string `json:"title"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+}
+
+// FieldChange describes a single field that differs between snapshots
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// UpdatedTask describes a task whose content changed between snapshots
+type UpdatedTask struct {
+	ID      int           `json:"id"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// BoardDiff lists everything that changed from one snapshot to another
+type BoardDiff struct {
+	Added   []*Task       `json:"added"`
+	Removed []*Task       `json:"removed"`
+	Moved   []MovedTask   `json:"moved"`
+	Updated []UpdatedTask `json:"updated"`
+}
+
+// Diff compares two snapshots and reports the changes needed to go from a to b
+func Diff(a, b *BoardSnapshot) BoardDiff {
+	diff := BoardDiff{
+		Added:   []*Task{},
+		Removed: []*Task{},
+		Moved:   []MovedTask{},
+		Updated: []UpdatedTask{},
+	}
+
+	before := make(map[int]*Task)
+	for _, task := range a.Data.Tasks {
+		before[task.ID] = task
+	}
+	after := make(map[int]*Task)
+	for _, task := range b.Data.Tasks {
+		after[task.ID] = task
+	}
+
+	for id, old := range before {
+		if _, ok := after[id]; !ok {
+			diff.Removed = append(diff.Removed, old)
+		}
+	}
+
+	for id, cur := range after {
+		old, ok := before[id]
+		if !ok {
+			diff.Added = append(diff.Added, cur)
+			continue
+		}
+		if old.Status != cur.Status {
+			diff.Moved = append(diff.Moved, MovedTask{
+				ID:         id,
+				Title:      cur.Title,
+				FromStatus: old.Status,
+				ToStatus:   cur.Status,
+			})
+		}
+		if changes := taskFieldChanges(old, cur); len(changes) > 0 {
+			diff.Updated = append(diff.Updated, UpdatedTask{ID: id, Changes: changes})
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].ID < diff.Added[j].ID })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].ID < diff.Removed[j].ID })
+	sort.Slice(diff.Moved, func(i, j int) bool { return diff.Moved[i].ID < diff.Moved[j].ID })
+	sort.Slice(diff.Updated, func(i, j int) bool { return diff.Updated[i].ID < diff.Updated[j].ID })
+	return diff
+}
+
+// taskFieldChanges lists content fields that differ between two versions of a task.
+// Status changes are reported separately as moves.
+func taskFieldChanges(old, cur *Task) []FieldChange {
+	var changes []FieldChange
+	add := func(field, oldVal, newVal string) {
+		if oldVal != newVal {
+			changes = append(changes, FieldChange{Field: field, Old: oldVal, New: newVal})
+		}
+	}
+	add("title", old.Title, cur.Title)
+	add("description", old.Description, cur.Description)
+	add("assignee", old.Assignee, cur.Assignee)
+	add("effort", strconv.Itoa(old.Effort), strconv.Itoa(cur.Effort))
+	add("priority", strconv.Itoa(old.Priority), strconv.Itoa(cur.Priority))
+	add("due_date", formatDueDate(old.DueDate), formatDueDate(cur.DueDate))
+	add("labels", strings.Join(old.Labels, ","), strings.Join(cur.Labels, ","))
+	add("checklist", formatChecklist(old.Checklist), formatChecklist(cur.Checklist))
+	add("depends_on", formatIDs(old.DependsOn), formatIDs(cur.DependsOn))
+	return changes
+}
+
+// formatIDs renders task IDs as a comma-separated list
+func formatIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatDueDate renders an optional due date as YYYY-MM-DD
+func formatDueDate(due *time.Time) string {
+	if due == nil {
+		return ""
+	}
+	return due.Format("2006-01-02")
+}
+
+// createSnapshotHandler stores the current board state under the given name
+func (srv *Server) createSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "Snapshot name is required", http.StatusBadRequest)
+		return
+	}
+
+	snap := &BoardSnapshot{
+		Name:      name,
+		CreatedAt: time.Now(),
+		Data:      srv.Snapshot(),
+	}
+	if !snapshots.Add(snap) {
+		http.Error(w, "Snapshot already exists", http.StatusConflict)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"name":       snap.Name,
+		"created_at": snap.CreatedAt,
+		"task_count": len(snap.Data.Tasks),
+	})
+}
+
+// diffHandler returns the changes between two snapshots as JSON
+func diffHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, ok := snapshots.Get(r.URL.Query().Get("from"))
+	if !ok {
+		http.Error(w, "Snapshot 'from' not found", http.StatusNotFound)
+		return
+	}
+	to, ok := snapshots.Get(r.URL.Query().Get("to"))
+	if !ok {
+		http.Error(w, "Snapshot 'to' not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Diff(from, to))
+}
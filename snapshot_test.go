@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	store := newTestStore()
+	store.AddTask("Keep", "")
+	store.AddTask("Rename", "Old description")
+	store.AddTask("Remove", "")
+	before := &BoardSnapshot{Name: "before", Data: store.Snapshot()}
+
+	store.MoveTask(1, "doing")
+	store.tasks[2].Title = "Renamed"
+	delete(store.tasks, 3)
+	store.AddTask("New", "")
+	after := &BoardSnapshot{Name: "after", Data: store.Snapshot()}
+
+	diff := Diff(before, after)
+	if len(diff.Added) != 1 || diff.Added[0].ID != 4 {
+		t.Errorf("Expected task 4 to be added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ID != 3 {
+		t.Errorf("Expected task 3 to be removed, got %+v", diff.Removed)
+	}
+	wantMoved := MovedTask{ID: 1, Title: "Keep", FromStatus: "todo", ToStatus: "doing"}
+	if len(diff.Moved) != 1 || diff.Moved[0] != wantMoved {
+		t.Errorf("Expected %+v to be moved, got %+v", wantMoved, diff.Moved)
+	}
+	wantChange := FieldChange{Field: "title", Old: "Rename", New: "Renamed"}
+	if len(diff.Updated) != 1 || diff.Updated[0].ID != 2 ||
+		len(diff.Updated[0].Changes) != 1 || diff.Updated[0].Changes[0] != wantChange {
+		t.Errorf("Expected task 2 title change, got %+v", diff.Updated)
+	}
+}
+
+func TestSnapshotIsolatedFromStore(t *testing.T) {
+	store := newTestStore()
+	store.AddTask("Original", "")
+	snap := store.Snapshot()
+	store.MoveTask(1, "done")
+	if snap.Tasks[0].Status != "todo" {
+		t.Errorf("Snapshot should not change when the store does")
+	}
+}
+
+func TestSnapshotStoreLimit(t *testing.T) {
+	ss := &SnapshotStore{limit: 3}
+	for i := 1; i <= 5; i++ {
+		ss.Add(&BoardSnapshot{Name: fmt.Sprintf("snap-%d", i)})
+	}
+	list := ss.List()
+	if len(list) != 3 || list[0].Name != "snap-3" {
+		t.Errorf("Expected the 3 newest snapshots to be kept, got %d starting at %s", len(list), list[0].Name)
+	}
+	if ss.Add(&BoardSnapshot{Name: "snap-5"}) {
+		t.Errorf("Duplicate snapshot names should be rejected")
+	}
+}
+
+func TestDiffHandler(t *testing.T) {
+	origStore, origSnapshots := store, snapshots
+	defer func() { store, snapshots = origStore, origSnapshots }()
+	store = newTestStore()
+	snapshots = &SnapshotStore{limit: maxSnapshots}
+
+	store.AddTask("First", "")
+	rec := httptest.NewRecorder()
+	NewServer(store).createSnapshotHandler(rec, httptest.NewRequest(http.MethodPost, "/snapshots?name=before-release", nil))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", rec.Code)
+	}
+
+	store.MoveTask(1, "done")
+	rec = httptest.NewRecorder()
+	NewServer(store).createSnapshotHandler(rec, httptest.NewRequest(http.MethodPost, "/snapshots?name=after-release", nil))
+
+	rec = httptest.NewRecorder()
+	diffHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/diff?from=before-release&to=after-release", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var diff BoardDiff
+	if err := json.NewDecoder(rec.Body).Decode(&diff); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(diff.Moved) != 1 || diff.Moved[0].ToStatus != "done" {
+		t.Errorf("Expected one move to done, got %+v", diff.Moved)
+	}
+
+	rec = httptest.NewRecorder()
+	diffHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/diff?from=missing&to=after-release", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown snapshot, got %d", rec.Code)
+	}
+}
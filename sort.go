@@ -0,0 +1,95 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// taskLess reports whether a sorts strictly before b, and whether the two
+// compared equal (so the next sort key should decide)
+type taskLess func(a, b *Task) (less, equal bool)
+
+// sortKeys maps each supported sort key to its comparison
+var sortKeys = map[string]taskLess{
+	"created_asc":   func(a, b *Task) (bool, bool) { return compareTime(a.CreatedAt, b.CreatedAt) },
+	"created_desc":  func(a, b *Task) (bool, bool) { return compareTime(b.CreatedAt, a.CreatedAt) },
+	"priority_asc":  func(a, b *Task) (bool, bool) { return a.Priority < b.Priority, a.Priority == b.Priority },
+	"priority_desc": func(a, b *Task) (bool, bool) { return a.Priority > b.Priority, a.Priority == b.Priority },
+	"due_asc":       func(a, b *Task) (bool, bool) { return compareDue(a.DueDate, b.DueDate, false) },
+	"due_desc":      func(a, b *Task) (bool, bool) { return compareDue(a.DueDate, b.DueDate, true) },
+	"title_asc": func(a, b *Task) (bool, bool) {
+		ta, tb := strings.ToLower(a.Title), strings.ToLower(b.Title)
+		return ta < tb, ta == tb
+	},
+}
+
+// SortKeyNames returns the supported sort keys in alphabetical order
+func SortKeyNames() []string {
+	names := make([]string, 0, len(sortKeys))
+	for name := range sortKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseSortKeys splits a comma-separated sort parameter, returning the first
+// unrecognised key if there is one
+func ParseSortKeys(param string) ([]string, string) {
+	var keys []string
+	for _, key := range strings.Split(param, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if _, ok := sortKeys[key]; !ok {
+			return nil, key
+		}
+		keys = append(keys, key)
+	}
+	return keys, ""
+}
+
+// SortTasks returns a sorted copy of tasks. sortKey may list several keys
+// separated by commas; later keys break ties left by earlier ones, and ID is
+// the final tiebreaker. Unknown keys are ignored.
+func SortTasks(tasks []*Task, sortKey string) []*Task {
+	var comparators []taskLess
+	for _, key := range strings.Split(sortKey, ",") {
+		if cmp, ok := sortKeys[strings.TrimSpace(key)]; ok {
+			comparators = append(comparators, cmp)
+		}
+	}
+
+	sorted := make([]*Task, len(tasks))
+	copy(sorted, tasks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for _, cmp := range comparators {
+			if less, equal := cmp(sorted[i], sorted[j]); !equal {
+				return less
+			}
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+func compareTime(a, b time.Time) (less, equal bool) {
+	return a.Before(b), a.Equal(b)
+}
+
+// compareDue orders tasks by due date, always placing tasks without one last
+func compareDue(a, b *time.Time, desc bool) (less, equal bool) {
+	switch {
+	case a == nil && b == nil:
+		return false, true
+	case a == nil:
+		return false, false
+	case b == nil:
+		return true, false
+	case desc:
+		return b.Before(*a), a.Equal(*b)
+	}
+	return a.Before(*b), a.Equal(*b)
+}
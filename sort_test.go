@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sortFixture() []*Task {
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []*Task{
+		{ID: 1, Title: "Charlie", Priority: PriorityLow, DueDate: day(10), CreatedAt: base.Add(3 * time.Hour)},
+		{ID: 2, Title: "alpha", Priority: PriorityHigh, DueDate: nil, CreatedAt: base.Add(1 * time.Hour)},
+		{ID: 3, Title: "Bravo", Priority: PriorityHigh, DueDate: day(5), CreatedAt: base.Add(2 * time.Hour)},
+		{ID: 4, Title: "delta", Priority: PriorityNone, DueDate: day(20), CreatedAt: base.Add(4 * time.Hour)},
+	}
+}
+
+func taskIDs(tasks []*Task) []int {
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSortTasks(t *testing.T) {
+	cases := []struct {
+		key  string
+		want []int
+	}{
+		{"created_asc", []int{2, 3, 1, 4}},
+		{"created_desc", []int{4, 1, 3, 2}},
+		{"priority_asc", []int{4, 1, 2, 3}},
+		{"priority_desc", []int{2, 3, 1, 4}},
+		{"due_asc", []int{3, 1, 4, 2}},
+		{"due_desc", []int{4, 1, 3, 2}},
+		{"title_asc", []int{2, 3, 1, 4}},
+		{"priority_desc,title_asc", []int{2, 3, 1, 4}},
+		{"priority_desc,due_asc", []int{3, 2, 1, 4}},
+	}
+	for _, tc := range cases {
+		got := taskIDs(SortTasks(sortFixture(), tc.key))
+		if !equalIDs(got, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.key, tc.want, got)
+		}
+	}
+}
+
+func TestSortTasksDoesNotModifyInput(t *testing.T) {
+	tasks := sortFixture()
+	SortTasks(tasks, "title_asc")
+	if !equalIDs(taskIDs(tasks), []int{1, 2, 3, 4}) {
+		t.Errorf("Input slice was reordered: %v", taskIDs(tasks))
+	}
+}
+
+func TestParseSortKeys(t *testing.T) {
+	keys, invalid := ParseSortKeys("priority_desc, due_asc")
+	if invalid != "" || len(keys) != 2 {
+		t.Errorf("Expected two valid keys, got %v (invalid %q)", keys, invalid)
+	}
+	if _, invalid := ParseSortKeys("priority_desc,sideways"); invalid != "sideways" {
+		t.Errorf("Expected 'sideways' to be reported invalid, got %q", invalid)
+	}
+}
+
+func TestTasksAPISort(t *testing.T) {
+	s := newTestStore()
+	s.CreateTask(TaskSpec{Title: "Low", Priority: PriorityLow})
+	s.CreateTask(TaskSpec{Title: "High", Priority: PriorityHigh})
+	srv := NewServer(s)
+
+	rec := httptest.NewRecorder()
+	srv.tasksAPIHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tasks?sort=priority_desc", nil))
+	var tasks []*Task
+	if err := json.NewDecoder(rec.Body).Decode(&tasks); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if !equalIDs(taskIDs(tasks), []int{2, 1}) {
+		t.Errorf("Expected high priority first, got %v", taskIDs(tasks))
+	}
+
+	rec = httptest.NewRecorder()
+	srv.tasksAPIHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tasks?sort=bogus", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown sort key, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "created_asc") {
+		t.Errorf("Expected valid options to be listed, got %q", rec.Body.String())
+	}
+}
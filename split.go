@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SplitTask replaces a task with one new task per title, each copying the
+// original's description, labels, priority, assignee and status. The
+// original is archived rather than deleted so its history stays. It returns
+// false if the task does not exist or is already archived, and
+// ErrBoardLocked while the board is locked.
+func (s *TaskStore) SplitTask(id int, newTitles []string) ([]*Task, bool, error) {
+	span := s.startSpan(context.Background(), "SplitTask", attribute.Int("task.id", id))
+	defer span.End()
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, true, err
+	}
+	original, ok := s.tasks[id]
+	if !ok || original.ArchivedAt != nil {
+		s.mu.Unlock()
+		return nil, false, nil
+	}
+
+	archived := s.archive(original)
+
+	var parts []*Task
+	var created []*Task
+	for _, title := range newTitles {
+		task := s.newTask(TaskSpec{
+			Title:       title,
+			Description: original.Description,
+			Status:      original.Status,
+			Assignee:    original.Assignee,
+			Priority:    original.Priority,
+			Labels:      append([]string(nil), original.Labels...),
+		})
+		parts = append(parts, task)
+		created = append(created, task.clone())
+	}
+	s.persist(original.Status)
+	s.mu.Unlock()
+
+	s.publish(archived)
+	for _, task := range created {
+		s.publish(Event{Type: EventTaskCreated, Task: task, ToStatus: task.Status})
+	}
+	return parts, true, nil
+}
+
+// taskSplitHandler splits a task into one task per entry of
+// {"subtitles": [...]} and returns the new tasks as JSON
+func (srv *Server) taskSplitHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Subtitles []string `json:"subtitles"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if len(body.Subtitles) < 2 || len(body.Subtitles) > maxBulkSize {
+		http.Error(w, fmt.Sprintf("Give between 2 and %d subtitles", maxBulkSize), http.StatusBadRequest)
+		return
+	}
+	titles := make([]string, len(body.Subtitles))
+	for i, title := range body.Subtitles {
+		if titles[i] = strings.TrimSpace(title); titles[i] == "" {
+			http.Error(w, "Subtitles cannot be empty", http.StatusBadRequest)
+			return
+		}
+	}
+
+	tasks, ok, err := srv.SplitTask(id, titles)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusCreated, tasks)
+}
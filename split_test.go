@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSplitTask(t *testing.T) {
+	s := newTestStore()
+	original, _ := s.CreateTask(TaskSpec{
+		Title:       "Build login",
+		Description: "OAuth and password",
+		Assignee:    "dana",
+		Priority:    PriorityHigh,
+		Effort:      8,
+		Labels:      []string{"auth"},
+	})
+	s.MoveTask(original.ID, "doing")
+
+	parts, ok, _ := s.SplitTask(original.ID, []string{"OAuth", "Password", "Reset flow"})
+	if !ok || len(parts) != 3 {
+		t.Fatalf("Expected 3 new tasks, got %d, %v", len(parts), ok)
+	}
+	for i, part := range parts {
+		if part.Title != []string{"OAuth", "Password", "Reset flow"}[i] {
+			t.Errorf("Unexpected title %q", part.Title)
+		}
+		if part.Description != "OAuth and password" || part.Assignee != "dana" || part.Priority != PriorityHigh ||
+			part.Status != "doing" || len(part.Labels) != 1 || part.Labels[0] != "auth" {
+			t.Errorf("Expected part %d to inherit the original's fields, got %+v", i, part)
+		}
+		if part.ArchivedAt != nil {
+			t.Errorf("Expected part %d not to be archived", i)
+		}
+	}
+
+	archived, ok := s.GetTask(original.ID)
+	if !ok || archived.ArchivedAt == nil {
+		t.Fatalf("Expected the original to be kept and archived")
+	}
+	if doing := s.GetTasksByStatus("doing"); !equalIDs(taskIDs(doing), []int{2, 3, 4}) {
+		t.Errorf("Expected only the parts on the board, got %v", taskIDs(doing))
+	}
+	if data := s.GetColumnData("doing"); data.Count != 3 {
+		t.Errorf("Expected the archived task to be left out of the column, got %d", data.Count)
+	}
+
+	if _, ok, _ := s.SplitTask(original.ID, []string{"Again", "Twice"}); ok {
+		t.Errorf("Expected an archived task not to be split again")
+	}
+	if _, ok, _ := s.SplitTask(99, []string{"A", "B"}); ok {
+		t.Errorf("Expected splitting a missing task to return false")
+	}
+}
+
+func TestTaskSplitHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Big task", "")
+
+	split := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		NewServer(s).taskRouter(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+
+	for _, body := range []string{`{"subtitles": ["Only one"]}`, `{"subtitles": ["A", "  "]}`, `not json`} {
+		if w := split("/task/1/split", body); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, w.Code)
+		}
+	}
+	if w := split("/task/42/split", `{"subtitles": ["A", "B"]}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing task, got %d", w.Code)
+	}
+
+	w := split("/task/1/split", `{"subtitles": ["Part A", "Part B"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created []Task
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || len(created) != 2 || created[1].Title != "Part B" {
+		t.Errorf("Unexpected response %s", w.Body.String())
+	}
+	if len(s.GetTasksByStatus("todo")) != 2 {
+		t.Errorf("Expected the two parts to replace the original in todo")
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// sprintSettingPrefix is the settings key prefix holding each sprint's name,
+// keyed by its ID
+const sprintSettingPrefix = "sprint."
+
+const maxSprintNameLength = 50
+
+var errSprintNotFound = errors.New("sprint not found")
+
+// Sprint is a named iteration that tasks can be planned into
+type Sprint struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// SprintRegistry holds the board's sprints, kept in settings.json
+type SprintRegistry struct {
+	settings *SettingsStore
+}
+
+// sprints returns the registry backed by the store's settings
+func (s *TaskStore) sprints() *SprintRegistry {
+	return &SprintRegistry{settings: s.settings}
+}
+
+// validateSprint checks a sprint's settings key suffix and name
+func validateSprint(id, name string) error {
+	if n, err := strconv.Atoi(id); err != nil || n < 1 || strconv.Itoa(n) != id {
+		return fmt.Errorf("sprint ID must be a positive number")
+	}
+	if name == "" || len(name) > maxSprintNameLength || name != strings.TrimSpace(name) {
+		return fmt.Errorf("name must be 1-%d characters without surrounding spaces", maxSprintNameLength)
+	}
+	return nil
+}
+
+// List returns every sprint ordered by ID
+func (sr *SprintRegistry) List() []Sprint {
+	sprints := []Sprint{}
+	if sr.settings == nil {
+		return sprints
+	}
+	for key, name := range sr.settings.All() {
+		if id, ok := strings.CutPrefix(key, sprintSettingPrefix); ok {
+			n, _ := strconv.Atoi(id)
+			sprints = append(sprints, Sprint{ID: n, Name: name})
+		}
+	}
+	sort.Slice(sprints, func(i, j int) bool { return sprints[i].ID < sprints[j].ID })
+	return sprints
+}
+
+// Get returns the sprint with the given ID
+func (sr *SprintRegistry) Get(id int) (Sprint, bool) {
+	if sr.settings == nil {
+		return Sprint{}, false
+	}
+	name, ok := sr.settings.Get(sprintSettingPrefix + strconv.Itoa(id))
+	return Sprint{ID: id, Name: name}, ok
+}
+
+// Create adds a sprint, numbered one past the highest existing ID. Creates
+// are serialized so two sprints never get the same ID.
+func (sr *SprintRegistry) Create(name string) (Sprint, error) {
+	if sr.settings == nil {
+		return Sprint{}, fmt.Errorf("sprints need a settings file")
+	}
+	sr.settings.sprintMu.Lock()
+	defer sr.settings.sprintMu.Unlock()
+
+	sprint := Sprint{ID: 1, Name: strings.TrimSpace(name)}
+	if sprints := sr.List(); len(sprints) > 0 {
+		sprint.ID = sprints[len(sprints)-1].ID + 1
+	}
+	key := sprintSettingPrefix + strconv.Itoa(sprint.ID)
+	if err := sr.settings.Merge(map[string]string{key: sprint.Name}); err != nil {
+		return Sprint{}, err
+	}
+	return sprint, nil
+}
+
+// ListSprints returns every sprint in ID order
+func (s *TaskStore) ListSprints() []Sprint {
+	return s.sprints().List()
+}
+
+// CreateSprint adds a sprint numbered after the existing ones
+func (s *TaskStore) CreateSprint(name string) (Sprint, error) {
+	return s.sprints().Create(name)
+}
+
+// DuplicateToSprint copies a task's title, description, labels, effort and
+// assignee into a new "todo" task in sprintID, leaving the original as it
+// is. It returns errSprintNotFound or ErrTaskNotFound if either ID is
+// unknown, and an error if the To Do column is at its WIP limit.
+func (s *TaskStore) DuplicateToSprint(taskID, sprintID int) (*Task, error) {
+	span := s.startSpan(context.Background(), "DuplicateToSprint", attribute.Int("task.id", taskID), attribute.Int("sprint.id", sprintID))
+	defer span.End()
+
+	if _, ok := s.sprints().Get(sprintID); !ok {
+		return nil, fmt.Errorf("%w: %d", errSprintNotFound, sprintID)
+	}
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	original, ok := s.tasks[taskID]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrTaskNotFound
+	}
+	if err := s.checkWIPLimit("", "todo"); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	source := original.clone()
+	task := s.newTask(TaskSpec{
+		Title:       source.Title,
+		Description: source.Description,
+		Status:      "todo",
+		Assignee:    source.Assignee,
+		Effort:      source.Effort,
+		Labels:      source.Labels,
+	})
+	task.SprintID = sprintID
+	s.persist(task.Status)
+	created := task.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskCreated, Task: created, ToStatus: created.Status})
+	return task, nil
+}
+
+// sprintsAPIHandler serves /api/v1/sprints: GET lists the sprints and POST
+// creates one from {"name"}
+func (srv *Server) sprintsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, srv.ListSprints())
+	case http.MethodPost:
+		var in Sprint
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		sprint, err := srv.CreateSprint(in.Name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, sprint)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// taskDuplicateToSprintHandler serves POST
+// /api/v1/tasks/{id}/duplicate-to-sprint with {"sprint_id": 3}, returning
+// the copy
+func (srv *Server) taskDuplicateToSprintHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in struct {
+		SprintID int `json:"sprint_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := srv.DuplicateToSprint(id, in.SprintID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, task)
+}
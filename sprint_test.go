@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDuplicateToSprint(t *testing.T) {
+	s := newTestStore()
+	s.settings = withTestSettings(t)
+	s.sprints().Create("Sprint 1")
+	sprint, err := s.sprints().Create("Sprint 2")
+	if err != nil || sprint.ID != 2 {
+		t.Fatalf("Expected sprint 2, got %+v, %v", sprint, err)
+	}
+	original, _ := s.CreateTask(TaskSpec{
+		Title:       "Finish login",
+		Description: "OAuth",
+		Assignee:    "dana",
+		Effort:      5,
+		Priority:    PriorityHigh,
+		Labels:      []string{"auth"},
+	})
+	s.MoveTask(original.ID, "doing")
+	before, _ := s.GetTask(original.ID)
+
+	task, err := s.DuplicateToSprint(original.ID, sprint.ID)
+	if err != nil {
+		t.Fatalf("DuplicateToSprint failed: %v", err)
+	}
+	if task.ID == original.ID || task.SprintID != sprint.ID || task.Status != "todo" {
+		t.Errorf("Expected a new todo task in sprint %d, got %+v", sprint.ID, task)
+	}
+	if task.Title != "Finish login" || task.Description != "OAuth" || task.Assignee != "dana" || task.Effort != 5 ||
+		!reflect.DeepEqual(task.Labels, []string{"auth"}) {
+		t.Errorf("Expected the copy to keep the task's content, got %+v", task)
+	}
+	if after, _ := s.GetTask(original.ID); !reflect.DeepEqual(after, before) {
+		t.Errorf("Expected the original to be unchanged, got %+v", after)
+	}
+
+	if _, err := s.DuplicateToSprint(original.ID, 99); !errors.Is(err, errSprintNotFound) {
+		t.Errorf("Expected errSprintNotFound for an unknown sprint, got %v", err)
+	}
+	if _, err := s.DuplicateToSprint(99, sprint.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound for an unknown task, got %v", err)
+	}
+	if n := len(s.GetAllTasks()); n != 2 {
+		t.Errorf("Expected failed copies to create nothing, got %d tasks", n)
+	}
+}
+
+func TestDuplicateToSprintHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.settings = withTestSettings(t)
+	s.AddTask("Carry over", "")
+
+	post := func(path, body string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+	if w := post("/api/v1/sprints", `{"name": "Sprint 3"}`, NewServer(s).sprintsAPIHandler); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 creating a sprint, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("/api/v1/sprints", `{"name": ""}`, NewServer(s).sprintsAPIHandler); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unnamed sprint, got %d", w.Code)
+	}
+
+	w := post("/api/v1/tasks/1/duplicate-to-sprint", `{"sprint_id": 1}`, NewServer(s).taskAPIHandler)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var task Task
+	if err := json.Unmarshal(w.Body.Bytes(), &task); err != nil || task.ID != 2 || task.SprintID != 1 {
+		t.Errorf("Expected task 2 in sprint 1, got %s", w.Body.String())
+	}
+
+	w = post("/api/v1/tasks/1/duplicate-to-sprint", `{"sprint_id": 7}`, NewServer(s).taskAPIHandler)
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "Sprint not found") {
+		t.Errorf("Expected 404 for an unknown sprint, got %d: %s", w.Code, w.Body.String())
+	}
+	w = post("/api/v1/tasks/9/duplicate-to-sprint", `{"sprint_id": 1}`, NewServer(s).taskAPIHandler)
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "Task not found") {
+		t.Errorf("Expected 404 for an unknown task, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateSprintsConcurrently(t *testing.T) {
+	s := newTestStore()
+	s.settings = withTestSettings(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := s.sprints().Create(fmt.Sprintf("Sprint %d", i)); err != nil {
+				t.Errorf("Create failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	sprints := s.sprints().List()
+	if len(sprints) != 20 {
+		t.Fatalf("Expected 20 sprints with distinct IDs, got %d", len(sprints))
+	}
+	for i, sprint := range sprints {
+		if sprint.ID != i+1 {
+			t.Errorf("Expected sprint IDs 1-20, got %+v", sprints)
+			break
+		}
+	}
+}
@@ -0,0 +1,472 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteColumns = `id, title, description, status, assignee, effort, priority, due_date, labels, checklist, position, created_at, updated_at, moved_at, status_changed_at`
+
+// dualConnectionPool splits a SQLite database's connections in two: a
+// single write connection, serialized by writeMu since SQLite allows one
+// writer, and a pool of read-only connections. In WAL mode readers see the
+// last committed state without waiting for a write in progress.
+type dualConnectionPool struct {
+	writeMu sync.Mutex
+	write   *sql.DB
+	read    *sql.DB
+}
+
+// sqliteReadConns is how many read-only connections a pool opens
+var sqliteReadConns = runtime.NumCPU()
+
+// openDualConnectionPool opens the write connection to the database at path,
+// switching it to WAL mode, and then readConns read-only connections. With
+// readConns of zero, reads share the write connection.
+func openDualConnectionPool(path string, readConns int) (*dualConnectionPool, error) {
+	write, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	write.SetMaxOpenConns(1)
+	// Create the file and switch it to WAL before any reader opens it
+	if err := write.Ping(); err != nil {
+		write.Close()
+		return nil, err
+	}
+	p := &dualConnectionPool{write: write, read: write}
+	if readConns <= 0 {
+		return p, nil
+	}
+
+	p.read, err = sql.Open("sqlite", "file:"+path+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		write.Close()
+		return nil, err
+	}
+	p.read.SetMaxOpenConns(readConns)
+	p.read.SetMaxIdleConns(readConns)
+	return p, nil
+}
+
+// exec runs a write statement holding the write lock
+func (p *dualConnectionPool) exec(stmt *sql.Stmt, args ...interface{}) (sql.Result, error) {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	return stmt.Exec(args...)
+}
+
+// Close closes both sets of connections
+func (p *dualConnectionPool) Close() error {
+	if p.read != p.write {
+		p.read.Close()
+	}
+	return p.write.Close()
+}
+
+// SQLiteStore keeps tasks in a SQLite database. Writes go through one
+// connection and reads through a pool of read-only ones (see
+// dualConnectionPool), and every query is prepared once in NewSQLiteStore.
+// Like TaskStore, errors are logged and reported as a missing result.
+type SQLiteStore struct {
+	pool            *dualConnectionPool
+	now             func() time.Time // overridable clock for tests
+	insertStmt      *sql.Stmt
+	upsertStmt      *sql.Stmt
+	moveStmt        *sql.Stmt
+	deleteStmt      *sql.Stmt
+	getStmt         *sql.Stmt
+	getByStatusStmt *sql.Stmt
+	getAllStmt      *sql.Stmt
+	saveExtrasStmt  *sql.Stmt
+	loadExtrasStmt  *sql.Stmt
+}
+
+var (
+	_ TaskRepository = (*SQLiteStore)(nil)
+	_ Backend        = (*SQLiteStore)(nil)
+)
+
+// NewSQLiteStore opens (creating if needed) the database at path in WAL mode,
+// migrates it to the latest schema and prepares its statements
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	return newSQLiteStore(path, sqliteReadConns)
+}
+
+// newSQLiteStore is NewSQLiteStore with a given number of read connections
+func newSQLiteStore(path string, readConns int) (*SQLiteStore, error) {
+	pool, err := openDualConnectionPool(path, readConns)
+	if err != nil {
+		return nil, err
+	}
+	if err := NewMigrationRunner(pool.write).Run(); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("could not migrate schema: %w", err)
+	}
+
+	s := &SQLiteStore{pool: pool}
+	for _, p := range []struct {
+		stmt  **sql.Stmt
+		db    *sql.DB
+		query string
+	}{
+		{&s.insertStmt, pool.write, `INSERT INTO tasks (title, description, status, assignee, effort, priority, due_date, labels, checklist, position, created_at, updated_at, moved_at, status_changed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`},
+		{&s.upsertStmt, pool.write, `INSERT OR REPLACE INTO tasks (` + sqliteColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`},
+		{&s.moveStmt, pool.write, `UPDATE tasks SET
+			moved_at = CASE WHEN status = ?1 THEN moved_at ELSE ?2 END,
+			status_changed_at = CASE WHEN status = ?1 THEN status_changed_at ELSE ?2 END,
+			status = ?1, updated_at = ?2 WHERE id = ?3`},
+		{&s.deleteStmt, pool.write, `DELETE FROM tasks WHERE id = ?`},
+		{&s.getStmt, pool.read, `SELECT ` + sqliteColumns + ` FROM tasks WHERE id = ?`},
+		{&s.getByStatusStmt, pool.read, `SELECT ` + sqliteColumns + ` FROM tasks WHERE status = ? ORDER BY position, id`},
+		{&s.getAllStmt, pool.read, `SELECT ` + sqliteColumns + ` FROM tasks ORDER BY id`},
+		{&s.saveExtrasStmt, pool.write, `INSERT OR REPLACE INTO board_extras (id, data) VALUES (1, ?)`},
+		{&s.loadExtrasStmt, pool.read, `SELECT data FROM board_extras WHERE id = 1`},
+	} {
+		if *p.stmt, err = p.db.Prepare(p.query); err != nil {
+			s.Close()
+			return nil, fmt.Errorf("could not prepare statement: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// Close closes the prepared statements and the database
+func (s *SQLiteStore) Close() error {
+	for _, stmt := range []*sql.Stmt{
+		s.insertStmt, s.upsertStmt, s.moveStmt, s.deleteStmt,
+		s.getStmt, s.getByStatusStmt, s.getAllStmt, s.saveExtrasStmt, s.loadExtrasStmt,
+	} {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+	return s.pool.Close()
+}
+
+func (s *SQLiteStore) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// AddTask adds a new task to the "todo" column, returning nil if the
+// backend rejects it
+func (s *SQLiteStore) AddTask(title, description string) *Task {
+	task, err := s.CreateTask(TaskSpec{Title: title, Description: description})
+	if err != nil {
+		log.Printf("Error creating task: %v", err)
+	}
+	return task
+}
+
+// CreateTask inserts a task built from a spec, letting SQLite assign the ID
+func (s *SQLiteStore) CreateTask(spec TaskSpec) (*Task, error) {
+	status := spec.Status
+	if status == "" {
+		status = "todo"
+	}
+	task := &Task{
+		Title:       spec.Title,
+		Description: spec.Description,
+		Status:      status,
+		Assignee:    spec.Assignee,
+		Effort:      spec.Effort,
+		Priority:    spec.Priority,
+		DueDate:     spec.DueDate,
+		Labels:      spec.Labels,
+		CreatedAt:   s.clock(),
+	}
+	task.UpdatedAt = task.CreatedAt
+	task.StatusChangedAt = task.CreatedAt
+
+	args, err := sqliteTaskArgs(task)
+	if err != nil {
+		return nil, fmt.Errorf("encoding task: %w", err)
+	}
+	res, err := s.pool.exec(s.insertStmt, args[1:]...)
+	if err != nil {
+		return nil, fmt.Errorf("inserting task: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("reading task ID: %w", err)
+	}
+	task.ID = int(id)
+	return task, nil
+}
+
+// SaveTask inserts or replaces a task, keeping its ID
+func (s *SQLiteStore) SaveTask(task *Task) error {
+	args, err := sqliteTaskArgs(task)
+	if err != nil {
+		return err
+	}
+	_, err = s.pool.exec(s.upsertStmt, args...)
+	return err
+}
+
+// GetTask retrieves a task by ID
+func (s *SQLiteStore) GetTask(id int) (*Task, bool) {
+	task, err := scanSQLiteTask(s.getStmt.QueryRow(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Error reading task %d: %v", id, err)
+		return nil, false
+	}
+	return task, true
+}
+
+// GetTasksByStatus returns all tasks with a specific status in board order
+func (s *SQLiteStore) GetTasksByStatus(status string) []*Task {
+	return s.queryTasks(s.getByStatusStmt, status)
+}
+
+// GetAllTasks returns every task ordered by ID
+func (s *SQLiteStore) GetAllTasks() []*Task {
+	return s.queryTasks(s.getAllStmt)
+}
+
+// FilterTasks returns the tasks matching opts, ordered by ID. Statuses,
+// assignees and the minimum priority are matched in SQL; labels and
+// overdue dates are checked on the rows that come back.
+func (s *SQLiteStore) FilterTasks(opts FilterOptions) []*Task {
+	var where []string
+	var args []interface{}
+	in := func(column string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		where = append(where, column+" IN (?"+strings.Repeat(", ?", len(values)-1)+")")
+		for _, v := range values {
+			args = append(args, v)
+		}
+	}
+	in("status", opts.Statuses)
+	in("assignee", opts.Assignees)
+	if opts.MinPriority > 0 {
+		where = append(where, "priority >= ?")
+		args = append(args, opts.MinPriority)
+	}
+	query := `SELECT ` + sqliteColumns + ` FROM tasks`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY id`
+
+	tasks := []*Task{}
+	rows, err := s.pool.read.Query(query, args...)
+	if err != nil {
+		log.Printf("Error filtering tasks: %v", err)
+		return tasks
+	}
+	defer rows.Close()
+
+	now := s.clock()
+	for rows.Next() {
+		task, err := scanSQLiteTask(rows)
+		if err != nil {
+			log.Printf("Error reading task: %v", err)
+			continue
+		}
+		if opts.matches(task, now) {
+			tasks = append(tasks, task)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error filtering tasks: %v", err)
+	}
+	return tasks
+}
+
+func (s *SQLiteStore) queryTasks(stmt *sql.Stmt, args ...interface{}) []*Task {
+	tasks := []*Task{}
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		log.Printf("Error querying tasks: %v", err)
+		return tasks
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := scanSQLiteTask(rows)
+		if err != nil {
+			log.Printf("Error reading task: %v", err)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error querying tasks: %v", err)
+	}
+	return tasks
+}
+
+// MoveTask changes the status of a task
+func (s *SQLiteStore) MoveTask(id int, newStatus string) (*Task, bool, error) {
+	res, err := s.pool.exec(s.moveStmt, newStatus, s.clock().Format(time.RFC3339Nano), id)
+	if err != nil {
+		return nil, true, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, false, nil
+	}
+	task, ok := s.GetTask(id)
+	return task, ok, nil
+}
+
+// DeleteTask removes a task
+func (s *SQLiteStore) DeleteTask(id int) (bool, error) {
+	res, err := s.pool.exec(s.deleteStmt, id)
+	if err != nil {
+		return false, fmt.Errorf("deleting task %d: %w", id, err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Load reads the whole board along with the next ID to hand out. The next ID
+// comes from SQLite's AUTOINCREMENT sequence, so IDs of deleted tasks are not
+// reused.
+func (s *SQLiteStore) Load() (map[int]*Task, int, error) {
+	var seq int
+	err := s.pool.read.QueryRow(`SELECT seq FROM sqlite_sequence WHERE name = 'tasks'`).Scan(&seq)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, err
+	}
+
+	tasks := make(map[int]*Task)
+	next := seq + 1
+	for _, task := range s.GetAllTasks() {
+		tasks[task.ID] = task
+		if task.ID >= next {
+			next = task.ID + 1
+		}
+	}
+	return tasks, next, nil
+}
+
+// saveExtras replaces the board's links, comments, reactions and
+// relationships
+func (s *SQLiteStore) saveExtras(data PersistentData) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = s.pool.exec(s.saveExtrasStmt, string(encoded))
+	return err
+}
+
+// loadExtras reads what saveExtras last saved, or nothing if it never ran
+func (s *SQLiteStore) loadExtras() (PersistentData, error) {
+	var data PersistentData
+	var encoded string
+	err := s.loadExtrasStmt.QueryRow().Scan(&encoded)
+	if errors.Is(err, sql.ErrNoRows) {
+		return data, nil
+	}
+	if err != nil {
+		return data, err
+	}
+	return data, json.Unmarshal([]byte(encoded), &data)
+}
+
+// mirror applies a TaskStore event to the database
+func (s *SQLiteStore) mirror(e Event) {
+	if e.Task == nil {
+		return
+	}
+	var err error
+	switch e.Type {
+	case EventTaskCreated, EventTaskUpdated, EventTaskMoved:
+		err = s.SaveTask(e.Task)
+	case EventTaskDeleted:
+		_, err = s.pool.exec(s.deleteStmt, e.Task.ID)
+	}
+	if err != nil {
+		log.Printf("Error mirroring %s for task %d to sqlite: %v", e.Type, e.Task.ID, err)
+	}
+}
+
+// sqliteTaskArgs returns a task's values in sqliteColumns order
+func sqliteTaskArgs(task *Task) ([]interface{}, error) {
+	labels, err := json.Marshal(task.Labels)
+	if err != nil {
+		return nil, err
+	}
+	checklist, err := json.Marshal(task.Checklist)
+	if err != nil {
+		return nil, err
+	}
+	var due, moved interface{}
+	if task.DueDate != nil {
+		due = task.DueDate.Format(time.RFC3339Nano)
+	}
+	if task.MovedAt != nil {
+		moved = task.MovedAt.Format(time.RFC3339Nano)
+	}
+	return []interface{}{
+		task.ID, task.Title, task.Description, task.Status, task.Assignee,
+		task.Effort, task.Priority, due, string(labels), string(checklist),
+		task.Position, task.CreatedAt.Format(time.RFC3339Nano), task.UpdatedAt.Format(time.RFC3339Nano), moved,
+		task.StatusChangedAt.Format(time.RFC3339Nano),
+	}, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSQLiteTask reads a row selected with sqliteColumns
+func scanSQLiteTask(row rowScanner) (*Task, error) {
+	var (
+		task                 Task
+		due, moved           sql.NullString
+		labels, checklist    string
+		createdAt, updatedAt string
+		statusChangedAt      sql.NullString
+	)
+	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.Assignee,
+		&task.Effort, &task.Priority, &due, &labels, &checklist, &task.Position, &createdAt, &updatedAt, &moved, &statusChangedAt)
+	if err != nil {
+		return nil, err
+	}
+	if due.Valid {
+		d, err := time.Parse(time.RFC3339Nano, due.String)
+		if err != nil {
+			return nil, fmt.Errorf("task %d: invalid due_date %q", task.ID, due.String)
+		}
+		task.DueDate = &d
+	}
+	if err := json.Unmarshal([]byte(labels), &task.Labels); err != nil {
+		return nil, fmt.Errorf("task %d: invalid labels", task.ID)
+	}
+	if err := json.Unmarshal([]byte(checklist), &task.Checklist); err != nil {
+		return nil, fmt.Errorf("task %d: invalid checklist", task.ID)
+	}
+	task.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	task.UpdatedAt, _ = time.Parse(time.RFC3339Nano, updatedAt)
+	task.StatusChangedAt, _ = time.Parse(time.RFC3339Nano, statusChangedAt.String)
+	if moved.Valid {
+		m, err := time.Parse(time.RFC3339Nano, moved.String)
+		if err != nil {
+			return nil, fmt.Errorf("task %d: invalid moved_at %q", task.ID, moved.String)
+		}
+		task.MovedAt = &m
+	}
+	return &task, nil
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestSQLiteStore(t testing.TB) *SQLiteStore {
+	sq, err := NewSQLiteStore(filepath.Join(t.TempDir(), "kanban.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	t.Cleanup(func() { sq.Close() })
+	return sq
+}
+
+func TestSQLiteStoreCRUD(t *testing.T) {
+	sq := newTestSQLiteStore(t)
+	due := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	a := sq.AddTask("A", "first")
+	b, _ := sq.CreateTask(TaskSpec{Title: "B", Status: "doing", Priority: PriorityHigh, DueDate: &due, Labels: []string{"x", "y"}})
+	if a.ID != 1 || b.ID != 2 {
+		t.Fatalf("Expected IDs 1 and 2, got %d and %d", a.ID, b.ID)
+	}
+
+	got, ok := sq.GetTask(b.ID)
+	if !ok || got.Priority != PriorityHigh || !got.DueDate.Equal(due) || len(got.Labels) != 2 || got.Labels[1] != "y" {
+		t.Errorf("Task did not round-trip: %+v", got)
+	}
+	if got, _ := sq.GetTask(a.ID); got.DueDate != nil {
+		t.Errorf("Expected NULL due_date to load as nil, got %v", got.DueDate)
+	}
+
+	moved, ok, err := sq.MoveTask(a.ID, "done")
+	if !ok || err != nil {
+		t.Fatalf("MoveTask failed: ok=%v err=%v", ok, err)
+	}
+	if moved.MovedAt == nil {
+		t.Errorf("Expected MoveTask to record moved_at")
+	}
+	if todo := sq.GetTasksByStatus("todo"); len(todo) != 0 {
+		t.Errorf("Expected todo to be empty after move, got %v", taskIDs(todo))
+	}
+	if done := taskIDs(sq.GetTasksByStatus("done")); !equalIDs(done, []int{1}) {
+		t.Errorf("Expected done [1], got %v", done)
+	}
+	if _, ok, _ := sq.MoveTask(99, "done"); ok {
+		t.Errorf("Expected missing task to report not found")
+	}
+
+	if ok, err := sq.DeleteTask(b.ID); !ok || err != nil {
+		t.Errorf("Expected delete to succeed, got %v, %v", ok, err)
+	}
+	if ok, _ := sq.DeleteTask(b.ID); ok {
+		t.Errorf("Expected a second delete to find nothing")
+	}
+	if all := taskIDs(sq.GetAllTasks()); !equalIDs(all, []int{1}) {
+		t.Errorf("Expected all tasks [1], got %v", all)
+	}
+}
+
+func TestSQLiteStoreReusesStatements(t *testing.T) {
+	sq := newTestSQLiteStore(t)
+	if max := sq.pool.write.Stats().MaxOpenConnections; max != 1 {
+		t.Errorf("Expected a single write connection, got %d", max)
+	}
+
+	insert, move, byStatus := sq.insertStmt, sq.moveStmt, sq.getByStatusStmt
+	for i := 0; i < 5; i++ {
+		task := sq.AddTask(fmt.Sprintf("Task %d", i), "")
+		sq.MoveTask(task.ID, "doing")
+		sq.GetTasksByStatus("doing")
+	}
+	if sq.insertStmt != insert || sq.moveStmt != move || sq.getByStatusStmt != byStatus {
+		t.Errorf("Expected prepared statements to be reused across calls")
+	}
+	if n := len(sq.GetTasksByStatus("doing")); n != 5 {
+		t.Errorf("Expected 5 doing tasks, got %d", n)
+	}
+	if open := sq.pool.write.Stats().OpenConnections; open > 1 {
+		t.Errorf("Expected at most one open write connection, got %d", open)
+	}
+}
+
+func TestTaskStoreMirrorsToSQLite(t *testing.T) {
+	sq := newTestSQLiteStore(t)
+	s := withTestGlobals(t)
+	s.backend = sq
+	bus.Subscribe(EventAll, sq.mirror)
+
+	task := s.AddTask("Stored", "")
+	s.MoveTask(task.ID, "doing")
+	s.AssignTask(task.ID, "alice")
+	s.AddChecklistItem(task.ID, "Check")
+	other := s.AddTask("Temporary", "")
+	s.DeleteTask(other.ID)
+
+	reloaded := newTestStore()
+	reloaded.backend = sq
+	if err := reloaded.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	loaded, ok := reloaded.GetTask(task.ID)
+	if !ok || loaded.Status != "doing" || loaded.Assignee != "alice" || len(loaded.Checklist) != 1 {
+		t.Errorf("Expected mirrored task in doing assigned to alice, got %+v", loaded)
+	}
+	if len(reloaded.GetAllTasks()) != 1 {
+		t.Errorf("Expected deleted task to be gone, got %d tasks", len(reloaded.GetAllTasks()))
+	}
+	if created := reloaded.AddTask("After reload", ""); created.ID != 3 {
+		t.Errorf("Expected ID 3 after reload, got %d", created.ID)
+	}
+}
+
+func TestTaskStoreSavesExtrasToSQLite(t *testing.T) {
+	sq := newTestSQLiteStore(t)
+	s := withTestGlobals(t)
+	s.backend = sq
+	bus.Subscribe(EventAll, sq.mirror)
+
+	a := s.AddTask("A", "")
+	b := s.AddTask("B", "")
+	gone := s.AddTask("Gone", "")
+	comment, _, _ := s.AddComment(a.ID, "ana", "Looks good")
+	s.ToggleReaction(a.ID, comment.ID, "👍", "bo")
+	s.AddLink(a.ID, "https://example.com/spec", "Spec")
+	s.AddRelationship(a.ID, b.ID, RelationBlocks)
+	s.AddComment(gone.ID, "ana", "Soon deleted")
+	s.DeleteTask(gone.ID)
+
+	reloaded := newTestStore()
+	reloaded.backend = sq
+	if err := reloaded.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	if comments, _ := reloaded.Comments(a.ID); len(comments) != 1 || comments[0].Body != "Looks good" {
+		t.Errorf("Expected the comment saved to the backend, got %+v", comments)
+	}
+	if reactions := reloaded.GetReactions(comment.ID); len(reactions["👍"]) != 1 {
+		t.Errorf("Expected the reaction saved to the backend, got %v", reactions)
+	}
+	if links, _ := reloaded.Links(a.ID); len(links) != 1 || links[0].Label != "Spec" {
+		t.Errorf("Expected the link saved to the backend, got %+v", links)
+	}
+	if related := reloaded.GetRelated(a.ID); len(related) != 1 {
+		t.Errorf("Expected the relationship saved to the backend, got %+v", related)
+	}
+	if comments, _ := reloaded.Comments(gone.ID); len(comments) != 0 {
+		t.Errorf("Expected a deleted task's comments dropped from the backend, got %+v", comments)
+	}
+	if next, _, _ := reloaded.AddComment(b.ID, "bo", "New"); next.ID <= comment.ID {
+		t.Errorf("Expected comment IDs to carry on after reload, got %d", next.ID)
+	}
+}
+
+func TestTaskStoreMirrorsBulkChangesToSQLite(t *testing.T) {
+	sq := newTestSQLiteStore(t)
+	s := withTestGlobals(t)
+	s.backend = sq
+	bus.Subscribe(EventAll, sq.mirror)
+
+	reload := func() *TaskStore {
+		t.Helper()
+		reloaded := newTestStore()
+		reloaded.backend = sq
+		if err := reloaded.LoadFromFile(); err != nil {
+			t.Fatalf("LoadFromFile failed: %v", err)
+		}
+		return reloaded
+	}
+
+	gone := s.AddTask("Gone", "")
+	kept := s.AddTask("Kept", "")
+	s.AddComment(kept.ID, "ana", "Still here")
+	s.DeleteTask(gone.ID)
+	if _, err := s.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	reloaded := reload()
+	if task, ok := reloaded.GetTask(1); !ok || task.Title != "Kept" || len(reloaded.GetAllTasks()) != 1 {
+		t.Fatalf("Expected the compacted board in the backend, got %+v", reloaded.GetAllTasks())
+	}
+	if comments, _ := reloaded.Comments(1); len(comments) != 1 {
+		t.Errorf("Expected the comment renumbered in the backend, got %+v", comments)
+	}
+
+	s.mu.Lock()
+	s.tasks[1].DependsOn = []int{42}
+	s.mu.Unlock()
+	if fixed, err := s.Repair(s.CheckIntegrity()); err != nil || fixed == 0 {
+		t.Fatalf("Repair fixed %d, %v", fixed, err)
+	}
+	if task, _ := reload().GetTask(1); len(task.DependsOn) != 0 {
+		t.Errorf("Expected the repaired dependencies in the backend, got %v", task.DependsOn)
+	}
+
+	restored := &Task{ID: 7, Title: "Restored", Status: "done"}
+	if err := s.Restore(PersistentData{Tasks: []*Task{restored}, NextID: 8}); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	reloaded = reload()
+	if tasks := reloaded.GetAllTasks(); len(tasks) != 1 || tasks[0].Title != "Restored" || tasks[0].Status != "done" {
+		t.Errorf("Expected only the restored task in the backend, got %+v", tasks)
+	}
+	if comments, _ := reloaded.Comments(1); len(comments) != 0 {
+		t.Errorf("Expected the restored-away task's comments dropped from the backend, got %+v", comments)
+	}
+}
+
+func seedSQLiteBenchmark(b *testing.B) *SQLiteStore {
+	sq := newTestSQLiteStore(b)
+	for i := 0; i < 200; i++ {
+		sq.CreateTask(TaskSpec{Title: fmt.Sprintf("Task %d", i), Status: boardColumns[i%3].Status})
+	}
+	b.ResetTimer()
+	return sq
+}
+
+func BenchmarkSQLiteGetByStatusPrepared(b *testing.B) {
+	sq := seedSQLiteBenchmark(b)
+	for i := 0; i < b.N; i++ {
+		sq.GetTasksByStatus("todo")
+	}
+}
+
+func BenchmarkSQLiteGetByStatusRaw(b *testing.B) {
+	sq := seedSQLiteBenchmark(b)
+	query := `SELECT ` + sqliteColumns + ` FROM tasks WHERE status = ? ORDER BY position, id`
+	for i := 0; i < b.N; i++ {
+		rows, err := sq.pool.read.Query(query, "todo")
+		if err != nil {
+			b.Fatal(err)
+		}
+		for rows.Next() {
+			if _, err := scanSQLiteTask(rows); err != nil {
+				b.Fatal(err)
+			}
+		}
+		rows.Close()
+	}
+}
+
+func TestSQLiteReadsDuringWrite(t *testing.T) {
+	sq := newTestSQLiteStore(t)
+	for i := 0; i < 20; i++ {
+		sq.AddTask(fmt.Sprintf("Task %d", i), "")
+	}
+
+	// Hold a write transaction open while readers run
+	sq.pool.writeMu.Lock()
+	tx, err := sq.pool.write.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`UPDATE tasks SET status = 'done'`); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				var count int
+				if err := sq.pool.read.QueryRow(`SELECT COUNT(*) FROM tasks WHERE status = 'todo'`).Scan(&count); err != nil {
+					errs <- err
+					return
+				}
+				if count != 20 {
+					errs <- fmt.Errorf("expected the uncommitted write to be invisible, saw %d todo tasks", count)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Read during write failed: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	sq.pool.writeMu.Unlock()
+	if n := len(sq.GetTasksByStatus("done")); n != 20 {
+		t.Errorf("Expected reads to see the committed write, got %d done tasks", n)
+	}
+	if _, err := sq.pool.read.Exec(`DELETE FROM tasks`); err == nil {
+		t.Errorf("Expected the read connections to be read-only")
+	}
+}
+
+func TestSQLiteFilterTasks(t *testing.T) {
+	sq := newTestSQLiteStore(t)
+	sq.CreateTask(TaskSpec{Title: "A", Assignee: "dana", Priority: PriorityHigh, Labels: []string{"bug"}})
+	sq.CreateTask(TaskSpec{Title: "B", Status: "doing", Assignee: "dana", Priority: PriorityLow})
+	sq.CreateTask(TaskSpec{Title: "C", Status: "doing", Assignee: "sam", Priority: PriorityHigh, Labels: []string{"bug"}})
+
+	for _, tc := range []struct {
+		opts FilterOptions
+		want []int
+	}{
+		{FilterOptions{}, []int{1, 2, 3}},
+		{FilterOptions{Statuses: []string{"doing"}, Assignees: []string{"dana", "sam"}}, []int{2, 3}},
+		{FilterOptions{MinPriority: PriorityHigh, Labels: []string{"bug"}}, []int{1, 3}},
+		{FilterOptions{Statuses: []string{"done"}}, []int{}},
+	} {
+		if got := taskIDs(sq.FilterTasks(tc.opts)); !equalIDs(got, tc.want) {
+			t.Errorf("FilterTasks(%+v) = %v, want %v", tc.opts, got, tc.want)
+		}
+	}
+}
+
+// benchmarkSQLiteConcurrentReads runs 10 readers against the board while one
+// writer keeps moving tasks
+func benchmarkSQLiteConcurrentReads(b *testing.B, readConns int) {
+	sq, err := newSQLiteStore(filepath.Join(b.TempDir(), "kanban.db"), readConns)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer sq.Close()
+	for i := 0; i < 200; i++ {
+		sq.CreateTask(TaskSpec{Title: fmt.Sprintf("Task %d", i), Status: boardColumns[i%3].Status})
+	}
+
+	stop := make(chan struct{})
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				sq.MoveTask(i%200+1, boardColumns[i%3].Status)
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for r := 0; r < 10; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			for i := r; i < b.N; i += 10 {
+				sq.GetTasksByStatus("todo")
+			}
+		}(r)
+	}
+	wg.Wait()
+	b.StopTimer()
+	close(stop)
+	<-writerDone
+}
+
+func BenchmarkSQLiteConcurrentReadsSingleConnection(b *testing.B) {
+	benchmarkSQLiteConcurrentReads(b, 0)
+}
+
+func BenchmarkSQLiteConcurrentReadsDualPool(b *testing.B) {
+	benchmarkSQLiteConcurrentReads(b, 10)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// staleTitlePrefix is prepended to tasks returned to "todo" for being stale
+const staleTitlePrefix = "(stale) "
+
+// LoadStaleThreshold reads KANBAN_STALE_DOING_DAYS. A zero threshold means
+// stale tasks are never moved back.
+func LoadStaleThreshold() (time.Duration, error) {
+	raw := os.Getenv("KANBAN_STALE_DOING_DAYS")
+	if raw == "" {
+		return 0, nil
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid KANBAN_STALE_DOING_DAYS %q", raw)
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// GetStaleTasks returns "doing" tasks that have not been updated within the threshold
+func (s *TaskStore) GetStaleTasks(threshold time.Duration) []*Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.staleTasks(threshold)
+}
+
+// staleTasks finds stale "doing" tasks (must be called with lock held)
+func (s *TaskStore) staleTasks(threshold time.Duration) []*Task {
+	cutoff := s.clock().Add(-threshold)
+	var tasks []*Task
+	for _, task := range s.tasks {
+		if task.Status == "doing" && !task.UpdatedAt.IsZero() && task.UpdatedAt.Before(cutoff) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// ReturnStaleTasks moves stale "doing" tasks back to "todo", marking their
+// titles, and returns the tasks that were moved, or ErrBoardLocked
+func (s *TaskStore) ReturnStaleTasks(threshold time.Duration) ([]*Task, error) {
+	span := s.startSpan(context.Background(), "ReturnStaleTasks")
+	defer span.End()
+
+	s.mu.Lock()
+
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	stale := s.staleTasks(threshold)
+	if len(stale) == 0 {
+		s.mu.Unlock()
+		return nil, nil
+	}
+	var events []Event
+	for _, task := range stale {
+		var changes []FieldChange
+		if !strings.HasPrefix(task.Title, staleTitlePrefix) {
+			changes = append(changes, FieldChange{Field: "title", Old: task.Title, New: staleTitlePrefix + task.Title})
+			task.Title = staleTitlePrefix + task.Title
+		}
+		task.Status = "todo"
+		task.UpdatedAt = s.clock()
+		movedAt := task.UpdatedAt
+		task.MovedAt = &movedAt
+		task.StatusChangedAt = task.UpdatedAt
+		task.recordTransition("doing", "todo", task.UpdatedAt)
+		log.Printf("Moved stale task %d (%s) back to todo", task.ID, task.Title)
+		events = append(events, Event{
+			Type:       EventTaskMoved,
+			Task:       task.clone(),
+			FromStatus: "doing",
+			ToStatus:   "todo",
+			Changes:    changes,
+			Actor:      "system",
+		})
+	}
+	s.persist("doing", "todo")
+	s.mu.Unlock()
+
+	for _, e := range events {
+		s.publish(e)
+	}
+	return stale, nil
+}
+
+// runStaleSweeper returns stale tasks to "todo" on every tick until stop is
+// closed. Ticks that arrive while the board is locked are skipped.
+func (s *TaskStore) runStaleSweeper(ticks <-chan time.Time, threshold time.Duration, stop <-chan struct{}) {
+	for {
+		select {
+		case <-ticks:
+			s.ReturnStaleTasks(threshold)
+		case <-stop:
+			return
+		}
+	}
+}
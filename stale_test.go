@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetStaleTasks(t *testing.T) {
+	store := newTestStore()
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	current := start
+	store.now = func() time.Time { return current }
+
+	store.AddTask("Stuck", "")
+	store.AddTask("Fresh", "")
+	store.AddTask("Waiting", "")
+	store.MoveTask(1, "doing")
+
+	current = start.Add(10 * 24 * time.Hour)
+	store.MoveTask(2, "doing")
+
+	current = start.Add(15 * 24 * time.Hour)
+	stale := store.GetStaleTasks(14 * 24 * time.Hour)
+	if len(stale) != 1 || stale[0].ID != 1 {
+		t.Errorf("Expected only task 1 to be stale, got %v", stale)
+	}
+}
+
+func TestStaleSweeperReturnsTasksToTodo(t *testing.T) {
+	store := newTestStore()
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	var current time.Time
+	store.now = func() time.Time { return current }
+
+	current = start
+	store.AddTask("Stuck", "")
+	store.MoveTask(1, "doing")
+	current = start.Add(15 * 24 * time.Hour)
+
+	ticks := make(chan time.Time)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		store.runStaleSweeper(ticks, 14*24*time.Hour, stop)
+		close(done)
+	}()
+	ticks <- current
+	close(stop)
+	<-done
+
+	task, _ := store.GetTask(1)
+	if task.Status != "todo" {
+		t.Errorf("Expected stale task to be moved back to todo, got %s", task.Status)
+	}
+	if task.Title != "(stale) Stuck" {
+		t.Errorf("Expected stale marker in title, got %q", task.Title)
+	}
+
+	// A second sweep must not touch the task again
+	if moved, _ := store.ReturnStaleTasks(14 * 24 * time.Hour); len(moved) != 0 {
+		t.Errorf("Task in todo should not be considered stale")
+	}
+}
+
+func TestLoadStaleThreshold(t *testing.T) {
+	t.Setenv("KANBAN_STALE_DOING_DAYS", "14")
+	threshold, err := LoadStaleThreshold()
+	if err != nil || threshold != 14*24*time.Hour {
+		t.Errorf("Expected 14 days, got %v (%v)", threshold, err)
+	}
+	t.Setenv("KANBAN_STALE_DOING_DAYS", "soon")
+	if _, err := LoadStaleThreshold(); err == nil {
+		t.Errorf("Expected error for non-numeric days")
+	}
+}
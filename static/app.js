@@ -0,0 +1,117 @@
+// Show refused moves in the #move-error banner, and clear it on the next successful one
+htmx.on('htmx:beforeSwap', function (evt) {
+    if (evt.detail.xhr.status === 422 && evt.detail.xhr.getResponseHeader('HX-Retarget') === '#move-error') {
+        evt.detail.shouldSwap = true;
+        evt.detail.isError = false;
+    } else if (evt.detail.successful && document.getElementById('move-error')) {
+        document.getElementById('move-error').innerHTML = '';
+    }
+});
+
+// Fade cards that don't match the search box right away; the server's
+// filtered board replaces them once typing pauses
+function fadeUnmatchedCards(query) {
+    query = query.trim().toLowerCase();
+    document.querySelectorAll('#board .task-card').forEach(function (card) {
+        card.classList.toggle('search-miss', query !== '' && !card.textContent.toLowerCase().includes(query));
+    });
+}
+
+// Drag cards to reorder a column; the new order is saved via /reorder/{status}
+htmx.onLoad(function (content) {
+    content.querySelectorAll('.task-list').forEach(function (list) {
+        if (list.sortable) return;
+        list.sortable = new Sortable(list, {
+            draggable: '.task-card',
+            animation: 150,
+            onEnd: function () {
+                var ids = Array.from(list.querySelectorAll('.task-card')).map(function (card) {
+                    return card.dataset.id;
+                });
+                htmx.ajax('POST', '/reorder/' + list.id.replace(/-tasks$/, ''), {
+                    target: list,
+                    swap: 'innerHTML',
+                    values: {order: ids.join(',')}
+                });
+            }
+        });
+    });
+});
+
+// Keyboard shortcuts, from window.keyboardShortcuts (see
+// /settings/keyboard-shortcuts). The focus shortcuts move focus between
+// cards, up and down within a column and left and right across columns.
+var focusedCardID = null;
+
+document.addEventListener('focusin', function (evt) {
+    var card = evt.target.closest && evt.target.closest('.task-card');
+    if (card) focusedCardID = card.dataset.id;
+});
+
+function shortcutAction(evt) {
+    var shortcuts = window.keyboardShortcuts || [];
+    for (var i = 0; i < shortcuts.length; i++) {
+        var s = shortcuts[i];
+        var modifiers = ['ctrl', 'alt', 'shift', 'meta'].every(function (m) {
+            // Shift is part of keys like "?" unless a shortcut asks for it
+            return m === 'shift' && s.modifier !== 'shift' ? true : evt[m + 'Key'] === (s.modifier === m);
+        });
+        if (modifiers && evt.key.toLowerCase() === s.key.toLowerCase()) return s.action;
+    }
+    return null;
+}
+
+var shortcutHandlers = {
+    'new-task': function () { return focusElement('title'); },
+    'search': function () { return focusElement('search'); },
+    'move-task': function (card) {
+        if (!card) return false;
+        htmx.ajax('POST', '/task/' + card.dataset.id + '/move-next', {target: '#board', swap: 'innerHTML'});
+        return true;
+    },
+    'focus-down': function (card) { return focusSibling(card, 1); },
+    'focus-up': function (card) { return focusSibling(card, -1); },
+    'focus-left': function (card) { return focusColumn(card, -1); },
+    'focus-right': function (card) { return focusColumn(card, 1); }
+};
+
+function focusElement(id) {
+    var el = document.getElementById(id);
+    if (el) el.focus();
+    return !!el;
+}
+
+function focusSibling(card, step) {
+    if (!card) return false;
+    var cards = Array.from(card.parentElement.querySelectorAll('.task-card'));
+    var next = cards[cards.indexOf(card) + step];
+    if (next) next.focus();
+    return !!next;
+}
+
+function focusColumn(card, step) {
+    if (!card) return false;
+    var columns = Array.from(document.querySelectorAll('#board .column'));
+    var column = columns[columns.indexOf(card.closest('.column')) + step];
+    var next = column && column.querySelector('.task-card');
+    if (next) next.focus();
+    return !!next;
+}
+
+document.addEventListener('keydown', function (evt) {
+    var target = evt.target;
+    var card = target.classList && target.classList.contains('task-card') ? target : null;
+    // Leave keys alone while typing, except on a focused card
+    if (!card && target.closest && target.closest('input, textarea, select, [contenteditable]')) return;
+    var handler = shortcutHandlers[shortcutAction(evt)];
+    if (handler && handler(card)) evt.preventDefault();
+});
+
+// Restore keyboard focus after the board is swapped, to the card that had it
+// if it still exists and otherwise to the first card
+function focusFirstCard() {
+    if (!focusedCardID) return;
+    var card = document.querySelector('#board .task-card[data-id="' + focusedCardID + '"]') ||
+        document.querySelector('#board .task-card');
+    if (card) card.focus();
+}
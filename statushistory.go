@@ -0,0 +1,61 @@
+package main
+
+import "time"
+
+// StatusTransition records one move of a task between columns
+type StatusTransition struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	At         time.Time `json:"at"`
+}
+
+// recordTransition appends a move to the task's status history
+func (t *Task) recordTransition(from, to string, at time.Time) {
+	t.StatusHistory = append(t.StatusHistory, StatusTransition{FromStatus: from, ToStatus: to, At: at})
+}
+
+// GetStatusHistory returns the columns a task has moved through, oldest
+// first, or nil if the task does not exist
+func (s *TaskStore) GetStatusHistory(taskID int) []StatusTransition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return nil
+	}
+	return append([]StatusTransition{}, task.StatusHistory...)
+}
+
+// TimeInStatus sums every interval the task has spent in status, counting
+// from its creation and up to now if it is still there. It returns zero if
+// the task does not exist.
+func (s *TaskStore) TimeInStatus(taskID int, status string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return 0
+	}
+	return task.timeInStatus(status, s.clock())
+}
+
+func (t *Task) timeInStatus(status string, now time.Time) time.Duration {
+	current := t.Status
+	if len(t.StatusHistory) > 0 {
+		current = t.StatusHistory[0].FromStatus
+	}
+	since := t.CreatedAt
+	var total time.Duration
+	for _, transition := range t.StatusHistory {
+		if current == status {
+			total += transition.At.Sub(since)
+		}
+		current, since = transition.ToStatus, transition.At
+	}
+	if current == status {
+		total += now.Sub(since)
+	}
+	return total
+}
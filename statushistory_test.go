@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusHistory(t *testing.T) {
+	s := newTestStore()
+	start := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	current := start
+	s.now = func() time.Time { return current }
+
+	task := s.AddTask("Journey", "")
+	current = start.Add(2 * time.Hour)
+	s.MoveTask(task.ID, "doing")
+	current = start.Add(3 * time.Hour)
+	s.MoveTask(task.ID, "todo")
+	current = start.Add(6 * time.Hour)
+	s.MoveTask(task.ID, "doing")
+	s.MoveTask(task.ID, "doing") // not a transition
+	current = start.Add(10 * time.Hour)
+	s.MoveTask(task.ID, "done")
+	current = start.Add(24 * time.Hour)
+
+	history := s.GetStatusHistory(task.ID)
+	want := []StatusTransition{
+		{"todo", "doing", start.Add(2 * time.Hour)},
+		{"doing", "todo", start.Add(3 * time.Hour)},
+		{"todo", "doing", start.Add(6 * time.Hour)},
+		{"doing", "done", start.Add(10 * time.Hour)},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("Expected %d transitions, got %+v", len(want), history)
+	}
+	for i := range want {
+		if history[i] != want[i] {
+			t.Errorf("Transition %d: expected %+v, got %+v", i, want[i], history[i])
+		}
+	}
+
+	for status, want := range map[string]time.Duration{
+		"todo":  5 * time.Hour,
+		"doing": 5 * time.Hour,
+		"done":  14 * time.Hour,
+	} {
+		if got := s.TimeInStatus(task.ID, status); got != want {
+			t.Errorf("TimeInStatus(%s) = %v, want %v", status, got, want)
+		}
+	}
+
+	untouched := s.AddTask("Still waiting", "")
+	current = current.Add(time.Hour)
+	if got := s.TimeInStatus(untouched.ID, "todo"); got != time.Hour {
+		t.Errorf("Expected an unmoved task to count from its creation, got %v", got)
+	}
+	if s.GetStatusHistory(99) != nil || s.TimeInStatus(99, "todo") != 0 {
+		t.Errorf("Expected nothing for a missing task")
+	}
+}
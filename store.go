@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// Store is everything handlers can ask of the board. TaskStore is the real
+// implementation; tests can substitute their own.
+type Store interface {
+	TaskRepository
+	CreateTaskContext(ctx context.Context, spec TaskSpec) (*Task, error)
+	MoveTaskContext(ctx context.Context, id int, newStatus string) (*Task, bool, error)
+	DeleteTaskContext(ctx context.Context, id int) (bool, error)
+	UpdateTask(id int, update func(task *Task)) (*Task, error)
+	AssignTask(id int, assignee string) (*Task, error)
+	PatchTask(ctx context.Context, id int, patch map[string]interface{}) (*Task, error)
+	ApproveTask(id int, requestedBy string) (*Task, error)
+	SetPinned(id int, pinned bool) (*Task, bool, error)
+	SetWatching(id int, user string, watching bool) (*Task, bool, error)
+	DuplicateTask(id int, targetStatus string) (*Task, bool, error)
+	DuplicateToSprint(taskID, sprintID int) (*Task, error)
+	ConvertToSubtask(id, parentID int) (*Task, error)
+	SetDependencies(id int, deps []int) (*Task, error)
+	BatchMove(from, to string, opts FilterOptions) ([]*Task, error)
+	MoveToTop(id int) (*Task, error)
+	MoveToBottom(id int) (*Task, error)
+	SplitTask(id int, newTitles []string) ([]*Task, bool, error)
+	CopySpec(id int) (TaskSpec, bool)
+	CreateCopy(spec TaskSpec) (*Task, error)
+	CloneStore(includeTasks bool) Store
+	CreateTasks(specs []TaskSpec) ([]*Task, error)
+	ValidateImport(tasks []TaskInput) []ValidationIssue
+
+	// Board views
+	GetBoardData() BoardData
+	GetBoardDataContext(ctx context.Context) BoardData
+	GetColumnData(status string) ColumnData
+	GetColumnDataContext(ctx context.Context, status string) ColumnData
+	GetSwimLanes() map[string]map[string][]*Task
+	FilterTasks(opts FilterOptions) []*Task
+	SearchTasks(query string) []*Task
+	SearchBoardData(query string) BoardData
+	FindSimilarTasks(id int, threshold float64) []*Task
+	ReorderColumn(status string, ids []int) error
+	TasksSince(since time.Time) []SyncTask
+
+	// Columns
+	Columns() []ColumnInfo
+	GetColumnColor(status string) string
+	AddColumn(status, displayName string, wipLimit int, color string) error
+	UpdateColumn(status string, displayName *string, wipLimit *int, color *string) error
+	RemoveColumn(status string) error
+
+	// Checklists, comments, links, labels and relationships
+	AddChecklistItem(id int, text string) (*Task, bool, error)
+	ToggleChecklistItem(id, index int) (*Task, bool, error)
+	DeleteChecklistItem(id, index int) (*Task, bool, error)
+	AddComment(taskID int, author, body string) (*Comment, bool, error)
+	AddReply(taskID, parentID int, author, body string) (*Comment, bool, error)
+	Comments(taskID int) ([]*Comment, bool)
+	GetComments(taskID int) []CommentThread
+	ToggleReaction(taskID, commentID int, emoji, userID string) (bool, error)
+	GetReactions(commentID int) map[string][]string
+	AddLink(taskID int, rawURL, label string) (*Link, bool, error)
+	Links(taskID int) ([]*Link, bool)
+	DeleteLink(taskID, linkID int) bool
+	GetTasksByLabel(name string) ([]*Task, bool)
+	DeleteLabel(name string) (bool, error)
+	BulkLabel(taskIDs []int, addLabels, removeLabels []string) (BulkResult, error)
+	AddRelationship(fromID, toID int, relType string) (*Relationship, error)
+	GetRelated(taskID int) []RelatedTask
+	DeleteRelationship(id int) bool
+
+	// History and statistics
+	GetStatusHistory(taskID int) []StatusTransition
+	TimeInStatus(taskID int, status string) time.Duration
+	CompletionRate() float64
+	RecentActivity(since time.Duration) ActivitySummary
+	Velocity(window time.Duration, periods int) []VelocityPeriod
+	CycleTime(taskID int) (time.Duration, error)
+	AverageCycleTime(status string) time.Duration
+	CycleTimes(status string) CycleTimeStats
+	GetStaleTasks(threshold time.Duration) []*Task
+	GetTasksDueWithin(d time.Duration) []*Task
+	GetExpiringTasks(within time.Duration) []*Task
+	ReturnStaleTasks(threshold time.Duration) ([]*Task, error)
+	CountArchivable(status string, olderThan time.Duration) int
+	BulkArchive(status string, olderThan time.Duration) (int, error)
+
+	// Snapshots, maintenance and lifecycle
+	Snapshot() PersistentData
+	Restore(data PersistentData) error
+	CheckIntegrity() []IntegrityError
+	Repair(problems []IntegrityError) (int, error)
+	Compact() (int, error)
+	LoadFromFile() error
+	RegisterTransitionHook(hook TransitionHook)
+	SubscribeToTask(taskID int, ch chan TaskEvent)
+	UnsubscribeFromTask(taskID int, ch chan TaskEvent)
+	LockBoard(reason string) error
+	UnlockBoard() error
+	BoardLock() error
+	Close() error
+
+	// Board state the handlers render from
+	Now() time.Time
+	Health() BoardHealth
+	Workflow() WorkflowConfig
+	Revision() uint64
+	Settings() map[string]string
+	ListLabels() []Label
+	CreateLabel(name, color string) (Label, error)
+	ListSprints() []Sprint
+	CreateSprint(name string) (Sprint, error)
+}
+
+var _ Store = (*TaskStore)(nil)
+
+// Server serves the handlers that take their store from it rather than
+// from the store global, so tests can run them against any Store
+type Server struct {
+	Store
+	pages ResponseCache // the last rendered index page
+}
+
+// NewServer returns a server backed by s
+func NewServer(s Store) *Server {
+	return &Server{Store: s}
+}
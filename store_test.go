@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mockStore is a Store for handler tests: a real in-memory TaskStore, seeded
+// with the given tasks, that also records what was deleted through it. Every
+// method works, so a handler can reach for anything, and a test can still
+// tell the handler used the injected store rather than the global one.
+type mockStore struct {
+	*TaskStore
+	deleted []int
+}
+
+func newMockStore(tasks ...*Task) *mockStore {
+	m := &mockStore{TaskStore: newTestStore()}
+	for _, task := range tasks {
+		m.tasks[task.ID] = task
+		if task.ID >= m.nextID {
+			m.nextID = task.ID + 1
+		}
+	}
+	return m
+}
+
+func (m *mockStore) DeleteTaskContext(ctx context.Context, id int) (bool, error) {
+	found, err := m.TaskStore.DeleteTaskContext(ctx, id)
+	if found {
+		m.deleted = append(m.deleted, id)
+	}
+	return found, err
+}
+
+func TestServerUsesInjectedStore(t *testing.T) {
+	m := newMockStore(&Task{ID: 7, Title: "Mocked", Status: "todo"})
+	srv := NewServer(m)
+
+	rec := httptest.NewRecorder()
+	srv.tasksAPIHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil))
+	var tasks []*Task
+	if err := json.NewDecoder(rec.Body).Decode(&tasks); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if !equalIDs(taskIDs(tasks), []int{7}) {
+		t.Errorf("Expected the mock's task 7, got %v", taskIDs(tasks))
+	}
+
+	rec = httptest.NewRecorder()
+	srv.columnHandler(rec, httptest.NewRequest(http.MethodGet, "/column/todo", nil))
+	if !strings.Contains(rec.Body.String(), "Mocked") {
+		t.Errorf("Expected the column to render the mock's task")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/delete-task", strings.NewReader("id=7"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	srv.deleteTaskHandler(rec, req)
+	if rec.Code != http.StatusOK || len(m.deleted) != 1 || m.deleted[0] != 7 {
+		t.Errorf("Expected task 7 deleted through the mock, got %d, deleted %v", rec.Code, m.deleted)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/delete-task", strings.NewReader("id=7"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	srv.deleteTaskHandler(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an already deleted task, got %d", rec.Code)
+	}
+}
+
+func TestBoardHandlersUseInjectedStore(t *testing.T) {
+	withTestGlobals(t)
+	m := newMockStore(&Task{ID: 7, Title: "Mocked", Status: "todo", Labels: []string{"urgent"}})
+	m.settings = NewSettingsStore(filepath.Join(t.TempDir(), "settings.json"))
+	m.CreateLabel("urgent", "#ef4444")
+	srv := NewServer(m)
+
+	rec := httptest.NewRecorder()
+	srv.columnHandler(rec, httptest.NewRequest(http.MethodGet, "/column/todo", nil))
+	if !strings.Contains(rec.Body.String(), `style="background-color: #ef4444; color: #ffffff">🏷️ urgent`) {
+		t.Errorf("Expected the label colored from the mock's labels")
+	}
+
+	rec = httptest.NewRecorder()
+	srv.boardsRouter(rec, httptest.NewRequest(http.MethodPost, "/boards/default/tasks/7/copy-to/default", nil))
+	if rec.Code != http.StatusCreated || len(m.GetAllTasks()) != 2 {
+		t.Errorf("Expected task 7 copied within the mock, got %d, %d tasks", rec.Code, len(m.GetAllTasks()))
+	}
+}
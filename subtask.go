@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ConvertToSubtask turns a task into a checklist item on parentID, titled
+// after it and remembering its ID, and archives the task since subtasks
+// aren't on the board. It returns the updated parent, ErrTaskNotFound if
+// either task is missing or the task is already archived,
+// ErrCircularSubtask if parentID is the task or one of its subtasks, at
+// any depth, and an ErrValidation if the parent is archived.
+func (s *TaskStore) ConvertToSubtask(id, parentID int) (*Task, error) {
+	span := s.startSpan(context.Background(), "ConvertToSubtask", attribute.Int("task.id", id))
+	defer span.End()
+
+	s.mu.Lock()
+
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	task, ok := s.tasks[id]
+	if !ok || task.ArchivedAt != nil {
+		s.mu.Unlock()
+		return nil, ErrTaskNotFound
+	}
+	parent, ok := s.tasks[parentID]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrTaskNotFound
+	}
+	if s.isSubtaskOf(parentID, id) {
+		s.mu.Unlock()
+		return nil, ErrCircularSubtask
+	}
+	if parent.ArchivedAt != nil {
+		s.mu.Unlock()
+		return nil, fieldError("parent_id", "task %d is archived", parentID)
+	}
+
+	before := parent.clone()
+	parent.Checklist = append(parent.Checklist, ChecklistItem{
+		Index:  len(parent.Checklist),
+		Text:   truncateText(task.Title, maxChecklistItemLength),
+		TaskID: task.ID,
+	})
+	parent.UpdatedAt = s.clock()
+	archived := s.archive(task)
+	s.persist(parent.Status, task.Status)
+	updated := parent.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventTaskUpdated, Task: updated, Changes: taskFieldChanges(before, updated)})
+	s.publish(archived)
+	return updated, nil
+}
+
+// isSubtaskOf reports whether id is ancestor itself or was converted into a
+// subtask of it, directly or through other subtasks (must be called with
+// lock held)
+func (s *TaskStore) isSubtaskOf(id, ancestor int) bool {
+	seen := map[int]bool{}
+	pending := []int{ancestor}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		if current == id {
+			return true
+		}
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+		if task, ok := s.tasks[current]; ok {
+			for _, item := range task.Checklist {
+				if item.TaskID != 0 {
+					pending = append(pending, item.TaskID)
+				}
+			}
+		}
+	}
+	return false
+}
+
+// taskConvertToSubtaskHandler serves POST
+// /api/v1/tasks/{id}/convert-to-subtask with {"parent_id": 5}, returning
+// the parent with its new subtask. It is part of the subtasks feature.
+func (srv *Server) taskConvertToSubtaskHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if !featureEnabled(r.Context(), "subtasks") {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		ParentID int `json:"parent_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if body.ParentID <= 0 {
+		http.Error(w, "parent_id is required", http.StatusBadRequest)
+		return
+	}
+
+	parent, err := srv.ConvertToSubtask(id, body.ParentID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, parent)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvertToSubtask(t *testing.T) {
+	s := withTestGlobals(t)
+	parent := s.AddTask("Release 2.0", "")
+	s.AddChecklistItem(parent.ID, "Write notes")
+	child := s.AddTask("Tag the build", "")
+
+	updated, err := s.ConvertToSubtask(child.ID, parent.ID)
+	if err != nil {
+		t.Fatalf("ConvertToSubtask failed: %v", err)
+	}
+	if len(updated.Checklist) != 2 {
+		t.Fatalf("Expected the parent to gain a subtask, got %+v", updated.Checklist)
+	}
+	item := updated.Checklist[1]
+	if item.Index != 1 || item.Text != "Tag the build" || item.TaskID != child.ID || item.Checked {
+		t.Errorf("Unexpected subtask %+v", item)
+	}
+	if original, _ := s.GetTask(child.ID); original.ArchivedAt == nil {
+		t.Errorf("Expected the original task archived")
+	}
+	for _, task := range s.GetTasksByStatus("todo") {
+		if task.ID == child.ID {
+			t.Errorf("Expected the converted task off the board")
+		}
+	}
+
+	if _, err := s.ConvertToSubtask(child.ID, parent.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected an archived task not to convert again, got %v", err)
+	}
+	if _, err := s.ConvertToSubtask(parent.ID, 99); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound for a missing parent, got %v", err)
+	}
+}
+
+func TestConvertToSubtaskRejectsArchivedParent(t *testing.T) {
+	s := withTestGlobals(t)
+	parent := s.AddTask("Old release", "")
+	child := s.AddTask("Tag the build", "")
+	s.UpdateTask(parent.ID, func(task *Task) { now := time.Now(); task.ArchivedAt = &now })
+
+	var validation *ErrValidation
+	if _, err := s.ConvertToSubtask(child.ID, parent.ID); !errors.As(err, &validation) {
+		t.Fatalf("Expected an archived parent to be refused, got %v", err)
+	}
+	if task, _ := s.GetTask(child.ID); task.ArchivedAt != nil {
+		t.Errorf("Expected the refused task to stay on the board")
+	}
+	if task, _ := s.GetTask(parent.ID); len(task.Checklist) != 0 {
+		t.Errorf("Expected the archived parent to gain no subtask, got %+v", task.Checklist)
+	}
+}
+
+func TestConvertToSubtaskRejectsCycles(t *testing.T) {
+	s := withTestGlobals(t)
+	top := s.AddTask("Top", "")
+	middle := s.AddTask("Middle", "")
+	bottom := s.AddTask("Bottom", "")
+	other := s.AddTask("Other", "")
+
+	if _, err := s.ConvertToSubtask(top.ID, top.ID); !errors.Is(err, ErrCircularSubtask) {
+		t.Errorf("Expected a task not to become its own subtask, got %v", err)
+	}
+
+	// bottom under middle under top...
+	if _, err := s.ConvertToSubtask(bottom.ID, middle.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ConvertToSubtask(middle.ID, top.ID); err != nil {
+		t.Fatal(err)
+	}
+	// ...so top can't go under either of them
+	for _, parentID := range []int{middle.ID, bottom.ID} {
+		if _, err := s.ConvertToSubtask(top.ID, parentID); !errors.Is(err, ErrCircularSubtask) {
+			t.Errorf("Expected converting top under task %d to be circular, got %v", parentID, err)
+		}
+	}
+	if task, _ := s.GetTask(top.ID); task.ArchivedAt != nil {
+		t.Errorf("Expected a rejected conversion to leave the task on the board")
+	}
+
+	if _, err := s.ConvertToSubtask(top.ID, other.ID); err != nil {
+		t.Errorf("Expected top to convert under an unrelated task, got %v", err)
+	}
+}
+
+func TestConvertToSubtaskHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	withTestFeatures(t, FeatureFlags{Subtasks: true})
+	s.AddTask("Parent", "")
+	s.AddTask("Child", "")
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		NewServer(s).taskAPIHandler(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := post("/api/v1/tasks/2/convert-to-subtask", `{"parent_id": 1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d %q", w.Code, w.Body.String())
+	}
+	var parent Task
+	if err := json.Unmarshal(w.Body.Bytes(), &parent); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if parent.ID != 1 || len(parent.Checklist) != 1 || parent.Checklist[0].TaskID != 2 {
+		t.Errorf("Expected the parent with its new subtask, got %+v", parent)
+	}
+
+	for _, tc := range []struct {
+		path, body string
+		code       int
+	}{
+		{"/api/v1/tasks/1/convert-to-subtask", `{"parent_id": 2}`, http.StatusUnprocessableEntity},
+		{"/api/v1/tasks/1/convert-to-subtask", `{"parent_id": 9}`, http.StatusNotFound},
+		{"/api/v1/tasks/1/convert-to-subtask", `{}`, http.StatusBadRequest},
+		{"/api/v1/tasks/1/convert-to-subtask", `nope`, http.StatusBadRequest},
+	} {
+		if w := post(tc.path, tc.body); w.Code != tc.code {
+			t.Errorf("%s %s: expected %d, got %d %q", tc.path, tc.body, tc.code, w.Code, w.Body.String())
+		}
+	}
+
+	withTestFeatures(t, FeatureFlags{})
+	if w := post("/api/v1/tasks/1/convert-to-subtask", `{"parent_id": 2}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 with subtasks off, got %d", w.Code)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+)
+
+// unassignedLane is the swim lane key for tasks without an assignee
+const unassignedLane = "unassigned"
+
+// GetSwimLanes groups tasks by assignee, then by status
+func (s *TaskStore) GetSwimLanes() map[string]map[string][]*Task {
+	span := s.startSpan(context.Background(), "GetSwimLanes")
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lanes := make(map[string]map[string][]*Task)
+	for _, task := range s.tasks {
+		if task.ArchivedAt != nil {
+			continue
+		}
+		assignee := task.Assignee
+		if assignee == "" {
+			assignee = unassignedLane
+		}
+		lane, ok := lanes[assignee]
+		if !ok {
+			lane = make(map[string][]*Task)
+			for _, col := range s.columnList() {
+				lane[col.Status] = []*Task{}
+			}
+			lanes[assignee] = lane
+		}
+		lane[task.Status] = append(lane[task.Status], task)
+	}
+
+	for _, lane := range lanes {
+		for _, tasks := range lane {
+			sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+		}
+	}
+	return lanes
+}
+
+// AssignTask sets the assignee of a task
+func (s *TaskStore) AssignTask(id int, assignee string) (*Task, error) {
+	return s.UpdateTask(id, func(task *Task) {
+		task.Assignee = assignee
+	})
+}
+
+// swimLanesHandler returns tasks grouped by assignee and status as JSON
+func (srv *Server) swimLanesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, srv.GetSwimLanes())
+}
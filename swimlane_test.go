@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetSwimLanes(t *testing.T) {
+	store := newTestStore()
+	store.AddTask("Alice todo", "")
+	store.AddTask("Alice doing", "")
+	store.AddTask("Bob done", "")
+	store.AddTask("Nobody", "")
+	store.AssignTask(1, "alice")
+	store.AssignTask(2, "alice")
+	store.AssignTask(3, "bob")
+	store.MoveTask(2, "doing")
+	store.MoveTask(3, "done")
+
+	lanes := store.GetSwimLanes()
+	if len(lanes) != 3 {
+		t.Fatalf("Expected 3 lanes, got %d", len(lanes))
+	}
+	alice := lanes["alice"]
+	if len(alice["todo"]) != 1 || alice["todo"][0].ID != 1 {
+		t.Errorf("Expected alice to have task 1 in todo")
+	}
+	if len(alice["doing"]) != 1 || alice["doing"][0].ID != 2 {
+		t.Errorf("Expected alice to have task 2 in doing")
+	}
+	if len(alice["done"]) != 0 {
+		t.Errorf("Expected alice to have nothing done")
+	}
+	if len(lanes["bob"]["done"]) != 1 || lanes["bob"]["done"][0].ID != 3 {
+		t.Errorf("Expected bob to have task 3 in done")
+	}
+	if len(lanes[unassignedLane]["todo"]) != 1 || lanes[unassignedLane]["todo"][0].ID != 4 {
+		t.Errorf("Expected unassigned task 4 in todo")
+	}
+	if _, ok := lanes[""]; ok {
+		t.Errorf("Empty assignee should be mapped to %q", unassignedLane)
+	}
+}
+
+func TestIndexSwimLaneView(t *testing.T) {
+	origStore := store
+	defer func() { store = origStore }()
+	store = newTestStore()
+	store.AddTask("Lane task", "")
+	store.AssignTask(1, "carol")
+
+	rec := httptest.NewRecorder()
+	NewServer(store).indexHandler(rec, httptest.NewRequest(http.MethodGet, "/?view=swimlane", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, `class="swimlane"`) || !strings.Contains(body, "carol") {
+		t.Errorf("Expected swimlane view with carol's lane")
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SyncTask is a task as sent to sync clients. Deleted tasks are sent once
+// more, as they were when deleted, so clients know to drop them.
+type SyncTask struct {
+	*Task
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// MarshalJSON adds "deleted" to the task's own JSON, since Task's
+// MarshalJSON would otherwise be promoted and leave it out
+func (t SyncTask) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(t.Task)
+	if err != nil || !t.Deleted {
+		return data, err
+	}
+	return append(data[:len(data)-1], `,"deleted":true}`...), nil
+}
+
+// recordDeletion keeps a deleted task's last state, stamped with when it
+// was deleted, for TasksSince (must be called with lock held). They aren't
+// saved, so a client that last synced before a restart misses deletions
+// from before it.
+func (s *TaskStore) recordDeletion(task *Task) {
+	if s.deletedTasks == nil {
+		s.deletedTasks = make(map[int]*Task)
+	}
+	deleted := task.clone()
+	deleted.UpdatedAt = s.clock()
+	s.deletedTasks[task.ID] = deleted
+}
+
+// TasksSince returns the tasks updated or deleted at or after since, oldest
+// change first
+func (s *TaskStore) TasksSince(since time.Time) []SyncTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks := []SyncTask{}
+	for _, task := range s.tasks {
+		if !task.UpdatedAt.Before(since) {
+			tasks = append(tasks, SyncTask{Task: task.clone()})
+		}
+	}
+	for id, task := range s.deletedTasks {
+		// A later task can't reuse the ID, but a restore can bring it back
+		if _, ok := s.tasks[id]; !ok && !task.UpdatedAt.Before(since) {
+			tasks = append(tasks, SyncTask{Task: task.clone(), Deleted: true})
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].UpdatedAt.Equal(tasks[j].UpdatedAt) {
+			return tasks[i].UpdatedAt.Before(tasks[j].UpdatedAt)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks
+}
+
+// tasksSinceHandler serves GET /api/v1/tasks/since?t=, where t is a Unix
+// timestamp, with {"server_time", "tasks"}. The server time, also sent as
+// X-Server-Time, is the t to pass on the next sync.
+func (srv *Server) tasksSinceHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	since, err := strconv.ParseInt(r.URL.Query().Get("t"), 10, 64)
+	if err != nil || since < 0 {
+		http.Error(w, "t must be a Unix timestamp", http.StatusBadRequest)
+		return
+	}
+
+	// Read the clock first so nothing changed during the scan is missed
+	// next time
+	now := srv.Now().Unix()
+	tasks := srv.TasksSince(time.Unix(since, 0))
+	w.Header().Set("X-Server-Time", strconv.FormatInt(now, 10))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"server_time": now,
+		"tasks":       tasks,
+	})
+}
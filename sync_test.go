@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTasksSince(t *testing.T) {
+	s := withTestGlobals(t)
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	current := start
+	s.now = func() time.Time { return current }
+
+	s.CreateTask(TaskSpec{Title: "Unchanged"})
+	s.CreateTask(TaskSpec{Title: "Edited"})
+	s.CreateTask(TaskSpec{Title: "Moved"})
+	s.CreateTask(TaskSpec{Title: "Deleted"})
+
+	current = start.Add(time.Hour)
+	lastSync := current
+	s.UpdateTask(2, func(task *Task) { task.Description = "More detail" })
+	current = current.Add(time.Minute)
+	s.MoveTask(3, "doing")
+	s.DeleteTask(4)
+
+	tasks := s.TasksSince(lastSync)
+	if len(tasks) != 3 {
+		t.Fatalf("Expected 3 changed tasks, got %+v", tasks)
+	}
+	if tasks[0].ID != 2 || tasks[0].Deleted || tasks[0].Description != "More detail" {
+		t.Errorf("Expected the edited task first, got %+v", tasks[0])
+	}
+	if tasks[1].ID != 3 || tasks[1].Status != "doing" {
+		t.Errorf("Expected the moved task, got %+v", tasks[1])
+	}
+	if tasks[2].ID != 4 || !tasks[2].Deleted || tasks[2].Title != "Deleted" || !tasks[2].UpdatedAt.Equal(current) {
+		t.Errorf("Expected the deleted task flagged as deleted, got %+v", tasks[2])
+	}
+
+	if got := s.TasksSince(current.Add(time.Second)); len(got) != 0 {
+		t.Errorf("Expected nothing after the last change, got %+v", got)
+	}
+	if got := s.TasksSince(time.Time{}); len(got) != 4 {
+		t.Errorf("Expected every task and the deletion from the start, got %d", len(got))
+	}
+}
+
+func TestTasksSinceHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	current := start
+	s.now = func() time.Time { return current }
+
+	s.CreateTask(TaskSpec{Title: "Old"})
+	s.CreateTask(TaskSpec{Title: "Renamed later"})
+	current = start.Add(time.Hour)
+	s.UpdateTask(2, func(task *Task) { task.Title = "Renamed" })
+	s.DeleteTask(1)
+	current = current.Add(time.Minute)
+
+	since := start.Add(30 * time.Minute).Unix()
+	w := httptest.NewRecorder()
+	NewServer(s).tasksSinceHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/tasks/since?t="+strconv.FormatInt(since, 10), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("X-Server-Time"); got != strconv.FormatInt(current.Unix(), 10) {
+		t.Errorf("Expected X-Server-Time %d, got %q", current.Unix(), got)
+	}
+	var resp struct {
+		ServerTime int64 `json:"server_time"`
+		Tasks      []struct {
+			ID      int    `json:"id"`
+			Title   string `json:"title"`
+			Deleted bool   `json:"deleted"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", w.Body.String(), err)
+	}
+	if resp.ServerTime != current.Unix() {
+		t.Errorf("Expected server_time %d, got %d", current.Unix(), resp.ServerTime)
+	}
+	if len(resp.Tasks) != 2 || resp.Tasks[0].ID != 1 || !resp.Tasks[0].Deleted ||
+		resp.Tasks[1].ID != 2 || resp.Tasks[1].Title != "Renamed" || resp.Tasks[1].Deleted {
+		t.Errorf("Expected the deletion and the rename, got %+v", resp.Tasks)
+	}
+
+	for _, query := range []string{"", "?t=yesterday", "?t=-5"} {
+		w := httptest.NewRecorder()
+		NewServer(s).tasksSinceHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/tasks/since"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", query, w.Code)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// taskRouter dispatches /task/{id}/... requests to the matching handler
+func (srv *Server) taskRouter(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/task/"), "/"), "/")
+	id, err := ParseTaskID(parts[0], taskIDPrefix)
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	action := ""
+	if len(parts) > 1 {
+		action = parts[1]
+	}
+	switch action {
+	case "":
+		srv.taskDetailHandler(w, r, id)
+	case "history":
+		srv.taskHistoryHandler(w, r, id)
+	case "events":
+		srv.taskEventsHandler(w, r, id)
+	case "duplicate":
+		srv.taskDuplicateHandler(w, r, id)
+	case "split":
+		srv.taskSplitHandler(w, r, id)
+	case "approve":
+		srv.taskApproveHandler(w, r, id)
+	case "move-next", "move-prev":
+		srv.taskMoveStepHandler(w, r, id, action == "move-next")
+	case "collapse", "expand":
+		srv.taskCollapseHandler(w, r, id, action == "collapse")
+	case "checklist":
+		srv.taskChecklistHandler(w, r, id, parts[2:])
+	case "comments":
+		if !featureEnabled(r.Context(), "comments") {
+			http.NotFound(w, r)
+			return
+		}
+		srv.taskCommentsHandler(w, r, id, parts[2:])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// TaskDetail fills task-detail.html
+type TaskDetail struct {
+	Task    *Task
+	Column  string // display name of the task's column
+	Related []RelatedTask
+	Labels  []Label // registered labels, for coloring the task's labels
+}
+
+// taskDetailHandler serves a task's detail page, GET /task/{id}
+func (srv *Server) taskDetailHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	task, ok := srv.GetTask(id)
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	detail := TaskDetail{Task: task, Column: task.Status, Related: srv.GetRelated(id), Labels: srv.ListLabels()}
+	for _, col := range srv.Columns() {
+		if col.Status == task.Status {
+			detail.Column = col.DisplayName
+		}
+	}
+	renderPartial(w, r, "task-detail.html", detail)
+}
+
+// taskHistoryHandler returns a task's audit trail, newest first, as an HTML
+// partial or as JSON when requested via ?format=json or the Accept header
+func (srv *Server) taskHistoryHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	history := auditLog.GetTaskHistory(id)
+	if _, ok := srv.GetTask(id); !ok && len(history) == 0 {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, history)
+		return
+	}
+	templates.ExecuteTemplate(w, "task-history.html", history)
+}
+
+// wantsJSON reports whether the client asked for a JSON response
+func wantsJSON(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "json" ||
+		strings.Contains(r.Header.Get("Accept"), "application/json")
+}
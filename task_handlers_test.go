@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetTaskHistory(t *testing.T) {
+	store := withTestGlobals(t)
+	store.AddTask("Other", "")
+	task := store.AddTask("Tracked", "")
+	store.MoveTask(task.ID, "doing")
+	store.MoveTask(task.ID, "done")
+	store.UpdateTask(task.ID, func(t *Task) { t.Title = "Tracked and renamed" })
+
+	history := auditLog.GetTaskHistory(task.ID)
+	want := []string{EventTaskUpdated, EventTaskMoved, EventTaskMoved, EventTaskCreated}
+	if len(history) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(history))
+	}
+	for i, e := range history {
+		if e.Type != want[i] || e.TaskID != task.ID {
+			t.Errorf("Event %d: expected %s for task %d, got %s for task %d", i, want[i], task.ID, e.Type, e.TaskID)
+		}
+	}
+	if history[1].ToStatus != "done" || history[2].ToStatus != "doing" {
+		t.Errorf("Moves are not newest-first: %+v", history[1:3])
+	}
+	change := history[0].Changes
+	if len(change) != 1 || change[0].Old != "Tracked" || change[0].New != "Tracked and renamed" {
+		t.Errorf("Expected title change in update event, got %+v", change)
+	}
+}
+
+func TestTaskHistoryHandler(t *testing.T) {
+	store := withTestGlobals(t)
+	store.AddTask("Tracked", "")
+	store.MoveTask(1, "doing")
+	store.UpdateTask(1, func(t *Task) { t.Title = "Renamed" })
+
+	rec := httptest.NewRecorder()
+	NewServer(store).taskRouter(rec, httptest.NewRequest(http.MethodGet, "/task/1/history?format=json", nil))
+	var history []BoardEvent
+	if err := json.NewDecoder(rec.Body).Decode(&history); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(history) != 3 || history[0].Type != EventTaskUpdated {
+		t.Errorf("Expected 3 events newest-first, got %+v", history)
+	}
+
+	rec = httptest.NewRecorder()
+	NewServer(store).taskRouter(rec, httptest.NewRequest(http.MethodGet, "/task/1/history", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "Tracked → Renamed") || !strings.Contains(body, "todo → doing") {
+		t.Errorf("Expected timeline with diff summaries, got %q", body)
+	}
+
+	rec = httptest.NewRecorder()
+	NewServer(store).taskRouter(rec, httptest.NewRequest(http.MethodGet, "/task/99/history", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown task, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultSSEHeartbeatInterval = 30 * time.Second
+
+// loadSSEHeartbeatInterval reads KANBAN_SSE_HEARTBEAT_INTERVAL, falling back
+// to the default
+func loadSSEHeartbeatInterval() time.Duration {
+	if raw := os.Getenv("KANBAN_SSE_HEARTBEAT_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultSSEHeartbeatInterval
+}
+
+// sseHeartbeatInterval is how often an idle event stream gets a comment
+// line, so proxies with idle timeouts don't drop it
+var sseHeartbeatInterval = loadSSEHeartbeatInterval()
+
+// TaskEvent is a change to one task, sent to its subscribers
+type TaskEvent struct {
+	Type string `json:"type"`
+	Task *Task  `json:"task"` // copy after the change (before it, for deletes)
+}
+
+// TaskSubscriptions holds the channels subscribed to each task. The zero
+// value has no subscribers.
+type TaskSubscriptions struct {
+	mu     sync.Mutex
+	byTask map[int]map[chan TaskEvent]struct{}
+}
+
+// SubscribeToTask sends ch every change to a task until it unsubscribes.
+// Sends never block: a change is dropped for a subscriber whose channel is
+// full, so give ch a buffer.
+func (s *TaskStore) SubscribeToTask(taskID int, ch chan TaskEvent) {
+	ts := &s.taskSubscribers
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.byTask == nil {
+		ts.byTask = make(map[int]map[chan TaskEvent]struct{})
+	}
+	if ts.byTask[taskID] == nil {
+		ts.byTask[taskID] = make(map[chan TaskEvent]struct{})
+	}
+	ts.byTask[taskID][ch] = struct{}{}
+}
+
+// UnsubscribeFromTask stops sending a task's changes to ch
+func (s *TaskStore) UnsubscribeFromTask(taskID int, ch chan TaskEvent) {
+	ts := &s.taskSubscribers
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	delete(ts.byTask[taskID], ch)
+	if len(ts.byTask[taskID]) == 0 {
+		delete(ts.byTask, taskID)
+	}
+}
+
+// notifyTaskSubscribers passes an event to its task's subscribers.
+// TaskAssigned, TaskWatched and CommentMention events are skipped since
+// subscribers already get the change they follow.
+func (s *TaskStore) notifyTaskSubscribers(e Event) {
+	if e.Task == nil || e.Type == EventTaskAssigned || e.Type == EventTaskWatched || e.Type == EventCommentMention {
+		return
+	}
+	ts := &s.taskSubscribers
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	for ch := range ts.byTask[e.Task.ID] {
+		select {
+		case ch <- TaskEvent{Type: e.Type, Task: e.Task}:
+		default:
+		}
+	}
+}
+
+// taskEventsHandler serves GET /task/{id}/events, an SSE stream sending
+// "event: task-updated" with the task as JSON whenever it changes, and a
+// final "event: task-deleted" if it is deleted. A ": heartbeat" comment,
+// which EventSource ignores, is sent every sseHeartbeatInterval.
+func (srv *Server) taskEventsHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan TaskEvent, 8)
+	srv.SubscribeToTask(id, ch)
+	defer srv.UnsubscribeFromTask(id, ch)
+	// Subscribe first so a change between the check and the stream opening
+	// isn't missed
+	if _, ok := srv.GetTask(id); !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case e := <-ch:
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			name := "task-updated"
+			if e.Type == EventTaskDeleted {
+				name = "task-deleted"
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+			flusher.Flush()
+			if e.Type == EventTaskDeleted {
+				return
+			}
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubscribeToTask(t *testing.T) {
+	s := newTestStore()
+	s.AddTask("Watched", "")
+	s.AddTask("Other", "")
+
+	ch := make(chan TaskEvent, 4)
+	s.SubscribeToTask(1, ch)
+	s.MoveTask(2, "doing")
+	s.MoveTask(1, "doing")
+	s.AssignTask(1, "dana")
+	if len(ch) != 2 {
+		t.Fatalf("Expected the move and update of task 1 only, got %d events", len(ch))
+	}
+	if e := <-ch; e.Type != EventTaskMoved || e.Task.Status != "doing" {
+		t.Errorf("Unexpected first event %+v", e)
+	}
+	<-ch
+
+	s.UnsubscribeFromTask(1, ch)
+	s.MoveTask(1, "done")
+	if len(ch) != 0 {
+		t.Errorf("Expected no events after unsubscribing")
+	}
+}
+
+func TestTaskEventsHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Live", "")
+
+	srv := httptest.NewServer(http.HandlerFunc(NewServer(s).taskRouter))
+	defer srv.Close()
+	if resp, err := http.Get(srv.URL + "/task/9/events"); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown task, got %v %v", resp, err)
+	}
+
+	resp, err := http.Get(srv.URL + "/task/1/events")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected an event stream, got %q", ct)
+	}
+
+	events := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			events <- scanner.Text()
+		}
+	}()
+
+	// The handler subscribes before returning headers, so the move can't
+	// race ahead of the subscription
+	s.MoveTask(1, "doing")
+	select {
+	case line := <-events:
+		if line != "event: task-updated" {
+			t.Fatalf("Expected a task-updated event, got %q", line)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("No event within 1s of moving the task")
+	}
+	data := strings.TrimPrefix(<-events, "data: ")
+	var e TaskEvent
+	if err := json.Unmarshal([]byte(data), &e); err != nil || e.Type != EventTaskMoved || e.Task.Status != "doing" {
+		t.Errorf("Unexpected event data %q: %v", data, err)
+	}
+}
+
+// streamWriter is a ResponseWriter safe to read while a handler streams to it
+type streamWriter struct {
+	mu     sync.Mutex
+	header http.Header
+	body   strings.Builder
+}
+
+func (w *streamWriter) Header() http.Header { return w.header }
+func (w *streamWriter) WriteHeader(int)     {}
+func (w *streamWriter) Flush()              {}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.body.Write(p)
+}
+
+func (w *streamWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.body.String()
+}
+
+func TestTaskEventsHeartbeat(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Idle", "")
+	orig := sseHeartbeatInterval
+	defer func() { sseHeartbeatInterval = orig }()
+	sseHeartbeatInterval = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &streamWriter{header: make(http.Header)}
+	done := make(chan struct{})
+	go func() {
+		NewServer(s).taskEventsHandler(w, httptest.NewRequest(http.MethodGet, "/task/1/events", nil).WithContext(ctx), 1)
+		close(done)
+	}()
+
+	time.Sleep(110 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Handler did not return after the request was cancelled")
+	}
+	if n := strings.Count(w.String(), ": heartbeat\n\n"); n < 3 || n > 6 {
+		t.Errorf("Expected about 5 heartbeats in 110ms at 20ms, got %d", n)
+	}
+	if strings.Contains(w.String(), "event:") {
+		t.Errorf("Expected only heartbeats on an idle stream, got %q", w.String())
+	}
+}
+
+func TestLoadSSEHeartbeatInterval(t *testing.T) {
+	t.Setenv("KANBAN_SSE_HEARTBEAT_INTERVAL", "")
+	if d := loadSSEHeartbeatInterval(); d != defaultSSEHeartbeatInterval {
+		t.Errorf("Expected the default, got %v", d)
+	}
+	t.Setenv("KANBAN_SSE_HEARTBEAT_INTERVAL", "15s")
+	if d := loadSSEHeartbeatInterval(); d != 15*time.Second {
+		t.Errorf("Expected 15s, got %v", d)
+	}
+	t.Setenv("KANBAN_SSE_HEARTBEAT_INTERVAL", "soon")
+	if d := loadSSEHeartbeatInterval(); d != defaultSSEHeartbeatInterval {
+		t.Errorf("Expected an invalid value to fall back to the default, got %v", d)
+	}
+}
+
+func TestTaskEventsHandlerBehindMiddleware(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Live", "")
+
+	srv := httptest.NewServer(withMiddleware(http.HandlerFunc(NewServer(s).taskRouter), 0))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/task/1/events")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream through the middleware, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	lines := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	s.MoveTask(1, "doing")
+	select {
+	case line := <-lines:
+		if line != "event: task-updated" {
+			t.Errorf("Expected a task-updated event, got %q", line)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("No event flushed through the middleware within 1s")
+	}
+}
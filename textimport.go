@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxTextImportBytes caps the body accepted by /import/text
+const maxTextImportBytes = 1 << 20
+
+// ParseTextImport turns pasted text into task specs, one per line. Leading
+// bullets ("-", "*", "•") are dropped, "!" marks a task high priority, and a
+// "[doing]" or "[done]" prefix sets its status. Blank lines and "---"
+// separators are skipped.
+func ParseTextImport(text string) []TaskSpec {
+	var specs []TaskSpec
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Trim(line, "-") == "" {
+			continue
+		}
+		for _, bullet := range []string{"- ", "* ", "• "} {
+			line = strings.TrimSpace(strings.TrimPrefix(line, bullet))
+		}
+
+		spec := TaskSpec{Status: "todo"}
+		for {
+			if rest, ok := strings.CutPrefix(line, "!"); ok {
+				spec.Priority = PriorityHigh
+				line = strings.TrimSpace(rest)
+				continue
+			}
+			if status, rest, ok := cutStatusPrefix(line); ok {
+				spec.Status = status
+				line = rest
+				continue
+			}
+			break
+		}
+		if line == "" {
+			continue
+		}
+		spec.Title = line
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// cutStatusPrefix strips a leading "[status]" naming a board column
+func cutStatusPrefix(line string) (status, rest string, ok bool) {
+	if !strings.HasPrefix(line, "[") {
+		return "", line, false
+	}
+	end := strings.Index(line, "]")
+	if end < 0 {
+		return "", line, false
+	}
+	status = strings.ToLower(line[1:end])
+	if !isValidStatus(status) {
+		return "", line, false
+	}
+	return status, strings.TrimSpace(line[end+1:]), true
+}
+
+// readImportText reads the text of an import from a text/plain body or a
+// form-encoded "text" field, writing an error if it can't
+func readImportText(w http.ResponseWriter, r *http.Request) (string, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxTextImportBytes)
+	switch contentType := r.Header.Get("Content-Type"); {
+	case strings.HasPrefix(contentType, "text/plain"):
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return "", false
+		}
+		return string(body), true
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		return r.FormValue("text"), true
+	default:
+		http.Error(w, "Content-Type must be text/plain", http.StatusUnsupportedMediaType)
+		return "", false
+	}
+}
+
+// importTextHandler creates a task per line of a text/plain body and
+// re-renders the board. The board's paste form posts the same text as a
+// form-encoded "text" field.
+func (srv *Server) importTextHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	text, ok := readImportText(w, r)
+	if !ok {
+		return
+	}
+	specs := ParseTextImport(text)
+	if len(specs) > maxBulkSize {
+		http.Error(w, fmt.Sprintf("Import of %d tasks exceeds the maximum of %d", len(specs), maxBulkSize),
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+	if _, err := srv.CreateTasks(specs); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	templates.ExecuteTemplate(w, "all-columns.html", srv.GetBoardData())
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseTextImport(t *testing.T) {
+	specs := ParseTextImport(`Write docs
+! Fix login bug
+
+---
+[doing] Review PR
+[done] !Release 1.0
+- Bulleted item
+* [DOING] Starred item
+   
+!
+[archived] Unknown status stays in title`)
+
+	want := []TaskSpec{
+		{Title: "Write docs", Status: "todo"},
+		{Title: "Fix login bug", Status: "todo", Priority: PriorityHigh},
+		{Title: "Review PR", Status: "doing"},
+		{Title: "Release 1.0", Status: "done", Priority: PriorityHigh},
+		{Title: "Bulleted item", Status: "todo"},
+		{Title: "Starred item", Status: "doing"},
+		{Title: "[archived] Unknown status stays in title", Status: "todo"},
+	}
+	if len(specs) != len(want) {
+		t.Fatalf("Expected %d specs, got %d: %+v", len(want), len(specs), specs)
+	}
+	for i, spec := range specs {
+		if spec.Title != want[i].Title || spec.Status != want[i].Status || spec.Priority != want[i].Priority {
+			t.Errorf("Line %d: expected %+v, got %+v", i, want[i], spec)
+		}
+	}
+}
+
+func TestParseTextImportEmpty(t *testing.T) {
+	if specs := ParseTextImport("\n\n---\n  \n"); len(specs) != 0 {
+		t.Errorf("Expected no specs, got %+v", specs)
+	}
+}
+
+func TestImportTextHandler(t *testing.T) {
+	s := withTestGlobals(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/import/text", strings.NewReader("First\n[doing] Second\n"))
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	w := httptest.NewRecorder()
+	NewServer(s).importTextHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `id="doing-tasks"`) {
+		t.Errorf("Expected the all-columns partial in the response")
+	}
+	if len(s.GetTasksByStatus("todo")) != 1 || len(s.GetTasksByStatus("doing")) != 1 {
+		t.Errorf("Expected one task in todo and one in doing")
+	}
+
+	form := url.Values{"text": {"From the form"}}
+	req = httptest.NewRequest(http.MethodPost, "/import/text", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	NewServer(s).importTextHandler(w, req)
+	if len(s.GetAllTasks()) != 3 {
+		t.Errorf("Expected form import to add a third task")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/import/text", strings.NewReader(`{"title":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	NewServer(s).importTextHandler(w, req)
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected 415 for JSON body, got %d", w.Code)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import "net/http"
+
+// themeCookie names the session cookie holding the chosen theme
+const themeCookie = "kanban_theme"
+
+// defaultTheme is used without a theme cookie or with an unknown one
+const defaultTheme = "light"
+
+// themes lists the stylesheets in static/themes/, in the order offered.
+// layout.html links the viewer's after style.css.
+var themes = []string{"light", "dark", "solarized", "high-contrast"}
+
+// isValidTheme reports whether name is one of the themes
+func isValidTheme(name string) bool {
+	return containsString(themes, name)
+}
+
+// requestTheme returns the theme chosen by the request's cookie
+func requestTheme(r *http.Request) string {
+	if cookie, err := r.Cookie(themeCookie); err == nil && isValidTheme(cookie.Value) {
+		return cookie.Value
+	}
+	return defaultTheme
+}
+
+// ThemesPage fills themes.html
+type ThemesPage struct {
+	Themes  []string
+	Current string
+}
+
+// themeHandler serves /settings/theme: GET lists the themes and POST stores
+// the "theme" form value in a session cookie and returns to the board
+func themeHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		renderPartial(w, r, "themes.html", ThemesPage{Themes: themes, Current: requestTheme(r)})
+	case http.MethodPost:
+		theme := r.FormValue("theme")
+		if !isValidTheme(theme) {
+			http.Error(w, "Unknown theme", http.StatusBadRequest)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     themeCookie,
+			Value:    theme,
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestThemeHandlerSetsCookie(t *testing.T) {
+	withTestGlobals(t)
+
+	form := url.Values{"theme": {"dark"}}
+	req := httptest.NewRequest(http.MethodPost, "/settings/theme", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	themeHandler(w, req)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/" {
+		t.Fatalf("Expected a redirect to the board, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != themeCookie || cookies[0].Value != "dark" {
+		t.Fatalf("Expected a dark theme cookie, got %v", cookies)
+	}
+	if !cookies[0].Expires.IsZero() || cookies[0].MaxAge != 0 {
+		t.Errorf("Expected a session cookie, got %+v", cookies[0])
+	}
+
+	form = url.Values{"theme": {"neon"}}
+	req = httptest.NewRequest(http.MethodPost, "/settings/theme", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	themeHandler(w, req)
+	if w.Code != http.StatusBadRequest || len(w.Result().Cookies()) != 0 {
+		t.Errorf("Expected 400 and no cookie for an unknown theme, got %d", w.Code)
+	}
+}
+
+func TestThemesPage(t *testing.T) {
+	withTestGlobals(t)
+	req := httptest.NewRequest(http.MethodGet, "/settings/theme", nil)
+	req.AddCookie(&http.Cookie{Name: themeCookie, Value: "solarized"})
+	w := httptest.NewRecorder()
+	themeHandler(w, req)
+	body := w.Body.String()
+	for _, theme := range themes {
+		if !strings.Contains(body, `value="`+theme+`"`) {
+			t.Errorf("Expected %s to be offered", theme)
+		}
+	}
+	if !strings.Contains(body, `value="solarized" checked`) {
+		t.Errorf("Expected the current theme to be selected")
+	}
+}
+
+func TestIndexLinksThemeStylesheet(t *testing.T) {
+	withTestGlobals(t)
+	index := func(theme string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if theme != "" {
+			req.AddCookie(&http.Cookie{Name: themeCookie, Value: theme})
+		}
+		w := httptest.NewRecorder()
+		NewServer(store).indexHandler(w, req)
+		return w.Body.String()
+	}
+
+	if body := index("high-contrast"); !strings.Contains(body, `<link rel="stylesheet" href="/static/themes/high-contrast.css">`) {
+		t.Errorf("Expected the high-contrast stylesheet")
+	}
+	for _, theme := range []string{"", "neon"} {
+		if body := index(theme); !strings.Contains(body, `href="/static/themes/light.css"`) {
+			t.Errorf("Expected %q to fall back to the light stylesheet", theme)
+		}
+	}
+}
+
+func TestThemeStylesheetsAreEmbedded(t *testing.T) {
+	for _, theme := range themes {
+		if _, err := embeddedStatic.ReadFile("static/themes/" + theme + ".css"); err != nil {
+			t.Errorf("Missing stylesheet for %s: %v", theme, err)
+		}
+	}
+}
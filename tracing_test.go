@@ -0,0 +1,115 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// withSpanRecorder routes spans to an in-memory recorder for the duration of a test
+func withSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	orig := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(orig) })
+	return recorder
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestAddTaskProducesSpan(t *testing.T) {
+	recorder := withSpanRecorder(t)
+	store := newTestStore()
+	task := store.AddTask("Traced", "")
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name() != "TaskStore.CreateTask" {
+		t.Errorf("Unexpected span name %q", spans[0].Name())
+	}
+	if id, ok := spanAttr(spans[0], "task.id"); !ok || id.AsInt64() != int64(task.ID) {
+		t.Errorf("Expected task.id attribute %d, got %v", task.ID, id)
+	}
+}
+
+func TestMoveTaskSpanAttributes(t *testing.T) {
+	store := newTestStore()
+	store.AddTask("Traced", "")
+	recorder := withSpanRecorder(t)
+	store.MoveTask(1, "doing")
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "TaskStore.MoveTask" {
+		t.Fatalf("Expected a single MoveTask span, got %d", len(spans))
+	}
+	if status, _ := spanAttr(spans[0], "task.status"); status.AsString() != "doing" {
+		t.Errorf("Expected task.status attribute doing, got %v", status)
+	}
+}
+
+func TestOtelMiddlewareRecordsRequest(t *testing.T) {
+	recorder := withSpanRecorder(t)
+	handler := otelMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Task not found", http.StatusNotFound)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/move-task", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	want := map[attribute.Key]string{"http.method": "POST", "http.route": "/move-task"}
+	for key, value := range want {
+		if got, _ := spanAttr(spans[0], key); got.AsString() != value {
+			t.Errorf("Expected %s=%s, got %v", key, value, got)
+		}
+	}
+	if code, _ := spanAttr(spans[0], "http.status_code"); code.AsInt64() != http.StatusNotFound {
+		t.Errorf("Expected http.status_code 404, got %v", code)
+	}
+}
+
+func TestStoreSpansJoinRequestTrace(t *testing.T) {
+	withTestGlobals(t)
+	recorder := withSpanRecorder(t)
+	srv := NewServer(store)
+	handler := otelMiddleware(http.HandlerFunc(srv.addTaskHandler))
+
+	req := httptest.NewRequest(http.MethodPost, "/add-task", strings.NewReader("title=Traced"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	root, ok := spans["POST /add-task"]
+	if !ok {
+		t.Fatalf("Expected a span for the request, got %v", spans)
+	}
+	for _, name := range []string{"TaskStore.CreateTask", "TaskStore.GetBoardData"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("Expected a %s span", name)
+			continue
+		}
+		if span.Parent().SpanID() != root.SpanContext().SpanID() || span.SpanContext().TraceID() != root.SpanContext().TraceID() {
+			t.Errorf("Expected %s to be a child of the request span", name)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// maxPageSize caps how much of a page is read looking for its title
+const maxPageSize = 1 << 20
+
+// maxPageTitleLength caps titles taken from pages
+const maxPageTitleLength = 200
+
+// PageTitleFetcher downloads web pages to read their <title>
+type PageTitleFetcher struct {
+	Client *http.Client
+}
+
+// FetchTitle returns the page's title, or "" if it has none
+func (f *PageTitleFetcher) FetchTitle(pageURL string) (string, error) {
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	resp, err := client.Get(pageURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned %s", pageURL, resp.Status)
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return "", fmt.Errorf("invalid HTML: %w", err)
+	}
+	return findTitle(doc), nil
+}
+
+// findTitle returns the text of the first <title> element, with whitespace
+// collapsed
+func findTitle(n *html.Node) string {
+	if n.Type == html.ElementNode && n.Data == "title" {
+		var text strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				text.WriteString(c.Data)
+			}
+		}
+		return strings.Join(strings.Fields(text.String()), " ")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		// <svg> has its own <title> elements, which aren't the page's
+		if c.Type == html.ElementNode && c.Data == "svg" {
+			continue
+		}
+		if title := findTitle(c); title != "" {
+			return title
+		}
+	}
+	return ""
+}
+
+// resolvePageURL parses raw as an absolute http(s) URL, resolving it against
+// base when it is relative
+func resolvePageURL(raw, base string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q", raw)
+	}
+	if !u.IsAbs() && base != "" {
+		b, err := url.Parse(strings.TrimSpace(base))
+		if err != nil || !b.IsAbs() {
+			return nil, fmt.Errorf("invalid base URL %q", base)
+		}
+		u = b.ResolveReference(u)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("URL %q must be an absolute http or https URL, or a path with a base", raw)
+	}
+	return u, nil
+}
+
+var pageTitleFetcher = &PageTitleFetcher{Client: &http.Client{Timeout: 5 * time.Second}}
+
+// addTaskFromURLHandler creates a task titled after the page at the url form
+// value (resolved against base if relative), keeping the URL in its
+// description, and returns the "To Do" column
+func (srv *Server) addTaskFromURLHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.FormValue("url") == "" {
+		http.Error(w, "URL is required", http.StatusBadRequest)
+		return
+	}
+	pageURL, err := resolvePageURL(r.FormValue("url"), r.FormValue("base"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	title, err := pageTitleFetcher.FetchTitle(pageURL.String())
+	if err != nil {
+		http.Error(w, "Could not fetch page: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	if title == "" {
+		title = pageURL.Host + pageURL.Path
+	}
+
+	if _, err := srv.CreateTask(TaskSpec{
+		Title:       truncateText(title, maxPageTitleLength),
+		Description: pageURL.String(),
+	}); err != nil {
+		writeError(w, err)
+		return
+	}
+	templates.ExecuteTemplate(w, "column-content.html", srv.GetColumnData("todo"))
+}
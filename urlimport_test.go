@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// postAddTaskFromURL submits the add-task-from-url form
+func postAddTaskFromURL(form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/add-task-from-url", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	NewServer(store).addTaskFromURLHandler(w, req)
+	return w
+}
+
+func newTestPageServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<!DOCTYPE html><html><head>
+			<title>
+				Fix the   login &amp; signup flow
+			</title></head>
+			<body><svg><title>icon</title></svg></body></html>`))
+	})
+	mux.HandleFunc("/docs/guide", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><svg><title>icon</title></svg><h1>No head title</h1></body></html>`))
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAddTaskFromURLExtractsTitle(t *testing.T) {
+	s := withTestGlobals(t)
+	srv := newTestPageServer(t)
+
+	w := postAddTaskFromURL(url.Values{"url": {srv.URL + "/article"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	task, ok := s.GetTask(1)
+	if !ok || task.Title != "Fix the login & signup flow" || task.Description != srv.URL+"/article" {
+		t.Errorf("Unexpected task %+v", task)
+	}
+	if !strings.Contains(w.Body.String(), "Fix the login &amp; signup flow") {
+		t.Errorf("Expected the todo column with the new task")
+	}
+}
+
+func TestAddTaskFromURLResolvesRelativeURLs(t *testing.T) {
+	s := withTestGlobals(t)
+	srv := newTestPageServer(t)
+
+	w := postAddTaskFromURL(url.Values{"url": {"guide"}, "base": {srv.URL + "/docs/"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	// The page has no <title>, so the URL stands in for it
+	task, _ := s.GetTask(1)
+	if task.Description != srv.URL+"/docs/guide" || !strings.HasSuffix(task.Title, "/docs/guide") {
+		t.Errorf("Expected the URL resolved against the base, got %+v", task)
+	}
+
+	if w := postAddTaskFromURL(url.Values{"url": {"/docs/guide"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a relative URL without a base, got %d", w.Code)
+	}
+}
+
+func TestAddTaskFromURLErrors(t *testing.T) {
+	s := withTestGlobals(t)
+	srv := newTestPageServer(t)
+
+	w := postAddTaskFromURL(url.Values{"url": {srv.URL + "/gone"}})
+	if w.Code != http.StatusBadGateway || !strings.Contains(w.Body.String(), "410") {
+		t.Errorf("Expected 502 mentioning the upstream status, got %d: %s", w.Code, w.Body.String())
+	}
+	for _, raw := range []string{"", "ftp://example.com/file", "http://"} {
+		if w := postAddTaskFromURL(url.Values{"url": {raw}}); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %q, got %d", raw, w.Code)
+		}
+	}
+	if len(s.GetAllTasks()) != 0 {
+		t.Errorf("Failed requests must not create tasks")
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultVelocityPeriods = 8
+	maxVelocityPeriods     = 104
+)
+
+// VelocityPeriod is the work finished in one window of time
+type VelocityPeriod struct {
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	TasksDone   int       `json:"tasks_done"`
+	EffortDone  int       `json:"effort_done"` // story points
+}
+
+// CalculateVelocity counts the moves into done in each of the last periods
+// windows ending at end, oldest first. A period holds the moves after its
+// start and up to and including its end. Effort isn't part of a transition,
+// so EffortDone is left to the caller.
+func CalculateVelocity(history []StatusTransition, window time.Duration, periods int, end time.Time) []VelocityPeriod {
+	result := make([]VelocityPeriod, periods)
+	for i := range result {
+		result[i].PeriodEnd = end.Add(-time.Duration(periods-1-i) * window)
+		result[i].PeriodStart = result[i].PeriodEnd.Add(-window)
+	}
+	for _, tr := range history {
+		if tr.ToStatus != "done" {
+			continue
+		}
+		for i := range result {
+			if tr.At.After(result[i].PeriodStart) && !tr.At.After(result[i].PeriodEnd) {
+				result[i].TasksDone++
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Velocity returns the tasks and effort finished in each of the last
+// periods windows up to now. A task done, reopened and done again within
+// one period counts once; archived tasks still count.
+func (s *TaskStore) Velocity(window time.Duration, periods int) []VelocityPeriod {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock()
+	total := CalculateVelocity(nil, window, periods, now)
+	for _, task := range s.tasks {
+		for i, period := range CalculateVelocity(task.StatusHistory, window, periods, now) {
+			if period.TasksDone > 0 {
+				total[i].TasksDone++
+				total[i].EffortDone += task.Effort
+			}
+		}
+	}
+	return total
+}
+
+// velocityHandler serves GET /api/v1/velocity. ?window= takes a number of
+// days like 7d or a duration like 12h, defaulting to a week, and ?periods=
+// how many windows to return.
+func (srv *Server) velocityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	window := 7 * 24 * time.Hour
+	if raw := r.URL.Query().Get("window"); raw != "" {
+		var err error
+		if window, err = parseAge(raw); err != nil {
+			http.Error(w, "window must be a number of days like 7d or a duration like 12h", http.StatusBadRequest)
+			return
+		}
+	}
+	periods := defaultVelocityPeriods
+	if raw := r.URL.Query().Get("periods"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxVelocityPeriods {
+			http.Error(w, "periods must be between 1 and "+strconv.Itoa(maxVelocityPeriods), http.StatusBadRequest)
+			return
+		}
+		periods = n
+	}
+	writeJSON(w, http.StatusOK, srv.Velocity(window, periods))
+}
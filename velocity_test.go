@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCalculateVelocity(t *testing.T) {
+	end := time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	history := []StatusTransition{
+		{FromStatus: "doing", ToStatus: "done", At: end.Add(-20 * day)}, // before the first period
+		{FromStatus: "doing", ToStatus: "done", At: end.Add(-13 * day)},
+		{FromStatus: "todo", ToStatus: "doing", At: end.Add(-12 * day)},
+		{FromStatus: "doing", ToStatus: "done", At: end.Add(-7 * day)}, // a period's end is inclusive
+		{FromStatus: "doing", ToStatus: "done", At: end.Add(-time.Hour)},
+		{FromStatus: "doing", ToStatus: "done", At: end},
+	}
+
+	periods := CalculateVelocity(history, 7*day, 2, end)
+	if len(periods) != 2 {
+		t.Fatalf("Expected 2 periods, got %d", len(periods))
+	}
+	if !periods[0].PeriodStart.Equal(end.Add(-14*day)) || !periods[0].PeriodEnd.Equal(end.Add(-7*day)) || !periods[1].PeriodEnd.Equal(end) {
+		t.Errorf("Unexpected period bounds %+v", periods)
+	}
+	if periods[0].TasksDone != 2 || periods[1].TasksDone != 2 {
+		t.Errorf("Expected 2 and 2 done, got %d and %d", periods[0].TasksDone, periods[1].TasksDone)
+	}
+}
+
+func TestVelocityEndpoint(t *testing.T) {
+	s := withTestGlobals(t)
+	now := time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC)
+	current := now.AddDate(0, 0, -10)
+	s.now = func() time.Time { return current }
+
+	first, _ := s.CreateTask(TaskSpec{Title: "Early", Effort: 3})
+	second, _ := s.CreateTask(TaskSpec{Title: "Late", Effort: 5})
+	third, _ := s.CreateTask(TaskSpec{Title: "Reopened", Effort: 2})
+	s.CreateTask(TaskSpec{Title: "Open", Effort: 8})
+	s.MoveTask(first.ID, "done") // 10 days ago, in the first week
+	current = now.AddDate(0, 0, -2)
+	s.MoveTask(second.ID, "done")
+	s.MoveTask(third.ID, "done")
+	s.MoveTask(third.ID, "doing")
+	s.MoveTask(third.ID, "done")
+	current = now
+
+	rr := httptest.NewRecorder()
+	NewServer(s).velocityHandler(rr, httptest.NewRequest(http.MethodGet, "/api/v1/velocity?window=7d&periods=3", nil))
+	var periods []VelocityPeriod
+	if err := json.Unmarshal(rr.Body.Bytes(), &periods); err != nil {
+		t.Fatal(err)
+	}
+	want := []VelocityPeriod{{TasksDone: 0}, {TasksDone: 1, EffortDone: 3}, {TasksDone: 2, EffortDone: 7}}
+	if len(periods) != len(want) {
+		t.Fatalf("Expected %d periods, got %d", len(want), len(periods))
+	}
+	for i, p := range periods {
+		if p.TasksDone != want[i].TasksDone || p.EffortDone != want[i].EffortDone {
+			t.Errorf("Period %d: expected %d tasks and %d points, got %d and %d", i, want[i].TasksDone, want[i].EffortDone, p.TasksDone, p.EffortDone)
+		}
+	}
+	if !periods[2].PeriodEnd.Equal(now) {
+		t.Errorf("Expected the last period to end now, got %v", periods[2].PeriodEnd)
+	}
+
+	for _, query := range []string{"window=0d", "periods=0", "periods=x"} {
+		rr := httptest.NewRecorder()
+		NewServer(s).velocityHandler(rr, httptest.NewRequest(http.MethodGet, "/api/v1/velocity?"+query, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", query, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SetWatching adds user to a task's watchers, or removes them. Users are
+// compared ignoring case. It returns false if the task doesn't exist and
+// ErrBoardLocked while the board is locked.
+func (s *TaskStore) SetWatching(id int, user string, watching bool) (*Task, bool, error) {
+	span := s.startSpan(context.Background(), "SetWatching", attribute.Int("task.id", id))
+	defer span.End()
+
+	s.mu.Lock()
+	if err := s.checkLocked(); err != nil {
+		s.mu.Unlock()
+		return nil, true, err
+	}
+	task, ok := s.tasks[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, false, nil
+	}
+	before := strings.Join(task.Watchers, ",")
+	watchers := []string{}
+	for _, watcher := range task.Watchers {
+		if !strings.EqualFold(watcher, user) {
+			watchers = append(watchers, watcher)
+		}
+	}
+	if watching {
+		watchers = append(watchers, user)
+	}
+	if strings.Join(watchers, ",") == before {
+		unchanged := task.clone()
+		s.mu.Unlock()
+		return unchanged, true, nil
+	}
+	if len(watchers) == 0 {
+		watchers = nil
+	}
+	task.Watchers = watchers
+	task.UpdatedAt = s.clock()
+	s.persist(task.Status)
+	updated := task.clone()
+	s.mu.Unlock()
+
+	s.publish(Event{
+		Type:    EventTaskUpdated,
+		Task:    updated,
+		Actor:   user,
+		Changes: []FieldChange{{Field: "watchers", Old: before, New: strings.Join(watchers, ",")}},
+	})
+	return updated, true, nil
+}
+
+// watchedEvent returns the TaskWatched event that follows e, if e changed a
+// task someone watches. Changes to the watchers themselves notify nobody.
+func watchedEvent(e Event) (Event, bool) {
+	if e.Type != EventTaskUpdated && e.Type != EventTaskMoved || e.Task == nil || len(e.Task.Watchers) == 0 {
+		return Event{}, false
+	}
+	if len(e.Changes) == 1 && e.Changes[0].Field == "watchers" {
+		return Event{}, false
+	}
+	watched := e
+	watched.Type = EventTaskWatched
+	watched.Watchers = append([]string(nil), e.Task.Watchers...)
+	return watched, true
+}
+
+// taskWatchHandler serves POST and DELETE /api/v1/tasks/{id}/watch, which
+// start and stop watching a task for {"user": "alice@example.com"} and
+// return the task
+func (srv *Server) taskWatchHandler(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in struct {
+		User string `json:"user"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	user := strings.TrimSpace(in.User)
+	if user == "" || strings.Contains(user, ",") {
+		writeError(w, fieldError("user", "user is required and must not contain commas"))
+		return
+	}
+
+	task, ok, err := srv.SetWatching(id, user, r.Method == http.MethodPost)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !ok {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func watchRequest(method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	NewServer(store).taskAPIHandler(rr, req)
+	return rr
+}
+
+func TestTaskWatchHandler(t *testing.T) {
+	s := withTestGlobals(t)
+	s.AddTask("Watched", "")
+
+	watchRequest(http.MethodPost, "/api/v1/tasks/1/watch", `{"user": "ann@example.com"}`)
+	rr := watchRequest(http.MethodPost, "/api/v1/tasks/1/watch", `{"user": "bo@example.com"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var task Task
+	json.Unmarshal(rr.Body.Bytes(), &task)
+	if strings.Join(task.Watchers, ",") != "ann@example.com,bo@example.com" {
+		t.Errorf("Expected both watchers, got %v", task.Watchers)
+	}
+	watchRequest(http.MethodPost, "/api/v1/tasks/1/watch", `{"user": "ANN@example.com"}`)
+	if got, _ := s.GetTask(1); len(got.Watchers) != 2 {
+		t.Errorf("Expected watching twice to keep one entry, got %v", got.Watchers)
+	}
+
+	rr = watchRequest(http.MethodDelete, "/api/v1/tasks/1/watch", `{"user": "ann@example.com"}`)
+	json.Unmarshal(rr.Body.Bytes(), &task)
+	if rr.Code != http.StatusOK || strings.Join(task.Watchers, ",") != "bo@example.com" {
+		t.Errorf("Expected only bo to remain, got %d %v", rr.Code, task.Watchers)
+	}
+
+	if rr := watchRequest(http.MethodPost, "/api/v1/tasks/1/watch", `{"user": " "}`); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a user, got %d", rr.Code)
+	}
+	if rr := watchRequest(http.MethodPost, "/api/v1/tasks/9/watch", `{"user": "ann"}`); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown task, got %d", rr.Code)
+	}
+}
+
+func TestTaskWatchedEvent(t *testing.T) {
+	s := newTestStore()
+	s.events = NewEventBus()
+	var got []Event
+	s.events.Subscribe(EventTaskWatched, func(e Event) { got = append(got, e) })
+
+	s.AddTask("Quiet", "")
+	s.AddTask("Watched", "")
+	s.MoveTask(1, "doing")
+	s.SetWatching(2, "ann", true)
+	s.SetWatching(2, "bo", true)
+	if len(got) != 0 {
+		t.Fatalf("Expected no watched events before the task changes, got %+v", got)
+	}
+
+	s.MoveTask(2, "doing")
+	s.AssignTask(2, "cy")
+	if len(got) != 2 {
+		t.Fatalf("Expected a watched event for the move and the update, got %d", len(got))
+	}
+	if got[0].ToStatus != "doing" || got[0].Task.ID != 2 {
+		t.Errorf("Unexpected move payload %+v", got[0])
+	}
+	for _, e := range got {
+		if strings.Join(e.Watchers, ",") != "ann,bo" {
+			t.Errorf("Expected watchers ann and bo, got %v", e.Watchers)
+		}
+	}
+	if got[1].Changes[0].Field != "assignee" {
+		t.Errorf("Expected the update's changes to be carried, got %+v", got[1].Changes)
+	}
+
+	s.SetWatching(2, "ann", false)
+	s.MoveTask(2, "done")
+	if len(got) != 3 || strings.Join(got[2].Watchers, ",") != "bo" {
+		t.Errorf("Expected only bo to be notified after ann stops watching, got %+v", got[len(got)-1].Watchers)
+	}
+}
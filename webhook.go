@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WebhookNotifier POSTs every task event as JSON to a list of URLs. A nil
+// notifier is valid and sends nothing.
+type WebhookNotifier struct {
+	URLs   []string
+	Client *http.Client
+
+	mu    sync.Mutex
+	stats WebhookStats
+}
+
+// maxRecentDeliveries is how many deliveries WebhookStats keeps
+const maxRecentDeliveries = 20
+
+// WebhookDelivery is the outcome of posting one event to one URL
+type WebhookDelivery struct {
+	URL   string
+	Event string
+	Time  time.Time
+	Error string // empty on success
+}
+
+// WebhookStats counts deliveries and keeps the most recent ones, newest
+// first
+type WebhookStats struct {
+	Succeeded int
+	Failed    int
+	Recent    []WebhookDelivery
+}
+
+// webhooks is the server's notifier, nil without webhook URLs
+var webhooks *WebhookNotifier
+
+// record notes a delivery's outcome
+func (n *WebhookNotifier) record(url string, e Event, err error) {
+	delivery := WebhookDelivery{URL: url, Event: e.Type, Time: time.Now()}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err != nil {
+		delivery.Error = err.Error()
+		n.stats.Failed++
+	} else {
+		n.stats.Succeeded++
+	}
+	n.stats.Recent = append([]WebhookDelivery{delivery}, n.stats.Recent...)
+	if len(n.stats.Recent) > maxRecentDeliveries {
+		n.stats.Recent = n.stats.Recent[:maxRecentDeliveries]
+	}
+}
+
+// Stats returns the delivery counts and recent deliveries
+func (n *WebhookNotifier) Stats() WebhookStats {
+	if n == nil {
+		return WebhookStats{}
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	stats := n.stats
+	stats.Recent = append([]WebhookDelivery(nil), n.stats.Recent...)
+	return stats
+}
+
+// webhookPayload is the JSON body sent for each event
+type webhookPayload struct {
+	Type       string        `json:"type"`
+	Task       *Task         `json:"task"`
+	FromStatus string        `json:"from_status,omitempty"`
+	ToStatus   string        `json:"to_status,omitempty"`
+	Changes    []FieldChange `json:"changes,omitempty"`
+	Time       time.Time     `json:"time"`
+}
+
+// NewWebhookNotifier returns a notifier for the given URLs, or nil when
+// there are none
+func NewWebhookNotifier(urls []string) *WebhookNotifier {
+	if len(urls) == 0 {
+		return nil
+	}
+	return &WebhookNotifier{URLs: urls, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Send delivers an event to every URL, returning the first failure
+func (n *WebhookNotifier) Send(e Event) error {
+	return n.SendContext(context.Background(), e)
+}
+
+// SendContext is Send, giving up on the deliveries once ctx is done
+func (n *WebhookNotifier) SendContext(ctx context.Context, e Event) error {
+	if n == nil {
+		return nil
+	}
+	body, err := json.Marshal(webhookPayload{
+		Type:       e.Type,
+		Task:       e.Task,
+		FromStatus: e.FromStatus,
+		ToStatus:   e.ToStatus,
+		Changes:    e.Changes,
+		Time:       e.Time,
+	})
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, url := range n.URLs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := n.Client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				err = fmt.Errorf("%s returned %s", url, resp.Status)
+			}
+		}
+		n.record(url, e, err)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// subscribeWebhooks forwards every event to the notifier as a background
+// job, without blocking the publisher
+func subscribeWebhooks(b *EventBus, n *WebhookNotifier) {
+	if n == nil {
+		return
+	}
+	b.Subscribe(EventAll, func(e Event) {
+		runInBackground("webhook", func(ctx context.Context) error {
+			if err := n.SendContext(ctx, e); err != nil {
+				return fmt.Errorf("sending %s webhook: %w", e.Type, err)
+			}
+			return nil
+		})
+	})
+}
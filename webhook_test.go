@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubscribeWebhooksPostsEvents(t *testing.T) {
+	payloads := make(chan webhookPayload, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON content type, got %q", r.Header.Get("Content-Type"))
+		}
+		var p webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("Invalid payload: %v", err)
+		}
+		payloads <- p
+	}))
+	defer srv.Close()
+
+	s := withTestGlobals(t)
+	subscribeWebhooks(bus, NewWebhookNotifier([]string{srv.URL}))
+	task := s.AddTask("Hooked", "")
+	s.MoveTask(task.ID, "doing")
+
+	got := map[string]webhookPayload{}
+	for len(got) < 2 {
+		select {
+		case p := <-payloads:
+			got[p.Type] = p
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for webhooks, got %v", got)
+		}
+	}
+	if p := got[EventTaskCreated]; p.Task == nil || p.Task.Title != "Hooked" {
+		t.Errorf("Unexpected created payload %+v", p)
+	}
+	if p := got[EventTaskMoved]; p.FromStatus != "todo" || p.ToStatus != "doing" {
+		t.Errorf("Unexpected moved payload %+v", p)
+	}
+}
+
+func TestWebhookNotifierReportsFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhookNotifier([]string{srv.URL}).Send(Event{Type: EventTaskCreated}); err == nil {
+		t.Errorf("Expected an error for a 500 response")
+	}
+	if NewWebhookNotifier(nil) != nil {
+		t.Errorf("Expected nil notifier without URLs")
+	}
+	var n *WebhookNotifier
+	if err := n.Send(Event{}); err != nil {
+		t.Errorf("Nil notifier should be a no-op, got %v", err)
+	}
+}
+
+func TestWebhookNotifierHonorsContext(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := NewWebhookNotifier([]string{srv.URL}).SendContext(ctx, Event{Type: EventTaskCreated}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled delivery to fail with context.Canceled, got %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("Expected nothing to be sent once the context is cancelled")
+	}
+}
+
+func TestWebhookNotifierStats(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	n := NewWebhookNotifier([]string{ok.URL, failing.URL})
+	n.Send(Event{Type: EventTaskCreated})
+	stats := n.Stats()
+	if stats.Succeeded != 1 || stats.Failed != 1 || len(stats.Recent) != 2 {
+		t.Fatalf("Expected one success and one failure, got %+v", stats)
+	}
+	if latest := stats.Recent[0]; latest.URL != failing.URL || latest.Error == "" || latest.Event != EventTaskCreated {
+		t.Errorf("Expected the failed delivery first, got %+v", latest)
+	}
+
+	for i := 0; i < maxRecentDeliveries; i++ {
+		n.record(ok.URL, Event{Type: EventTaskMoved}, nil)
+	}
+	if got := len(n.Stats().Recent); got != maxRecentDeliveries {
+		t.Errorf("Expected %d recent deliveries kept, got %d", maxRecentDeliveries, got)
+	}
+	var none *WebhookNotifier
+	if stats := none.Stats(); stats.Succeeded != 0 || stats.Recent != nil {
+		t.Errorf("Expected empty stats from a nil notifier")
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WorkflowConfig defines which status transitions are allowed as a directed
+// graph, e.g. {"todo":["doing"], "doing":["todo","done"], "done":[]}.
+// A nil graph allows every transition.
+type WorkflowConfig struct {
+	Transitions map[string][]string
+}
+
+// ErrTransitionNotAllowed is returned when the workflow forbids a move
+type ErrTransitionNotAllowed struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *ErrTransitionNotAllowed) Error() string {
+	allowed := "none"
+	if len(e.Allowed) > 0 {
+		allowed = strings.Join(e.Allowed, ", ")
+	}
+	return fmt.Sprintf("transition from %q to %q is not allowed (allowed: %s)", e.From, e.To, allowed)
+}
+
+// DefaultWorkflow returns a workflow that allows all transitions
+func DefaultWorkflow() *WorkflowConfig {
+	return &WorkflowConfig{}
+}
+
+// ParseWorkflow parses a workflow graph from JSON
+func ParseWorkflow(data []byte) (*WorkflowConfig, error) {
+	var transitions map[string][]string
+	if err := json.Unmarshal(data, &transitions); err != nil {
+		return nil, fmt.Errorf("invalid workflow: %w", err)
+	}
+	if transitions == nil {
+		transitions = make(map[string][]string)
+	}
+	return &WorkflowConfig{Transitions: transitions}, nil
+}
+
+// LoadWorkflow reads the workflow from the KANBAN_WORKFLOW env var, then
+// from workflow.json, falling back to the default when neither is set
+func LoadWorkflow() (*WorkflowConfig, error) {
+	if raw := os.Getenv("KANBAN_WORKFLOW"); raw != "" {
+		return ParseWorkflow([]byte(raw))
+	}
+
+	data, err := os.ReadFile(filepath.Join(".", "workflow.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultWorkflow(), nil
+		}
+		return nil, err
+	}
+	return ParseWorkflow(data)
+}
+
+// Allows reports whether a task may move from one status to another
+func (wf *WorkflowConfig) Allows(from, to string) bool {
+	if wf == nil || wf.Transitions == nil || from == to {
+		return true
+	}
+	for _, next := range wf.Transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedFrom returns the statuses reachable from the given status
+func (wf *WorkflowConfig) AllowedFrom(from string) []string {
+	if wf == nil || wf.Transitions == nil {
+		return nil
+	}
+	return wf.Transitions[from]
+}
+
+// checkTransition returns ErrTransitionNotAllowed if the move is forbidden
+func (wf *WorkflowConfig) checkTransition(from, to string) error {
+	if wf.Allows(from, to) {
+		return nil
+	}
+	return &ErrTransitionNotAllowed{From: from, To: to, Allowed: wf.AllowedFrom(from)}
+}
+
+// Workflow returns the store's workflow, which is empty when every move is
+// allowed
+func (s *TaskStore) Workflow() WorkflowConfig {
+	if s.workflow == nil {
+		return WorkflowConfig{}
+	}
+	return *s.workflow
+}
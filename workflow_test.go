@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const strictWorkflow = `{"todo":["doing"], "doing":["todo","done"], "done":[]}`
+
+func TestWorkflowAllowedTransitions(t *testing.T) {
+	store := newTestStore()
+	wf, err := ParseWorkflow([]byte(strictWorkflow))
+	if err != nil {
+		t.Fatalf("ParseWorkflow error: %v", err)
+	}
+	store.workflow = wf
+
+	task := store.AddTask("Flow", "")
+	if _, _, err := store.MoveTask(task.ID, "doing"); err != nil {
+		t.Errorf("todo -> doing should be allowed: %v", err)
+	}
+	if _, _, err := store.MoveTask(task.ID, "done"); err != nil {
+		t.Errorf("doing -> done should be allowed: %v", err)
+	}
+}
+
+func TestWorkflowForbiddenTransition(t *testing.T) {
+	store := newTestStore()
+	store.workflow, _ = ParseWorkflow([]byte(strictWorkflow))
+
+	task := store.AddTask("Flow", "")
+	_, ok, err := store.MoveTask(task.ID, "done")
+	if !ok {
+		t.Fatalf("Task should exist")
+	}
+	var notAllowed *ErrTransitionNotAllowed
+	if !errors.As(err, &notAllowed) {
+		t.Fatalf("Expected ErrTransitionNotAllowed, got %v", err)
+	}
+	if notAllowed.From != "todo" || notAllowed.To != "done" {
+		t.Errorf("Unexpected transition in error: %+v", notAllowed)
+	}
+	if store.tasks[task.ID].Status != "todo" {
+		t.Errorf("Forbidden move should not change status")
+	}
+}
+
+func TestDefaultWorkflowAllowsEverything(t *testing.T) {
+	store := newTestStore()
+	store.workflow = DefaultWorkflow()
+
+	task := store.AddTask("Anything goes", "")
+	for _, status := range []string{"done", "todo", "doing", "todo"} {
+		if _, _, err := store.MoveTask(task.ID, status); err != nil {
+			t.Errorf("Default workflow rejected move to %s: %v", status, err)
+		}
+	}
+}
+
+func TestLoadWorkflowFromEnv(t *testing.T) {
+	t.Setenv("KANBAN_WORKFLOW", strictWorkflow)
+	wf, err := LoadWorkflow()
+	if err != nil {
+		t.Fatalf("LoadWorkflow error: %v", err)
+	}
+	if wf.Allows("done", "todo") {
+		t.Errorf("done -> todo should be forbidden")
+	}
+
+	t.Setenv("KANBAN_WORKFLOW", "{not json")
+	if _, err := LoadWorkflow(); err == nil {
+		t.Errorf("Expected error for invalid workflow JSON")
+	}
+}
+
+func TestMoveTaskHandlerForbiddenTransition(t *testing.T) {
+	s := newTestStore()
+	s.workflow, _ = ParseWorkflow([]byte(strictWorkflow))
+	s.AddTask("Flow", "")
+
+	req := httptest.NewRequest(http.MethodPost, "/move-task", strings.NewReader("id=1&status=done"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	NewServer(s).moveTaskHandler(rec, req)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "not allowed") {
+		t.Errorf("Expected error message in body, got %q", rec.Body.String())
+	}
+}
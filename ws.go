@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsSendBuffer is how many outgoing messages a client may fall behind by
+// before it is dropped
+const wsSendBuffer = 32
+
+// wsMaxMessageSize caps incoming client messages
+const wsMaxMessageSize = 4096
+
+var wsUpgrader = websocket.Upgrader{}
+
+// wsRequest is a command sent by a client, e.g. {"type":"move","id":1,"status":"done"}
+type wsRequest struct {
+	Type   string `json:"type"`
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+// wsMessage is sent to clients: the full board on connect, a delta for each
+// task event, or an error in reply to a bad request
+type wsMessage struct {
+	Type       string        `json:"type"`
+	Tasks      []*Task       `json:"tasks,omitempty"`
+	Task       *Task         `json:"task,omitempty"`
+	FromStatus string        `json:"from_status,omitempty"`
+	ToStatus   string        `json:"to_status,omitempty"`
+	Changes    []FieldChange `json:"changes,omitempty"`
+	Message    string        `json:"message,omitempty"`
+}
+
+// wsClient is one connected socket. Only the hub goroutine writes to or
+// closes send.
+type wsClient struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// wsDirect is a message addressed to a single client
+type wsDirect struct {
+	client *wsClient
+	msg    []byte
+}
+
+// WSHub tracks connected clients. All client bookkeeping happens in the Run
+// goroutine, so the hub itself needs no locks.
+type WSHub struct {
+	clients    map[*wsClient]bool
+	register   chan *wsClient
+	unregister chan *wsClient
+	broadcast  chan []byte
+	direct     chan wsDirect
+	done       chan struct{}
+}
+
+// NewWSHub returns a hub that must be started with Run
+func NewWSHub() *WSHub {
+	return &WSHub{
+		clients:    make(map[*wsClient]bool),
+		register:   make(chan *wsClient),
+		unregister: make(chan *wsClient),
+		broadcast:  make(chan []byte),
+		direct:     make(chan wsDirect),
+		done:       make(chan struct{}),
+	}
+}
+
+var wsHub = NewWSHub()
+
+// Run services the hub's channels until stop is closed
+func (h *WSHub) Run(stop <-chan struct{}) {
+	defer close(h.done)
+	for {
+		select {
+		case c := <-h.register:
+			h.clients[c] = true
+		case c := <-h.unregister:
+			h.drop(c)
+		case msg := <-h.broadcast:
+			for c := range h.clients {
+				h.deliver(c, msg)
+			}
+		case d := <-h.direct:
+			if h.clients[d.client] {
+				h.deliver(d.client, d.msg)
+			}
+		case <-stop:
+			for c := range h.clients {
+				h.drop(c)
+			}
+			return
+		}
+	}
+}
+
+// deliver queues msg for c, dropping clients that have fallen too far behind
+func (h *WSHub) deliver(c *wsClient, msg []byte) {
+	select {
+	case c.send <- msg:
+	default:
+		h.drop(c)
+	}
+}
+
+func (h *WSHub) drop(c *wsClient) {
+	if h.clients[c] {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+// join, leave, and enqueue hand work to the Run goroutine, giving up if the
+// hub has stopped
+func (h *WSHub) join(c *wsClient) {
+	select {
+	case h.register <- c:
+	case <-h.done:
+	}
+}
+
+func (h *WSHub) leave(c *wsClient) {
+	select {
+	case h.unregister <- c:
+	case <-h.done:
+	}
+}
+
+func (h *WSHub) enqueue(d wsDirect) {
+	select {
+	case h.direct <- d:
+	case <-h.done:
+	}
+}
+
+// PublishEvent broadcasts a task event to every client. Subscribe it to the
+// event bus so changes made over HTTP reach socket clients too.
+func (h *WSHub) PublishEvent(e Event) {
+	msg, err := json.Marshal(wsMessage{
+		Type:       e.Type,
+		Task:       e.Task,
+		FromStatus: e.FromStatus,
+		ToStatus:   e.ToStatus,
+		Changes:    e.Changes,
+	})
+	if err != nil {
+		log.Printf("Error encoding websocket event: %v", err)
+		return
+	}
+	select {
+	case h.broadcast <- msg:
+	case <-h.done:
+	}
+}
+
+// reply sends a message to one client
+func (h *WSHub) reply(c *wsClient, m wsMessage) {
+	msg, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("Error encoding websocket reply: %v", err)
+		return
+	}
+	h.enqueue(wsDirect{client: c, msg: msg})
+}
+
+// wsHandler upgrades /ws connections, sends the current board, and then
+// executes commands from the client. Resulting changes are broadcast to
+// every client through the event bus.
+func (srv *Server) wsHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has already written an error response
+	}
+	conn.SetReadLimit(wsMaxMessageSize)
+
+	// Queue the board before registering so it always arrives before any delta
+	board, err := json.Marshal(wsMessage{Type: "board", Tasks: srv.GetAllTasks()})
+	if err != nil {
+		log.Printf("Error encoding websocket board: %v", err)
+		conn.Close()
+		return
+	}
+	c := &wsClient{conn: conn, send: make(chan []byte, wsSendBuffer)}
+	c.send <- board
+
+	wsHub.join(c)
+	go c.writePump()
+	c.readPump(wsHub, srv.Store)
+}
+
+// readPump executes client commands against s until the connection closes
+func (c *wsClient) readPump(h *WSHub, s Store) {
+	defer func() {
+		h.leave(c)
+		c.conn.Close()
+	}()
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req wsRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			h.reply(c, wsMessage{Type: "error", Message: "invalid JSON"})
+			continue
+		}
+
+		switch req.Type {
+		case "move":
+			if !isValidStatus(req.Status) {
+				h.reply(c, wsMessage{Type: "error", Message: "invalid status"})
+				continue
+			}
+			if _, ok, err := s.MoveTask(req.ID, req.Status); !ok {
+				h.reply(c, wsMessage{Type: "error", Message: "task not found"})
+			} else if err != nil {
+				h.reply(c, wsMessage{Type: "error", Message: err.Error()})
+			}
+		default:
+			h.reply(c, wsMessage{Type: "error", Message: "unknown message type"})
+		}
+	}
+}
+
+// writePump sends queued messages until the hub closes send
+func (c *wsClient) writePump() {
+	defer c.conn.Close()
+	for msg := range c.send {
+		if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			return
+		}
+	}
+	c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// withTestHub starts a fresh hub wired to the test bus and serves /ws
+func withTestHub(t *testing.T) string {
+	withTestGlobals(t)
+	orig := wsHub
+	wsHub = NewWSHub()
+	stop := make(chan struct{})
+	go wsHub.Run(stop)
+	bus.Subscribe(EventAll, wsHub.PublishEvent)
+
+	srv := httptest.NewServer(http.HandlerFunc(NewServer(store).wsHandler))
+	t.Cleanup(func() {
+		srv.Close()
+		close(stop)
+		wsHub = orig
+	})
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func dialWS(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readWS(t *testing.T, conn *websocket.Conn) wsMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	return msg
+}
+
+func TestWSMoveBroadcastsToAllClients(t *testing.T) {
+	url := withTestHub(t)
+	task := store.AddTask("Shared", "")
+
+	alice := dialWS(t, url)
+	bob := dialWS(t, url)
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		if msg := readWS(t, conn); msg.Type != "board" || len(msg.Tasks) != 1 {
+			t.Fatalf("Expected initial board with 1 task, got %+v", msg)
+		}
+	}
+
+	if err := alice.WriteJSON(wsRequest{Type: "move", ID: task.ID, Status: "done"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	for name, conn := range map[string]*websocket.Conn{"alice": alice, "bob": bob} {
+		msg := readWS(t, conn)
+		if msg.Type != EventTaskMoved || msg.Task.ID != task.ID || msg.FromStatus != "todo" || msg.ToStatus != "done" {
+			t.Errorf("%s: unexpected delta %+v", name, msg)
+		}
+	}
+	if moved, _ := store.GetTask(task.ID); moved.Status != "done" {
+		t.Errorf("Expected store to reflect the move, got %s", moved.Status)
+	}
+}
+
+func TestWSHTTPChangesReachClients(t *testing.T) {
+	url := withTestHub(t)
+	conn := dialWS(t, url)
+	readWS(t, conn)
+
+	store.AddTask("From HTTP", "")
+	if msg := readWS(t, conn); msg.Type != EventTaskCreated || msg.Task.Title != "From HTTP" {
+		t.Errorf("Expected created delta, got %+v", msg)
+	}
+}
+
+func TestWSErrorsGoOnlyToSender(t *testing.T) {
+	url := withTestHub(t)
+	alice := dialWS(t, url)
+	bob := dialWS(t, url)
+	readWS(t, alice)
+	readWS(t, bob)
+
+	alice.WriteMessage(websocket.TextMessage, []byte("not json"))
+	if msg := readWS(t, alice); msg.Type != "error" {
+		t.Errorf("Expected error reply, got %+v", msg)
+	}
+	alice.WriteJSON(wsRequest{Type: "move", ID: 99, Status: "done"})
+	if msg := readWS(t, alice); msg.Type != "error" || msg.Message != "task not found" {
+		t.Errorf("Expected not found error, got %+v", msg)
+	}
+
+	// bob's next message must be the broadcast, not alice's errors
+	store.AddTask("After errors", "")
+	if msg := readWS(t, bob); msg.Type != EventTaskCreated {
+		t.Errorf("Expected bob to only see the broadcast, got %+v", msg)
+	}
+}
+
+func TestWSAndDevReloadBehindMiddleware(t *testing.T) {
+	withTestGlobals(t)
+	orig := wsHub
+	wsHub = NewWSHub()
+	stop := make(chan struct{})
+	go wsHub.Run(stop)
+	bus.Subscribe(EventAll, wsHub.PublishEvent)
+
+	changes := make(chan struct{}, 1)
+	d, err := NewDevReloadServer(t.TempDir(), func() error {
+		changes <- struct{}{}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewDevReloadServer failed: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", NewServer(store).wsHandler)
+	mux.Handle("/dev/reload", d)
+	srv := httptest.NewServer(withMiddleware(mux, 0))
+	t.Cleanup(func() {
+		srv.Close()
+		d.Close()
+		close(stop)
+		wsHub = orig
+	})
+
+	conn := dialWS(t, "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws")
+	if msg := readWS(t, conn); msg.Type != "board" {
+		t.Fatalf("Expected the board on connecting, got %+v", msg)
+	}
+	store.AddTask("Through the stack", "")
+	if msg := readWS(t, conn); msg.Type != EventTaskCreated || msg.Task.Title != "Through the stack" {
+		t.Errorf("Expected the created task over the upgraded connection, got %+v", msg)
+	}
+
+	resp, err := http.Get(srv.URL + "/dev/reload")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Expected the reload stream through the middleware, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+}